@@ -16,6 +16,7 @@ func installKubernetesMetadataEndpoints(r *mux.Router) {
 	r.HandleFunc("/tags/pod/{nodeName}", getPodMetadataForNode).Methods("GET")
 	r.HandleFunc("/tags/pod", getAllMetadata).Methods("GET")
 	r.HandleFunc("/tags/node/{nodeName}", getNodeMetadata).Methods("GET")
+	r.HandleFunc("/tags/ips", getPodsMetadataForIPs).Methods("POST")
 }
 
 // getNodeMetadata is only used when the node agent hits the DCA for the list of labels
@@ -237,3 +238,60 @@ func getAllMetadata(w http.ResponseWriter, r *http.Request) {
 	)
 	return
 }
+
+// getPodsMetadataForIPs is used by the process-agent to resolve connections to pods running on other nodes.
+func getPodsMetadataForIPs(w http.ResponseWriter, r *http.Request) {
+	/*
+		Input
+			localhost:5001/api/v1/tags/ips
+			Body: ["10.0.1.2", "1.1.1.1"]
+		Outputs
+			Status: 200
+			Returns: map[string]PodIPMetadata
+			Example: {"10.0.1.2": {"name": "web-5d69", "namespace": "default", "uid": "...", "container_ids": ["..."], "services": ["web"]}}
+
+			Status: 400
+			Returns: string
+			Example: "invalid character 'x' looking for beginning of value"
+
+			Status: 500
+			Returns: string
+			Example: "pod IPs collection is disabled on the Cluster Agent"
+	*/
+	var ips []string
+	if err := json.NewDecoder(r.Body).Decode(&ips); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		apiRequests.Inc(
+			"getPodsMetadataForIPs",
+			strconv.Itoa(http.StatusBadRequest),
+		)
+		return
+	}
+
+	metadata, err := as.GetPodsMetadataForIPs(ips)
+	if err != nil {
+		log.Debugf("Could not retrieve the pods owning %d IPs: %v", len(ips), err) //nolint:errcheck
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apiRequests.Inc(
+			"getPodsMetadataForIPs",
+			strconv.Itoa(http.StatusInternalServerError),
+		)
+		return
+	}
+
+	metaBytes, err := json.Marshal(metadata)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apiRequests.Inc(
+			"getPodsMetadataForIPs",
+			strconv.Itoa(http.StatusInternalServerError),
+		)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(metaBytes)
+	apiRequests.Inc(
+		"getPodsMetadataForIPs",
+		strconv.Itoa(http.StatusOK),
+	)
+}
@@ -32,9 +32,12 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/clusteragent"
 	admissionpkg "github.com/DataDog/datadog-agent/pkg/clusteragent/admission"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/validate"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/orchestrator"
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
 	"github.com/DataDog/datadog-agent/pkg/forwarder"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
@@ -182,6 +185,18 @@ func start(cmd *cobra.Command, args []string) error {
 	aggregatorInstance := aggregator.InitAggregator(s, hostname)
 	aggregatorInstance.AddAgentStartupTelemetry(fmt.Sprintf("%s - Datadog Cluster Agent", version.AgentVersion))
 
+	// Start the DogStatsD server used by node agents for cluster-level aggregation
+	var dsdServer *dogstatsd.Server
+	if config.Datadog.GetBool("cluster_aggregation.enabled") {
+		// The DogStatsD server of the Cluster Agent always listens to non-local traffic
+		dsdServer, err = dogstatsd.NewServer(aggregatorInstance)
+		if err != nil {
+			return log.Errorf("Could not start cluster-level DogStatsD: %s", err)
+		}
+		// Locally aggregated metrics can be used as fallback values of DatadogMetrics
+		externalmetrics.SetLocalMetricsSource(dsdServer)
+	}
+
 	log.Infof("Datadog Cluster Agent is now running.")
 
 	apiCl, err := apiserver.WaitForAPIClient(context.Background()) // make sure we can connect to the apiserver
@@ -314,6 +329,8 @@ func start(cmd *cobra.Command, args []string) error {
 		server := admissioncmd.NewServer()
 		server.Register(config.Datadog.GetString("admission_controller.inject_config.endpoint"), mutate.InjectConfig, apiCl.DynamicCl)
 		server.Register(config.Datadog.GetString("admission_controller.inject_tags.endpoint"), mutate.InjectTags, apiCl.DynamicCl)
+		server.Register(config.Datadog.GetString("admission_controller.auto_instrumentation.endpoint"), mutate.InjectAutoInstrumentation, apiCl.DynamicCl)
+		server.Register(config.Datadog.GetString("admission_controller.validate_ad_annotations.endpoint"), validate.ValidateADAnnotations, apiCl.DynamicCl)
 
 		// Start the k8s admission webhook server
 		wg.Add(1)
@@ -354,6 +371,10 @@ func start(cmd *cobra.Command, args []string) error {
 	// Cancel the main context to stop components
 	mainCtxCancel()
 
+	if dsdServer != nil {
+		dsdServer.Stop()
+	}
+
 	// wait for the External Metrics Server and
 	// the Admission Webhook Server to stop properly
 	wg.Wait()
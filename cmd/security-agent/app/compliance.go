@@ -145,6 +145,7 @@ func startCompliance(hostname string, endpoints *config.Endpoints, context *clie
 		checks.WithHostRootMount(os.Getenv("HOST_ROOT")),
 		checks.MayFail(checks.WithDocker()),
 		checks.MayFail(checks.WithAudit()),
+		checks.MayFail(checks.WithSystemd()),
 	}
 
 	if coreconfig.IsKubernetes() {
@@ -10,10 +10,12 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/auditor"
@@ -25,6 +27,7 @@ import (
 	secconfig "github.com/DataDog/datadog-agent/pkg/security/config"
 	"github.com/DataDog/datadog-agent/pkg/security/policy"
 	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/replay"
 	"github.com/DataDog/datadog-agent/pkg/security/rules"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -45,11 +48,34 @@ var (
 	checkPoliciesArgs = struct {
 		dir string
 	}{}
+
+	reloadPoliciesCmd = &cobra.Command{
+		Use:   "reload-policies",
+		Short: "Reload the policies of system-probe and return the changed rules",
+		RunE:  reloadPolicies,
+	}
+
+	replayCmd = &cobra.Command{
+		Use:   "replay",
+		Short: "Evaluate policies against recorded events and return a report",
+		RunE:  replayEvents,
+	}
+
+	replayArgs = struct {
+		dir    string
+		events string
+	}{}
 )
 
 func init() {
 	runtimeCmd.AddCommand(checkPoliciesCmd)
 	checkPoliciesCmd.Flags().StringVar(&checkPoliciesArgs.dir, "policies-dir", coreconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
+
+	runtimeCmd.AddCommand(reloadPoliciesCmd)
+
+	runtimeCmd.AddCommand(replayCmd)
+	replayCmd.Flags().StringVar(&replayArgs.dir, "policies-dir", coreconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
+	replayCmd.Flags().StringVar(&replayArgs.events, "events", "", "Path to a file of recorded events, '-' to read from the standard input")
 }
 
 func checkPolicies(cmd *cobra.Command, args []string) error {
@@ -83,6 +109,65 @@ func checkPolicies(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func reloadPolicies(cmd *cobra.Command, args []string) error {
+	// we'll search for a config file named `datadog.yaml`
+	coreconfig.Datadog.SetConfigName("datadog")
+	if err := common.SetupConfig(confPath); err != nil {
+		return fmt.Errorf("unable to set up global agent configuration: %v", err)
+	}
+
+	client, err := secagent.NewRuntimeSecurityClient()
+	if err != nil {
+		return errors.Wrap(err, "unable to create a runtime security client instance")
+	}
+	defer client.Close()
+
+	report, err := client.ReloadPolicies()
+	if err != nil {
+		return errors.Wrap(err, "unable to reload policies")
+	}
+
+	content, _ := json.MarshalIndent(report, "", "\t")
+	fmt.Printf("%s\n", string(content))
+
+	return nil
+}
+
+func replayEvents(cmd *cobra.Command, args []string) error {
+	if replayArgs.events == "" {
+		return errors.New("a file of recorded events is required")
+	}
+
+	cfg := &secconfig.Config{
+		PoliciesDir: replayArgs.dir,
+	}
+
+	ruleSet := replay.NewRuleSet(rules.NewOptsWithParams(false, sprobe.SECLConstants, sprobe.InvalidDiscarders))
+	if err := policy.LoadPolicies(cfg, ruleSet); err != nil {
+		return err
+	}
+
+	reader := os.Stdin
+	if replayArgs.events != "-" {
+		f, err := os.Open(replayArgs.events)
+		if err != nil {
+			return errors.Wrap(err, "failed to open events")
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	replayer := replay.NewReplayer(ruleSet)
+	if err := replayer.ReplayFrom(reader); err != nil {
+		return err
+	}
+
+	content, _ := json.MarshalIndent(replayer.GetReport(), "", "\t")
+	fmt.Printf("%s\n", string(content))
+
+	return nil
+}
+
 func newRuntimeReporter(stopper restart.Stopper, sourceName, sourceType string, endpoints *config.Endpoints, context *client.DestinationsContext) (event.Reporter, error) {
 	health := health.RegisterLiveness("runtime-security")
 
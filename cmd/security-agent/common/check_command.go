@@ -13,9 +13,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/compliance"
 	"github.com/DataDog/datadog-agent/pkg/compliance/agent"
 	"github.com/DataDog/datadog-agent/pkg/compliance/checks"
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
@@ -24,6 +27,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/version"
 	"github.com/cihub/seelog"
 
 	"github.com/spf13/cobra"
@@ -31,9 +35,11 @@ import (
 
 var (
 	checkArgs = struct {
-		framework string
-		file      string
-		verbose   bool
+		framework    string
+		file         string
+		verbose      bool
+		report       string
+		reportFormat string
 	}{}
 )
 
@@ -41,6 +47,8 @@ func setupCheckCmd(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&checkArgs.framework, "framework", "", "", "Framework to run the checks from")
 	cmd.Flags().StringVarP(&checkArgs.file, "file", "f", "", "Compliance suite file to read rules from")
 	cmd.Flags().BoolVarP(&checkArgs.verbose, "verbose", "v", false, "Include verbose details")
+	cmd.Flags().StringVarP(&checkArgs.report, "report", "", "", "Write a report of the checks results to the specified file")
+	cmd.Flags().StringVarP(&checkArgs.reportFormat, "report-format", "", "", "Format of the report, json or sarif (default: sarif for a .sarif file, json otherwise)")
 }
 
 // CheckCmd returns a cobra command to run security agent checks
@@ -91,6 +99,7 @@ func runCheck(cmd *cobra.Command, confPath *string, args []string) error {
 			checks.WithHostRootMount(os.Getenv("HOST_ROOT")),
 			checks.MayFail(checks.WithDocker()),
 			checks.MayFail(checks.WithAudit()),
+			checks.MayFail(checks.WithSystemd()),
 		}...)
 
 		if config.IsKubernetes() {
@@ -127,17 +136,57 @@ func runCheck(cmd *cobra.Command, confPath *string, args []string) error {
 		options = append(options, checks.WithMatchSuite(checks.IsFramework(checkArgs.framework)))
 	}
 
+	reportFormat, err := getReportFormat()
+	if err != nil {
+		return err
+	}
+
+	var checksStatus compliance.CheckStatusList
 	if checkArgs.file != "" {
-		err = agent.RunChecksFromFile(reporter, checkArgs.file, options...)
+		checksStatus, err = agent.RunChecksFromFile(reporter, checkArgs.file, options...)
 	} else {
 		configDir := config.Datadog.GetString("compliance_config.dir")
-		err = agent.RunChecks(reporter, configDir, options...)
+		checksStatus, err = agent.RunChecks(reporter, configDir, options...)
 	}
 
 	if err != nil {
 		log.Errorf("Failed to run checks: %v", err)
 		return err
 	}
+
+	if checkArgs.report != "" {
+		return writeReport(hostname, checksStatus, reportFormat)
+	}
+	return nil
+}
+
+func getReportFormat() (compliance.ExportFormat, error) {
+	switch format := compliance.ExportFormat(strings.ToLower(checkArgs.reportFormat)); format {
+	case compliance.ExportFormatJSON, compliance.ExportFormatSARIF:
+		return format, nil
+	case "":
+		if strings.ToLower(filepath.Ext(checkArgs.report)) == ".sarif" {
+			return compliance.ExportFormatSARIF, nil
+		}
+		return compliance.ExportFormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown report format `%s`, expecting json or sarif", checkArgs.reportFormat)
+	}
+}
+
+func writeReport(hostname string, checksStatus compliance.CheckStatusList, format compliance.ExportFormat) error {
+	f, err := os.Create(checkArgs.report)
+	if err != nil {
+		return fmt.Errorf("unable to create report file: %v", err)
+	}
+	defer f.Close()
+
+	report := compliance.NewExportReport(hostname, version.AgentVersion, time.Now(), checksStatus)
+	if err := report.Write(f, format); err != nil {
+		return fmt.Errorf("unable to write report: %v", err)
+	}
+
+	log.Infof("Report of %d checks written to %s", len(report.Checks), checkArgs.report)
 	return nil
 }
 
@@ -38,6 +38,10 @@ var opts struct {
 	debug       bool
 	version     bool
 	console     bool // windows only; execute on console rather than via SCM
+
+	// linux only; replay recorded network tracer events instead of running the system-probe
+	replayPath     string
+	replayPcapPath string
 }
 
 // Version info sourced from build flags
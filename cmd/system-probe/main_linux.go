@@ -4,6 +4,8 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"os"
 
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 )
@@ -13,8 +15,18 @@ func main() {
 	flag.StringVar(&opts.configPath, "config", "/etc/datadog-agent/system-probe.yaml", "Path to system-probe config formatted as YAML")
 	flag.StringVar(&opts.pidFilePath, "pid", "", "Path to set pidfile for process")
 	flag.BoolVar(&opts.version, "version", false, "Print the version and exit")
+	flag.StringVar(&opts.replayPath, "replay", "", "Replay network tracer events recorded with system_probe_config.replay_record_file, print the connections and exit")
+	flag.StringVar(&opts.replayPcapPath, "replay-pcap", "", "Replay the DNS packets of a pcap file, print the DNS stats and exit")
 	flag.Parse()
 
+	if opts.replayPath != "" || opts.replayPcapPath != "" {
+		if err := runReplay(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "replay failed: %s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Handles signals, which tells us whether we should exit.
 	exit := make(chan struct{})
 	go util.HandleSignals(exit)
@@ -0,0 +1,69 @@
+// +build linux_bpf
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/encoding"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+)
+
+// runReplay feeds the recorded tracer events (or the DNS packets of a pcap file) through the
+// network state and DNS snooper, and writes the connections returned for each recorded request
+func runReplay(out io.Writer) error {
+	if opts.replayPath != "" && opts.replayPcapPath != "" {
+		return fmt.Errorf("-replay and -replay-pcap are mutually exclusive")
+	}
+
+	cfg, err := config.NewSystemProbeConfig(loggerName, opts.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to create agent config: %s", err)
+	}
+	tracerConfig := config.SysProbeConfigFromConfig(cfg)
+
+	var events []network.ReplayEvent
+	if opts.replayPath != "" {
+		events, err = network.ReadReplayFile(opts.replayPath)
+	} else {
+		events, err = network.ReadPcapFile(opts.replayPcapPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	state := network.NewState(
+		tracerConfig.ClientStateExpiry,
+		tracerConfig.MaxClosedConnectionsBuffered,
+		tracerConfig.MaxConnectionsStateBuffered,
+		tracerConfig.MaxDNSStatsBufferred,
+	)
+	replayer := network.NewReplayer(state, tracerConfig.CollectDNSStats, tracerConfig.CollectLocalDNS, tracerConfig.DNSTimeout)
+	defer replayer.Close()
+
+	results, err := replayer.Replay(events)
+	if err != nil {
+		return err
+	}
+
+	marshaler := encoding.GetMarshaler(encoding.ContentTypeJSON)
+	for _, conns := range results {
+		content, err := marshaler.Marshal(conns)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "%s\n", content); err != nil {
+			return err
+		}
+	}
+
+	stats, err := json.Marshal(replayer.GetDNSStats())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", stats)
+	return err
+}
@@ -0,0 +1,12 @@
+// +build linux,!linux_bpf
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+func runReplay(out io.Writer) error {
+	return fmt.Errorf("replaying network tracer events requires a system-probe built with eBPF support")
+}
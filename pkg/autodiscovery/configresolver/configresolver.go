@@ -92,6 +92,7 @@ func Resolve(tpl integration.Config, svc listeners.Service) (integration.Config,
 		Source:          tpl.Source,
 		MetricsExcluded: svc.HasFilter(containers.MetricsFilter),
 		LogsExcluded:    svc.HasFilter(containers.LogsFilter),
+		Placement:       tpl.Placement,
 	}
 	copy(resolvedConfig.InitConfig, tpl.InitConfig)
 	copy(resolvedConfig.Instances, tpl.Instances)
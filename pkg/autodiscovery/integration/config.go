@@ -57,6 +57,13 @@ type Config struct {
 	IgnoreAutodiscoveryTags bool         `json:"ignore_autodiscovery_tags"` // used to ignore tags coming from autodiscovery (include in digest: true)
 	MetricsExcluded         bool         `json:"-"`                         // whether metrics collection is disabled (set by container listeners only) (include in digest: false)
 	LogsExcluded            bool         `json:"-"`                         // whether logs collection is disabled (set by container listeners only) (include in digest: false)
+	Placement               *Placement   `json:"placement,omitempty"`       // placement constraints of a cluster check (optional) (include in digest: true)
+}
+
+// Placement holds the constraints on the nodes a cluster check can be dispatched to
+type Placement struct {
+	NodeSelector map[string]string `json:"node_selector,omitempty" yaml:"node_selector"` // labels a node must have to run the check
+	SpreadBy     string            `json:"spread_by,omitempty" yaml:"spread_by"`         // node label whose values the instances of the check are spread across
 }
 
 // CommonInstanceConfig holds the reserved fields for the yaml instance data
@@ -314,6 +321,19 @@ func (c *Config) Digest() string {
 	h.Write([]byte(c.LogsConfig))                                  //nolint:errcheck
 	h.Write([]byte(c.Entity))                                      //nolint:errcheck
 	h.Write([]byte(strconv.FormatBool(c.IgnoreAutodiscoveryTags))) //nolint:errcheck
+	if c.Placement != nil {
+		// only hash placement constraints when they are set
+		// to keep the digest of other configs unchanged
+		selector := make([]string, 0, len(c.Placement.NodeSelector))
+		for k, v := range c.Placement.NodeSelector {
+			selector = append(selector, k+"="+v)
+		}
+		sort.Strings(selector)
+		for _, s := range selector {
+			h.Write([]byte(s)) //nolint:errcheck
+		}
+		h.Write([]byte(c.Placement.SpreadBy)) //nolint:errcheck
+	}
 
 	return strconv.FormatUint(h.Sum64(), 16)
 }
@@ -236,6 +236,27 @@ func TestDigest(t *testing.T) {
 
 	// assert the ClusterCheck field is not taken into account
 	assert.NotEqual(t, simpleConfig.Digest(), simpleIngoreADTagsConfig.Digest())
+
+	placementConfig := &Config{
+		Name:       "foo",
+		InitConfig: Data(""),
+		Placement:  &Placement{NodeSelector: map[string]string{"zone": "a", "role": "checks"}},
+	}
+	samePlacementConfig := &Config{
+		Name:       "foo",
+		InitConfig: Data(""),
+		Placement:  &Placement{NodeSelector: map[string]string{"role": "checks", "zone": "a"}},
+	}
+	spreadConfig := &Config{
+		Name:       "foo",
+		InitConfig: Data(""),
+		Placement:  &Placement{SpreadBy: "zone"},
+	}
+
+	// assert placement constraints are taken into account, regardless of the selector order
+	assert.Equal(t, placementConfig.Digest(), samePlacementConfig.Digest())
+	assert.NotEqual(t, simpleConfig.Digest(), placementConfig.Digest())
+	assert.NotEqual(t, placementConfig.Digest(), spreadConfig.Digest())
 }
 
 func TestGetNameForInstance(t *testing.T) {
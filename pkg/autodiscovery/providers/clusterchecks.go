@@ -14,10 +14,16 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/hostinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
-const defaultGraceDuration = 60 * time.Second
+const (
+	defaultGraceDuration = 60 * time.Second
+	// nodeLabelsTTL is how long the node labels are cached before being
+	// retrieved again, so that label changes are reported to the cluster-agent
+	nodeLabelsTTL = 5 * time.Minute
+)
 
 // ClusterChecksConfigProvider implements the ConfigProvider interface
 // for the cluster check feature.
@@ -27,6 +33,8 @@ type ClusterChecksConfigProvider struct {
 	heartbeat      time.Time
 	lastChange     int64
 	nodeName       string
+	nodeLabels     map[string]string
+	nodeLabelsTime time.Time
 	flushedConfigs bool
 }
 
@@ -87,6 +95,7 @@ func (c *ClusterChecksConfigProvider) IsUpToDate() (bool, error) {
 
 	status := types.NodeStatus{
 		LastChange: c.lastChange,
+		Labels:     c.getNodeLabels(),
 	}
 
 	reply, err := c.dcaClient.PostClusterCheckStatus(c.nodeName, status)
@@ -109,6 +118,38 @@ func (c *ClusterChecksConfigProvider) IsUpToDate() (bool, error) {
 	return reply.IsUpToDate, nil
 }
 
+// getNodeLabels returns the labels reported to the cluster-agent to honor
+// the placement constraints of cluster checks. The node labels are cached
+// for nodeLabelsTTL and the last known ones are kept when they cannot be
+// refreshed, labels set in the configuration take precedence.
+func (c *ClusterChecksConfigProvider) getNodeLabels() map[string]string {
+	if c.nodeLabels != nil && time.Since(c.nodeLabelsTime) < nodeLabelsTTL {
+		return c.nodeLabels
+	}
+
+	labels := make(map[string]string)
+	if config.IsKubernetes() {
+		nodeLabels, err := hostinfo.GetNodeLabels()
+		if err != nil {
+			log.Debugf("Cannot get node labels, cluster checks placement constraints may not be honored: %v", err)
+			if c.nodeLabels != nil {
+				return c.nodeLabels
+			}
+			return config.Datadog.GetStringMapString("cluster_checks.node_labels")
+		}
+		for k, v := range nodeLabels {
+			labels[k] = v
+		}
+	}
+	for k, v := range config.Datadog.GetStringMapString("cluster_checks.node_labels") {
+		labels[k] = v
+	}
+
+	c.nodeLabels = labels
+	c.nodeLabelsTime = time.Now()
+	return labels
+}
+
 // Collect retrieves configurations the cluster-agent dispatched to this agent
 func (c *ClusterChecksConfigProvider) Collect() ([]integration.Config, error) {
 	if c.dcaClient == nil {
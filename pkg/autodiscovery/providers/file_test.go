@@ -56,6 +56,15 @@ func TestGetIntegrationConfig(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, config.ADIdentifiers, []string{"foo_id", "bar_id"})
 
+	// cluster check with placement constraints
+	config, err = GetIntegrationConfigFromFile("foo", "tests/cluster_check_placement.yaml")
+	require.Nil(t, err)
+	assert.True(t, config.ClusterCheck)
+	assert.Equal(t, &integration.Placement{
+		NodeSelector: map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+		SpreadBy:     "kubernetes.io/hostname",
+	}, config.Placement)
+
 	// autodiscovery: check if we correctly refuse to load if a 'docker_images' section is present
 	config, err = GetIntegrationConfigFromFile("foo", "tests/ad_deprecated.yaml")
 	assert.NotNil(t, err)
@@ -126,8 +135,11 @@ func TestCollect(t *testing.T) {
 	// ignored autoconf file not collected
 	assert.Equal(t, 0, len(get("ignored")))
 
+	// cluster check configs collected in root directory
+	assert.Equal(t, 1, len(get("cluster_check_placement")))
+
 	// total number of configurations found
-	assert.Equal(t, 15, len(configs))
+	assert.Equal(t, 16, len(configs))
 
 	// incorrect configs get saved in the Errors map (invalid.yaml & notaconfig.yaml & ad_deprecated.yaml)
 	assert.Equal(t, 3, len(provider.Errors))
@@ -105,16 +105,10 @@ func buildTemplates(key string, checkNames []string, initConfigs, instances [][]
 	templates := make([]integration.Config, 0)
 
 	// sanity checks
-	if len(checkNames) != len(initConfigs) || len(checkNames) != len(instances) {
-		log.Error("Template entries don't all have the same length, not using them.")
+	if err := validateTemplates(checkNames, initConfigs, instances); err != nil {
+		log.Errorf("%s, not using them", err)
 		return templates
 	}
-	for idx := range initConfigs {
-		if len(initConfigs[idx]) != 1 {
-			log.Error("Templates init Configs list is not valid, not using Templates entries")
-			return templates
-		}
-	}
 
 	for idx := range checkNames {
 		for _, instance := range instances[idx] {
@@ -129,6 +123,28 @@ func buildTemplates(key string, checkNames []string, initConfigs, instances [][]
 	return templates
 }
 
+// validateTemplates checks that the check names, init configs and instances can be combined into templates
+func validateTemplates(checkNames []string, initConfigs, instances [][]integration.Data) error {
+	if len(checkNames) != len(initConfigs) || len(checkNames) != len(instances) {
+		return fmt.Errorf("template entries don't all have the same length: %d check names, %d init configs, %d instances",
+			len(checkNames), len(initConfigs), len(instances))
+	}
+	for idx := range initConfigs {
+		if len(initConfigs[idx]) != 1 {
+			return fmt.Errorf("templates init configs list is not valid: expected one init config for check %s, got %d",
+				checkNames[idx], len(initConfigs[idx]))
+		}
+	}
+	return nil
+}
+
+// ValidateTemplatesFromMap checks the autodiscovery configurations in a given map
+// (either docker labels or kubernetes annotations) and returns the errors preventing their use.
+func ValidateTemplatesFromMap(input map[string]string, prefix string) []error {
+	_, errors := extractTemplatesFromMap("", input, prefix)
+	return errors
+}
+
 // extractTemplatesFromMap looks for autodiscovery configurations in a given map
 // (either docker labels or kubernetes annotations) and returns them if found.
 func extractTemplatesFromMap(key string, input map[string]string, prefix string) ([]integration.Config, []error) {
@@ -179,6 +195,10 @@ func extractCheckTemplatesFromMap(key string, input map[string]string, prefix st
 		return []integration.Config{}, fmt.Errorf("in %s: %s", instancePath, err)
 	}
 
+	if err = validateTemplates(checkNames, initConfigs, instances); err != nil {
+		return []integration.Config{}, err
+	}
+
 	return buildTemplates(key, checkNames, initConfigs, instances), nil
 }
 
@@ -317,6 +317,18 @@ func TestExtractTemplatesFromMap(t *testing.T) {
 			output:       nil,
 			errs:         []error{errors.New("could not extract checks config: in instances: failed to unmarshal JSON: invalid character '\"' after object key")},
 		},
+		{
+			// Mismatched check names and init configs lengths
+			source: map[string]string{
+				"prefix.check_names":  "[\"apache\",\"http_check\"]",
+				"prefix.init_configs": "[{}]",
+				"prefix.instances":    "[{\"apache_status_url\":\"http://%%host%%/server-status?auto\"}]",
+			},
+			adIdentifier: "id",
+			prefix:       "prefix.",
+			output:       nil,
+			errs:         []error{errors.New("could not extract checks config: template entries don't all have the same length: 2 check names, 1 init configs, 1 instances")},
+		},
 		{
 			// Invalid logs json
 			source: map[string]string{
@@ -16,19 +16,22 @@ type Config struct {
 	secretName string
 	secretNs   string
 	templates  []admiv1beta1.MutatingWebhook
+	validating []admiv1beta1.ValidatingWebhook
 }
 
 // NewConfig creates a webhook controller configuration
-func NewConfig(name, secretName, secretNs string, webhooks []admiv1beta1.MutatingWebhook) Config {
+func NewConfig(name, secretName, secretNs string, webhooks []admiv1beta1.MutatingWebhook, validatingWebhooks []admiv1beta1.ValidatingWebhook) Config {
 	return Config{
 		name:       name,
 		secretName: secretName,
 		secretNs:   secretNs,
 		templates:  webhooks,
+		validating: validatingWebhooks,
 	}
 }
 
-func (w *Config) GetName() string                             { return w.name }
-func (w *Config) GetSecretName() string                       { return w.secretName }
-func (w *Config) GetSecretNs() string                         { return w.secretNs }
-func (w *Config) GetTemplates() []admiv1beta1.MutatingWebhook { return w.templates }
+func (w *Config) GetName() string                                         { return w.name }
+func (w *Config) GetSecretName() string                                   { return w.secretName }
+func (w *Config) GetSecretNs() string                                     { return w.secretNs }
+func (w *Config) GetTemplates() []admiv1beta1.MutatingWebhook             { return w.templates }
+func (w *Config) GetValidatingTemplates() []admiv1beta1.ValidatingWebhook { return w.validating }
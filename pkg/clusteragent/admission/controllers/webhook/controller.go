@@ -30,20 +30,23 @@ import (
 )
 
 // Controller is responsible for watching the TLS certificate stored
-// in a Secret and reconciling the webhook configuration based on it.
+// in a Secret and reconciling the webhook configurations based on it.
 type Controller struct {
-	clientSet      kubernetes.Interface
-	config         Config
-	secretsLister  corelisters.SecretLister
-	secretsSynced  cache.InformerSynced
-	webhooksLister admissionlisters.MutatingWebhookConfigurationLister
-	webhooksSynced cache.InformerSynced
-	queue          workqueue.RateLimitingInterface
-	isLeaderFunc   func() bool
+	clientSet                kubernetes.Interface
+	config                   Config
+	secretsLister            corelisters.SecretLister
+	secretsSynced            cache.InformerSynced
+	webhooksLister           admissionlisters.MutatingWebhookConfigurationLister
+	webhooksSynced           cache.InformerSynced
+	validatingWebhooksLister admissionlisters.ValidatingWebhookConfigurationLister
+	validatingWebhooksSynced cache.InformerSynced
+	queue                    workqueue.RateLimitingInterface
+	isLeaderFunc             func() bool
 }
 
 // NewController returns a new Webhook Controller.
-func NewController(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, isLeaderFunc func() bool, config Config) *Controller {
+// The validating webhook informer can be nil if no validating webhook is configured.
+func NewController(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, validatingWebhookInformer admissioninformers.ValidatingWebhookConfigurationInformer, isLeaderFunc func() bool, config Config) *Controller {
 	controller := &Controller{
 		clientSet:      client,
 		config:         config,
@@ -64,6 +67,15 @@ func NewController(client kubernetes.Interface, secretInformer coreinformers.Sec
 		UpdateFunc: controller.handleWebhookUpdate,
 		DeleteFunc: controller.handleWebhook,
 	})
+	if validatingWebhookInformer != nil {
+		controller.validatingWebhooksLister = validatingWebhookInformer.Lister()
+		controller.validatingWebhooksSynced = validatingWebhookInformer.Informer().HasSynced
+		validatingWebhookInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.handleWebhook,
+			UpdateFunc: controller.handleWebhookUpdate,
+			DeleteFunc: controller.handleWebhook,
+		})
+	}
 	return controller
 }
 
@@ -75,7 +87,11 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	log.Infof("Starting webhook controller for secret %s/%s and webhook %s", c.config.GetSecretNs(), c.config.GetSecretName(), c.config.GetName())
 	defer log.Infof("Stopping webhook controller for secret %s/%s and webhook %s", c.config.GetSecretNs(), c.config.GetSecretName(), c.config.GetName())
 
-	if ok := cache.WaitForCacheSync(stopCh, c.secretsSynced, c.webhooksSynced); !ok {
+	synced := []cache.InformerSynced{c.secretsSynced, c.webhooksSynced}
+	if c.validatingWebhooksSynced != nil {
+		synced = append(synced, c.validatingWebhooksSynced)
+	}
+	if ok := cache.WaitForCacheSync(stopCh, synced...); !ok {
 		return
 	}
 
@@ -141,17 +157,17 @@ func (c *Controller) handleWebhookUpdate(oldObj, newObj interface{}) {
 	if !c.isLeaderFunc() {
 		return
 	}
-	newWebhook, ok := newObj.(*admiv1beta1.MutatingWebhookConfiguration)
+	newWebhook, ok := newObj.(metav1.Object)
 	if !ok {
-		log.Debugf("Expected MutatingWebhookConfiguration object, got: %v", newObj)
+		log.Debugf("Expected webhook configuration object, got: %v", newObj)
 		return
 	}
-	oldWebhook, ok := oldObj.(*admiv1beta1.MutatingWebhookConfiguration)
+	oldWebhook, ok := oldObj.(metav1.Object)
 	if !ok {
-		log.Debugf("Expected MutatingWebhookConfiguration object, got: %v", oldObj)
+		log.Debugf("Expected webhook configuration object, got: %v", oldObj)
 		return
 	}
-	if newWebhook.ResourceVersion == oldWebhook.ResourceVersion {
+	if newWebhook.GetResourceVersion() == oldWebhook.GetResourceVersion() {
 		return
 	}
 	c.handleWebhook(newObj)
@@ -205,7 +221,7 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
-// reconcile reconciles the current state of the Webhook with its desired state.
+// reconcile reconciles the current state of the Webhooks with their desired state.
 func (c *Controller) reconcile() error {
 	secret, err := c.secretsLister.Secrets(c.config.GetSecretNs()).Get(c.config.GetSecretName())
 	if err != nil {
@@ -215,6 +231,18 @@ func (c *Controller) reconcile() error {
 		return err
 	}
 
+	if err := c.reconcileMutatingWebhook(secret); err != nil {
+		return err
+	}
+
+	if c.validatingWebhooksLister == nil {
+		return nil
+	}
+	return c.reconcileValidatingWebhook(secret)
+}
+
+// reconcileMutatingWebhook reconciles the MutatingWebhookConfiguration object.
+func (c *Controller) reconcileMutatingWebhook(secret *corev1.Secret) error {
 	webhook, err := c.webhooksLister.Get(c.config.GetName())
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -261,3 +289,52 @@ func (c *Controller) newWebhooks(secret *corev1.Secret) []admiv1beta1.MutatingWe
 	}
 	return webhooks
 }
+
+// reconcileValidatingWebhook reconciles the ValidatingWebhookConfiguration object.
+func (c *Controller) reconcileValidatingWebhook(secret *corev1.Secret) error {
+	webhook, err := c.validatingWebhooksLister.Get(c.config.GetName())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Infof("Validating Webhook %s was not found, creating it", c.config.GetName())
+			return c.createValidatingWebhook(secret)
+		}
+		return err
+	}
+
+	log.Debugf("The Validating Webhook %s was found, updating it", c.config.GetName())
+	return c.updateValidatingWebhook(secret, webhook)
+}
+
+// createValidatingWebhook creates a new ValidatingWebhookConfiguration object.
+func (c *Controller) createValidatingWebhook(secret *corev1.Secret) error {
+	webhook := &admiv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.config.GetName(),
+		},
+		Webhooks: c.newValidatingWebhooks(secret),
+	}
+	_, err := c.clientSet.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Create(webhook)
+	if errors.IsAlreadyExists(err) {
+		log.Infof("Validating Webhook %s already exists", webhook.GetName())
+		return nil
+	}
+	return err
+}
+
+// updateValidatingWebhook stores a new configuration in the ValidatingWebhookConfiguration object.
+func (c *Controller) updateValidatingWebhook(secret *corev1.Secret, webhook *admiv1beta1.ValidatingWebhookConfiguration) error {
+	webhook = webhook.DeepCopy()
+	webhook.Webhooks = c.newValidatingWebhooks(secret)
+	_, err := c.clientSet.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Update(webhook)
+	return err
+}
+
+// newValidatingWebhooks generates ValidatingWebhook objects from config templates with updated CABundle from Secret.
+func (c *Controller) newValidatingWebhooks(secret *corev1.Secret) []admiv1beta1.ValidatingWebhook {
+	webhooks := []admiv1beta1.ValidatingWebhook{}
+	for _, tpl := range c.config.GetValidatingTemplates() {
+		tpl.ClientConfig.CABundle = certificate.GetCABundle(secret.Data)
+		webhooks = append(webhooks, tpl)
+	}
+	return webhooks
+}
@@ -36,6 +36,11 @@ var (
 				Name: "webhook-bar",
 			},
 		},
+		validating: []admiv1beta1.ValidatingWebhook{
+			{
+				Name: "webhook-validate",
+			},
+		},
 	}
 )
 
@@ -75,6 +80,15 @@ func TestCreateWebhook(t *testing.T) {
 		t.Fatalf("Invalid Webhook: %v", err)
 	}
 
+	validatingWebhook, err := c.validatingWebhooksLister.Get(cfg.GetName())
+	if err != nil {
+		t.Fatalf("Failed to get the Validating Webhook: %v", err)
+	}
+
+	if err := validateValidating(validatingWebhook, secret); err != nil {
+		t.Fatalf("Invalid Validating Webhook: %v", err)
+	}
+
 	if c.queue.Len() != 0 {
 		t.Fatal("Work queue isn't empty")
 	}
@@ -126,6 +140,16 @@ func validate(w *admiv1beta1.MutatingWebhookConfiguration, s *corev1.Secret) err
 	return nil
 }
 
+func validateValidating(w *admiv1beta1.ValidatingWebhookConfiguration, s *corev1.Secret) error {
+	if len(w.Webhooks) != 1 {
+		return fmt.Errorf("Webhooks should contain 1 entry, got %d", len(w.Webhooks))
+	}
+	if !reflect.DeepEqual(w.Webhooks[0].ClientConfig.CABundle, certificate.GetCABundle(s.Data)) {
+		return fmt.Errorf("The Webhook CABundle doesn't match the Secret: CABundle: %v, Secret: %v", w.Webhooks[0].ClientConfig.CABundle, s)
+	}
+	return nil
+}
+
 type fixture struct {
 	t      *testing.T
 	client *fake.Clientset
@@ -147,6 +171,7 @@ func (f *fixture) run(t *testing.T) *Controller {
 		f.client,
 		factory.Core().V1().Secrets(),
 		factory.Admissionregistration().V1beta1().MutatingWebhookConfigurations(),
+		factory.Admissionregistration().V1beta1().ValidatingWebhookConfigurations(),
 		func() bool { return true },
 		cfg,
 	)
@@ -0,0 +1,260 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package mutate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	admiv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// libVersionAnnotationKeyFormat is the format of the annotation used to opt-in
+	// a pod to the injection of a tracing library, e.g. admission.datadoghq.com/java-lib.version
+	libVersionAnnotationKeyFormat = "admission.datadoghq.com/%s-lib.version"
+
+	volumeName = "datadog-auto-instrumentation"
+	mountPath  = "/datadog-lib"
+
+	// libCopyScriptPath is the script shipped in the library init images which copies
+	// the library into the directory given as argument
+	libCopyScriptPath = "/datadog-init/copy-lib.sh"
+
+	javaToolOptionsEnvVarName = "JAVA_TOOL_OPTIONS"
+	pythonPathEnvVarName      = "PYTHONPATH"
+	nodeOptionsEnvVarName     = "NODE_OPTIONS"
+)
+
+type language string
+
+const (
+	java   language = "java"
+	python language = "python"
+	js     language = "js"
+)
+
+// supportedLanguages lists the languages of the tracing libraries that can be injected
+var supportedLanguages = []language{java, python, js}
+
+// libInfo describes a tracing library to inject
+type libInfo struct {
+	lang  language
+	image string
+}
+
+// libEnvVar describes how an env var must be amended to load a tracing library
+type libEnvVar struct {
+	name      string
+	value     string
+	separator string
+	prepend   bool
+}
+
+// libEnvVars contains the env vars to set for each supported language
+var libEnvVars = map[language]libEnvVar{
+	java: {
+		name:      javaToolOptionsEnvVarName,
+		value:     "-javaagent:" + mountPath + "/dd-java-agent.jar",
+		separator: " ",
+	},
+	python: {
+		name:      pythonPathEnvVarName,
+		value:     mountPath + "/",
+		separator: ":",
+		prepend:   true,
+	},
+	js: {
+		name:      nodeOptionsEnvVarName,
+		value:     "--require=" + mountPath + "/node_modules/dd-trace/init",
+		separator: " ",
+	},
+}
+
+// InjectAutoInstrumentation injects the APM tracing libraries requested by the pod annotations
+func InjectAutoInstrumentation(req *admiv1beta1.AdmissionRequest, dc dynamic.Interface) (*admiv1beta1.AdmissionResponse, error) {
+	return mutate(req, injectAutoInstrumentation, dc)
+}
+
+// injectAutoInstrumentation adds an init container copying the tracing library
+// into a shared volume and sets the env vars loading it, for each requested language
+func injectAutoInstrumentation(pod *corev1.Pod, _ string, _ dynamic.Interface) error {
+	var injected bool
+	defer func() {
+		metrics.MutationAttempts.Inc(metrics.LibInjectionMutationType, strconv.FormatBool(injected))
+	}()
+
+	if pod == nil {
+		metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "nil pod")
+		return errors.New("cannot inject lib into nil pod")
+	}
+
+	libs := extractLibInfo(pod, config.Datadog.GetString("admission_controller.auto_instrumentation.container_registry"))
+	if len(libs) == 0 {
+		return nil
+	}
+
+	for _, lib := range libs {
+		if containsInitContainer(pod.Spec.InitContainers, initContainerName(lib.lang)) {
+			log.Debugf("Ignoring %s library injection in pod %s: init container already exists", lib.lang, podString(pod))
+			continue
+		}
+		if err := injectLibEnv(pod, libEnvVars[lib.lang]); err != nil {
+			metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "env var conflict")
+			log.Warnf("Cannot inject %s library in pod %s: %v", lib.lang, podString(pod), err)
+			continue
+		}
+		injectLibInitContainer(pod, lib)
+		injected = true
+	}
+
+	if injected {
+		injectLibVolume(pod)
+	}
+
+	return nil
+}
+
+// extractLibInfo returns the tracing libraries requested by the pod annotations
+func extractLibInfo(pod *corev1.Pod, registry string) []libInfo {
+	var libs []libInfo
+	annotations := pod.GetAnnotations()
+	for _, lang := range supportedLanguages {
+		key := fmt.Sprintf(libVersionAnnotationKeyFormat, lang)
+		version, found := annotations[key]
+		if !found {
+			continue
+		}
+		if version == "" {
+			log.Warnf("Invalid empty annotation '%s' on pod %s, ignoring it", key, podString(pod))
+			continue
+		}
+		libs = append(libs, libInfo{
+			lang:  lang,
+			image: fmt.Sprintf("%s/dd-lib-%s-init:%s", registry, lang, version),
+		})
+	}
+	return libs
+}
+
+func initContainerName(lang language) string {
+	return fmt.Sprintf("datadog-lib-%s-init", lang)
+}
+
+// containsInitContainer returns whether the init containers contain a container with a given name
+func containsInitContainer(containers []corev1.Container, name string) bool {
+	for _, ctr := range containers {
+		if ctr.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// injectLibInitContainer adds the init container copying the library into the shared volume
+func injectLibInitContainer(pod *corev1.Pod, lib libInfo) {
+	log.Debugf("Injecting init container '%s' into pod %s", initContainerName(lib.lang), podString(pod))
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+		Name:    initContainerName(lib.lang),
+		Image:   lib.image,
+		Command: []string{"sh", libCopyScriptPath, mountPath},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      volumeName,
+				MountPath: mountPath,
+			},
+		},
+	})
+}
+
+// injectLibVolume adds the shared volume to the pod and mounts it into the application containers
+func injectLibVolume(pod *corev1.Pod) {
+	found := false
+	for _, vol := range pod.Spec.Volumes {
+		if vol.Name == volumeName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	for i, ctr := range pod.Spec.Containers {
+		mounted := false
+		for _, mount := range ctr.VolumeMounts {
+			if mount.Name == volumeName {
+				mounted = true
+				break
+			}
+		}
+		if !mounted {
+			pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: mountPath,
+			})
+		}
+	}
+}
+
+// injectLibEnv amends the env var loading the library in every container.
+// It fails without modifying the pod if a container defines the env var from a source.
+func injectLibEnv(pod *corev1.Pod, env libEnvVar) error {
+	for _, ctr := range pod.Spec.Containers {
+		for _, e := range ctr.Env {
+			if e.Name == env.name && e.ValueFrom != nil {
+				return fmt.Errorf("env var '%s' of container '%s' is set from a source", env.name, ctr.Name)
+			}
+		}
+	}
+
+	for i, ctr := range pod.Spec.Containers {
+		index := -1
+		for j, e := range ctr.Env {
+			if e.Name == env.name {
+				index = j
+				break
+			}
+		}
+
+		if index < 0 {
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, corev1.EnvVar{
+				Name:  env.name,
+				Value: env.value,
+			})
+			continue
+		}
+
+		current := ctr.Env[index].Value
+		switch {
+		case strings.Contains(current, env.value):
+			log.Debugf("Ignoring container '%s' in pod %s: env var '%s' already loads the library", ctr.Name, podString(pod), env.name)
+		case current == "":
+			pod.Spec.Containers[i].Env[index].Value = env.value
+		case env.prepend:
+			pod.Spec.Containers[i].Env[index].Value = env.value + env.separator + current
+		default:
+			pod.Spec.Containers[i].Env[index].Value = current + env.separator + env.value
+		}
+	}
+
+	return nil
+}
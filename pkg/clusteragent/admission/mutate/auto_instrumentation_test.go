@@ -0,0 +1,157 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package mutate
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func fakePodWithAnnotation(k, v string) *corev1.Pod {
+	pod := fakePodWithContainer("foo-pod", fakeContainer("foo-container"))
+	pod.Annotations = map[string]string{k: v}
+	return pod
+}
+
+func TestInjectAutoInstrumentation(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("admission_controller.auto_instrumentation.container_registry", "my.registry")
+	defer mockConfig.Set("admission_controller.auto_instrumentation.container_registry", "gcr.io/datadoghq")
+
+	tests := []struct {
+		name               string
+		pod                *corev1.Pod
+		wantInitContainers []string
+		wantImages         []string
+		wantEnv            map[string]string
+	}{
+		{
+			name: "no annotation",
+			pod:  fakePodWithContainer("foo-pod", fakeContainer("foo-container")),
+		},
+		{
+			name:               "java",
+			pod:                fakePodWithAnnotation("admission.datadoghq.com/java-lib.version", "v0.87.0"),
+			wantInitContainers: []string{"datadog-lib-java-init"},
+			wantImages:         []string{"my.registry/dd-lib-java-init:v0.87.0"},
+			wantEnv:            map[string]string{"JAVA_TOOL_OPTIONS": "-javaagent:/datadog-lib/dd-java-agent.jar"},
+		},
+		{
+			name:               "python",
+			pod:                fakePodWithAnnotation("admission.datadoghq.com/python-lib.version", "v0.45.0"),
+			wantInitContainers: []string{"datadog-lib-python-init"},
+			wantImages:         []string{"my.registry/dd-lib-python-init:v0.45.0"},
+			wantEnv:            map[string]string{"PYTHONPATH": "/datadog-lib/"},
+		},
+		{
+			name:               "js",
+			pod:                fakePodWithAnnotation("admission.datadoghq.com/js-lib.version", "v1.0.0"),
+			wantInitContainers: []string{"datadog-lib-js-init"},
+			wantImages:         []string{"my.registry/dd-lib-js-init:v1.0.0"},
+			wantEnv:            map[string]string{"NODE_OPTIONS": "--require=/datadog-lib/node_modules/dd-trace/init"},
+		},
+		{
+			name: "java with existing options",
+			pod: func() *corev1.Pod {
+				pod := fakePodWithAnnotation("admission.datadoghq.com/java-lib.version", "latest")
+				pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-Xmx1g"))
+				return pod
+			}(),
+			wantInitContainers: []string{"datadog-lib-java-init"},
+			wantImages:         []string{"my.registry/dd-lib-java-init:latest"},
+			wantEnv:            map[string]string{"JAVA_TOOL_OPTIONS": "-Xmx1g -javaagent:/datadog-lib/dd-java-agent.jar"},
+		},
+		{
+			name: "python with existing path",
+			pod: func() *corev1.Pod {
+				pod := fakePodWithAnnotation("admission.datadoghq.com/python-lib.version", "latest")
+				pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, fakeEnvWithValue("PYTHONPATH", "/app"))
+				return pod
+			}(),
+			wantInitContainers: []string{"datadog-lib-python-init"},
+			wantImages:         []string{"my.registry/dd-lib-python-init:latest"},
+			wantEnv:            map[string]string{"PYTHONPATH": "/datadog-lib/:/app"},
+		},
+		{
+			name: "env var set from a source",
+			pod: func() *corev1.Pod {
+				pod := fakePodWithAnnotation("admission.datadoghq.com/js-lib.version", "latest")
+				pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{
+					Name:      "NODE_OPTIONS",
+					ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "node-options"}},
+				})
+				return pod
+			}(),
+		},
+		{
+			name:               "empty version",
+			pod:                fakePodWithAnnotation("admission.datadoghq.com/java-lib.version", ""),
+			wantInitContainers: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := injectAutoInstrumentation(tt.pod, "", nil)
+			require.NoError(t, err)
+
+			var initContainers, images []string
+			for _, ctr := range tt.pod.Spec.InitContainers {
+				initContainers = append(initContainers, ctr.Name)
+				images = append(images, ctr.Image)
+				assert.Equal(t, []string{"sh", "/datadog-init/copy-lib.sh", mountPath}, ctr.Command)
+			}
+			assert.Equal(t, tt.wantInitContainers, initContainers)
+			assert.Equal(t, tt.wantImages, images)
+
+			for name, value := range tt.wantEnv {
+				found := false
+				for _, env := range tt.pod.Spec.Containers[0].Env {
+					if env.Name == name {
+						found = true
+						assert.Equal(t, value, env.Value)
+					}
+				}
+				assert.True(t, found, "env var %s not found", name)
+			}
+
+			if len(tt.wantInitContainers) == 0 {
+				assert.Empty(t, tt.pod.Spec.Volumes)
+				assert.Empty(t, tt.pod.Spec.Containers[0].VolumeMounts)
+				return
+			}
+			require.Len(t, tt.pod.Spec.Volumes, 1)
+			assert.Equal(t, volumeName, tt.pod.Spec.Volumes[0].Name)
+			assert.NotNil(t, tt.pod.Spec.Volumes[0].EmptyDir)
+			require.Len(t, tt.pod.Spec.Containers[0].VolumeMounts, 1)
+			assert.Equal(t, mountPath, tt.pod.Spec.Containers[0].VolumeMounts[0].MountPath)
+		})
+	}
+}
+
+func TestInjectAutoInstrumentationIdempotency(t *testing.T) {
+	pod := fakePodWithAnnotation("admission.datadoghq.com/java-lib.version", "v0.87.0")
+	pod.Annotations["admission.datadoghq.com/python-lib.version"] = "v0.45.0"
+
+	require.NoError(t, injectAutoInstrumentation(pod, "", nil))
+	require.NoError(t, injectAutoInstrumentation(pod, "", nil))
+
+	assert.Len(t, pod.Spec.InitContainers, 2)
+	assert.Len(t, pod.Spec.Volumes, 1)
+	assert.Len(t, pod.Spec.Containers[0].VolumeMounts, 1)
+
+	env := map[string]string{}
+	for _, e := range pod.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	assert.Equal(t, "-javaagent:/datadog-lib/dd-java-agent.jar", env["JAVA_TOOL_OPTIONS"])
+	assert.Equal(t, "/datadog-lib/", env["PYTHONPATH"])
+}
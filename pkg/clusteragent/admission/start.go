@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"k8s.io/client-go/informers"
+	admissioninformers "k8s.io/client-go/informers/admissionregistration/v1beta1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
@@ -54,15 +55,31 @@ func StartControllers(ctx ControllerContext) error {
 	)
 	go secretController.Run(ctx.StopCh)
 
+	informersToSync := map[apiserver.InformerName]cache.SharedInformer{
+		apiserver.SecretsInformer:  ctx.SecretInformers.Core().V1().Secrets().Informer(),
+		apiserver.WebhooksInformer: ctx.WebhookInformers.Admissionregistration().V1beta1().MutatingWebhookConfigurations().Informer(),
+	}
+
+	// Only watch ValidatingWebhookConfigurations when needed,
+	// the Cluster Agent might not be allowed to access them.
+	validatingWebhooks := generateValidatingWebhooks()
+	var validatingWebhookInformer admissioninformers.ValidatingWebhookConfigurationInformer
+	if len(validatingWebhooks) > 0 {
+		validatingWebhookInformer = ctx.WebhookInformers.Admissionregistration().V1beta1().ValidatingWebhookConfigurations()
+		informersToSync[apiserver.ValidatingWebhooksInformer] = validatingWebhookInformer.Informer()
+	}
+
 	webhookConfig := webhook.NewConfig(
 		config.Datadog.GetString("admission_controller.webhook_name"),
 		config.Datadog.GetString("admission_controller.certificate.secret_name"),
 		common.GetResourcesNamespace(),
-		generateWebhooks())
+		generateWebhooks(),
+		validatingWebhooks)
 	webhookController := webhook.NewController(
 		ctx.Client,
 		ctx.SecretInformers.Core().V1().Secrets(),
 		ctx.WebhookInformers.Admissionregistration().V1beta1().MutatingWebhookConfigurations(),
+		validatingWebhookInformer,
 		ctx.IsLeaderFunc,
 		webhookConfig,
 	)
@@ -71,8 +88,5 @@ func StartControllers(ctx ControllerContext) error {
 	ctx.SecretInformers.Start(ctx.StopCh)
 	ctx.WebhookInformers.Start(ctx.StopCh)
 
-	return apiserver.SyncInformers(map[apiserver.InformerName]cache.SharedInformer{
-		apiserver.SecretsInformer:  ctx.SecretInformers.Core().V1().Secrets().Informer(),
-		apiserver.WebhooksInformer: ctx.WebhookInformers.Admissionregistration().V1beta1().MutatingWebhookConfigurations().Informer(),
-	})
+	return apiserver.SyncInformers(informersToSync)
 }
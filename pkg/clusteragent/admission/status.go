@@ -42,6 +42,8 @@ func GetStatus(apiCl kubernetes.Interface) map[string]interface{} {
 		status["Webhooks"] = webhookStatus
 	}
 
+	status["AutoInstrumentation"] = getAutoInstrumentationStatus()
+
 	secretStatus, err := getSecretStatus(ns, secretName, apiCl)
 	if err != nil {
 		status["SecretError"] = err.Error()
@@ -89,6 +91,14 @@ func getWebhookStatus(name string, apiCl kubernetes.Interface) (map[string]inter
 	return webhookStatus, nil
 }
 
+func getAutoInstrumentationStatus() map[string]interface{} {
+	autoInstruStatus := make(map[string]interface{})
+	autoInstruStatus["Enabled"] = config.Datadog.GetBool("admission_controller.auto_instrumentation.enabled")
+	autoInstruStatus["ContainerRegistry"] = config.Datadog.GetString("admission_controller.auto_instrumentation.container_registry")
+	autoInstruStatus["Endpoint"] = config.Datadog.GetString("admission_controller.auto_instrumentation.endpoint")
+	return autoInstruStatus
+}
+
 func getSecretStatus(ns, name string, apiCl kubernetes.Interface) (map[string]interface{}, error) {
 	secretStatus := make(map[string]interface{})
 	secret, err := apiCl.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
@@ -25,25 +25,7 @@ func generateWebhooks() []admiv1beta1.MutatingWebhook {
 	// DD_AGENT_HOST injection
 	if config.Datadog.GetBool("admission_controller.inject_config.enabled") {
 		webhook := getWebhookSkeleton("config", config.Datadog.GetString("admission_controller.inject_config.endpoint"))
-		if config.Datadog.GetBool("admission_controller.mutate_unlabelled") {
-			// Accept all, ignore pods if they're explicitly filtered-out
-			webhook.ObjectSelector = &metav1.LabelSelector{
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      EnabledLabelKey,
-						Operator: metav1.LabelSelectorOpNotIn,
-						Values:   []string{"false"},
-					},
-				},
-			}
-		} else {
-			// Ignore all, accept pods if they're explicitly whitelisted
-			webhook.ObjectSelector = &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					EnabledLabelKey: "true",
-				},
-			}
-		}
+		webhook.ObjectSelector = getLabelSelector()
 		webhooks = append(webhooks, webhook)
 	}
 
@@ -63,9 +45,62 @@ func generateWebhooks() []admiv1beta1.MutatingWebhook {
 		webhooks = append(webhooks, webhook)
 	}
 
+	// APM tracing libraries injection
+	if config.Datadog.GetBool("admission_controller.auto_instrumentation.enabled") {
+		webhook := getWebhookSkeleton("auto.instrumentation", config.Datadog.GetString("admission_controller.auto_instrumentation.endpoint"))
+		webhook.ObjectSelector = getLabelSelector()
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks
+}
+
+// generateValidatingWebhooks returns validating webhooks based on the configuration
+func generateValidatingWebhooks() []admiv1beta1.ValidatingWebhook {
+	webhooks := []admiv1beta1.ValidatingWebhook{}
+
+	// Autodiscovery annotations validation
+	if config.Datadog.GetBool("admission_controller.validate_ad_annotations.enabled") {
+		webhook := getValidatingWebhookSkeleton("ad.annotations", config.Datadog.GetString("admission_controller.validate_ad_annotations.endpoint"))
+		// Accept all, ignore pods if they're explicitly filtered-out
+		webhook.ObjectSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      EnabledLabelKey,
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{"false"},
+				},
+			},
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
 	return webhooks
 }
 
+// getLabelSelector returns the object selector of the webhooks
+// depending on whether unlabelled pods should be mutated
+func getLabelSelector() *metav1.LabelSelector {
+	if config.Datadog.GetBool("admission_controller.mutate_unlabelled") {
+		// Accept all, ignore pods if they're explicitly filtered-out
+		return &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      EnabledLabelKey,
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{"false"},
+				},
+			},
+		}
+	}
+	// Ignore all, accept pods if they're explicitly whitelisted
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			EnabledLabelKey: "true",
+		},
+	}
+}
+
 func getWebhookSkeleton(nameSuffix, path string) admiv1beta1.MutatingWebhook {
 	failurePolicy := admiv1beta1.Ignore
 	sideEffects := admiv1beta1.SideEffectClassNone
@@ -96,3 +131,14 @@ func getWebhookSkeleton(nameSuffix, path string) admiv1beta1.MutatingWebhook {
 		SideEffects:   &sideEffects,
 	}
 }
+
+func getValidatingWebhookSkeleton(nameSuffix, path string) admiv1beta1.ValidatingWebhook {
+	mutating := getWebhookSkeleton(nameSuffix, path)
+	return admiv1beta1.ValidatingWebhook{
+		Name:          mutating.Name,
+		ClientConfig:  mutating.ClientConfig,
+		Rules:         mutating.Rules,
+		FailurePolicy: mutating.FailurePolicy,
+		SideEffects:   mutating.SideEffects,
+	}
+}
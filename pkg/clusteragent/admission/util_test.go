@@ -163,6 +163,51 @@ func Test_generateWebhooks(t *testing.T) {
 				return []admiv1beta1.MutatingWebhook{webhookConfig, webhookTags}
 			},
 		},
+		{
+			name: "auto instrumentation, mutate labelled",
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.inject_config.enabled", false)
+				mockConfig.Set("admission_controller.inject_tags.enabled", false)
+				mockConfig.Set("admission_controller.auto_instrumentation.enabled", true)
+				mockConfig.Set("admission_controller.mutate_unlabelled", false)
+			},
+			want: func() []admiv1beta1.MutatingWebhook {
+				webhook := getWebhookSkeleton("auto.instrumentation", "/injectlib")
+				webhook.ObjectSelector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"admission.datadoghq.com/enabled": "true",
+					},
+				}
+				return []admiv1beta1.MutatingWebhook{webhook}
+			},
+		},
+		{
+			name: "config, tags injection and auto instrumentation, mutate all",
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.inject_config.enabled", true)
+				mockConfig.Set("admission_controller.inject_tags.enabled", true)
+				mockConfig.Set("admission_controller.auto_instrumentation.enabled", true)
+				mockConfig.Set("admission_controller.mutate_unlabelled", true)
+			},
+			want: func() []admiv1beta1.MutatingWebhook {
+				selector := &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "admission.datadoghq.com/enabled",
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{"false"},
+						},
+					},
+				}
+				webhookConfig := getWebhookSkeleton("config", "/injectconfig")
+				webhookConfig.ObjectSelector = selector
+				webhookTags := getWebhookSkeleton("tags", "/injecttags")
+				webhookTags.ObjectSelector = selector
+				webhookLib := getWebhookSkeleton("auto.instrumentation", "/injectlib")
+				webhookLib.ObjectSelector = selector
+				return []admiv1beta1.MutatingWebhook{webhookConfig, webhookTags, webhookLib}
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	admiv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	adAnnotationPrefix       = "ad.datadoghq.com/"
+	legacyADAnnotationPrefix = "service-discovery.datadoghq.com/"
+
+	checkNamesAttribute  = "check_names"
+	initConfigsAttribute = "init_configs"
+	instancesAttribute   = "instances"
+	logsAttribute        = "logs"
+	tagsAttribute        = "tags"
+)
+
+// knownAttributes lists the per-container autodiscovery annotations
+var knownAttributes = map[string]bool{
+	checkNamesAttribute:  true,
+	initConfigsAttribute: true,
+	instancesAttribute:   true,
+	logsAttribute:        true,
+	tagsAttribute:        true,
+}
+
+// ValidateADAnnotations checks the autodiscovery annotations of a pod.
+// Pods with invalid annotations are rejected only if admission_controller.validate_ad_annotations.reject is set.
+func ValidateADAnnotations(req *admiv1beta1.AdmissionRequest, _ dynamic.Interface) (*admiv1beta1.AdmissionResponse, error) {
+	var pod corev1.Pod
+	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
+		return nil, fmt.Errorf("failed to decode raw object: %v", err)
+	}
+
+	errs := validateADAnnotations(&pod)
+	metrics.ValidationAttempts.Inc(metrics.ADAnnotationsValidationType, req.Namespace, strconv.FormatBool(len(errs) == 0))
+	if len(errs) == 0 {
+		return &admiv1beta1.AdmissionResponse{Allowed: true}, nil
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	message := fmt.Sprintf("invalid autodiscovery annotations: %s", strings.Join(messages, "; "))
+
+	reject := config.Datadog.GetBool("admission_controller.validate_ad_annotations.reject")
+	log.Warnf("Pod %s in namespace %s has %s (rejected: %v)", podName(&pod), req.Namespace, message, reject)
+
+	return &admiv1beta1.AdmissionResponse{
+		Allowed: !reject,
+		Result: &metav1.Status{
+			Message: message,
+			Reason:  metav1.StatusReasonInvalid,
+		},
+	}, nil
+}
+
+// validateADAnnotations returns the errors found in the autodiscovery annotations of a pod,
+// using the same parsing logic as the kubelet config provider
+func validateADAnnotations(pod *corev1.Pod) []error {
+	containers := make(map[string]bool)
+	for _, ctr := range pod.Spec.Containers {
+		containers[ctr.Name] = true
+	}
+	for _, ctr := range pod.Spec.InitContainers {
+		containers[ctr.Name] = true
+	}
+
+	annotations := pod.GetAnnotations()
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	validated := make(map[string]bool)
+	for _, key := range keys {
+		var prefix string
+		switch {
+		case strings.HasPrefix(key, adAnnotationPrefix):
+			prefix = adAnnotationPrefix
+		case strings.HasPrefix(key, legacyADAnnotationPrefix):
+			prefix = legacyADAnnotationPrefix
+		default:
+			continue
+		}
+
+		// Pod level annotations don't reference a container
+		parts := strings.SplitN(strings.TrimPrefix(key, prefix), ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		container, attribute := parts[0], parts[1]
+
+		if !containers[container] {
+			errs = append(errs, fmt.Errorf("annotation %s references unknown container %s", key, container))
+			continue
+		}
+		if !knownAttributes[attribute] {
+			errs = append(errs, fmt.Errorf("unknown annotation %s", key))
+			continue
+		}
+
+		containerPrefix := prefix + container + "."
+		if validated[containerPrefix] {
+			continue
+		}
+		validated[containerPrefix] = true
+
+		if _, found := annotations[containerPrefix+checkNamesAttribute]; !found {
+			for _, attr := range []string{initConfigsAttribute, instancesAttribute} {
+				if _, found := annotations[containerPrefix+attr]; found {
+					errs = append(errs, fmt.Errorf("annotation %s%s is set without %s%s", containerPrefix, attr, containerPrefix, checkNamesAttribute))
+				}
+			}
+		}
+
+		for _, err := range providers.ValidateTemplatesFromMap(annotations, containerPrefix) {
+			errs = append(errs, fmt.Errorf("container %s: %v", container, err))
+		}
+	}
+
+	return errs
+}
+
+// podName returns a string that helps identify the pod
+func podName(pod *corev1.Pod) string {
+	if pod.GetName() == "" {
+		return fmt.Sprintf("with generate name %s", pod.GetGenerateName())
+	}
+	return pod.GetName()
+}
@@ -0,0 +1,151 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admiv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func fakePodWithAnnotations(annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo-pod",
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "nginx"}, {Name: "redis"}},
+		},
+	}
+}
+
+func Test_validateADAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErrs    []string
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "valid check and logs",
+			annotations: map[string]string{
+				"ad.datadoghq.com/nginx.check_names":  `["nginx"]`,
+				"ad.datadoghq.com/nginx.init_configs": `[{}]`,
+				"ad.datadoghq.com/nginx.instances":    `[{"nginx_status_url": "http://%%host%%/nginx_status"}]`,
+				"ad.datadoghq.com/redis.logs":         `[{"source": "redis", "service": "redis"}]`,
+				"ad.datadoghq.com/redis.tags":         `{"team": "foo"}`,
+				"ad.datadoghq.com/tags":               `{"env": "prod"}`,
+				"ad.datadoghq.com/tolerate-unready":   "true",
+			},
+		},
+		{
+			name: "valid legacy annotations",
+			annotations: map[string]string{
+				"service-discovery.datadoghq.com/redis.check_names":  `["redisdb"]`,
+				"service-discovery.datadoghq.com/redis.init_configs": `[{}]`,
+				"service-discovery.datadoghq.com/redis.instances":    `[{"host": "%%host%%"}]`,
+			},
+		},
+		{
+			name: "invalid json",
+			annotations: map[string]string{
+				"ad.datadoghq.com/nginx.check_names":  `["nginx"]`,
+				"ad.datadoghq.com/nginx.init_configs": `[{}]`,
+				"ad.datadoghq.com/nginx.instances":    `[{"nginx_status_url" "http://%%host%%/nginx_status"}]`,
+			},
+			wantErrs: []string{"container nginx: could not extract checks config: in instances: failed to unmarshal JSON"},
+		},
+		{
+			name: "mismatched lengths",
+			annotations: map[string]string{
+				"ad.datadoghq.com/nginx.check_names":  `["nginx", "http_check"]`,
+				"ad.datadoghq.com/nginx.init_configs": `[{}]`,
+				"ad.datadoghq.com/nginx.instances":    `[{}]`,
+			},
+			wantErrs: []string{"container nginx: could not extract checks config: template entries don't all have the same length"},
+		},
+		{
+			name: "unknown container",
+			annotations: map[string]string{
+				"ad.datadoghq.com/ngnix.logs": `[{"source": "nginx"}]`,
+			},
+			wantErrs: []string{"annotation ad.datadoghq.com/ngnix.logs references unknown container ngnix"},
+		},
+		{
+			name: "unknown attribute",
+			annotations: map[string]string{
+				"ad.datadoghq.com/nginx.instance": `[{}]`,
+			},
+			wantErrs: []string{"unknown annotation ad.datadoghq.com/nginx.instance"},
+		},
+		{
+			name: "missing check names",
+			annotations: map[string]string{
+				"ad.datadoghq.com/init.init_configs": `[{}]`,
+				"ad.datadoghq.com/init.instances":    `[{}]`,
+			},
+			wantErrs: []string{
+				"annotation ad.datadoghq.com/init.init_configs is set without ad.datadoghq.com/init.check_names",
+				"annotation ad.datadoghq.com/init.instances is set without ad.datadoghq.com/init.check_names",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateADAnnotations(fakePodWithAnnotations(tt.annotations))
+			require.Len(t, errs, len(tt.wantErrs), "%v", errs)
+			for i, err := range errs {
+				assert.Contains(t, err.Error(), tt.wantErrs[i])
+			}
+		})
+	}
+}
+
+func TestValidateADAnnotations(t *testing.T) {
+	mockConfig := config.Mock()
+	defer mockConfig.Set("admission_controller.validate_ad_annotations.reject", false)
+
+	raw, err := json.Marshal(fakePodWithAnnotations(map[string]string{
+		"ad.datadoghq.com/ngnix.logs": `[{"source": "nginx"}]`,
+	}))
+	require.NoError(t, err)
+	req := &admiv1beta1.AdmissionRequest{
+		Namespace: "default",
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+
+	mockConfig.Set("admission_controller.validate_ad_annotations.reject", false)
+	resp, err := ValidateADAnnotations(req, nil)
+	require.NoError(t, err)
+	assert.True(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "unknown container ngnix")
+
+	mockConfig.Set("admission_controller.validate_ad_annotations.reject", true)
+	resp, err = ValidateADAnnotations(req, nil)
+	require.NoError(t, err)
+	assert.False(t, resp.Allowed)
+	assert.Contains(t, resp.Result.Message, "unknown container ngnix")
+
+	raw, err = json.Marshal(fakePodWithAnnotations(nil))
+	require.NoError(t, err)
+	resp, err = ValidateADAnnotations(&admiv1beta1.AdmissionRequest{Object: runtime.RawExtension{Raw: raw}}, nil)
+	require.NoError(t, err)
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Result)
+}
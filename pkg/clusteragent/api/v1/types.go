@@ -134,3 +134,19 @@ func NewMetadataResponse() *MetadataResponse {
 		Nodes: make(map[string]*MetadataResponseBundle),
 	}
 }
+
+// PodIPMetadata is the metadata of the pod owning an IP
+type PodIPMetadata struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+	// ContainerIDs are the IDs of the running containers of the pod
+	ContainerIDs []string `json:"container_ids,omitempty"`
+	// ContainerPorts maps the ports declared by the containers of the pod to the container IDs
+	ContainerPorts map[int32]string `json:"container_ports,omitempty"`
+	// Services are the names of the services selecting the pod
+	Services []string `json:"services,omitempty"`
+}
+
+// PodIPsMetadataResponse maps IPs to the metadata of the pods owning them
+type PodIPsMetadataResponse map[string]*PodIPMetadata
@@ -31,10 +31,13 @@ func (d *dispatcher) getState() (types.StateResponse, error) {
 		Dangling: makeConfigArray(d.store.danglingConfigs),
 	}
 	for _, node := range d.store.nodes {
+		node.RLock()
 		n := types.StateNodeResponse{
 			Name:    node.name,
+			Labels:  node.lastStatus.Labels,
 			Configs: makeConfigArray(node.digestToConfig),
 		}
+		node.RUnlock()
 		response.Nodes = append(response.Nodes, n)
 	}
 
@@ -32,6 +32,9 @@ type dispatcher struct {
 	extraTags             []string
 	clcRunnersClient      clusteragent.CLCRunnerClientInterface
 	advancedDispatching   bool
+	stateStore            stateStore
+	persistedAssignments  map[string]string // Last assignments saved in the stateStore
+	restoreGracePeriod    time.Duration     // How long restored assignments wait for their node to report
 }
 
 func newDispatcher() *dispatcher {
@@ -52,6 +55,15 @@ func newDispatcher() *dispatcher {
 		d.extraTags = append(d.extraTags, fmt.Sprintf("kube_cluster_name:%s", clusterTagValue))
 	}
 
+	if config.Datadog.GetBool("cluster_checks.persist_dispatch_state") {
+		d.restoreGracePeriod = time.Duration(config.Datadog.GetInt64("cluster_checks.state_restore_grace_period")) * time.Second
+		var err error
+		d.stateStore, err = newStateStore()
+		if err != nil {
+			log.Warnf("Cannot create the dispatching state store, the state will not be persisted: %v", err)
+		}
+	}
+
 	d.advancedDispatching = config.Datadog.GetBool("cluster_checks.advanced_dispatching_enabled")
 	if !d.advancedDispatching {
 		return d
@@ -130,10 +142,25 @@ func (d *dispatcher) reschedule(configs []integration.Config) {
 
 // add stores and delegates a given configuration
 func (d *dispatcher) add(config integration.Config) {
-	target := d.getLeastBusyNode()
+	if target, pending := d.getRestoredNode(config); pending {
+		// Keep the configuration dangling, it is dispatched again on every expiration tick
+		log.Debugf("Waiting for node %s to report before restoring configuration %s:%s", target, config.Name, config.Digest())
+		d.addConfig(config, "")
+		return
+	} else if target != "" {
+		log.Infof("Restoring configuration %s:%s on node %s", config.Name, config.Digest(), target)
+		d.addConfig(config, target)
+		return
+	}
+
+	target := d.getLeastBusyNode(config)
 	if target == "" {
 		// If no node is found, store it in the danglingConfigs map for retrying later.
-		log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		if config.Placement != nil {
+			log.Warnf("No available node satisfying the placement constraints to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		} else {
+			log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		}
 	} else {
 		log.Infof("Dispatching configuration %s:%s to node %s", config.Name, config.Digest(), target)
 	}
@@ -153,6 +180,7 @@ func (d *dispatcher) reset() {
 	d.store.Lock()
 	defer d.store.Unlock()
 	d.store.reset()
+	d.persistedAssignments = nil
 }
 
 // run is the main management goroutine for the dispatcher
@@ -181,11 +209,17 @@ func (d *dispatcher) run(ctx context.Context) {
 			// Expire old nodes, orphaned configs are moved to dangling
 			d.expireNodes()
 
+			// Stop waiting for the nodes of restored assignments after the grace period
+			d.expireRestoredAssignments()
+
 			// Re-dispatch dangling configs
 			if d.shouldDispatchDanling() {
 				danglingConfs := d.retrieveAndClearDangling()
 				d.reschedule(danglingConfs)
 			}
+
+			// Persist node assignments for the next leader
+			d.persistState()
 		case <-runnerStatsTicker.C:
 			// Collect stats with an exponential backoff 2 - 5 - 10 minutes
 			if runnerStatsMinutes == firstRunnerStatsMinutes {
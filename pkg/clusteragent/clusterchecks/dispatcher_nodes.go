@@ -69,9 +69,10 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 }
 
 // getLeastBusyNode returns the name of the node that is assigned
-// the lowest number of checks. In case of equality, one is chosen
+// the lowest number of checks among the nodes satisfying the placement
+// constraints of the configuration. In case of equality, one is chosen
 // randomly, based on map iterations being randomized.
-func (d *dispatcher) getLeastBusyNode() string {
+func (d *dispatcher) getLeastBusyNode(config integration.Config) string {
 	var leastBusyNode string
 	minCheckCount := int(-1)
 	minBusyness := int(-1)
@@ -79,10 +80,7 @@ func (d *dispatcher) getLeastBusyNode() string {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
-	for name, store := range d.store.nodes {
-		if name == "" {
-			continue
-		}
+	for name, store := range d.getCandidateNodes(config) {
 		if d.advancedDispatching && store.busyness > defaultBusynessValue {
 			// dispatching based on clc runners stats
 			// only when advancedDispatching is true and
@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// getCandidateNodes returns the nodes a configuration can be dispatched to
+// according to its placement constraints.
+// Store lock is to be held by the caller.
+func (d *dispatcher) getCandidateNodes(config integration.Config) map[string]*nodeStore {
+	candidates := make(map[string]*nodeStore)
+	for name, node := range d.store.nodes {
+		if name == "" {
+			continue
+		}
+		node.RLock()
+		matches := node.matchesSelector(config.Placement)
+		node.RUnlock()
+		if matches {
+			candidates[name] = node
+		}
+	}
+
+	if config.Placement == nil || config.Placement.SpreadBy == "" || len(candidates) == 0 {
+		return candidates
+	}
+	return spreadCandidates(config, candidates)
+}
+
+// spreadCandidates keeps the candidate nodes having the value of the spread_by
+// label that runs the fewest instances of the check.
+// Nodes without the label are only kept if no candidate node has it.
+func spreadCandidates(config integration.Config, candidates map[string]*nodeStore) map[string]*nodeStore {
+	spreadBy := config.Placement.SpreadBy
+	checkCount := make(map[string]int)
+	nodeValue := make(map[string]string)
+
+	for name, node := range candidates {
+		node.RLock()
+		value, found := node.lastStatus.Labels[spreadBy]
+		if found {
+			nodeValue[name] = value
+			if _, ok := checkCount[value]; !ok {
+				checkCount[value] = 0
+			}
+			for _, c := range node.digestToConfig {
+				if c.Name == config.Name {
+					checkCount[value]++
+				}
+			}
+		}
+		node.RUnlock()
+	}
+
+	if len(checkCount) == 0 {
+		log.Debugf("No node has the label %s to spread %s:%s on, ignoring it", spreadBy, config.Name, config.Digest())
+		return candidates
+	}
+
+	minCount := -1
+	for _, count := range checkCount {
+		if minCount == -1 || count < minCount {
+			minCount = count
+		}
+	}
+
+	spread := make(map[string]*nodeStore)
+	for name, value := range nodeValue {
+		if checkCount[value] == minCount {
+			spread[name] = candidates[name]
+		}
+	}
+	return spread
+}
+
+// canMoveCheck returns whether a configuration running on the source node
+// can be moved to the destination node without breaking its placement constraints.
+// Moving a check spread by a label is only allowed between nodes having the same label value.
+func (d *dispatcher) canMoveCheck(config integration.Config, src, dest string) bool {
+	if config.Placement == nil {
+		return true
+	}
+
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	sourceNode, srcFound := d.store.getNodeStore(src)
+	destNode, destFound := d.store.getNodeStore(dest)
+	if !srcFound || !destFound {
+		return false
+	}
+
+	destNode.RLock()
+	defer destNode.RUnlock()
+	if !destNode.matchesSelector(config.Placement) {
+		return false
+	}
+
+	spreadBy := config.Placement.SpreadBy
+	if spreadBy == "" {
+		return true
+	}
+
+	sourceNode.RLock()
+	srcValue, found := sourceNode.lastStatus.Labels[spreadBy]
+	sourceNode.RUnlock()
+	if !found {
+		return true
+	}
+	return destNode.lastStatus.Labels[spreadBy] == srcValue
+}
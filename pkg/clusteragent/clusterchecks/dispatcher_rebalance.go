@@ -104,7 +104,8 @@ func (d *dispatcher) updateDiff(avg int) map[string]int {
 // A check Xi running on a node N is chosen to move to another node if it satisfies the following
 // Weight(Xi) >  Weight(Xj) (for each j != i, 0 <= j < len(weights))
 // where Weight(X) is the busyness value caused by running the check X.
-func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
+// The checks in excluded are not considered.
+func (d *dispatcher) pickCheckToMove(nodeName string, excluded map[string]bool) (string, int, error) {
 	d.store.RLock()
 	node, found := d.store.getNodeStore(nodeName)
 	d.store.RUnlock()
@@ -114,7 +115,7 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 		return "", -1, fmt.Errorf("node %s not found in store", nodeName)
 	}
 
-	return node.GetMostWeightedClusterCheck(busynessFunc)
+	return node.GetMostWeightedClusterCheck(busynessFunc, excluded)
 }
 
 // pickNode select the most appropriate node to receive a specific check.
@@ -122,12 +123,13 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 // if it satisfies the following
 // Diff(Ni) < Diff(Nj) (for each j != i, 0 <= j < len(nodes))
 // where Diff(N) is the difference between the busyness on N and the total average busyness.
-func pickNode(diffMap map[string]int, sourceNode string) string {
+// Only the nodes accepted by canReceive are considered.
+func pickNode(diffMap map[string]int, sourceNode string, canReceive func(string) bool) string {
 	firstItr := true
 	minDiff := 0
 	pickedNode := ""
 	for _, node := range orderedKeys(diffMap) {
-		if node == sourceNode {
+		if node == sourceNode || !canReceive(node) {
 			continue
 		}
 		if diffMap[node] < minDiff || firstItr {
@@ -196,16 +198,26 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 	sort.Sort(weights)
 
 	for _, nodeWeight := range weights {
+		// checks of the node which can't be moved, the next heaviest ones are tried instead
+		excluded := make(map[string]bool)
 		for diffMap[nodeWeight.nodeName] > 0 {
 			// try to move checks from a node only of the node busyness is above the average
 			sourceNodeName := nodeWeight.nodeName
-			checkID, checkWeight, err := d.pickCheckToMove(sourceNodeName)
+			checkID, checkWeight, err := d.pickCheckToMove(sourceNodeName, excluded)
 			if err != nil {
 				log.Debugf("Cannot pick a check to move from node %s: %v", sourceNodeName, err)
 				break
 			}
 
-			destNodeName := pickNode(diffMap, sourceNodeName)
+			config, _ := d.getConfigAndDigest(checkID)
+			destNodeName := pickNode(diffMap, sourceNodeName, func(dest string) bool {
+				return d.canMoveCheck(config, sourceNodeName, dest)
+			})
+			if destNodeName == "" {
+				log.Debugf("No node satisfying the placement constraints of check %s to move it from node %s", checkID, sourceNodeName)
+				excluded[checkID] = true
+				continue
+			}
 			sourceDiff := diffMap[sourceNodeName]
 			destDiff := diffMap[destNodeName]
 
@@ -219,6 +231,7 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 				err = d.moveCheck(sourceNodeName, destNodeName, checkID)
 				if err != nil {
 					log.Debugf("Cannot move check %s: %v", checkID, err)
+					excluded[checkID] = true
 					continue
 				}
 
@@ -1448,3 +1448,72 @@ func TestMoveCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestCanMoveCheck(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.processNodeStatus("nodeA1", "10.0.0.1", types.NodeStatus{Labels: map[string]string{"zone": "a", "role": "checks"}})
+	dispatcher.processNodeStatus("nodeA2", "10.0.0.2", types.NodeStatus{Labels: map[string]string{"zone": "a"}})
+	dispatcher.processNodeStatus("nodeB1", "10.0.0.3", types.NodeStatus{Labels: map[string]string{"zone": "b", "role": "checks"}})
+
+	noConstraint := integration.Config{Name: "check"}
+	assert.True(t, dispatcher.canMoveCheck(noConstraint, "nodeA1", "nodeB1"))
+
+	selector := integration.Config{Name: "check", Placement: &integration.Placement{NodeSelector: map[string]string{"role": "checks"}}}
+	assert.True(t, dispatcher.canMoveCheck(selector, "nodeA1", "nodeB1"))
+	assert.False(t, dispatcher.canMoveCheck(selector, "nodeA1", "nodeA2"))
+
+	spread := integration.Config{Name: "check", Placement: &integration.Placement{SpreadBy: "zone"}}
+	assert.True(t, dispatcher.canMoveCheck(spread, "nodeA1", "nodeA2"))
+	assert.False(t, dispatcher.canMoveCheck(spread, "nodeA1", "nodeB1"))
+
+	assert.False(t, dispatcher.canMoveCheck(selector, "nodeA1", "unknown"))
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestPickNode(t *testing.T) {
+	diffMap := map[string]int{"A": 10, "B": -5, "C": -10}
+	assert.Equal(t, "C", pickNode(diffMap, "A", func(string) bool { return true }))
+	assert.Equal(t, "B", pickNode(diffMap, "A", func(node string) bool { return node != "C" }))
+	assert.Equal(t, "", pickNode(diffMap, "A", func(string) bool { return false }))
+}
+
+func TestRebalanceSkipsUnplaceableCheck(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.clcRunnersClient = nil
+	dispatcher.store.active = true
+	dispatcher.processNodeStatus("A", "", types.NodeStatus{Labels: map[string]string{"zone": "a"}})
+	dispatcher.processNodeStatus("B", "", types.NodeStatus{Labels: map[string]string{"zone": "b"}})
+
+	// the heaviest check of A can't run on B, the next heaviest one is moved instead
+	pinned := integration.Config{
+		Name:      "pinned",
+		Instances: []integration.Data{integration.Data("")},
+		Placement: &integration.Placement{NodeSelector: map[string]string{"zone": "a"}},
+	}
+	free := integration.Config{
+		Name:      "free",
+		Instances: []integration.Data{integration.Data("")},
+	}
+	pinnedID := string(check.BuildID(pinned.Name, pinned.Instances[0], pinned.InitConfig))
+	freeID := string(check.BuildID(free.Name, free.Instances[0], free.InitConfig))
+	dispatcher.addConfig(pinned, "A")
+	dispatcher.addConfig(free, "A")
+
+	dispatcher.store.nodes["A"].clcRunnerStats = types.CLCRunnersStats{
+		pinnedID: types.CLCRunnerStats{AverageExecutionTime: 500, MetricSamples: 10, IsClusterCheck: true},
+		freeID:   types.CLCRunnerStats{AverageExecutionTime: 100, MetricSamples: 10, IsClusterCheck: true},
+	}
+	dispatcher.store.nodes["B"].clcRunnerStats = types.CLCRunnersStats{}
+
+	moved := dispatcher.rebalance()
+
+	if assert.Len(t, moved, 1) {
+		assert.Equal(t, freeID, moved[0].CheckID)
+		assert.Equal(t, "B", moved[0].DestNodeName)
+	}
+	assert.Contains(t, dispatcher.store.nodes["A"].clcRunnerStats, pinnedID)
+	assert.Contains(t, dispatcher.store.nodes["B"].clcRunnerStats, freeID)
+
+	requireNotLocked(t, dispatcher.store)
+}
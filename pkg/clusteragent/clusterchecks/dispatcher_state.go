@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// stateStore persists the node each configuration is dispatched to,
+// so that a new leader can restore the dispatching state on failover.
+type stateStore interface {
+	loadAssignments() (map[string]string, error)
+	saveAssignments(assignments map[string]string) error
+}
+
+// restoreState loads the persisted assignments. They are consumed by add when
+// the configurations are scheduled again: configurations assigned to nodes that
+// did not report yet wait for them until the grace period expires, as their
+// node-agents keep running the checks in the meantime.
+func (d *dispatcher) restoreState() {
+	if d.stateStore == nil {
+		return
+	}
+
+	assignments, err := d.stateStore.loadAssignments()
+	if err != nil {
+		log.Warnf("Cannot load the persisted dispatching state, checks will be dispatched from scratch: %v", err)
+		return
+	}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	for digest, nodeName := range assignments {
+		if nodeName == "" {
+			continue
+		}
+		d.store.restoredAssignments[digest] = nodeName
+	}
+	d.store.restoredUntil = time.Now().Add(d.restoreGracePeriod)
+	d.persistedAssignments = assignments
+	log.Infof("Restored %d out of %d persisted cluster check assignments", len(d.store.restoredAssignments), len(assignments))
+}
+
+// getRestoredNode returns the node a configuration was dispatched to by the
+// previous leader, if this node is reporting and satisfies the placement
+// constraints. If the node did not report yet and the grace period is not
+// expired, it is returned as pending. Restored assignments are only used once.
+func (d *dispatcher) getRestoredNode(config integration.Config) (string, bool) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	digest := config.Digest()
+	nodeName, found := d.store.restoredAssignments[digest]
+	if !found {
+		return "", false
+	}
+
+	node, found := d.store.getNodeStore(nodeName)
+	if !found {
+		if time.Now().Before(d.store.restoredUntil) {
+			return nodeName, true
+		}
+		delete(d.store.restoredAssignments, digest)
+		return "", false
+	}
+	delete(d.store.restoredAssignments, digest)
+
+	node.RLock()
+	defer node.RUnlock()
+	if !node.matchesSelector(config.Placement) {
+		return "", false
+	}
+	return nodeName, false
+}
+
+// expireRestoredAssignments drops the restored assignments once the grace period
+// is expired, the configurations still waiting for their node are then dispatched
+// as usual with the dangling ones.
+func (d *dispatcher) expireRestoredAssignments() {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	if len(d.store.restoredAssignments) == 0 || time.Now().Before(d.store.restoredUntil) {
+		return
+	}
+	log.Infof("Dropping %d restored cluster check assignments, their nodes did not report", len(d.store.restoredAssignments))
+	d.store.clearRestoredAssignments()
+}
+
+// persistState saves the current assignments if they changed since the last save.
+// Restored assignments still waiting for their node are kept, for a new leader to
+// restore them if the grace period did not expire.
+func (d *dispatcher) persistState() {
+	if d.stateStore == nil {
+		return
+	}
+
+	d.store.RLock()
+	assignments := make(map[string]string, len(d.store.digestToNode)+len(d.store.restoredAssignments))
+	for digest, nodeName := range d.store.restoredAssignments {
+		assignments[digest] = nodeName
+	}
+	for digest, nodeName := range d.store.digestToNode {
+		assignments[digest] = nodeName
+	}
+	d.store.RUnlock()
+
+	if reflect.DeepEqual(assignments, d.persistedAssignments) {
+		return
+	}
+
+	if err := d.stateStore.saveAssignments(assignments); err != nil {
+		log.Warnf("Cannot persist the dispatching state: %v", err)
+		return
+	}
+	d.persistedAssignments = assignments
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -241,22 +242,22 @@ func TestGetLeastBusyNode(t *testing.T) {
 	dispatcher := newDispatcher()
 
 	// No node registered -> empty string
-	assert.Equal(t, "", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "", dispatcher.getLeastBusyNode(integration.Config{}))
 
 	// 1 config on node1, 2 on node2
 	dispatcher.addConfig(generateIntegration("A"), "node1")
 	dispatcher.addConfig(generateIntegration("B"), "node2")
 	dispatcher.addConfig(generateIntegration("C"), "node2")
-	assert.Equal(t, "node1", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node1", dispatcher.getLeastBusyNode(integration.Config{}))
 
 	// 3 configs on node1, 2 on node2
 	dispatcher.addConfig(generateIntegration("D"), "node1")
 	dispatcher.addConfig(generateIntegration("E"), "node1")
-	assert.Equal(t, "node2", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node2", dispatcher.getLeastBusyNode(integration.Config{}))
 
 	// Add an empty node3
 	dispatcher.processNodeStatus("node3", "10.0.0.3", types.NodeStatus{})
-	assert.Equal(t, "node3", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node3", dispatcher.getLeastBusyNode(integration.Config{}))
 
 	requireNotLocked(t, dispatcher.store)
 }
@@ -637,3 +638,173 @@ func TestUpdateRunnersStats(t *testing.T) {
 
 	requireNotLocked(t, dispatcher.store)
 }
+
+func TestPlacementNodeSelector(t *testing.T) {
+	dispatcher := newDispatcher()
+
+	dispatcher.processNodeStatus("nodeA", "10.0.0.1", types.NodeStatus{Labels: map[string]string{"zone": "a"}})
+	dispatcher.processNodeStatus("nodeB", "10.0.0.2", types.NodeStatus{Labels: map[string]string{"zone": "b", "role": "checks"}})
+	dispatcher.processNodeStatus("nodeC", "10.0.0.3", types.NodeStatus{})
+
+	pinned := generateIntegration("postgres")
+	pinned.Placement = &integration.Placement{NodeSelector: map[string]string{"zone": "b"}}
+	unsatisfiable := generateIntegration("mysql")
+	unsatisfiable.Placement = &integration.Placement{NodeSelector: map[string]string{"zone": "c"}}
+
+	dispatcher.Schedule([]integration.Config{pinned, pinned, unsatisfiable})
+
+	configsB, _, err := dispatcher.getNodeConfigs("nodeB")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"postgres"}, extractCheckNames(configsB))
+
+	// The unsatisfiable config stays dangling
+	assert.Len(t, dispatcher.store.danglingConfigs, 1)
+	assert.Equal(t, "", dispatcher.getLeastBusyNode(unsatisfiable))
+
+	// A node satisfying the constraints reports
+	dispatcher.processNodeStatus("nodeD", "10.0.0.4", types.NodeStatus{Labels: map[string]string{"zone": "c"}})
+	dispatcher.reschedule(dispatcher.retrieveAndClearDangling())
+	configsD, _, err := dispatcher.getNodeConfigs("nodeD")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mysql"}, extractCheckNames(configsD))
+
+	state, err := dispatcher.getState()
+	assert.NoError(t, err)
+	for _, node := range state.Nodes {
+		if node.Name == "nodeB" {
+			assert.Equal(t, map[string]string{"zone": "b", "role": "checks"}, node.Labels)
+		}
+	}
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestPlacementSpreadBy(t *testing.T) {
+	dispatcher := newDispatcher()
+
+	dispatcher.processNodeStatus("nodeA1", "10.0.0.1", types.NodeStatus{Labels: map[string]string{"zone": "a"}})
+	dispatcher.processNodeStatus("nodeA2", "10.0.0.2", types.NodeStatus{Labels: map[string]string{"zone": "a"}})
+	dispatcher.processNodeStatus("nodeB1", "10.0.0.3", types.NodeStatus{Labels: map[string]string{"zone": "b"}})
+
+	// Busy node in zone b, spreading prevails on busyness
+	dispatcher.addConfig(generateIntegration("other1"), "nodeB1")
+	dispatcher.addConfig(generateIntegration("other2"), "nodeB1")
+
+	var configs []integration.Config
+	for i := 0; i < 4; i++ {
+		config := generateIntegration("http_check")
+		config.Instances = []integration.Data{integration.Data(fmt.Sprintf("url: http://%d", i))}
+		config.Placement = &integration.Placement{SpreadBy: "zone"}
+		configs = append(configs, config)
+	}
+	dispatcher.Schedule(configs)
+
+	perZone := map[string]int{}
+	for _, node := range []string{"nodeA1", "nodeA2", "nodeB1"} {
+		nodeConfigs, _, err := dispatcher.getNodeConfigs(node)
+		assert.NoError(t, err)
+		for _, c := range nodeConfigs {
+			if c.Name == "http_check" {
+				perZone[node[4:5]]++
+			}
+		}
+	}
+	assert.Equal(t, map[string]int{"A": 2, "B": 2}, perZone)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+type fakeStateStore struct {
+	assignments map[string]string
+}
+
+func (s *fakeStateStore) loadAssignments() (map[string]string, error) {
+	return s.assignments, nil
+}
+
+func (s *fakeStateStore) saveAssignments(assignments map[string]string) error {
+	s.assignments = assignments
+	return nil
+}
+
+func TestPersistRestoreState(t *testing.T) {
+	store := &fakeStateStore{}
+	configA := generateIntegration("A")
+	configB := generateIntegration("B")
+
+	// Previous leader dispatched A on node1 and B on node2
+	previous := newDispatcher()
+	previous.stateStore = store
+	patchedA, err := previous.patchConfiguration(configA)
+	require.NoError(t, err)
+	patchedB, err := previous.patchConfiguration(configB)
+	require.NoError(t, err)
+	previous.addConfig(patchedA, "node1")
+	previous.addConfig(patchedB, "node2")
+	previous.persistState()
+	assert.Equal(t, map[string]string{
+		patchedA.Digest(): "node1",
+		patchedB.Digest(): "node2",
+	}, store.assignments)
+
+	// New leader, node2 did not report during warmup
+	dispatcher := newDispatcher()
+	dispatcher.stateStore = store
+	dispatcher.restoreGracePeriod = time.Minute
+	dispatcher.processNodeStatus("node1", "10.0.0.1", types.NodeStatus{})
+	dispatcher.processNodeStatus("node3", "10.0.0.3", types.NodeStatus{})
+	dispatcher.restoreState()
+	assert.Len(t, dispatcher.store.restoredAssignments, 2)
+
+	// B waits for node2 during the grace period
+	dispatcher.Schedule([]integration.Config{configA, configB})
+	assert.Equal(t, "node1", dispatcher.store.digestToNode[patchedA.Digest()])
+	assert.Contains(t, dispatcher.store.danglingConfigs, patchedB.Digest())
+	assert.Equal(t, map[string]string{patchedB.Digest(): "node2"}, dispatcher.store.restoredAssignments)
+
+	// Pending assignments are persisted for the next leader
+	dispatcher.persistState()
+	assert.Equal(t, map[string]string{
+		patchedA.Digest(): "node1",
+		patchedB.Digest(): "node2",
+	}, store.assignments)
+
+	// node2 reports within the grace period
+	dispatcher.processNodeStatus("node2", "10.0.0.2", types.NodeStatus{})
+	dispatcher.expireRestoredAssignments()
+	dispatcher.reschedule(dispatcher.retrieveAndClearDangling())
+	assert.Equal(t, "node2", dispatcher.store.digestToNode[patchedB.Digest()])
+	assert.Len(t, dispatcher.store.restoredAssignments, 0)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestRestoreStateGracePeriod(t *testing.T) {
+	configA := generateIntegration("A")
+	store := &fakeStateStore{}
+
+	previous := newDispatcher()
+	previous.stateStore = store
+	patchedA, err := previous.patchConfiguration(configA)
+	require.NoError(t, err)
+	previous.addConfig(patchedA, "node1")
+	previous.persistState()
+
+	// node1 does not report before the grace period expires
+	dispatcher := newDispatcher()
+	dispatcher.stateStore = store
+	dispatcher.processNodeStatus("node2", "10.0.0.2", types.NodeStatus{})
+	dispatcher.restoreState()
+	dispatcher.store.restoredUntil = time.Now().Add(-time.Second)
+
+	dispatcher.expireRestoredAssignments()
+	assert.Len(t, dispatcher.store.restoredAssignments, 0)
+
+	dispatcher.Schedule([]integration.Config{configA})
+	assert.Equal(t, "node2", dispatcher.store.digestToNode[patchedA.Digest()])
+
+	dispatcher.persistState()
+	assert.Equal(t, map[string]string{patchedA.Digest(): "node2"}, store.assignments)
+
+	requireNotLocked(t, dispatcher.store)
+}
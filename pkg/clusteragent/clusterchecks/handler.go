@@ -150,6 +150,9 @@ func (h *Handler) Run(ctx context.Context) {
 
 // runDispatch hooks in the Autodiscovery and runs the dispatch's run method
 func (h *Handler) runDispatch(ctx context.Context) {
+	// Restore the assignments of the previous leader on nodes that reported during warmup
+	h.dispatcher.restoreState()
+
 	// Register our scheduler and ask for a config replay
 	h.autoconfig.AddScheduler(schedulerName, h.dispatcher, true)
 
@@ -3,8 +3,7 @@
 // This product includes software developed at Datadog (https://www.datadoghq.com/).
 // Copyright 2016-2020 Datadog, Inc.
 
-// +build clusterchecks
-// +build kubeapiserver
+// +build clusterchecks,kubeapiserver
 
 package clusterchecks
 
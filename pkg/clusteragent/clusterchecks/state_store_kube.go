@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build clusterchecks,kubeapiserver
+
+package clusterchecks
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/common"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// configMapStateStore persists the dispatching state in a configmap,
+// with one entry per configuration digest holding the node name.
+type configMapStateStore struct {
+	client    corev1.CoreV1Interface
+	namespace string
+	name      string
+}
+
+func newStateStore() (stateStore, error) {
+	apiCl, err := apiserver.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &configMapStateStore{
+		client:    apiCl.Cl.CoreV1(),
+		namespace: common.GetResourcesNamespace(),
+		name:      config.Datadog.GetString("cluster_checks.state_configmap_name"),
+	}, nil
+}
+
+// loadAssignments returns the persisted assignments, or nil if the configmap does not exist yet.
+func (s *configMapStateStore) loadAssignments() (map[string]string, error) {
+	cm, err := s.client.ConfigMaps(s.namespace).Get(s.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cm.Data, nil
+}
+
+// saveAssignments replaces the persisted assignments, creating the configmap if needed.
+func (s *configMapStateStore) saveAssignments(assignments map[string]string) error {
+	cm, err := s.client.ConfigMaps(s.namespace).Get(s.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.name,
+				Namespace: s.namespace,
+			},
+			Data: assignments,
+		}
+		_, err = s.client.ConfigMaps(s.namespace).Create(cm)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	cm.Data = assignments
+	_, err = s.client.ConfigMaps(s.namespace).Update(cm)
+	return err
+}
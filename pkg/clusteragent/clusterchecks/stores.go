@@ -10,6 +10,7 @@ package clusterchecks
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
@@ -23,13 +24,15 @@ import (
 // operations involving several calls.
 type clusterStore struct {
 	sync.RWMutex
-	active           bool
-	digestToConfig   map[string]integration.Config            // All configurations to dispatch
-	digestToNode     map[string]string                        // Node running a config
-	nodes            map[string]*nodeStore                    // All nodes known to the cluster-agent
-	danglingConfigs  map[string]integration.Config            // Configs we could not dispatch to any node
-	endpointsConfigs map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
-	idToDigest       map[check.ID]string                      // link check IDs to check configs
+	active              bool
+	digestToConfig      map[string]integration.Config            // All configurations to dispatch
+	digestToNode        map[string]string                        // Node running a config
+	nodes               map[string]*nodeStore                    // All nodes known to the cluster-agent
+	danglingConfigs     map[string]integration.Config            // Configs we could not dispatch to any node
+	endpointsConfigs    map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
+	idToDigest          map[check.ID]string                      // link check IDs to check configs
+	restoredAssignments map[string]string                        // Nodes assigned by the previous leader, consumed on dispatch
+	restoredUntil       time.Time                                // Deadline for the nodes of restoredAssignments to report
 }
 
 func newClusterStore() *clusterStore {
@@ -47,6 +50,7 @@ func (s *clusterStore) reset() {
 	s.danglingConfigs = make(map[string]integration.Config)
 	s.endpointsConfigs = make(map[string]map[string]integration.Config)
 	s.idToDigest = make(map[check.ID]string)
+	s.clearRestoredAssignments()
 }
 
 // getNodeStore retrieves the store struct for a given node name, if it exists
@@ -77,6 +81,11 @@ func (s *clusterStore) clearDangling() {
 	s.danglingConfigs = make(map[string]integration.Config)
 }
 
+// clearRestoredAssignments resets the restoredAssignments map to a new empty one
+func (s *clusterStore) clearRestoredAssignments() {
+	s.restoredAssignments = make(map[string]string)
+}
+
 // nodeStore holds the state store for one node.
 // Lock is to be held by the user (dispatcher)
 type nodeStore struct {
@@ -101,6 +110,21 @@ func newNodeStore(name, clientIP string) *nodeStore {
 	}
 }
 
+// matchesSelector returns whether the labels reported by the node
+// satisfy the node selector of the placement constraints.
+// Lock is to be held by the caller.
+func (s *nodeStore) matchesSelector(placement *integration.Placement) bool {
+	if placement == nil {
+		return true
+	}
+	for key, value := range placement.NodeSelector {
+		if label, found := s.lastStatus.Labels[key]; !found || label != value {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *nodeStore) addConfig(config integration.Config) {
 	s.lastConfigChange = timestampNow()
 	s.digestToConfig[config.Digest()] = config
@@ -163,9 +187,10 @@ func (s *nodeStore) GetBusyness(busynessFunc func(stats types.CLCRunnerStats) in
 	return busyness
 }
 
-// GetMostWeightedClusterCheck returns the Cluster Check with the most weight on the node
+// GetMostWeightedClusterCheck returns the Cluster Check with the most weight on the node,
+// ignoring the checks in excluded
 // The nodeStore handles thread safety for this public method
-func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CLCRunnerStats) int) (string, int, error) {
+func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CLCRunnerStats) int, excluded map[string]bool) (string, int, error) {
 	s.RLock()
 	defer s.RUnlock()
 	if len(s.clcRunnerStats) == 0 {
@@ -177,7 +202,7 @@ func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CL
 	checkWeight := 0
 	for id, stats := range s.clcRunnerStats {
 		busyness := busynessFunc(stats)
-		if (busyness > checkWeight || firstItr) && stats.IsClusterCheck {
+		if (busyness > checkWeight || firstItr) && stats.IsClusterCheck && !excluded[id] {
 			// Only consider Cluster Checks
 			checkWeight = busyness
 			checkID = id
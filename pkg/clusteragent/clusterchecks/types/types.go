@@ -11,7 +11,8 @@ import (
 
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
-	LastChange int64 `json:"last_change"`
+	LastChange int64             `json:"last_change"`
+	Labels     map[string]string `json:"labels,omitempty"` // labels of the node, used to honor placement constraints
 }
 
 // StatusResponse holds the DCA response for a status report
@@ -48,6 +49,7 @@ type StateResponse struct {
 // StateNodeResponse is a chunk of StateResponse
 type StateNodeResponse struct {
 	Name    string               `json:"name"`
+	Labels  map[string]string    `json:"labels,omitempty"`
 	Configs []integration.Config `json:"configs"`
 }
 
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package externalmetrics
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// evaluateExpression computes an arithmetic expression (+, -, *, /, parentheses and numbers)
+// where identifiers are replaced by their value in `values`.
+func evaluateExpression(expression string, values map[string]float64) (float64, error) {
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse expression %q: %v", expression, err)
+	}
+	return evaluateNode(expr, values)
+}
+
+func evaluateNode(node ast.Expr, values map[string]float64) (float64, error) {
+	switch n := node.(type) {
+	case *ast.ParenExpr:
+		return evaluateNode(n.X, values)
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return 0, fmt.Errorf("unsupported literal: %s", n.Value)
+		}
+		return strconv.ParseFloat(n.Value, 64)
+	case *ast.Ident:
+		value, found := values[n.Name]
+		if !found {
+			return 0, fmt.Errorf("unknown sub-query: %s", n.Name)
+		}
+		return value, nil
+	case *ast.UnaryExpr:
+		x, err := evaluateNode(n.X, values)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.SUB:
+			return -x, nil
+		case token.ADD:
+			return x, nil
+		}
+		return 0, fmt.Errorf("unsupported operator: %s", n.Op)
+	case *ast.BinaryExpr:
+		x, err := evaluateNode(n.X, values)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evaluateNode(n.Y, values)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return x / y, nil
+		}
+		return 0, fmt.Errorf("unsupported operator: %s", n.Op)
+	default:
+		return 0, fmt.Errorf("unsupported expression: %T", node)
+	}
+}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package externalmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	values := map[string]float64{
+		"depth":    30,
+		"replicas": 4,
+		"zero":     0,
+	}
+
+	tests := []struct {
+		expression string
+		want       float64
+		wantErr    bool
+	}{
+		{expression: "depth", want: 30},
+		{expression: "depth / replicas", want: 7.5},
+		{expression: "(depth + 10) / (replicas * 2)", want: 5},
+		{expression: "-depth + 2.5", want: -27.5},
+		{expression: "depth - replicas * 2", want: 22},
+		{expression: "depth / zero", wantErr: true},
+		{expression: "depth / unknown", wantErr: true},
+		{expression: "depth % replicas", wantErr: true},
+		{expression: "max(depth, replicas)", wantErr: true},
+		{expression: "depth +", wantErr: true},
+		{expression: `"depth"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := evaluateExpression(tt.expression, values)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package externalmetrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics/model"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	le "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection/metrics"
+)
+
+var (
+	fallbackUsed = telemetry.NewCounterWithOpts("external_metrics", "fallback_used",
+		[]string{"policy", le.JoinLeaderLabel}, "Total number of DatadogMetric values computed from a fallback policy, by policy.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+	fallbackFailed = telemetry.NewCounterWithOpts("external_metrics", "fallback_failed",
+		[]string{"policy", le.JoinLeaderLabel}, "Total number of invalid DatadogMetric values for which the fallback policy could not be used, by policy.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+)
+
+// LocalMetricsSource provides the values of metrics aggregated by the Cluster Agent
+type LocalMetricsSource interface {
+	// GetMetricValue returns the last value of the series aggregated without host
+	// with the given name and tags, and the time it was computed
+	GetMetricValue(metric string, tags []string) (float64, time.Time, error)
+}
+
+var (
+	localMetricsSource      LocalMetricsSource
+	localMetricsSourceMutex sync.RWMutex
+)
+
+// SetLocalMetricsSource registers the source used by the local fallback policy,
+// local policies are rejected until a source is registered.
+func SetLocalMetricsSource(source LocalMetricsSource) {
+	localMetricsSourceMutex.Lock()
+	defer localMetricsSourceMutex.Unlock()
+	localMetricsSource = source
+	model.EnableLocalFallbackPolicy(source != nil)
+}
+
+// getLocalMetricValue returns the value of a locally aggregated series, if it is recent enough
+func getLocalMetricValue(metric string, tags []string, currentTime time.Time, maxAge int64) (float64, error) {
+	localMetricsSourceMutex.RLock()
+	source := localMetricsSource
+	localMetricsSourceMutex.RUnlock()
+
+	if source == nil {
+		return 0, fmt.Errorf("no local metrics source available")
+	}
+
+	value, timestamp, err := source.GetMetricValue(metric, tags)
+	if err != nil {
+		return 0, err
+	}
+	if currentTime.Unix()-timestamp.Unix() > maxAge {
+		return 0, fmt.Errorf("outdated local value for metric: %s, tags: %v", metric, tags)
+	}
+	return value, nil
+}
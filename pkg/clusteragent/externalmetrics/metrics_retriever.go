@@ -8,20 +8,26 @@
 package externalmetrics
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics/model"
+	le "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 const (
-	invalidMetricBackendErrorMessage  string = "Invalid metric (from backend), query: %s"
-	invalidMetricOutdatedErrorMessage string = "Outdated result from backend, query: %s"
-	invalidMetricNoDataErrorMessage   string = "No data from backend, query: %s"
-	invalidMetricGlobalErrorMessage   string = "Global error (all queries) from backend"
-	metricRetrieverStoreID            string = "mr"
+	invalidMetricBackendErrorMessage    string = "Invalid metric (from backend), query: %s"
+	invalidMetricOutdatedErrorMessage   string = "Outdated result from backend, query: %s"
+	invalidMetricNoDataErrorMessage     string = "No data from backend, query: %s"
+	invalidMetricGlobalErrorMessage     string = "Global error (all queries) from backend"
+	invalidSubQueryErrorMessage         string = "Invalid sub-query %s: %v"
+	invalidMetricExpressionErrorMessage string = "Unable to evaluate expression: %s: %v"
+	metricRetrieverStoreID              string = "mr"
 )
 
 type MetricsRetriever struct {
@@ -87,48 +93,163 @@ func (mr *MetricsRetriever) retrieveMetricsValues() {
 			continue
 		}
 
-		if queryResult, found := results[datadogMetric.Query]; found {
-			log.Debug("QueryResult from DD: %v", queryResult)
-
-			if queryResult.Valid {
-				datadogMetricFromStore.Value = queryResult.Value
-
-				// If we get a valid but old metric, flag it as invalid
-				if currentTime.Unix()-queryResult.Timestamp <= mr.metricsMaxAge {
-					datadogMetricFromStore.Valid = true
-					datadogMetricFromStore.Error = nil
-					datadogMetricFromStore.UpdateTime = time.Unix(queryResult.Timestamp, 0).UTC()
-				} else {
-					datadogMetricFromStore.Valid = false
-					datadogMetricFromStore.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, datadogMetric.Query)
-					datadogMetricFromStore.UpdateTime = currentTime
-				}
-			} else {
-				datadogMetricFromStore.Valid = false
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricBackendErrorMessage, datadogMetric.Query)
-				datadogMetricFromStore.UpdateTime = currentTime
-			}
-		} else {
+		// A DatadogMetric with an invalid spec stays invalid until its spec is fixed
+		if datadogMetricFromStore.SpecError != nil {
 			datadogMetricFromStore.Valid = false
-			if globalError {
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricGlobalErrorMessage)
-			} else {
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricNoDataErrorMessage, datadogMetric.Query)
-			}
+			datadogMetricFromStore.Error = datadogMetricFromStore.SpecError
 			datadogMetricFromStore.UpdateTime = currentTime
+			mr.store.UnlockSet(datadogMetric.ID, *datadogMetricFromStore, metricRetrieverStoreID)
+			continue
 		}
 
+		wasValid := datadogMetricFromStore.Valid
+		if len(datadogMetricFromStore.SubQueries) > 0 {
+			mr.updateFromSubQueries(datadogMetricFromStore, results, globalError, currentTime)
+		} else {
+			mr.updateFromQuery(datadogMetricFromStore, results, globalError, currentTime)
+		}
+		mr.applyFallback(datadogMetricFromStore, wasValid, currentTime)
+
 		mr.store.UnlockSet(datadogMetric.ID, *datadogMetricFromStore, metricRetrieverStoreID)
 	}
 }
 
+// updateFromQuery updates a DatadogMetric from the result of its query
+func (mr *MetricsRetriever) updateFromQuery(datadogMetric *model.DatadogMetricInternal, results map[string]autoscalers.Point, globalError bool, currentTime time.Time) {
+	if queryResult, found := results[datadogMetric.Query]; found {
+		log.Debug("QueryResult from DD: %v", queryResult)
+
+		if queryResult.Valid {
+			datadogMetric.Value = queryResult.Value
+
+			// If we get a valid but old metric, flag it as invalid
+			if currentTime.Unix()-queryResult.Timestamp <= mr.metricsMaxAge {
+				datadogMetric.Valid = true
+				datadogMetric.Error = nil
+				datadogMetric.UpdateTime = time.Unix(queryResult.Timestamp, 0).UTC()
+			} else {
+				datadogMetric.Valid = false
+				datadogMetric.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, datadogMetric.Query)
+				datadogMetric.UpdateTime = currentTime
+			}
+		} else {
+			datadogMetric.Valid = false
+			datadogMetric.Error = fmt.Errorf(invalidMetricBackendErrorMessage, datadogMetric.Query)
+			datadogMetric.UpdateTime = currentTime
+		}
+	} else {
+		datadogMetric.Valid = false
+		if globalError {
+			datadogMetric.Error = fmt.Errorf(invalidMetricGlobalErrorMessage)
+		} else {
+			datadogMetric.Error = fmt.Errorf(invalidMetricNoDataErrorMessage, datadogMetric.Query)
+		}
+		datadogMetric.UpdateTime = currentTime
+	}
+}
+
+// updateFromSubQueries updates a DatadogMetric by evaluating its query expression with the results of its sub-queries.
+// The last valid result of each sub-query is kept, so that a sub-query missing from a refresh can still be used
+// until it is older than the max age. The DatadogMetric is invalid as long as one of the sub-queries is stale.
+func (mr *MetricsRetriever) updateFromSubQueries(datadogMetric *model.DatadogMetricInternal, results map[string]autoscalers.Point, globalError bool, currentTime time.Time) {
+	names := make([]string, 0, len(datadogMetric.SubQueries))
+	for name := range datadogMetric.SubQueries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// The map is shared with the copies of the DatadogMetric, it is never modified in place
+	lastValues := make(map[string]model.SubQueryValue, len(names))
+	values := make(map[string]float64, len(names))
+	var oldestTimestamp int64
+	var subQueryErrors []string
+	for _, name := range names {
+		query := datadogMetric.SubQueries[name]
+		lastValue, hasLastValue := datadogMetric.SubQueryValues[name]
+		hasLastValue = hasLastValue && lastValue.Query == query
+
+		queryResult, found := results[query]
+		if found && queryResult.Valid && (!hasLastValue || queryResult.Timestamp >= lastValue.Timestamp) {
+			lastValue = model.SubQueryValue{Query: query, Value: queryResult.Value, Timestamp: queryResult.Timestamp}
+			hasLastValue = true
+		}
+		if hasLastValue {
+			lastValues[name] = lastValue
+		}
+
+		var err error
+		switch {
+		case hasLastValue && currentTime.Unix()-lastValue.Timestamp <= mr.metricsMaxAge:
+		case !found && globalError:
+			err = fmt.Errorf(invalidMetricGlobalErrorMessage)
+		case !found:
+			err = fmt.Errorf(invalidMetricNoDataErrorMessage, query)
+		case !queryResult.Valid:
+			err = fmt.Errorf(invalidMetricBackendErrorMessage, query)
+		default:
+			err = fmt.Errorf(invalidMetricOutdatedErrorMessage, query)
+		}
+		if err != nil {
+			subQueryErrors = append(subQueryErrors, fmt.Sprintf(invalidSubQueryErrorMessage, name, err))
+			continue
+		}
+
+		values[name] = lastValue.Value
+		if oldestTimestamp == 0 || lastValue.Timestamp < oldestTimestamp {
+			oldestTimestamp = lastValue.Timestamp
+		}
+	}
+	datadogMetric.SubQueryValues = lastValues
+
+	if len(subQueryErrors) > 0 {
+		datadogMetric.Valid = false
+		datadogMetric.Error = errors.New(strings.Join(subQueryErrors, ", "))
+		datadogMetric.UpdateTime = currentTime
+		return
+	}
+
+	value, err := evaluateExpression(datadogMetric.Query, values)
+	if err != nil {
+		datadogMetric.Valid = false
+		datadogMetric.Error = fmt.Errorf(invalidMetricExpressionErrorMessage, datadogMetric.Query, err)
+		datadogMetric.UpdateTime = currentTime
+		return
+	}
+
+	datadogMetric.Value = value
+	datadogMetric.Valid = true
+	datadogMetric.Error = nil
+	datadogMetric.UpdateTime = time.Unix(oldestTimestamp, 0).UTC()
+}
+
+// applyFallback uses the fallback policy of a DatadogMetric that could not be updated from Datadog
+func (mr *MetricsRetriever) applyFallback(datadogMetric *model.DatadogMetricInternal, wasValid bool, currentTime time.Time) {
+	if datadogMetric.Valid || datadogMetric.Fallback == nil {
+		datadogMetric.ApplyFallback(wasValid, currentTime, nil)
+		return
+	}
+
+	getLocalValue := func(metric string, tags []string) (float64, error) {
+		return getLocalMetricValue(metric, tags, currentTime, mr.metricsMaxAge)
+	}
+	policy := string(datadogMetric.Fallback.Type)
+	if datadogMetric.ApplyFallback(wasValid, currentTime, getLocalValue) {
+		log.Debugf("Using %s fallback value for DatadogMetric: %s, cause: %v", policy, datadogMetric.ID, datadogMetric.Error)
+		fallbackUsed.Inc(policy, le.JoinLeaderValue)
+	} else {
+		fallbackFailed.Inc(policy, le.JoinLeaderValue)
+	}
+}
+
 func getUniqueQueries(datadogMetrics []model.DatadogMetricInternal) []string {
 	queries := make([]string, 0, len(datadogMetrics))
 	unique := make(map[string]struct{}, len(queries))
 	for _, datadogMetric := range datadogMetrics {
-		if _, found := unique[datadogMetric.Query]; !found {
-			unique[datadogMetric.Query] = struct{}{}
-			queries = append(queries, datadogMetric.Query)
+		for _, query := range datadogMetric.Queries() {
+			if _, found := unique[query]; !found {
+				unique[query] = struct{}{}
+				queries = append(queries, query)
+			}
 		}
 	}
 
@@ -9,6 +9,7 @@ package externalmetrics
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -499,3 +500,350 @@ func TestRetrieveMetricsNotActive(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrieveMetricsSubQueries(t *testing.T) {
+	defaultTestTime := time.Now().Add(time.Duration(-1) * time.Second).UTC().Truncate(time.Second)
+	defaultPreviousUpdateTime := time.Now().Add(time.Duration(-11) * time.Second).UTC().Truncate(time.Second)
+	olderTestTime := defaultTestTime.Add(time.Duration(-5) * time.Second)
+	subQueries := map[string]string{
+		"depth":    "query-depth",
+		"replicas": "query-replicas",
+	}
+
+	fixtures := []metricsFixture{
+		{
+			maxAge: 30,
+			desc:   "Test expression is evaluated with sub-query values, update time is the oldest sub-query timestamp",
+			storeContent: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / (replicas + 1)",
+					SubQueries: subQueries,
+					UpdateTime: defaultPreviousUpdateTime,
+					Valid:      false,
+				},
+			},
+			queryResults: map[string]autoscalers.Point{
+				"query-depth": {
+					Value:     30.0,
+					Timestamp: defaultTestTime.Unix(),
+					Valid:     true,
+				},
+				"query-replicas": {
+					Value:     2.0,
+					Timestamp: olderTestTime.Unix(),
+					Valid:     true,
+				},
+			},
+			expected: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / (replicas + 1)",
+					SubQueries: subQueries,
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth":    {Query: "query-depth", Value: 30.0, Timestamp: defaultTestTime.Unix()},
+						"replicas": {Query: "query-replicas", Value: 2.0, Timestamp: olderTestTime.Unix()},
+					},
+					Value:      10.0,
+					UpdateTime: olderTestTime,
+					Valid:      true,
+				},
+			},
+		},
+		{
+			maxAge: 5,
+			desc:   "Test outdated sub-query invalidates the metric",
+			storeContent: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: subQueries,
+					Value:      5.0,
+					UpdateTime: defaultPreviousUpdateTime,
+					Valid:      true,
+				},
+			},
+			queryResults: map[string]autoscalers.Point{
+				"query-depth": {
+					Value:     30.0,
+					Timestamp: defaultTestTime.Unix(),
+					Valid:     true,
+				},
+				"query-replicas": {
+					Value:     2.0,
+					Timestamp: defaultPreviousUpdateTime.Unix(),
+					Valid:     true,
+				},
+			},
+			expected: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: subQueries,
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth":    {Query: "query-depth", Value: 30.0, Timestamp: defaultTestTime.Unix()},
+						"replicas": {Query: "query-replicas", Value: 2.0, Timestamp: defaultPreviousUpdateTime.Unix()},
+					},
+					Value:      5.0,
+					UpdateTime: defaultTestTime,
+					Valid:      false,
+					Error:      fmt.Errorf(invalidSubQueryErrorMessage, "replicas", fmt.Errorf(invalidMetricOutdatedErrorMessage, "query-replicas")),
+				},
+			},
+		},
+		{
+			maxAge: 30,
+			desc:   "Test missing sub-query and division by zero",
+			storeContent: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: map[string]string{"depth": "query-depth", "replicas": "query-missing"},
+					UpdateTime: defaultPreviousUpdateTime,
+					Valid:      true,
+				},
+				{
+					ID:         "metric1",
+					Active:     true,
+					Query:      "depth / (replicas - 2)",
+					SubQueries: subQueries,
+					UpdateTime: defaultPreviousUpdateTime,
+					Valid:      true,
+				},
+			},
+			queryResults: map[string]autoscalers.Point{
+				"query-depth": {
+					Value:     30.0,
+					Timestamp: defaultTestTime.Unix(),
+					Valid:     true,
+				},
+				"query-replicas": {
+					Value:     2.0,
+					Timestamp: defaultTestTime.Unix(),
+					Valid:     true,
+				},
+			},
+			expected: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: map[string]string{"depth": "query-depth", "replicas": "query-missing"},
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth": {Query: "query-depth", Value: 30.0, Timestamp: defaultTestTime.Unix()},
+					},
+					UpdateTime: defaultTestTime,
+					Valid:      false,
+					Error:      fmt.Errorf(invalidSubQueryErrorMessage, "replicas", fmt.Errorf(invalidMetricNoDataErrorMessage, "query-missing")),
+				},
+				{
+					ID:         "metric1",
+					Active:     true,
+					Query:      "depth / (replicas - 2)",
+					SubQueries: subQueries,
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth":    {Query: "query-depth", Value: 30.0, Timestamp: defaultTestTime.Unix()},
+						"replicas": {Query: "query-replicas", Value: 2.0, Timestamp: defaultTestTime.Unix()},
+					},
+					UpdateTime: defaultTestTime,
+					Valid:      false,
+					Error:      fmt.Errorf(invalidMetricExpressionErrorMessage, "depth / (replicas - 2)", fmt.Errorf("division by zero")),
+				},
+			},
+		},
+		{
+			maxAge: 30,
+			desc:   "Test last value of a missing sub-query is used until it is stale, stale sub-queries are all reported",
+			storeContent: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: subQueries,
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth":    {Query: "query-depth", Value: 20.0, Timestamp: olderTestTime.Unix()},
+						"replicas": {Query: "query-replicas", Value: 4.0, Timestamp: olderTestTime.Unix()},
+					},
+					Value:      5.0,
+					UpdateTime: olderTestTime,
+					Valid:      true,
+				},
+				{
+					ID:         "metric1",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: map[string]string{"depth": "query-depth-stale", "replicas": "query-replicas-stale"},
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth":    {Query: "query-depth-stale", Value: 20.0, Timestamp: defaultTestTime.Unix() - 60},
+						"replicas": {Query: "query-replicas-changed", Value: 4.0, Timestamp: olderTestTime.Unix()},
+					},
+					Value:      5.0,
+					UpdateTime: olderTestTime,
+					Valid:      true,
+				},
+			},
+			queryResults: map[string]autoscalers.Point{
+				"query-depth": {
+					Value:     30.0,
+					Timestamp: defaultTestTime.Unix(),
+					Valid:     true,
+				},
+				"query-replicas": {
+					Valid: false,
+				},
+			},
+			expected: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: subQueries,
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth":    {Query: "query-depth", Value: 30.0, Timestamp: defaultTestTime.Unix()},
+						"replicas": {Query: "query-replicas", Value: 4.0, Timestamp: olderTestTime.Unix()},
+					},
+					Value:      7.5,
+					UpdateTime: olderTestTime,
+					Valid:      true,
+				},
+				{
+					ID:         "metric1",
+					Active:     true,
+					Query:      "depth / replicas",
+					SubQueries: map[string]string{"depth": "query-depth-stale", "replicas": "query-replicas-stale"},
+					SubQueryValues: map[string]model.SubQueryValue{
+						"depth": {Query: "query-depth-stale", Value: 20.0, Timestamp: defaultTestTime.Unix() - 60},
+					},
+					Value:      5.0,
+					UpdateTime: defaultTestTime,
+					Valid:      false,
+					Error: fmt.Errorf("%s, %s",
+						fmt.Errorf(invalidSubQueryErrorMessage, "depth", fmt.Errorf(invalidMetricNoDataErrorMessage, "query-depth-stale")),
+						fmt.Errorf(invalidSubQueryErrorMessage, "replicas", fmt.Errorf(invalidMetricNoDataErrorMessage, "query-replicas-stale")),
+					),
+				},
+			},
+		},
+		{
+			maxAge: 30,
+			desc:   "Test DatadogMetric with invalid sub-queries stays invalid",
+			storeContent: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					UpdateTime: defaultPreviousUpdateTime,
+					Valid:      false,
+					SpecError:  fmt.Errorf("invalid sub-query name: %q", "max-depth"),
+				},
+			},
+			queryResults: map[string]autoscalers.Point{},
+			expected: []model.DatadogMetricInternal{
+				{
+					ID:         "metric0",
+					Active:     true,
+					Query:      "depth / replicas",
+					UpdateTime: defaultTestTime,
+					Valid:      false,
+					Error:      fmt.Errorf("invalid sub-query name: %q", "max-depth"),
+					SpecError:  fmt.Errorf("invalid sub-query name: %q", "max-depth"),
+				},
+			},
+		},
+	}
+
+	for i, fixture := range fixtures {
+		t.Run(fmt.Sprintf("#%d %s", i, fixture.desc), func(t *testing.T) {
+			fixture.run(t, defaultTestTime)
+		})
+	}
+}
+
+func TestGetUniqueQueries(t *testing.T) {
+	queries := getUniqueQueries([]model.DatadogMetricInternal{
+		{ID: "metric0", Query: "query-depth"},
+		{ID: "metric1", Query: "depth / replicas", SubQueries: map[string]string{"depth": "query-depth", "replicas": "query-replicas"}},
+		{ID: "metric2", Query: "depth / replicas", SpecError: fmt.Errorf("invalid sub-queries")},
+	})
+	assert.Equal(t, []string{"query-depth", "query-replicas"}, queries)
+}
+
+type mockedLocalMetricsSource struct {
+	values map[string]float64
+	time   time.Time
+}
+
+func (s *mockedLocalMetricsSource) GetMetricValue(metric string, tags []string) (float64, time.Time, error) {
+	value, found := s.values[strings.Join(append([]string{metric}, tags...), ",")]
+	if !found {
+		return 0, time.Time{}, fmt.Errorf("unknown metric: %s, tags: %v", metric, tags)
+	}
+	return value, s.time, nil
+}
+
+func TestRetrieveMetricsFallback(t *testing.T) {
+	previousUpdateTime := time.Now().Add(time.Duration(-11) * time.Second).UTC().Truncate(time.Second)
+	holdSince := time.Now().Add(time.Duration(-10) * time.Minute).UTC()
+
+	SetLocalMetricsSource(&mockedLocalMetricsSource{values: map[string]float64{"jobs.queue.depth": 7, "jobs.queue.depth,queue:default": 3}, time: time.Now()})
+	defer SetLocalMetricsSource(nil)
+
+	store := NewDatadogMetricsInternalStore()
+	for _, datadogMetric := range []model.DatadogMetricInternal{
+		{ID: "hold", Active: true, Query: "query-hold", Value: 4, Valid: true, UpdateTime: previousUpdateTime, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyHold, Duration: time.Minute}},
+		{ID: "hold-never-valid", Active: true, Query: "query-hold", Value: 0, Valid: false, UpdateTime: previousUpdateTime, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyHold, Duration: time.Minute}},
+		{ID: "hold-expired", Active: true, Query: "query-hold", Value: 4, Valid: true, UpdateTime: previousUpdateTime, FallbackSince: holdSince, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyHold, Duration: time.Minute}},
+		{ID: "fixed", Active: true, Query: "query-fixed", Value: 4, Valid: false, UpdateTime: previousUpdateTime, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyFixed, Value: 2}},
+		{ID: "local", Active: true, Query: "query-local", Valid: false, UpdateTime: previousUpdateTime, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyLocal, Metric: "jobs.queue.depth"}},
+		{ID: "local-tags", Active: true, Query: "query-local", Valid: false, UpdateTime: previousUpdateTime, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyLocal, Metric: "jobs.queue.depth", Tags: []string{"queue:default"}}},
+		{ID: "local-unknown", Active: true, Query: "query-local", Valid: false, UpdateTime: previousUpdateTime, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyLocal, Metric: "unknown"}},
+		{ID: "recovered", Active: true, Query: "query-ok", Value: 2, Valid: true, UpdateTime: previousUpdateTime, FallbackSince: holdSince, Fallback: &model.FallbackPolicy{Type: model.FallbackPolicyFixed, Value: 2}},
+	} {
+		store.Set(datadogMetric.ID, datadogMetric, "utest")
+	}
+
+	mockedProcessor := mockedProcessor{
+		points: map[string]autoscalers.Point{
+			"query-ok": {Value: 12.0, Timestamp: time.Now().Unix(), Valid: true},
+		},
+	}
+	metricsRetriever, err := NewMetricsRetriever(0, 30, &mockedProcessor, getIsLeaderFunction(true), &store)
+	assert.Nil(t, err)
+	metricsRetriever.retrieveMetricsValues()
+
+	tests := []struct {
+		id       string
+		valid    bool
+		fallback bool
+		value    float64
+	}{
+		{id: "hold", valid: true, fallback: true, value: 4},
+		{id: "hold-never-valid", valid: false, fallback: false, value: 0},
+		{id: "hold-expired", valid: false, fallback: false, value: 4},
+		{id: "fixed", valid: true, fallback: true, value: 2},
+		{id: "local", valid: true, fallback: true, value: 7},
+		{id: "local-tags", valid: true, fallback: true, value: 3},
+		{id: "local-unknown", valid: false, fallback: false, value: 0},
+		{id: "recovered", valid: true, fallback: false, value: 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			datadogMetric := store.Get(tt.id)
+			assert.Equal(t, tt.valid, datadogMetric.Valid)
+			assert.Equal(t, tt.fallback, datadogMetric.IsFallback())
+			assert.Equal(t, tt.value, datadogMetric.Value)
+			if tt.id == "recovered" {
+				assert.Nil(t, datadogMetric.Error)
+				assert.True(t, datadogMetric.FallbackSince.IsZero())
+			} else {
+				// The cause is always reported, even when using a fallback value
+				assert.NotNil(t, datadogMetric.Error)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ package model
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -23,12 +24,18 @@ import (
 
 const (
 	DatadogMetricErrorConditionReason string = "Unable to fetch data from Datadog"
+	// DatadogMetricSubQueriesAnnotation holds the named sub-queries of a DatadogMetric, as a JSON object.
+	// When set, the query of the DatadogMetric is an arithmetic expression of the sub-query names.
+	DatadogMetricSubQueriesAnnotation string = "external-metrics.datadoghq.com/sub-queries"
 )
 
 // DatadogMetricInternal is a flatten, easier to use, representation of `DatadogMetric` CRD
 type DatadogMetricInternal struct {
 	ID                   string
 	Query                string
+	SubQueries           map[string]string
+	SubQueryValues       map[string]SubQueryValue
+	Fallback             *FallbackPolicy
 	Valid                bool
 	Active               bool
 	Deleted              bool
@@ -37,7 +44,16 @@ type DatadogMetricInternal struct {
 	Value                float64
 	AutoscalerReferences string
 	UpdateTime           time.Time
+	FallbackSince        time.Time
 	Error                error
+	SpecError            error
+}
+
+// SubQueryValue is the last valid result of a sub-query of a `DatadogMetricInternal`
+type SubQueryValue struct {
+	Query     string
+	Value     float64
+	Timestamp int64
 }
 
 // NewDatadogMetricInternal returns a `DatadogMetricInternal` object from a `DatadogMetric` CRD Object
@@ -46,6 +62,7 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 	internal := DatadogMetricInternal{
 		ID:                   id,
 		Query:                datadogMetric.Spec.Query,
+		Fallback:             parseFallbackPolicy(id, datadogMetric.Annotations),
 		Valid:                false,
 		Active:               false,
 		Deleted:              false,
@@ -68,6 +85,8 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 			internal.UpdateTime = condition.LastUpdateTime.UTC()
 		case condition.Type == datadoghq.DatadogMetricConditionTypeError && condition.Status == corev1.ConditionTrue:
 			internal.Error = errors.New(condition.Message)
+		case condition.Type == DatadogMetricConditionTypeFallback && condition.Status == corev1.ConditionTrue:
+			internal.FallbackSince = condition.LastTransitionTime.UTC()
 		}
 	}
 
@@ -87,6 +106,7 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 	}
 	internal.Value = value
 
+	internal.setSubQueries(datadogMetric.Annotations)
 	return internal
 }
 
@@ -106,9 +126,50 @@ func NewDatadogMetricInternalFromExternalMetric(id, query, metricName, autoscale
 	}
 }
 
-// UpdateFrom updates the `DatadogMetricInternal` from `DatadogMetric` Spec, returns modified instance
-func (d *DatadogMetricInternal) UpdateFrom(currentSpec datadoghq.DatadogMetricSpec) {
-	d.Query = currentSpec.Query
+// UpdateFrom updates the `DatadogMetricInternal` from `DatadogMetric` Spec, sub-queries and fallback annotations, returns modified instance
+func (d *DatadogMetricInternal) UpdateFrom(current datadoghq.DatadogMetric) {
+	d.Query = current.Spec.Query
+	d.Fallback = parseFallbackPolicy(d.ID, current.Annotations)
+	d.setSubQueries(current.Annotations)
+}
+
+// setSubQueries parses the sub-queries annotation, a `DatadogMetricInternal` with invalid sub-queries is invalid
+func (d *DatadogMetricInternal) setSubQueries(annotations map[string]string) {
+	subQueries, err := parseSubQueries(annotations)
+	if err != nil {
+		log.Errorf("Unable to parse sub-queries of DatadogMetric: %s, invalidating it: %v", d.ID, err)
+		d.SubQueries = nil
+		d.SpecError = err
+		d.Valid = false
+		d.Error = err
+		d.UpdateTime = time.Now().UTC()
+		return
+	}
+
+	// Clearing the error of a previously invalid spec, it will be updated with the next results
+	if d.SpecError != nil && d.Error == d.SpecError {
+		d.Error = nil
+	}
+	d.SubQueries = subQueries
+	d.SpecError = nil
+}
+
+// Queries returns the queries to send to Datadog to compute the value of the `DatadogMetricInternal`.
+// A `DatadogMetricInternal` with an invalid spec has no query to send.
+func (d *DatadogMetricInternal) Queries() []string {
+	if d.SpecError != nil {
+		return nil
+	}
+	if len(d.SubQueries) == 0 {
+		return []string{d.Query}
+	}
+
+	queries := make([]string, 0, len(d.SubQueries))
+	for _, query := range d.SubQueries {
+		queries = append(queries, query)
+	}
+	sort.Strings(queries)
+	return queries
 }
 
 // IsNewerThan returns true if the current `DatadogMetricInternal` has been updated more recently than `DatadogMetric` Status
@@ -138,6 +199,7 @@ func (d *DatadogMetricInternal) BuildStatus(currentStatus *datadoghq.DatadogMetr
 		datadoghq.DatadogMetricConditionTypeValid:   nil,
 		datadoghq.DatadogMetricConditionTypeUpdated: nil,
 		datadoghq.DatadogMetricConditionTypeError:   nil,
+		DatadogMetricConditionTypeFallback:          nil,
 	}
 
 	if currentStatus != nil {
@@ -164,6 +226,17 @@ func (d *DatadogMetricInternal) BuildStatus(currentStatus *datadoghq.DatadogMetr
 		AutoscalerReferences: d.AutoscalerReferences,
 	}
 
+	// Fallback condition is only reported for DatadogMetrics with a fallback policy
+	if d.Fallback != nil || existingConditions[DatadogMetricConditionTypeFallback] != nil {
+		fallbackCondition := d.newCondition(d.IsFallback(), updateTime, DatadogMetricConditionTypeFallback, existingConditions[DatadogMetricConditionTypeFallback])
+		if d.IsFallback() {
+			fallbackCondition.LastTransitionTime = metav1.NewTime(d.FallbackSince)
+			fallbackCondition.Reason = string(d.Fallback.Type)
+			fallbackCondition.Message = fmt.Sprintf("Using %s fallback value", d.Fallback.Type)
+		}
+		newStatus.Conditions = append(newStatus.Conditions, fallbackCondition)
+	}
+
 	return &newStatus
 }
 
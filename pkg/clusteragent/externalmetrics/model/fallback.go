@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	datadoghq "github.com/DataDog/datadog-operator/pkg/apis/datadoghq/v1alpha1"
+)
+
+const (
+	// DatadogMetricFallbackAnnotation holds the fallback policy of a DatadogMetric, as a JSON object.
+	DatadogMetricFallbackAnnotation string = "external-metrics.datadoghq.com/fallback"
+	// DatadogMetricConditionTypeFallback is true when the value of a DatadogMetric comes from its fallback policy
+	DatadogMetricConditionTypeFallback datadoghq.DatadogMetricConditionType = "Fallback"
+)
+
+// FallbackPolicyType is the type of a DatadogMetric fallback policy
+type FallbackPolicyType string
+
+const (
+	// FallbackPolicyHold keeps the last valid value for a given duration
+	FallbackPolicyHold FallbackPolicyType = "hold"
+	// FallbackPolicyFixed uses a fixed value
+	FallbackPolicyFixed FallbackPolicyType = "fixed"
+	// FallbackPolicyLocal uses a metric aggregated by the Cluster Agent from node agents' DogStatsD
+	FallbackPolicyLocal FallbackPolicyType = "local"
+)
+
+var (
+	localPolicyEnabled      bool
+	localPolicyEnabledMutex sync.RWMutex
+)
+
+// EnableLocalFallbackPolicy allows the local policy, it must only be enabled once a local
+// metrics source is registered. Local policies are rejected while it is disabled.
+func EnableLocalFallbackPolicy(enabled bool) {
+	localPolicyEnabledMutex.Lock()
+	defer localPolicyEnabledMutex.Unlock()
+	localPolicyEnabled = enabled
+}
+
+func isLocalFallbackPolicyEnabled() bool {
+	localPolicyEnabledMutex.RLock()
+	defer localPolicyEnabledMutex.RUnlock()
+	return localPolicyEnabled
+}
+
+// FallbackPolicy defines the value to use when a DatadogMetric cannot be retrieved from Datadog.
+// Examples:
+// {"type": "hold", "duration": "10m"}
+// {"type": "fixed", "value": 3}
+// {"type": "local", "metric": "jobs.queue.depth", "tags": ["queue:default"]}
+// The local policy uses the series aggregated without host with the given metric name and tags.
+type FallbackPolicy struct {
+	Type     FallbackPolicyType `json:"type"`
+	Duration time.Duration      `json:"-"`
+	Value    float64            `json:"value,omitempty"`
+	Metric   string             `json:"metric,omitempty"`
+	Tags     []string           `json:"tags,omitempty"`
+}
+
+// UnmarshalJSON parses a fallback policy, the hold duration is a Go duration string
+func (f *FallbackPolicy) UnmarshalJSON(data []byte) error {
+	type policy FallbackPolicy
+	raw := struct {
+		*policy
+		Duration string `json:"duration,omitempty"`
+	}{policy: (*policy)(f)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch f.Type {
+	case FallbackPolicyHold:
+		duration, err := time.ParseDuration(raw.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid duration for hold policy: %v", err)
+		}
+		f.Duration = duration
+	case FallbackPolicyFixed:
+	case FallbackPolicyLocal:
+		if !isLocalFallbackPolicyEnabled() {
+			return fmt.Errorf("local policy requires cluster-level aggregation, enable cluster_aggregation.enabled")
+		}
+		if len(f.Metric) == 0 {
+			return fmt.Errorf("metric is required for local policy")
+		}
+	default:
+		return fmt.Errorf("unknown fallback policy type: %q", f.Type)
+	}
+	return nil
+}
+
+func parseFallbackPolicy(id string, annotations map[string]string) *FallbackPolicy {
+	raw, found := annotations[DatadogMetricFallbackAnnotation]
+	if !found || len(raw) == 0 {
+		return nil
+	}
+
+	policy := &FallbackPolicy{}
+	if err := json.Unmarshal([]byte(raw), policy); err != nil {
+		log.Errorf("Unable to parse fallback policy of DatadogMetric: %s, ignoring it: %v", id, err)
+		return nil
+	}
+	return policy
+}
+
+// IsFallback returns true if the value of the `DatadogMetricInternal` comes from its fallback policy
+func (d *DatadogMetricInternal) IsFallback() bool {
+	return d.Fallback != nil && !d.FallbackSince.IsZero() && d.Valid
+}
+
+// ApplyFallback uses the fallback policy of an invalid `DatadogMetricInternal`.
+// `wasValid` is the validity before the last update, a value can only be held if it was valid.
+// `getLocalValue` returns the value of a series aggregated locally, by name and tags, it is used by the local policy.
+// Returns true if a fallback value is used.
+func (d *DatadogMetricInternal) ApplyFallback(wasValid bool, currentTime time.Time, getLocalValue func(string, []string) (float64, error)) bool {
+	if d.Valid {
+		d.FallbackSince = time.Time{}
+		return false
+	}
+	if d.Fallback == nil {
+		return false
+	}
+
+	if d.FallbackSince.IsZero() {
+		d.FallbackSince = currentTime
+	}
+
+	switch d.Fallback.Type {
+	case FallbackPolicyHold:
+		if !wasValid {
+			return false
+		}
+		if currentTime.Sub(d.FallbackSince) > d.Fallback.Duration {
+			d.Error = fmt.Errorf("%v, last value held since %s", d.Error, d.FallbackSince.Format(time.RFC3339))
+			return false
+		}
+	case FallbackPolicyFixed:
+		d.Value = d.Fallback.Value
+	case FallbackPolicyLocal:
+		value, err := getLocalValue(d.Fallback.Metric, d.Fallback.Tags)
+		if err != nil {
+			d.Error = fmt.Errorf("%v, local fallback failed: %v", d.Error, err)
+			return false
+		}
+		d.Value = value
+	}
+
+	d.Valid = true
+	return true
+}
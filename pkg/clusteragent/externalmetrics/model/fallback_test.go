@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	datadoghq "github.com/DataDog/datadog-operator/pkg/apis/datadoghq/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseFallbackPolicy(t *testing.T) {
+	EnableLocalFallbackPolicy(true)
+	defer EnableLocalFallbackPolicy(false)
+
+	tests := []struct {
+		annotation string
+		want       *FallbackPolicy
+	}{
+		{annotation: `{"type": "hold", "duration": "10m"}`, want: &FallbackPolicy{Type: FallbackPolicyHold, Duration: 10 * time.Minute}},
+		{annotation: `{"type": "fixed", "value": 3}`, want: &FallbackPolicy{Type: FallbackPolicyFixed, Value: 3}},
+		{annotation: `{"type": "local", "metric": "jobs.queue.depth"}`, want: &FallbackPolicy{Type: FallbackPolicyLocal, Metric: "jobs.queue.depth"}},
+		{annotation: `{"type": "local", "metric": "jobs.queue.depth", "tags": ["queue:default"]}`, want: &FallbackPolicy{Type: FallbackPolicyLocal, Metric: "jobs.queue.depth", Tags: []string{"queue:default"}}},
+		{annotation: `{"type": "hold"}`, want: nil},
+		{annotation: `{"type": "local"}`, want: nil},
+		{annotation: `{"type": "unknown"}`, want: nil},
+		{annotation: `not json`, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.annotation, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseFallbackPolicy("default/dd-metric-0", map[string]string{DatadogMetricFallbackAnnotation: tt.annotation}))
+		})
+	}
+}
+
+func TestParseLocalFallbackPolicyDisabled(t *testing.T) {
+	EnableLocalFallbackPolicy(false)
+
+	annotations := map[string]string{DatadogMetricFallbackAnnotation: `{"type": "local", "metric": "jobs.queue.depth"}`}
+	assert.Nil(t, parseFallbackPolicy("default/dd-metric-0", annotations))
+
+	EnableLocalFallbackPolicy(true)
+	defer EnableLocalFallbackPolicy(false)
+	assert.Equal(t, &FallbackPolicy{Type: FallbackPolicyLocal, Metric: "jobs.queue.depth"}, parseFallbackPolicy("default/dd-metric-0", annotations))
+}
+
+func TestFallbackCondition(t *testing.T) {
+	fallbackSince := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	internal := DatadogMetricInternal{
+		ID:            "default/dd-metric-0",
+		Valid:         true,
+		Active:        true,
+		Value:         2,
+		Fallback:      &FallbackPolicy{Type: FallbackPolicyFixed, Value: 2},
+		FallbackSince: fallbackSince,
+		UpdateTime:    time.Now().UTC().Truncate(time.Second),
+		Error:         errors.New("No data from backend, query: query-metric0"),
+	}
+
+	status := internal.BuildStatus(nil)
+	assert.Len(t, status.Conditions, 5)
+	fallbackCondition := status.Conditions[4]
+	assert.Equal(t, DatadogMetricConditionTypeFallback, fallbackCondition.Type)
+	assert.Equal(t, corev1.ConditionTrue, fallbackCondition.Status)
+	assert.Equal(t, "fixed", fallbackCondition.Reason)
+	assert.Equal(t, metav1.NewTime(fallbackSince), fallbackCondition.LastTransitionTime)
+
+	// Fallback start time is restored from the DatadogMetric status
+	restored := NewDatadogMetricInternal(internal.ID, datadoghq.DatadogMetric{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{DatadogMetricFallbackAnnotation: `{"type": "fixed", "value": 2}`}},
+		Status:     *status,
+	})
+	assert.True(t, restored.IsFallback())
+	assert.Equal(t, fallbackSince, restored.FallbackSince)
+
+	// Without fallback policy, the condition is not reported
+	internal.Fallback = nil
+	assert.Len(t, internal.BuildStatus(nil).Conditions, 4)
+}
@@ -8,6 +8,9 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
+	"go/token"
 	"strconv"
 )
 
@@ -22,3 +25,26 @@ func parseDatadogMetricValue(s string) (float64, error) {
 func formatDatadogMetricValue(v float64) string {
 	return strconv.FormatFloat(v, 'f', -1, 64)
 }
+
+// parseSubQueries returns the sub-queries of the annotation, indexed by their name.
+// Names are used in the query expression, so they must be valid identifiers.
+func parseSubQueries(annotations map[string]string) (map[string]string, error) {
+	raw, found := annotations[DatadogMetricSubQueriesAnnotation]
+	if !found || len(raw) == 0 {
+		return nil, nil
+	}
+
+	var subQueries map[string]string
+	if err := json.Unmarshal([]byte(raw), &subQueries); err != nil {
+		return nil, fmt.Errorf("invalid sub-queries: %v", err)
+	}
+	for name, query := range subQueries {
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("invalid sub-query name: %q", name)
+		}
+		if len(query) == 0 {
+			return nil, fmt.Errorf("empty sub-query: %s", name)
+		}
+	}
+	return subQueries, nil
+}
@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package model
+
+import (
+	"testing"
+
+	datadoghq "github.com/DataDog/datadog-operator/pkg/apis/datadoghq/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseSubQueries(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       map[string]string
+		wantErr    bool
+	}{
+		{annotation: `{"depth": "query-depth", "replicas": "query-replicas"}`, want: map[string]string{"depth": "query-depth", "replicas": "query-replicas"}},
+		{annotation: ``, want: nil},
+		{annotation: `{"max-depth": "query-depth"}`, wantErr: true},
+		{annotation: `{"depth": ""}`, wantErr: true},
+		{annotation: `not json`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.annotation, func(t *testing.T) {
+			subQueries, err := parseSubQueries(map[string]string{DatadogMetricSubQueriesAnnotation: tt.annotation})
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, subQueries)
+		})
+	}
+}
+
+func TestInvalidSubQueries(t *testing.T) {
+	datadogMetric := datadoghq.DatadogMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{DatadogMetricSubQueriesAnnotation: `not json`},
+		},
+		Spec: datadoghq.DatadogMetricSpec{Query: "depth / replicas"},
+	}
+
+	// A DatadogMetric with invalid sub-queries is invalid and its expression is never sent to Datadog
+	internal := NewDatadogMetricInternal("default/dd-metric-0", datadogMetric)
+	assert.False(t, internal.Valid)
+	assert.Error(t, internal.Error)
+	assert.Equal(t, internal.Error, internal.SpecError)
+	assert.Empty(t, internal.Queries())
+
+	// Fixing the sub-queries clears the error
+	datadogMetric.Annotations[DatadogMetricSubQueriesAnnotation] = `{"depth": "query-depth", "replicas": "query-replicas"}`
+	internal.UpdateFrom(datadogMetric)
+	assert.NoError(t, internal.Error)
+	assert.NoError(t, internal.SpecError)
+	assert.Equal(t, []string{"query-depth", "query-replicas"}, internal.Queries())
+}
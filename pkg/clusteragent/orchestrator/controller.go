@@ -29,6 +29,8 @@ import (
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	batchv1beta1listers "k8s.io/client-go/listers/batch/v1beta1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 )
@@ -57,6 +59,16 @@ type Controller struct {
 	serviceListerSync       cache.InformerSynced
 	nodesLister             corelisters.NodeLister
 	nodesListerSync         cache.InformerSynced
+	stsLister               appslisters.StatefulSetLister
+	stsListerSync           cache.InformerSynced
+	dsLister                appslisters.DaemonSetLister
+	dsListerSync            cache.InformerSynced
+	jobLister               batchlisters.JobLister
+	jobListerSync           cache.InformerSynced
+	cronJobLister           batchv1beta1listers.CronJobLister
+	cronJobListerSync       cache.InformerSynced
+	pvcLister               corelisters.PersistentVolumeClaimLister
+	pvcListerSync           cache.InformerSynced
 	groupID                 int32
 	hostName                string
 	clusterName             string
@@ -88,6 +100,16 @@ func StartController(ctx ControllerContext) error {
 	ctx.UnassignedPodInformerFactory.Start(ctx.StopCh)
 	ctx.InformerFactory.Start(ctx.StopCh)
 
+	// The cluster-agent may not be allowed to list the kinds added to the orchestrator explorer
+	// after the first ones: they are synced separately so that they don't block the other kinds
+	syncOptionalInformers(map[apiserver.InformerName]cache.SharedInformer{
+		apiserver.StatefulSetsInformer:           ctx.InformerFactory.Apps().V1().StatefulSets().Informer(),
+		apiserver.DaemonSetsInformer:             ctx.InformerFactory.Apps().V1().DaemonSets().Informer(),
+		apiserver.JobsInformer:                   ctx.InformerFactory.Batch().V1().Jobs().Informer(),
+		apiserver.CronJobsInformer:               ctx.InformerFactory.Batch().V1beta1().CronJobs().Informer(),
+		apiserver.PersistentVolumeClaimsInformer: ctx.InformerFactory.Core().V1().PersistentVolumeClaims().Informer(),
+	})
+
 	return apiserver.SyncInformers(map[apiserver.InformerName]cache.SharedInformer{
 		apiserver.PodsInformer:        ctx.UnassignedPodInformerFactory.Core().V1().Pods().Informer(),
 		apiserver.DeploysInformer:     ctx.InformerFactory.Apps().V1().Deployments().Informer(),
@@ -97,6 +119,19 @@ func StartController(ctx ControllerContext) error {
 	})
 }
 
+// syncOptionalInformers waits for each informer in the background and warns about the ones which
+// couldn't be synced. Their kinds are collected once they are synced.
+func syncOptionalInformers(informers map[apiserver.InformerName]cache.SharedInformer) {
+	for name, informer := range informers {
+		name, informer := name, informer
+		go func() {
+			if err := apiserver.SyncInformers(map[apiserver.InformerName]cache.SharedInformer{name: informer}); err != nil {
+				log.Warnf("Orchestrator explorer will not collect %s until their informer is synced, check the cluster-agent RBAC: %v", name, err)
+			}
+		}()
+	}
+}
+
 func newController(ctx ControllerContext) (*Controller, error) {
 	podInformer := ctx.UnassignedPodInformerFactory.Core().V1().Pods()
 	clusterID, err := clustername.GetClusterID()
@@ -108,6 +143,11 @@ func newController(ctx ControllerContext) (*Controller, error) {
 	rsInformer := ctx.InformerFactory.Apps().V1().ReplicaSets()
 	serviceInformer := ctx.InformerFactory.Core().V1().Services()
 	nodesInformer := ctx.InformerFactory.Core().V1().Nodes()
+	stsInformer := ctx.InformerFactory.Apps().V1().StatefulSets()
+	dsInformer := ctx.InformerFactory.Apps().V1().DaemonSets()
+	jobInformer := ctx.InformerFactory.Batch().V1().Jobs()
+	cronJobInformer := ctx.InformerFactory.Batch().V1beta1().CronJobs()
+	pvcInformer := ctx.InformerFactory.Core().V1().PersistentVolumeClaims()
 
 	cfg := processcfg.NewDefaultAgentConfig(true)
 	if err := cfg.LoadProcessYamlConfig(ctx.ConfigPath); err != nil {
@@ -133,6 +173,16 @@ func newController(ctx ControllerContext) (*Controller, error) {
 		serviceListerSync:       serviceInformer.Informer().HasSynced,
 		nodesLister:             nodesInformer.Lister(),
 		nodesListerSync:         nodesInformer.Informer().HasSynced,
+		stsLister:               stsInformer.Lister(),
+		stsListerSync:           stsInformer.Informer().HasSynced,
+		dsLister:                dsInformer.Lister(),
+		dsListerSync:            dsInformer.Informer().HasSynced,
+		jobLister:               jobInformer.Lister(),
+		jobListerSync:           jobInformer.Informer().HasSynced,
+		cronJobLister:           cronJobInformer.Lister(),
+		cronJobListerSync:       cronJobInformer.Informer().HasSynced,
+		pvcLister:               pvcInformer.Lister(),
+		pvcListerSync:           pvcInformer.Informer().HasSynced,
 		groupID:                 rand.Int31(),
 		hostName:                ctx.Hostname,
 		clusterName:             ctx.ClusterName,
@@ -167,6 +217,11 @@ func (o *Controller) Run(stopCh <-chan struct{}) {
 		o.processDeploys,
 		o.processServices,
 		o.processNodes,
+		skipUntilSynced(o.stsListerSync, o.processStatefulSets),
+		skipUntilSynced(o.dsListerSync, o.processDaemonSets),
+		skipUntilSynced(o.jobListerSync, o.processJobs),
+		skipUntilSynced(o.cronJobListerSync, o.processCronJobs),
+		skipUntilSynced(o.pvcListerSync, o.processPersistentVolumeClaims),
 	}
 
 	spreadProcessors(processors, 2*time.Second, 10*time.Second, stopCh)
@@ -176,6 +231,15 @@ func (o *Controller) Run(stopCh <-chan struct{}) {
 	o.forwarder.Stop()
 }
 
+// skipUntilSynced returns a processor which is skipped as long as the informer of its kind hasn't synced
+func skipUntilSynced(synced cache.InformerSynced, process func()) func() {
+	return func() {
+		if synced() {
+			process()
+		}
+	}
+}
+
 func (o *Controller) processDeploys() {
 	if !o.isLeaderFunc() {
 		return
@@ -317,6 +381,146 @@ func (o *Controller) processNodes() {
 	o.sendMessages(messages, forwarder.PayloadTypeNode)
 }
 
+func (o *Controller) processStatefulSets() {
+	if !o.isLeaderFunc() {
+		return
+	}
+
+	stsList, err := o.stsLister.List(labels.Everything())
+	if err != nil {
+		log.Errorf("Unable to list stateful sets: %s", err)
+		return
+	}
+
+	msg, err := processStatefulSetList(stsList, atomic.AddInt32(&o.groupID, 1), o.processConfig, o.clusterName, o.clusterID, o.isScrubbingEnabled)
+	if err != nil {
+		log.Errorf("Unable to process stateful set list: %v", err)
+		return
+	}
+
+	stats := CheckStats{
+		CacheHits: len(stsList) - len(msg),
+		CacheMiss: len(msg),
+		NodeType:  orchestrator.K8sStatefulSet,
+	}
+
+	orchestrator.KubernetesResourceCache.Set(BuildStatsKey(orchestrator.K8sStatefulSet), stats, orchestrator.NoExpiration)
+
+	o.sendMessages(msg, forwarder.PayloadTypeStatefulSet)
+}
+
+func (o *Controller) processDaemonSets() {
+	if !o.isLeaderFunc() {
+		return
+	}
+
+	dsList, err := o.dsLister.List(labels.Everything())
+	if err != nil {
+		log.Errorf("Unable to list daemon sets: %s", err)
+		return
+	}
+
+	msg, err := processDaemonSetList(dsList, atomic.AddInt32(&o.groupID, 1), o.processConfig, o.clusterName, o.clusterID, o.isScrubbingEnabled)
+	if err != nil {
+		log.Errorf("Unable to process daemon set list: %v", err)
+		return
+	}
+
+	stats := CheckStats{
+		CacheHits: len(dsList) - len(msg),
+		CacheMiss: len(msg),
+		NodeType:  orchestrator.K8sDaemonSet,
+	}
+
+	orchestrator.KubernetesResourceCache.Set(BuildStatsKey(orchestrator.K8sDaemonSet), stats, orchestrator.NoExpiration)
+
+	o.sendMessages(msg, forwarder.PayloadTypeDaemonSet)
+}
+
+func (o *Controller) processJobs() {
+	if !o.isLeaderFunc() {
+		return
+	}
+
+	jobList, err := o.jobLister.List(labels.Everything())
+	if err != nil {
+		log.Errorf("Unable to list jobs: %s", err)
+		return
+	}
+
+	msg, err := processJobList(jobList, atomic.AddInt32(&o.groupID, 1), o.processConfig, o.clusterName, o.clusterID, o.isScrubbingEnabled)
+	if err != nil {
+		log.Errorf("Unable to process job list: %v", err)
+		return
+	}
+
+	stats := CheckStats{
+		CacheHits: len(jobList) - len(msg),
+		CacheMiss: len(msg),
+		NodeType:  orchestrator.K8sJob,
+	}
+
+	orchestrator.KubernetesResourceCache.Set(BuildStatsKey(orchestrator.K8sJob), stats, orchestrator.NoExpiration)
+
+	o.sendMessages(msg, forwarder.PayloadTypeJob)
+}
+
+func (o *Controller) processCronJobs() {
+	if !o.isLeaderFunc() {
+		return
+	}
+
+	cronJobList, err := o.cronJobLister.List(labels.Everything())
+	if err != nil {
+		log.Errorf("Unable to list cron jobs: %s", err)
+		return
+	}
+
+	msg, err := processCronJobList(cronJobList, atomic.AddInt32(&o.groupID, 1), o.processConfig, o.clusterName, o.clusterID, o.isScrubbingEnabled)
+	if err != nil {
+		log.Errorf("Unable to process cron job list: %v", err)
+		return
+	}
+
+	stats := CheckStats{
+		CacheHits: len(cronJobList) - len(msg),
+		CacheMiss: len(msg),
+		NodeType:  orchestrator.K8sCronJob,
+	}
+
+	orchestrator.KubernetesResourceCache.Set(BuildStatsKey(orchestrator.K8sCronJob), stats, orchestrator.NoExpiration)
+
+	o.sendMessages(msg, forwarder.PayloadTypeCronJob)
+}
+
+func (o *Controller) processPersistentVolumeClaims() {
+	if !o.isLeaderFunc() {
+		return
+	}
+
+	pvcList, err := o.pvcLister.List(labels.Everything())
+	if err != nil {
+		log.Errorf("Unable to list persistent volume claims: %s", err)
+		return
+	}
+
+	msg, err := processPersistentVolumeClaimList(pvcList, atomic.AddInt32(&o.groupID, 1), o.processConfig, o.clusterName, o.clusterID)
+	if err != nil {
+		log.Errorf("Unable to process persistent volume claim list: %v", err)
+		return
+	}
+
+	stats := CheckStats{
+		CacheHits: len(pvcList) - len(msg),
+		CacheMiss: len(msg),
+		NodeType:  orchestrator.K8sPersistentVolumeClaim,
+	}
+
+	orchestrator.KubernetesResourceCache.Set(BuildStatsKey(orchestrator.K8sPersistentVolumeClaim), stats, orchestrator.NoExpiration)
+
+	o.sendMessages(msg, forwarder.PayloadTypePersistentVolumeClaim)
+}
+
 func (o *Controller) sendMessages(msg []model.MessageBody, payloadType string) {
 	for _, m := range msg {
 		extraHeaders := make(http.Header)
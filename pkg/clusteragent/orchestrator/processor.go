@@ -19,6 +19,8 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	v1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -309,3 +311,368 @@ func chunkNodes(nodes []*model.Node, chunkCount, chunkSize int) [][]*model.Node
 
 	return chunks
 }
+
+// processStatefulSetList process a stateful set list into process messages.
+func processStatefulSetList(stsList []*v1.StatefulSet, groupID int32, cfg *config.AgentConfig, clusterName string, clusterID string, withScrubbing bool) ([]model.MessageBody, error) {
+	start := time.Now()
+	stsMsgs := make([]*model.StatefulSet, 0, len(stsList))
+
+	for i := 0; i < len(stsList); i++ {
+		statefulSet := stsList[i]
+		if orchestrator.SkipKubernetesResource(statefulSet.UID, statefulSet.ResourceVersion, orchestrator.K8sStatefulSet) {
+			continue
+		}
+
+		stsModel := extractStatefulSet(statefulSet)
+
+		// scrub & generate YAML
+		if withScrubbing {
+			for c := 0; c < len(statefulSet.Spec.Template.Spec.InitContainers); c++ {
+				orchestrator.ScrubContainer(&statefulSet.Spec.Template.Spec.InitContainers[c], cfg)
+			}
+			for c := 0; c < len(statefulSet.Spec.Template.Spec.Containers); c++ {
+				orchestrator.ScrubContainer(&statefulSet.Spec.Template.Spec.Containers[c], cfg)
+			}
+		}
+
+		// k8s objects only have json "omitempty" annotations
+		// and marshalling is more performant than YAML
+		jsonStatefulSet, err := jsoniter.Marshal(statefulSet)
+		if err != nil {
+			log.Debugf("Could not marshal stateful set to JSON: %s", err)
+			continue
+		}
+		stsModel.Yaml = jsonStatefulSet
+
+		stsMsgs = append(stsMsgs, stsModel)
+	}
+
+	groupSize := len(stsMsgs) / cfg.MaxPerMessage
+	if len(stsMsgs)%cfg.MaxPerMessage != 0 {
+		groupSize++
+	}
+	chunked := chunkStatefulSets(stsMsgs, groupSize, cfg.MaxPerMessage)
+	messages := make([]model.MessageBody, 0, groupSize)
+	for i := 0; i < groupSize; i++ {
+		messages = append(messages, &model.CollectorStatefulSet{
+			ClusterName:  clusterName,
+			StatefulSets: chunked[i],
+			GroupId:      groupID,
+			GroupSize:    int32(groupSize),
+			ClusterId:    clusterID,
+		})
+	}
+
+	log.Debugf("Collected & enriched %d out of %d stateful sets in %s", len(stsMsgs), len(stsList), time.Now().Sub(start))
+	return messages, nil
+}
+
+// chunkStatefulSets chunks the given list of stateful sets, honoring the given chunk count and size.
+// The last chunk may be smaller than the others.
+func chunkStatefulSets(statefulSets []*model.StatefulSet, chunkCount, chunkSize int) [][]*model.StatefulSet {
+	chunks := make([][]*model.StatefulSet, 0, chunkCount)
+
+	for c := 1; c <= chunkCount; c++ {
+		var (
+			chunkStart = chunkSize * (c - 1)
+			chunkEnd   = chunkSize * (c)
+		)
+		// last chunk may be smaller than the chunk size
+		if c == chunkCount {
+			chunkEnd = len(statefulSets)
+		}
+		chunks = append(chunks, statefulSets[chunkStart:chunkEnd])
+	}
+
+	return chunks
+}
+
+// processDaemonSetList process a daemon set list into process messages.
+func processDaemonSetList(dsList []*v1.DaemonSet, groupID int32, cfg *config.AgentConfig, clusterName string, clusterID string, withScrubbing bool) ([]model.MessageBody, error) {
+	start := time.Now()
+	dsMsgs := make([]*model.DaemonSet, 0, len(dsList))
+
+	for i := 0; i < len(dsList); i++ {
+		daemonSet := dsList[i]
+		if orchestrator.SkipKubernetesResource(daemonSet.UID, daemonSet.ResourceVersion, orchestrator.K8sDaemonSet) {
+			continue
+		}
+
+		dsModel := extractDaemonSet(daemonSet)
+
+		// scrub & generate YAML
+		if withScrubbing {
+			for c := 0; c < len(daemonSet.Spec.Template.Spec.InitContainers); c++ {
+				orchestrator.ScrubContainer(&daemonSet.Spec.Template.Spec.InitContainers[c], cfg)
+			}
+			for c := 0; c < len(daemonSet.Spec.Template.Spec.Containers); c++ {
+				orchestrator.ScrubContainer(&daemonSet.Spec.Template.Spec.Containers[c], cfg)
+			}
+		}
+
+		// k8s objects only have json "omitempty" annotations
+		// and marshalling is more performant than YAML
+		jsonDaemonSet, err := jsoniter.Marshal(daemonSet)
+		if err != nil {
+			log.Debugf("Could not marshal daemon set to JSON: %s", err)
+			continue
+		}
+		dsModel.Yaml = jsonDaemonSet
+
+		dsMsgs = append(dsMsgs, dsModel)
+	}
+
+	groupSize := len(dsMsgs) / cfg.MaxPerMessage
+	if len(dsMsgs)%cfg.MaxPerMessage != 0 {
+		groupSize++
+	}
+	chunked := chunkDaemonSets(dsMsgs, groupSize, cfg.MaxPerMessage)
+	messages := make([]model.MessageBody, 0, groupSize)
+	for i := 0; i < groupSize; i++ {
+		messages = append(messages, &model.CollectorDaemonSet{
+			ClusterName: clusterName,
+			DaemonSets:  chunked[i],
+			GroupId:     groupID,
+			GroupSize:   int32(groupSize),
+			ClusterId:   clusterID,
+		})
+	}
+
+	log.Debugf("Collected & enriched %d out of %d daemon sets in %s", len(dsMsgs), len(dsList), time.Now().Sub(start))
+	return messages, nil
+}
+
+// chunkDaemonSets chunks the given list of daemon sets, honoring the given chunk count and size.
+// The last chunk may be smaller than the others.
+func chunkDaemonSets(daemonSets []*model.DaemonSet, chunkCount, chunkSize int) [][]*model.DaemonSet {
+	chunks := make([][]*model.DaemonSet, 0, chunkCount)
+
+	for c := 1; c <= chunkCount; c++ {
+		var (
+			chunkStart = chunkSize * (c - 1)
+			chunkEnd   = chunkSize * (c)
+		)
+		// last chunk may be smaller than the chunk size
+		if c == chunkCount {
+			chunkEnd = len(daemonSets)
+		}
+		chunks = append(chunks, daemonSets[chunkStart:chunkEnd])
+	}
+
+	return chunks
+}
+
+// processJobList process a job list into process messages.
+func processJobList(jobList []*batchv1.Job, groupID int32, cfg *config.AgentConfig, clusterName string, clusterID string, withScrubbing bool) ([]model.MessageBody, error) {
+	start := time.Now()
+	jobMsgs := make([]*model.Job, 0, len(jobList))
+
+	for i := 0; i < len(jobList); i++ {
+		j := jobList[i]
+		if orchestrator.SkipKubernetesResource(j.UID, j.ResourceVersion, orchestrator.K8sJob) {
+			continue
+		}
+
+		jobModel := extractJob(j)
+
+		// scrub & generate YAML
+		if withScrubbing {
+			for c := 0; c < len(j.Spec.Template.Spec.InitContainers); c++ {
+				orchestrator.ScrubContainer(&j.Spec.Template.Spec.InitContainers[c], cfg)
+			}
+			for c := 0; c < len(j.Spec.Template.Spec.Containers); c++ {
+				orchestrator.ScrubContainer(&j.Spec.Template.Spec.Containers[c], cfg)
+			}
+		}
+
+		// k8s objects only have json "omitempty" annotations
+		// and marshalling is more performant than YAML
+		jsonJob, err := jsoniter.Marshal(j)
+		if err != nil {
+			log.Debugf("Could not marshal job to JSON: %s", err)
+			continue
+		}
+		jobModel.Yaml = jsonJob
+
+		jobMsgs = append(jobMsgs, jobModel)
+	}
+
+	groupSize := len(jobMsgs) / cfg.MaxPerMessage
+	if len(jobMsgs)%cfg.MaxPerMessage != 0 {
+		groupSize++
+	}
+	chunked := chunkJobs(jobMsgs, groupSize, cfg.MaxPerMessage)
+	messages := make([]model.MessageBody, 0, groupSize)
+	for i := 0; i < groupSize; i++ {
+		messages = append(messages, &model.CollectorJob{
+			ClusterName: clusterName,
+			Jobs:        chunked[i],
+			GroupId:     groupID,
+			GroupSize:   int32(groupSize),
+			ClusterId:   clusterID,
+		})
+	}
+
+	log.Debugf("Collected & enriched %d out of %d jobs in %s", len(jobMsgs), len(jobList), time.Now().Sub(start))
+	return messages, nil
+}
+
+// chunkJobs chunks the given list of jobs, honoring the given chunk count and size.
+// The last chunk may be smaller than the others.
+func chunkJobs(jobs []*model.Job, chunkCount, chunkSize int) [][]*model.Job {
+	chunks := make([][]*model.Job, 0, chunkCount)
+
+	for c := 1; c <= chunkCount; c++ {
+		var (
+			chunkStart = chunkSize * (c - 1)
+			chunkEnd   = chunkSize * (c)
+		)
+		// last chunk may be smaller than the chunk size
+		if c == chunkCount {
+			chunkEnd = len(jobs)
+		}
+		chunks = append(chunks, jobs[chunkStart:chunkEnd])
+	}
+
+	return chunks
+}
+
+// processCronJobList process a cron job list into process messages.
+func processCronJobList(cronJobList []*batchv1beta1.CronJob, groupID int32, cfg *config.AgentConfig, clusterName string, clusterID string, withScrubbing bool) ([]model.MessageBody, error) {
+	start := time.Now()
+	cronJobMsgs := make([]*model.CronJob, 0, len(cronJobList))
+
+	for i := 0; i < len(cronJobList); i++ {
+		cj := cronJobList[i]
+		if orchestrator.SkipKubernetesResource(cj.UID, cj.ResourceVersion, orchestrator.K8sCronJob) {
+			continue
+		}
+
+		cronJobModel := extractCronJob(cj)
+
+		// scrub & generate YAML
+		if withScrubbing {
+			for c := 0; c < len(cj.Spec.JobTemplate.Spec.Template.Spec.InitContainers); c++ {
+				orchestrator.ScrubContainer(&cj.Spec.JobTemplate.Spec.Template.Spec.InitContainers[c], cfg)
+			}
+			for c := 0; c < len(cj.Spec.JobTemplate.Spec.Template.Spec.Containers); c++ {
+				orchestrator.ScrubContainer(&cj.Spec.JobTemplate.Spec.Template.Spec.Containers[c], cfg)
+			}
+		}
+
+		// k8s objects only have json "omitempty" annotations
+		// and marshalling is more performant than YAML
+		jsonCronJob, err := jsoniter.Marshal(cj)
+		if err != nil {
+			log.Debugf("Could not marshal cron job to JSON: %s", err)
+			continue
+		}
+		cronJobModel.Yaml = jsonCronJob
+
+		cronJobMsgs = append(cronJobMsgs, cronJobModel)
+	}
+
+	groupSize := len(cronJobMsgs) / cfg.MaxPerMessage
+	if len(cronJobMsgs)%cfg.MaxPerMessage != 0 {
+		groupSize++
+	}
+	chunked := chunkCronJobs(cronJobMsgs, groupSize, cfg.MaxPerMessage)
+	messages := make([]model.MessageBody, 0, groupSize)
+	for i := 0; i < groupSize; i++ {
+		messages = append(messages, &model.CollectorCronJob{
+			ClusterName: clusterName,
+			CronJobs:    chunked[i],
+			GroupId:     groupID,
+			GroupSize:   int32(groupSize),
+			ClusterId:   clusterID,
+		})
+	}
+
+	log.Debugf("Collected & enriched %d out of %d cron jobs in %s", len(cronJobMsgs), len(cronJobList), time.Now().Sub(start))
+	return messages, nil
+}
+
+// chunkCronJobs chunks the given list of cron jobs, honoring the given chunk count and size.
+// The last chunk may be smaller than the others.
+func chunkCronJobs(cronJobs []*model.CronJob, chunkCount, chunkSize int) [][]*model.CronJob {
+	chunks := make([][]*model.CronJob, 0, chunkCount)
+
+	for c := 1; c <= chunkCount; c++ {
+		var (
+			chunkStart = chunkSize * (c - 1)
+			chunkEnd   = chunkSize * (c)
+		)
+		// last chunk may be smaller than the chunk size
+		if c == chunkCount {
+			chunkEnd = len(cronJobs)
+		}
+		chunks = append(chunks, cronJobs[chunkStart:chunkEnd])
+	}
+
+	return chunks
+}
+
+// processPersistentVolumeClaimList process a persistent volume claim list into process messages.
+func processPersistentVolumeClaimList(pvcList []*corev1.PersistentVolumeClaim, groupID int32, cfg *config.AgentConfig, clusterName string, clusterID string) ([]model.MessageBody, error) {
+	start := time.Now()
+	pvcMsgs := make([]*model.PersistentVolumeClaim, 0, len(pvcList))
+
+	for i := 0; i < len(pvcList); i++ {
+		claim := pvcList[i]
+		if orchestrator.SkipKubernetesResource(claim.UID, claim.ResourceVersion, orchestrator.K8sPersistentVolumeClaim) {
+			continue
+		}
+
+		pvcModel := extractPersistentVolumeClaim(claim)
+
+		// k8s objects only have json "omitempty" annotations
+		// and marshalling is more performant than YAML
+		jsonPersistentVolumeClaim, err := jsoniter.Marshal(claim)
+		if err != nil {
+			log.Debugf("Could not marshal persistent volume claim to JSON: %s", err)
+			continue
+		}
+		pvcModel.Yaml = jsonPersistentVolumeClaim
+
+		pvcMsgs = append(pvcMsgs, pvcModel)
+	}
+
+	groupSize := len(pvcMsgs) / cfg.MaxPerMessage
+	if len(pvcMsgs)%cfg.MaxPerMessage != 0 {
+		groupSize++
+	}
+	chunked := chunkPersistentVolumeClaims(pvcMsgs, groupSize, cfg.MaxPerMessage)
+	messages := make([]model.MessageBody, 0, groupSize)
+	for i := 0; i < groupSize; i++ {
+		messages = append(messages, &model.CollectorPersistentVolumeClaim{
+			ClusterName:            clusterName,
+			PersistentVolumeClaims: chunked[i],
+			GroupId:                groupID,
+			GroupSize:              int32(groupSize),
+			ClusterId:              clusterID,
+		})
+	}
+
+	log.Debugf("Collected & enriched %d out of %d persistent volume claims in %s", len(pvcMsgs), len(pvcList), time.Now().Sub(start))
+	return messages, nil
+}
+
+// chunkPersistentVolumeClaims chunks the given list of persistent volume claims, honoring the given chunk count and size.
+// The last chunk may be smaller than the others.
+func chunkPersistentVolumeClaims(pvcs []*model.PersistentVolumeClaim, chunkCount, chunkSize int) [][]*model.PersistentVolumeClaim {
+	chunks := make([][]*model.PersistentVolumeClaim, 0, chunkCount)
+
+	for c := 1; c <= chunkCount; c++ {
+		var (
+			chunkStart = chunkSize * (c - 1)
+			chunkEnd   = chunkSize * (c)
+		)
+		// last chunk may be smaller than the chunk size
+		if c == chunkCount {
+			chunkEnd = len(pvcs)
+		}
+		chunks = append(chunks, pvcs[chunkStart:chunkEnd])
+	}
+
+	return chunks
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/orchestrator"
 
 	v1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -375,3 +377,244 @@ func findNodeRoles(nodeLabels map[string]string) []string {
 	}
 	return roles.List()
 }
+
+func extractStatefulSet(sts *v1.StatefulSet) *model.StatefulSet {
+	statefulSet := model.StatefulSet{
+		Metadata: orchestrator.ExtractMetadata(&sts.ObjectMeta),
+		Spec: &model.StatefulSetSpec{
+			ServiceName:         sts.Spec.ServiceName,
+			PodManagementPolicy: string(sts.Spec.PodManagementPolicy),
+			UpdateStrategy:      string(sts.Spec.UpdateStrategy.Type),
+		},
+		Status: &model.StatefulSetStatus{
+			Replicas:        sts.Status.Replicas,
+			ReadyReplicas:   sts.Status.ReadyReplicas,
+			CurrentReplicas: sts.Status.CurrentReplicas,
+			UpdatedReplicas: sts.Status.UpdatedReplicas,
+		},
+	}
+	// spec
+	statefulSet.Spec.DesiredReplicas = 1 // default
+	if sts.Spec.Replicas != nil {
+		statefulSet.Spec.DesiredReplicas = *sts.Spec.Replicas
+	}
+	if sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		statefulSet.Spec.Partition = *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	if sts.Spec.Selector != nil {
+		statefulSet.Spec.Selectors = extractLabelSelector(sts.Spec.Selector)
+	}
+
+	return &statefulSet
+}
+
+func extractDaemonSet(ds *v1.DaemonSet) *model.DaemonSet {
+	daemonSet := model.DaemonSet{
+		Metadata: orchestrator.ExtractMetadata(&ds.ObjectMeta),
+		Spec: &model.DaemonSetSpec{
+			DeploymentStrategy: string(ds.Spec.UpdateStrategy.Type),
+			MinReadySeconds:    ds.Spec.MinReadySeconds,
+		},
+		Status: &model.DaemonSetStatus{
+			CurrentNumberScheduled: ds.Status.CurrentNumberScheduled,
+			NumberMisscheduled:     ds.Status.NumberMisscheduled,
+			DesiredNumberScheduled: ds.Status.DesiredNumberScheduled,
+			NumberReady:            ds.Status.NumberReady,
+			UpdatedNumberScheduled: ds.Status.UpdatedNumberScheduled,
+			NumberAvailable:        ds.Status.NumberAvailable,
+			NumberUnavailable:      ds.Status.NumberUnavailable,
+		},
+	}
+	// spec
+	if ds.Spec.UpdateStrategy.Type == v1.RollingUpdateDaemonSetStrategyType && ds.Spec.UpdateStrategy.RollingUpdate != nil {
+		if ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable != nil {
+			daemonSet.Spec.MaxUnavailable = ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable.String()
+		}
+	}
+	if ds.Spec.RevisionHistoryLimit != nil {
+		daemonSet.Spec.RevisionHistoryLimit = *ds.Spec.RevisionHistoryLimit
+	}
+	if ds.Spec.Selector != nil {
+		daemonSet.Spec.Selectors = extractLabelSelector(ds.Spec.Selector)
+	}
+
+	return &daemonSet
+}
+
+func extractJob(j *batchv1.Job) *model.Job {
+	job := model.Job{
+		Metadata: orchestrator.ExtractMetadata(&j.ObjectMeta),
+		Spec: &model.JobSpec{
+			Parallelism: 1, // default
+			Completions: 1, // default
+		},
+		Status: &model.JobStatus{
+			Active:           j.Status.Active,
+			Succeeded:        j.Status.Succeeded,
+			Failed:           j.Status.Failed,
+			ConditionMessage: extractJobConditionMessage(j.Status.Conditions),
+		},
+	}
+	// spec
+	if j.Spec.Parallelism != nil {
+		job.Spec.Parallelism = *j.Spec.Parallelism
+	}
+	if j.Spec.Completions != nil {
+		job.Spec.Completions = *j.Spec.Completions
+	}
+	if j.Spec.ActiveDeadlineSeconds != nil {
+		job.Spec.ActiveDeadlineSeconds = *j.Spec.ActiveDeadlineSeconds
+	}
+	if j.Spec.BackoffLimit != nil {
+		job.Spec.BackoffLimit = *j.Spec.BackoffLimit
+	}
+	if j.Spec.ManualSelector != nil {
+		job.Spec.ManualSelector = *j.Spec.ManualSelector
+	}
+	if j.Spec.Selector != nil {
+		job.Spec.Selectors = extractLabelSelector(j.Spec.Selector)
+	}
+
+	// status
+	if j.Status.StartTime != nil {
+		job.Status.StartTime = j.Status.StartTime.Unix()
+	}
+	if j.Status.CompletionTime != nil {
+		job.Status.CompletionTime = j.Status.CompletionTime.Unix()
+	}
+
+	return &job
+}
+
+// extractJobConditionMessage returns the message of the condition the job failed with, if any
+func extractJobConditionMessage(conditions []batchv1.JobCondition) string {
+	for _, c := range conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return c.Message
+		}
+	}
+	return ""
+}
+
+func extractCronJob(cj *batchv1beta1.CronJob) *model.CronJob {
+	cronJob := model.CronJob{
+		Metadata: orchestrator.ExtractMetadata(&cj.ObjectMeta),
+		Spec: &model.CronJobSpec{
+			Schedule:          cj.Spec.Schedule,
+			ConcurrencyPolicy: string(cj.Spec.ConcurrencyPolicy),
+		},
+		Status: &model.CronJobStatus{},
+	}
+	// spec
+	if cj.Spec.StartingDeadlineSeconds != nil {
+		cronJob.Spec.StartingDeadlineSeconds = *cj.Spec.StartingDeadlineSeconds
+	}
+	if cj.Spec.Suspend != nil {
+		cronJob.Spec.Suspend = *cj.Spec.Suspend
+	}
+	if cj.Spec.SuccessfulJobsHistoryLimit != nil {
+		cronJob.Spec.SuccessfulJobsHistoryLimit = *cj.Spec.SuccessfulJobsHistoryLimit
+	}
+	if cj.Spec.FailedJobsHistoryLimit != nil {
+		cronJob.Spec.FailedJobsHistoryLimit = *cj.Spec.FailedJobsHistoryLimit
+	}
+
+	// status
+	for _, job := range cj.Status.Active {
+		cronJob.Status.Active = append(cronJob.Status.Active, &model.ObjectReference{
+			Kind:            job.Kind,
+			Namespace:       job.Namespace,
+			Name:            job.Name,
+			Uid:             string(job.UID),
+			ApiVersion:      job.APIVersion,
+			ResourceVersion: job.ResourceVersion,
+			FieldPath:       job.FieldPath,
+		})
+	}
+	if cj.Status.LastScheduleTime != nil {
+		cronJob.Status.LastScheduleTime = cj.Status.LastScheduleTime.Unix()
+	}
+
+	return &cronJob
+}
+
+func extractPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) *model.PersistentVolumeClaim {
+	claim := model.PersistentVolumeClaim{
+		Metadata: orchestrator.ExtractMetadata(&pvc.ObjectMeta),
+		Spec: &model.PersistentVolumeClaimSpec{
+			AccessModes: extractAccessModes(pvc.Spec.AccessModes),
+			VolumeName:  pvc.Spec.VolumeName,
+		},
+		Status: &model.PersistentVolumeClaimStatus{
+			Phase:       string(pvc.Status.Phase),
+			AccessModes: extractAccessModes(pvc.Status.AccessModes),
+		},
+	}
+	// spec
+	if len(pvc.Spec.Resources.Limits) > 0 || len(pvc.Spec.Resources.Requests) > 0 {
+		claim.Spec.Resources = &model.ResourceRequirements{
+			Limits:   extractResourceList(pvc.Spec.Resources.Limits),
+			Requests: extractResourceList(pvc.Spec.Resources.Requests),
+		}
+	}
+	if pvc.Spec.Selector != nil {
+		claim.Spec.Selector = extractLabelSelector(pvc.Spec.Selector)
+	}
+	if pvc.Spec.StorageClassName != nil {
+		claim.Spec.StorageClassName = *pvc.Spec.StorageClassName
+	}
+	if pvc.Spec.VolumeMode != nil {
+		claim.Spec.VolumeMode = string(*pvc.Spec.VolumeMode)
+	}
+	if ds := pvc.Spec.DataSource; ds != nil {
+		claim.Spec.DataSource = &model.TypedLocalObjectReference{
+			Kind: ds.Kind,
+			Name: ds.Name,
+		}
+		if ds.APIGroup != nil {
+			claim.Spec.DataSource.ApiGroup = *ds.APIGroup
+		}
+	}
+
+	// status
+	claim.Status.Capacity = extractResourceList(pvc.Status.Capacity)
+	for _, c := range pvc.Status.Conditions {
+		condition := &model.PersistentVolumeClaimCondition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		}
+		if !c.LastProbeTime.IsZero() {
+			condition.LastProbeTime = c.LastProbeTime.Unix()
+		}
+		if !c.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = c.LastTransitionTime.Unix()
+		}
+		claim.Status.Conditions = append(claim.Status.Conditions, condition)
+	}
+
+	return &claim
+}
+
+func extractAccessModes(modes []corev1.PersistentVolumeAccessMode) []string {
+	if len(modes) == 0 {
+		return nil
+	}
+	accessModes := make([]string, 0, len(modes))
+	for _, m := range modes {
+		accessModes = append(accessModes, string(m))
+	}
+	return accessModes
+}
+
+func extractResourceList(resources corev1.ResourceList) map[string]int64 {
+	if len(resources) == 0 {
+		return nil
+	}
+	quantities := make(map[string]int64, len(resources))
+	for name, quantity := range resources {
+		quantities[name.String()] = quantity.Value()
+	}
+	return quantities
+}
@@ -16,6 +16,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -871,3 +873,369 @@ func TestComputeNodeStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractStatefulSet(t *testing.T) {
+	testInt32 := int32(3)
+	partition := int32(1)
+	tests := map[string]struct {
+		input    v1.StatefulSet
+		expected model.StatefulSet
+	}{
+		"full sts": {
+			input: v1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:       types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+					Name:      "sts",
+					Namespace: "namespace",
+				},
+				Spec: v1.StatefulSetSpec{
+					Replicas:            &testInt32,
+					ServiceName:         "service",
+					PodManagementPolicy: v1.ParallelPodManagement,
+					UpdateStrategy: v1.StatefulSetUpdateStrategy{
+						Type:          v1.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &v1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+					},
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "db"},
+					},
+				},
+				Status: v1.StatefulSetStatus{
+					Replicas:        3,
+					ReadyReplicas:   2,
+					CurrentReplicas: 3,
+					UpdatedReplicas: 1,
+				},
+			}, expected: model.StatefulSet{
+				Metadata: &model.Metadata{
+					Name:      "sts",
+					Namespace: "namespace",
+					Uid:       "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+				},
+				Spec: &model.StatefulSetSpec{
+					DesiredReplicas:     3,
+					ServiceName:         "service",
+					PodManagementPolicy: "Parallel",
+					UpdateStrategy:      "RollingUpdate",
+					Partition:           1,
+					Selectors: []*model.LabelSelectorRequirement{
+						{
+							Key:      "app",
+							Operator: "In",
+							Values:   []string{"db"},
+						},
+					},
+				},
+				Status: &model.StatefulSetStatus{
+					Replicas:        3,
+					ReadyReplicas:   2,
+					CurrentReplicas: 3,
+					UpdatedReplicas: 1,
+				},
+			},
+		},
+		"empty sts": {
+			input: v1.StatefulSet{},
+			expected: model.StatefulSet{
+				Metadata: &model.Metadata{},
+				Spec:     &model.StatefulSetSpec{DesiredReplicas: 1},
+				Status:   &model.StatefulSetStatus{},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, extractStatefulSet(&tc.input))
+		})
+	}
+}
+
+func TestExtractDaemonSet(t *testing.T) {
+	testInt32 := int32(5)
+	maxUnavailable := intstr.FromString("10%")
+	tests := map[string]struct {
+		input    v1.DaemonSet
+		expected model.DaemonSet
+	}{
+		"full ds": {
+			input: v1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ds",
+					Namespace: "namespace",
+				},
+				Spec: v1.DaemonSetSpec{
+					MinReadySeconds:      10,
+					RevisionHistoryLimit: &testInt32,
+					UpdateStrategy: v1.DaemonSetUpdateStrategy{
+						Type:          v1.RollingUpdateDaemonSetStrategyType,
+						RollingUpdate: &v1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+					},
+				},
+				Status: v1.DaemonSetStatus{
+					CurrentNumberScheduled: 3,
+					DesiredNumberScheduled: 3,
+					NumberReady:            2,
+					UpdatedNumberScheduled: 3,
+					NumberAvailable:        2,
+					NumberUnavailable:      1,
+				},
+			}, expected: model.DaemonSet{
+				Metadata: &model.Metadata{
+					Name:      "ds",
+					Namespace: "namespace",
+				},
+				Spec: &model.DaemonSetSpec{
+					DeploymentStrategy:   "RollingUpdate",
+					MaxUnavailable:       "10%",
+					MinReadySeconds:      10,
+					RevisionHistoryLimit: 5,
+				},
+				Status: &model.DaemonSetStatus{
+					CurrentNumberScheduled: 3,
+					DesiredNumberScheduled: 3,
+					NumberReady:            2,
+					UpdatedNumberScheduled: 3,
+					NumberAvailable:        2,
+					NumberUnavailable:      1,
+				},
+			},
+		},
+		"empty ds": {
+			input: v1.DaemonSet{},
+			expected: model.DaemonSet{
+				Metadata: &model.Metadata{},
+				Spec:     &model.DaemonSetSpec{},
+				Status:   &model.DaemonSetStatus{},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, extractDaemonSet(&tc.input))
+		})
+	}
+}
+
+func TestExtractJob(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2014, time.January, 15, 0, 0, 0, 0, time.UTC)) // 1389744000
+	backoffLimit := int32(6)
+	tests := map[string]struct {
+		input    batchv1.Job
+		expected model.Job
+	}{
+		"failed job": {
+			input: batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job",
+					Namespace: "namespace",
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+				},
+				Status: batchv1.JobStatus{
+					StartTime: &startTime,
+					Failed:    6,
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:    batchv1.JobFailed,
+							Status:  corev1.ConditionTrue,
+							Reason:  "BackoffLimitExceeded",
+							Message: "Job has reached the specified backoff limit",
+						},
+					},
+				},
+			}, expected: model.Job{
+				Metadata: &model.Metadata{
+					Name:      "job",
+					Namespace: "namespace",
+				},
+				Spec: &model.JobSpec{
+					Parallelism:  1,
+					Completions:  1,
+					BackoffLimit: 6,
+				},
+				Status: &model.JobStatus{
+					ConditionMessage: "Job has reached the specified backoff limit",
+					StartTime:        1389744000,
+					Failed:           6,
+				},
+			},
+		},
+		"empty job": {
+			input: batchv1.Job{},
+			expected: model.Job{
+				Metadata: &model.Metadata{},
+				Spec:     &model.JobSpec{Parallelism: 1, Completions: 1},
+				Status:   &model.JobStatus{},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, extractJob(&tc.input))
+		})
+	}
+}
+
+func TestExtractCronJob(t *testing.T) {
+	lastSchedule := metav1.NewTime(time.Date(2014, time.January, 15, 0, 0, 0, 0, time.UTC)) // 1389744000
+	suspend := true
+	historyLimit := int32(3)
+	tests := map[string]struct {
+		input    batchv1beta1.CronJob
+		expected model.CronJob
+	}{
+		"full cron job": {
+			input: batchv1beta1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cronjob",
+					Namespace: "namespace",
+				},
+				Spec: batchv1beta1.CronJobSpec{
+					Schedule:                   "*/5 * * * *",
+					ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+					Suspend:                    &suspend,
+					SuccessfulJobsHistoryLimit: &historyLimit,
+				},
+				Status: batchv1beta1.CronJobStatus{
+					Active: []corev1.ObjectReference{
+						{
+							Kind:       "Job",
+							Namespace:  "namespace",
+							Name:       "cronjob-1389744000",
+							UID:        types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6"),
+							APIVersion: "batch/v1",
+						},
+					},
+					LastScheduleTime: &lastSchedule,
+				},
+			}, expected: model.CronJob{
+				Metadata: &model.Metadata{
+					Name:      "cronjob",
+					Namespace: "namespace",
+				},
+				Spec: &model.CronJobSpec{
+					Schedule:                   "*/5 * * * *",
+					ConcurrencyPolicy:          "Forbid",
+					Suspend:                    true,
+					SuccessfulJobsHistoryLimit: 3,
+				},
+				Status: &model.CronJobStatus{
+					Active: []*model.ObjectReference{
+						{
+							Kind:       "Job",
+							Namespace:  "namespace",
+							Name:       "cronjob-1389744000",
+							Uid:        "e42e5adc-0749-11e8-a2b8-000c29dea4f6",
+							ApiVersion: "batch/v1",
+						},
+					},
+					LastScheduleTime: 1389744000,
+				},
+			},
+		},
+		"empty cron job": {
+			input: batchv1beta1.CronJob{},
+			expected: model.CronJob{
+				Metadata: &model.Metadata{},
+				Spec:     &model.CronJobSpec{},
+				Status:   &model.CronJobStatus{},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, extractCronJob(&tc.input))
+		})
+	}
+}
+
+func TestExtractPersistentVolumeClaim(t *testing.T) {
+	storageClass := "standard"
+	volumeMode := corev1.PersistentVolumeFilesystem
+	tests := map[string]struct {
+		input    corev1.PersistentVolumeClaim
+		expected model.PersistentVolumeClaim
+	}{
+		"bound pvc": {
+			input: corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pvc",
+					Namespace: "namespace",
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("2Gi"),
+						},
+					},
+					VolumeName:       "volume",
+					StorageClassName: &storageClass,
+					VolumeMode:       &volumeMode,
+					DataSource: &corev1.TypedLocalObjectReference{
+						Kind: "PersistentVolumeClaim",
+						Name: "source",
+					},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Phase:       corev1.ClaimBound,
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("2Gi"),
+					},
+					Conditions: []corev1.PersistentVolumeClaimCondition{
+						{
+							Type:    corev1.PersistentVolumeClaimResizing,
+							Status:  corev1.ConditionTrue,
+							Message: "resizing",
+						},
+					},
+				},
+			}, expected: model.PersistentVolumeClaim{
+				Metadata: &model.Metadata{
+					Name:      "pvc",
+					Namespace: "namespace",
+				},
+				Spec: &model.PersistentVolumeClaimSpec{
+					AccessModes: []string{"ReadWriteOnce"},
+					Resources: &model.ResourceRequirements{
+						Requests: map[string]int64{"storage": 2147483648},
+					},
+					VolumeName:       "volume",
+					StorageClassName: "standard",
+					VolumeMode:       "Filesystem",
+					DataSource: &model.TypedLocalObjectReference{
+						Kind: "PersistentVolumeClaim",
+						Name: "source",
+					},
+				},
+				Status: &model.PersistentVolumeClaimStatus{
+					Phase:       "Bound",
+					AccessModes: []string{"ReadWriteOnce"},
+					Capacity:    map[string]int64{"storage": 2147483648},
+					Conditions: []*model.PersistentVolumeClaimCondition{
+						{
+							Type:    "Resizing",
+							Status:  "True",
+							Message: "resizing",
+						},
+					},
+				},
+			},
+		},
+		"empty pvc": {
+			input: corev1.PersistentVolumeClaim{},
+			expected: model.PersistentVolumeClaim{
+				Metadata: &model.Metadata{},
+				Spec:     &model.PersistentVolumeClaimSpec{},
+				Status:   &model.PersistentVolumeClaimStatus{},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, &tc.expected, extractPersistentVolumeClaim(&tc.input))
+		})
+	}
+}
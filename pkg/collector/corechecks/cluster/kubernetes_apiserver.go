@@ -24,9 +24,11 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/clustername"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/eventlogs"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -52,6 +54,10 @@ type KubeASConfig struct {
 	MaxEventCollection       int      `yaml:"max_events_per_run"`
 	LeaderSkip               bool     `yaml:"skip_leader_election"`
 	ResyncPeriodEvents       int      `yaml:"kubernetes_event_resync_period_s"`
+	EventsAsLogs             bool     `yaml:"events_as_logs"`
+	EventLogsReasons         []string `yaml:"event_logs_reasons"`
+	EventLogsExcludedReasons []string `yaml:"event_logs_excluded_reasons"`
+	EventLogsDedupWindow     int      `yaml:"event_logs_dedup_window_s"`
 }
 
 // EventC holds the information pertaining to which event we collected last and when we last re-synced.
@@ -69,6 +75,7 @@ type KubeASCheck struct {
 	ac              *apiserver.APIClient
 	oshiftAPILevel  apiserver.OpenShiftAPILevel
 	providerIDCache *cache.Cache
+	eventLogsFilter *eventLogsFilter
 }
 
 func (c *KubeASConfig) parse(data []byte) error {
@@ -76,6 +83,8 @@ func (c *KubeASConfig) parse(data []byte) error {
 	c.CollectEvent = config.Datadog.GetBool("collect_kubernetes_events")
 	c.CollectOShiftQuotas = true
 	c.ResyncPeriodEvents = defaultResyncPeriodInSecond
+	c.EventsAsLogs = config.Datadog.GetBool("kubernetes_events_as_logs")
+	c.EventLogsDedupWindow = defaultEventLogsDedupWindow
 
 	return yaml.Unmarshal(data, c)
 }
@@ -114,6 +123,12 @@ func (k *KubeASCheck) Configure(config, initConfig integration.Data, source stri
 		k.instance.MaxEventCollection = maxEventCardinality
 	}
 	k.ignoredEvents = convertFilter(k.instance.FilteredEventTypes)
+	// The Cluster Agent does not run a logs pipeline to forward the event logs
+	if k.instance.EventsAsLogs && flavor.GetFlavor() == flavor.ClusterAgent {
+		log.Warn("Forwarding Kubernetes events as logs is not supported by the Cluster Agent, submitting them as Datadog events. Run the check on a cluster check runner with logs enabled instead.")
+		k.instance.EventsAsLogs = false
+	}
+	k.eventLogsFilter = newEventLogsFilter(k.instance.EventLogsReasons, k.instance.EventLogsExcludedReasons, k.instance.EventLogsDedupWindow)
 
 	return nil
 }
@@ -206,6 +221,20 @@ func (k *KubeASCheck) Run() error {
 		return err
 	}
 
+	// Forward the events as logs instead of Datadog events if enabled and the logs pipeline is running,
+	// the events that cannot be forwarded are submitted as Datadog events.
+	if k.instance.EventsAsLogs {
+		if eventlogs.IsConsumed() {
+			hostname, _ := util.GetHostname()
+			events = k.processEventLogs(events, clustername.GetClusterName(hostname))
+		} else {
+			k.Warn("Kubernetes events cannot be forwarded as logs, the logs pipeline is not running: submitting them as Datadog events. Is logs_enabled set?") //nolint:errcheck
+		}
+		if len(events) == 0 {
+			return nil
+		}
+	}
+
 	// Process the events to have a Datadog format.
 	err = k.processEvents(sender, events)
 	if err != nil {
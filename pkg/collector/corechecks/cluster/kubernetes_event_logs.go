@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	cache "github.com/patrickmn/go-cache"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/eventlogs"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const defaultEventLogsDedupWindow = 300 // value in seconds
+
+// eventLog is the structured content of a Kubernetes event forwarded as a log
+type eventLog struct {
+	Message         string              `json:"message"`
+	Reason          string              `json:"reason"`
+	Type            string              `json:"type"`
+	Count           int32               `json:"count"`
+	SourceComponent string              `json:"source_component,omitempty"`
+	SourceHost      string              `json:"source_host,omitempty"`
+	FirstTimestamp  string              `json:"first_timestamp,omitempty"`
+	LastTimestamp   string              `json:"last_timestamp,omitempty"`
+	InvolvedObject  eventInvolvedObject `json:"involved_object"`
+}
+
+// eventInvolvedObject is the object an event is about
+type eventInvolvedObject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// eventLogsFilter holds the reason filters and the deduplication state of event logs
+type eventLogsFilter struct {
+	reasons         map[string]struct{}
+	excludedReasons map[string]struct{}
+	dedupWindow     time.Duration
+	forwarded       *cache.Cache // event UIDs forwarded during the deduplication window
+}
+
+func newEventLogsFilter(reasons, excludedReasons []string, dedupWindowSeconds int) *eventLogsFilter {
+	f := &eventLogsFilter{
+		reasons:         make(map[string]struct{}, len(reasons)),
+		excludedReasons: make(map[string]struct{}, len(excludedReasons)),
+		dedupWindow:     time.Duration(dedupWindowSeconds) * time.Second,
+	}
+	for _, r := range reasons {
+		f.reasons[r] = struct{}{}
+	}
+	for _, r := range excludedReasons {
+		f.excludedReasons[r] = struct{}{}
+	}
+	if f.dedupWindow > 0 {
+		f.forwarded = cache.New(f.dedupWindow, 2*f.dedupWindow)
+	}
+	return f
+}
+
+// shouldForward returns whether an event must be forwarded as a log.
+// An event whose count is incremented is only forwarded again once the deduplication window
+// elapsed since it was marked as forwarded.
+func (f *eventLogsFilter) shouldForward(event *v1.Event) bool {
+	if _, found := f.excludedReasons[event.Reason]; found {
+		return false
+	}
+	if len(f.reasons) > 0 {
+		if _, found := f.reasons[event.Reason]; !found {
+			return false
+		}
+	}
+	if f.forwarded == nil {
+		return true
+	}
+	if _, found := f.forwarded.Get(eventDedupKey(event)); found {
+		log.Tracef("Event %s/%s was already forwarded, skipping count %d", event.Namespace, event.Name, event.Count)
+		return false
+	}
+	return true
+}
+
+// markForwarded records that an event was forwarded as a log, for the deduplication window
+func (f *eventLogsFilter) markForwarded(event *v1.Event) {
+	if f.forwarded == nil {
+		return
+	}
+	f.forwarded.Set(eventDedupKey(event), struct{}{}, cache.DefaultExpiration)
+}
+
+func eventDedupKey(event *v1.Event) string {
+	if event.UID != "" {
+		return string(event.UID)
+	}
+	return event.Namespace + "/" + event.Name
+}
+
+// processEventLogs forwards the Kubernetes events as structured logs through the logs pipeline,
+// it returns the events which could not be forwarded because the logs channel is full.
+func (k *KubeASCheck) processEventLogs(events []*v1.Event, clusterName string) []*v1.Event {
+	var dropped []*v1.Event
+	for _, event := range events {
+		if event == nil || event.InvolvedObject.Kind == "" || event.InvolvedObject.Name == "" {
+			continue
+		}
+		if !k.eventLogsFilter.shouldForward(event) {
+			continue
+		}
+		l, err := formatEventLog(event, clusterName)
+		if err != nil {
+			k.Warnf("Error while formatting event as log, %s. Not submitting", err.Error()) //nolint:errcheck
+			continue
+		}
+		if !eventlogs.Submit(l) {
+			dropped = append(dropped, event)
+			continue
+		}
+		k.eventLogsFilter.markForwarded(event)
+	}
+	if len(dropped) > 0 {
+		log.Warnf("Could not forward %d Kubernetes events as logs, the logs pipeline is not keeping up: submitting them as Datadog events", len(dropped))
+	}
+	return dropped
+}
+
+// formatEventLog converts a Kubernetes event into a log
+func formatEventLog(event *v1.Event, clusterName string) (*eventlogs.Log, error) {
+	content, err := json.Marshal(eventLog{
+		Message:         event.Message,
+		Reason:          event.Reason,
+		Type:            event.Type,
+		Count:           event.Count,
+		SourceComponent: event.Source.Component,
+		SourceHost:      event.Source.Host,
+		FirstTimestamp:  formatEventTime(event.FirstTimestamp.Time),
+		LastTimestamp:   formatEventTime(event.LastTimestamp.Time),
+		InvolvedObject: eventInvolvedObject{
+			Kind:      event.InvolvedObject.Kind,
+			Name:      event.InvolvedObject.Name,
+			Namespace: event.InvolvedObject.Namespace,
+			UID:       string(event.InvolvedObject.UID),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := message.StatusInfo
+	if event.Type == v1.EventTypeWarning {
+		status = message.StatusWarning
+	}
+
+	return &eventlogs.Log{
+		Content: content,
+		Tags:    eventLogTags(event, clusterName),
+		Status:  status,
+	}, nil
+}
+
+// eventLogTags returns the tags of an event log, including the tagger tags of the involved pod
+func eventLogTags(event *v1.Event, clusterName string) []string {
+	tags := []string{
+		fmt.Sprintf("source_component:%s", event.Source.Component),
+		fmt.Sprintf("kubernetes_kind:%s", event.InvolvedObject.Kind),
+		fmt.Sprintf("name:%s", event.InvolvedObject.Name),
+		fmt.Sprintf("event_reason:%s", event.Reason),
+	}
+	if kindTag := addKindRelatedTag(event.InvolvedObject.Kind, event.InvolvedObject.Name); kindTag != "" {
+		tags = append(tags, kindTag)
+	}
+	if event.InvolvedObject.Namespace != "" {
+		tags = append(tags, fmt.Sprintf("kube_namespace:%s", event.InvolvedObject.Namespace))
+	}
+	if clusterName != "" {
+		tags = append(tags, fmt.Sprintf("kube_cluster_name:%s", clusterName))
+	}
+
+	if event.InvolvedObject.Kind == "Pod" && event.InvolvedObject.UID != "" {
+		entityTags, err := tagger.Tag(kubelet.PodUIDToTaggerEntityName(string(event.InvolvedObject.UID)), collectors.HighCardinality)
+		if err != nil {
+			log.Debugf("Cannot get tags for pod %s/%s: %v", event.InvolvedObject.Namespace, event.InvolvedObject.Name, err)
+		}
+		tags = append(tags, entityTags...)
+	}
+	return tags
+}
+
+func formatEventTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
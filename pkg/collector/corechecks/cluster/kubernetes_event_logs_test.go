@@ -0,0 +1,171 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	obj "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/eventlogs"
+)
+
+func newEventForLogs(uid, reason string, count int32) *v1.Event {
+	return &v1.Event{
+		ObjectMeta: obj.ObjectMeta{
+			Name:      "redis.15f1b1a2",
+			Namespace: "default",
+			UID:       types.UID(uid),
+		},
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Deployment",
+			Name:      "redis",
+			Namespace: "default",
+			UID:       "d3f1ac2e",
+		},
+		Reason:         reason,
+		Message:        "Scaled up replica set redis-5d8f7b to 1",
+		Type:           v1.EventTypeNormal,
+		Count:          count,
+		Source:         v1.EventSource{Component: "deployment-controller"},
+		FirstTimestamp: obj.NewTime(time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)),
+		LastTimestamp:  obj.NewTime(time.Date(2020, 6, 1, 10, 5, 0, 0, time.UTC)),
+	}
+}
+
+func TestEventLogsFilter(t *testing.T) {
+	tests := []struct {
+		name            string
+		reasons         []string
+		excludedReasons []string
+		events          []*v1.Event
+		expected        []bool
+	}{
+		{
+			name:     "no filter, count increment is deduplicated",
+			events:   []*v1.Event{newEventForLogs("1", "BackOff", 1), newEventForLogs("1", "BackOff", 2), newEventForLogs("2", "BackOff", 1)},
+			expected: []bool{true, false, true},
+		},
+		{
+			name:     "included reasons",
+			reasons:  []string{"BackOff"},
+			events:   []*v1.Event{newEventForLogs("1", "BackOff", 1), newEventForLogs("2", "Pulled", 1)},
+			expected: []bool{true, false},
+		},
+		{
+			name:            "excluded reasons",
+			excludedReasons: []string{"Pulled"},
+			events:          []*v1.Event{newEventForLogs("1", "BackOff", 1), newEventForLogs("2", "Pulled", 1)},
+			expected:        []bool{true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEventLogsFilter(tt.reasons, tt.excludedReasons, defaultEventLogsDedupWindow)
+			for i, ev := range tt.events {
+				forward := f.shouldForward(ev)
+				assert.Equal(t, tt.expected[i], forward, "event %d", i)
+				if forward {
+					f.markForwarded(ev)
+				}
+			}
+		})
+	}
+}
+
+func TestEventLogsFilterNoDedup(t *testing.T) {
+	f := newEventLogsFilter(nil, nil, 0)
+	assert.True(t, f.shouldForward(newEventForLogs("1", "BackOff", 1)))
+	f.markForwarded(newEventForLogs("1", "BackOff", 1))
+	assert.True(t, f.shouldForward(newEventForLogs("1", "BackOff", 2)))
+}
+
+func TestFormatEventLog(t *testing.T) {
+	ev := newEventForLogs("1", "ScalingReplicaSet", 3)
+	ev.Type = v1.EventTypeWarning
+
+	l, err := formatEventLog(ev, "mycluster")
+	require.NoError(t, err)
+	assert.Equal(t, message.StatusWarning, l.Status)
+	assert.ElementsMatch(t, []string{
+		"source_component:deployment-controller",
+		"kubernetes_kind:Deployment",
+		"name:redis",
+		"event_reason:ScalingReplicaSet",
+		"kube_deployment:redis",
+		"kube_namespace:default",
+		"kube_cluster_name:mycluster",
+	}, l.Tags)
+
+	var content map[string]interface{}
+	require.NoError(t, json.Unmarshal(l.Content, &content))
+	assert.Equal(t, map[string]interface{}{
+		"message":          "Scaled up replica set redis-5d8f7b to 1",
+		"reason":           "ScalingReplicaSet",
+		"type":             "Warning",
+		"count":            float64(3),
+		"source_component": "deployment-controller",
+		"first_timestamp":  "2020-06-01T10:00:00Z",
+		"last_timestamp":   "2020-06-01T10:05:00Z",
+		"involved_object": map[string]interface{}{
+			"kind":      "Deployment",
+			"name":      "redis",
+			"namespace": "default",
+			"uid":       "d3f1ac2e",
+		},
+	}, content)
+}
+
+func TestProcessEventLogs(t *testing.T) {
+	kubeASCheck := NewKubeASCheck(core.NewCheckBase(kubernetesAPIServerCheckName), &KubeASConfig{})
+	kubeASCheck.eventLogsFilter = newEventLogsFilter(nil, []string{"Pulled"}, defaultEventLogsDedupWindow)
+
+	dropped := kubeASCheck.processEventLogs([]*v1.Event{
+		newEventForLogs("1", "BackOff", 1),
+		newEventForLogs("1", "BackOff", 2),
+		newEventForLogs("2", "Pulled", 1),
+	}, "")
+	assert.Empty(t, dropped)
+
+	logsChan := eventlogs.GetLogsChannel()
+	require.Len(t, logsChan, 1)
+	l := <-logsChan
+	assert.Contains(t, string(l.Content), `"reason":"BackOff"`)
+	assert.Equal(t, message.StatusInfo, l.Status)
+}
+
+func TestProcessEventLogsDropped(t *testing.T) {
+	kubeASCheck := NewKubeASCheck(core.NewCheckBase(kubernetesAPIServerCheckName), &KubeASConfig{})
+	kubeASCheck.eventLogsFilter = newEventLogsFilter(nil, nil, defaultEventLogsDedupWindow)
+
+	// fill the logs channel so that the event cannot be submitted
+	logsChan := eventlogs.GetLogsChannel()
+	for eventlogs.Submit(&eventlogs.Log{}) {
+	}
+
+	dropped := kubeASCheck.processEventLogs([]*v1.Event{newEventForLogs("1", "BackOff", 1)}, "")
+	assert.Len(t, dropped, 1)
+
+	for len(logsChan) > 0 {
+		<-logsChan
+	}
+
+	// the dropped event was not recorded as forwarded, its next count is forwarded
+	dropped = kubeASCheck.processEventLogs([]*v1.Event{newEventForLogs("1", "BackOff", 2)}, "")
+	assert.Empty(t, dropped)
+	require.Len(t, logsChan, 1)
+	<-logsChan
+}
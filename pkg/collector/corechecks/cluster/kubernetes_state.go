@@ -82,6 +82,19 @@ type KSMConfig struct {
 	// Telemetry enables telemetry check's metrics, default false.
 	// Metrics can be found under kubernetes_state.telemetry
 	Telemetry bool `yaml:"telemetry"`
+
+	// CustomResources defines the metrics to collect from custom resources.
+	// Example: Collect the available replicas and the conditions of Argo Rollouts.
+	// custom_resources:
+	//   - group: argoproj.io
+	//     version: v1alpha1
+	//     resource: rollouts
+	//     metrics:
+	//       - name: argo_rollout.replicas.available
+	//         path: .status.availableReplicas
+	//       - name: argo_rollout.condition
+	//         type: conditions
+	CustomResources []CustomResourceConfig `yaml:"custom_resources"`
 }
 
 // KSMCheck wraps the config and the metric stores needed to run the check
@@ -90,6 +103,9 @@ type KSMCheck struct {
 	instance  *KSMConfig
 	store     []cache.Store
 	telemetry *telemetryCache
+
+	// customResourceMetrics maps the KSM names of custom resource metrics to Datadog metric names
+	customResourceMetrics map[string]string
 }
 
 // JoinsConfig contains the config parameters for label joins
@@ -148,6 +164,12 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 
 	k.initTags()
 
+	for i := range k.instance.CustomResources {
+		if err := k.instance.CustomResources[i].validate(); err != nil {
+			return err
+		}
+	}
+
 	builder := kubestatemetrics.New()
 
 	// Prepare the collectors for the resources specified in the configuration file.
@@ -189,6 +211,7 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 	}
 
 	builder.WithKubeClient(c.Cl)
+	builder.WithDynamicClient(c.DynamicCl)
 	builder.WithContext(context.Background())
 
 	resyncPeriod := k.instance.ResyncPeriod
@@ -203,6 +226,16 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 	// Start the collection process
 	k.store = builder.Build()
 
+	// Watch the configured custom resources
+	for i := range k.instance.CustomResources {
+		cr := &k.instance.CustomResources[i]
+		families, names := cr.familyGenerators()
+		for ksmName, ddName := range names {
+			k.customResourceMetrics[ksmName] = ddName
+		}
+		k.store = append(k.store, builder.GenerateCustomResourceStore(families, cr.gvr()))
+	}
+
 	return nil
 }
 
@@ -251,6 +284,13 @@ func (k *KSMCheck) processMetrics(sender aggregator.Sender, metrics map[string][
 				// they shouldn't be forwarded to Datadog
 				continue
 			}
+			if ddName, found := k.customResourceMetrics[metricFamily.Name]; found {
+				for _, m := range metricFamily.ListMetrics {
+					hostname, tags := k.hostnameAndTags(m.Labels, metricsToGet)
+					sender.Gauge(ddName, m.Val, hostname, tags)
+				}
+				continue
+			}
 			if !isKnownMetric(metricFamily.Name) {
 				// ignore the metric if it doesn't have a transformer
 				// or if it isn't mapped to a datadog metric name
@@ -423,7 +463,8 @@ func (k *KSMCheck) processTelemetry(metrics map[string][]ksmstore.DDMetricsFam)
 
 	for name, list := range metrics {
 		isMetadataMetric := metadataMetricsRegex.MatchString(name)
-		if !isKnownMetric(name) && !isMetadataMetric {
+		_, isCustomResourceMetric := k.customResourceMetrics[name]
+		if !isKnownMetric(name) && !isMetadataMetric && !isCustomResourceMetric {
 			k.telemetry.incUnknown()
 			continue
 		}
@@ -469,9 +510,10 @@ func KubeStateMetricsFactory() check.Check {
 
 func newKSMCheck(base core.CheckBase, instance *KSMConfig) *KSMCheck {
 	return &KSMCheck{
-		CheckBase: base,
-		instance:  instance,
-		telemetry: newTelemetryCache(),
+		CheckBase:             base,
+		instance:              instance,
+		telemetry:             newTelemetryCache(),
+		customResourceMetrics: make(map[string]string),
 	}
 }
 
@@ -0,0 +1,301 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/kube-state-metrics/pkg/metric"
+	generator "k8s.io/kube-state-metrics/pkg/metric_generator"
+)
+
+const (
+	customResourceMetricPrefix = "kube_customresource_"
+	customResourceGaugeType    = "gauge"
+	customResourceCondType     = "conditions"
+	defaultConditionsPath      = ".status.conditions"
+)
+
+// conditionStatuses contains the possible values of a status condition
+var conditionStatuses = []string{"true", "false", "unknown"}
+
+// CustomResourceConfig contains the config parameters to collect metrics from a custom resource
+type CustomResourceConfig struct {
+	// Group, Version and Resource identify the custom resource to watch.
+	// Resource is the plural name of the resource.
+	// Example: Argo Rollouts.
+	// group: argoproj.io
+	// version: v1alpha1
+	// resource: rollouts
+	Group    string `yaml:"group"`
+	Version  string `yaml:"version"`
+	Resource string `yaml:"resource"`
+
+	// LabelsFromPath adds labels extracted from the object to all the metrics of the resource.
+	// Example:
+	// labels_from_path:
+	//   strategy: .spec.strategy.type
+	LabelsFromPath map[string]string `yaml:"labels_from_path"`
+
+	// Metrics defines the metrics to generate from each object of the resource.
+	Metrics []CustomResourceMetricConfig `yaml:"metrics"`
+}
+
+// CustomResourceMetricConfig contains the config parameters of a custom resource metric
+type CustomResourceMetricConfig struct {
+	// Name is the metric name, it is prefixed with kubernetes_state.
+	// Example: argo_rollout.replicas.available
+	Name string `yaml:"name"`
+
+	// Type is either gauge (default) or conditions.
+	// A gauge reports the numeric, boolean or timestamp value found at Path.
+	// A conditions metric reports each status condition found at Path (.status.conditions by default)
+	// with a condition and a status label, its value is 1 for the current status and 0 for the others.
+	Type string `yaml:"type"`
+
+	// Path is the JSON path of the value in the object.
+	// Example: .status.availableReplicas
+	Path string `yaml:"path"`
+
+	// LabelsFromPath adds labels extracted from the object to the metric.
+	LabelsFromPath map[string]string `yaml:"labels_from_path"`
+}
+
+// gvr returns the GroupVersionResource of the custom resource
+func (c *CustomResourceConfig) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: c.Group, Version: c.Version, Resource: c.Resource}
+}
+
+// validate returns an error if the custom resource config is incomplete or contains invalid JSON paths
+func (c *CustomResourceConfig) validate() error {
+	if c.Version == "" || c.Resource == "" {
+		return fmt.Errorf("custom resource %q: version and resource are required", c.gvr().String())
+	}
+	if len(c.Metrics) == 0 {
+		return fmt.Errorf("custom resource %s: no metrics configured", c.Resource)
+	}
+	for label, path := range c.LabelsFromPath {
+		if _, err := parseJSONPath(path); err != nil {
+			return fmt.Errorf("custom resource %s: invalid path for label %s: %v", c.Resource, label, err)
+		}
+	}
+	for _, m := range c.Metrics {
+		if m.Name == "" {
+			return fmt.Errorf("custom resource %s: metric name is required", c.Resource)
+		}
+		switch m.Type {
+		case "", customResourceGaugeType:
+			if m.Path == "" {
+				return fmt.Errorf("custom resource %s: path is required for gauge %s", c.Resource, m.Name)
+			}
+		case customResourceCondType:
+		default:
+			return fmt.Errorf("custom resource %s: unknown type %q for metric %s", c.Resource, m.Type, m.Name)
+		}
+		if _, err := parseJSONPath(m.path()); err != nil {
+			return fmt.Errorf("custom resource %s: invalid path for metric %s: %v", c.Resource, m.Name, err)
+		}
+		for label, path := range m.LabelsFromPath {
+			if _, err := parseJSONPath(path); err != nil {
+				return fmt.Errorf("custom resource %s: invalid path for label %s of metric %s: %v", c.Resource, label, m.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// path returns the configured path, or the default conditions path for conditions metrics
+func (m *CustomResourceMetricConfig) path() string {
+	if m.Path == "" && m.Type == customResourceCondType {
+		return defaultConditionsPath
+	}
+	return m.Path
+}
+
+// ksmName returns the KSM metric family name of a custom resource metric
+func (m *CustomResourceMetricConfig) ksmName() string {
+	return customResourceMetricPrefix + strings.NewReplacer(".", "_", "-", "_").Replace(m.Name)
+}
+
+// familyGenerators returns the metric family generators of the custom resource,
+// and the mapping of their names to Datadog metric names.
+func (c *CustomResourceConfig) familyGenerators() ([]generator.FamilyGenerator, map[string]string) {
+	families := make([]generator.FamilyGenerator, 0, len(c.Metrics))
+	names := make(map[string]string, len(c.Metrics))
+	for i := range c.Metrics {
+		m := c.Metrics[i]
+		generate := c.generateGauge
+		if m.Type == customResourceCondType {
+			generate = c.generateConditions
+		}
+		families = append(families, generator.FamilyGenerator{
+			Name: m.ksmName(),
+			Type: metric.Gauge,
+			Help: fmt.Sprintf("Custom resource %s metric %s", c.Resource, m.Name),
+			GenerateFunc: func(obj interface{}) *metric.Family {
+				u, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return &metric.Family{}
+				}
+				return generate(u, &m)
+			},
+		})
+		names[m.ksmName()] = ksmMetricPrefix + m.Name
+	}
+	return families, names
+}
+
+// generateGauge generates the metric family of a gauge metric
+func (c *CustomResourceConfig) generateGauge(u *unstructured.Unstructured, m *CustomResourceMetricConfig) *metric.Family {
+	values, err := findValues(m.path(), u.UnstructuredContent())
+	if err != nil || len(values) == 0 {
+		return &metric.Family{}
+	}
+	value, err := toFloat(values[0])
+	if err != nil {
+		log.Debugf("Cannot convert the value of %s for %s %s/%s: %v", m.path(), c.Resource, u.GetNamespace(), u.GetName(), err)
+		return &metric.Family{}
+	}
+	keys, labelValues := c.labels(u, m)
+	return &metric.Family{
+		Metrics: []*metric.Metric{{LabelKeys: keys, LabelValues: labelValues, Value: value}},
+	}
+}
+
+// generateConditions generates the metric family of a conditions metric
+func (c *CustomResourceConfig) generateConditions(u *unstructured.Unstructured, m *CustomResourceMetricConfig) *metric.Family {
+	values, err := findValues(m.path(), u.UnstructuredContent())
+	if err != nil || len(values) == 0 {
+		return &metric.Family{}
+	}
+	conditions, ok := values[0].([]interface{})
+	if !ok {
+		return &metric.Family{}
+	}
+
+	keys, labelValues := c.labels(u, m)
+	family := &metric.Family{}
+	for _, cond := range conditions {
+		condition, ok := cond.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := condition["type"].(string)
+		condStatus, _ := condition["status"].(string)
+		if condType == "" {
+			continue
+		}
+		for _, status := range conditionStatuses {
+			value := float64(0)
+			if strings.EqualFold(condStatus, status) {
+				value = 1
+			}
+			family.Metrics = append(family.Metrics, &metric.Metric{
+				LabelKeys:   append(keys, "condition", "status"),
+				LabelValues: append(labelValues, condType, status),
+				Value:       value,
+			})
+		}
+	}
+	return family
+}
+
+// labels returns the label keys and values of a custom resource metric
+func (c *CustomResourceConfig) labels(u *unstructured.Unstructured, m *CustomResourceMetricConfig) ([]string, []string) {
+	keys := []string{"customresource_kind", "customresource_name"}
+	values := []string{strings.ToLower(u.GetKind()), u.GetName()}
+	if namespace := u.GetNamespace(); namespace != "" {
+		keys = append(keys, "namespace")
+		values = append(values, namespace)
+	}
+
+	for _, labelsFromPath := range []map[string]string{c.LabelsFromPath, m.LabelsFromPath} {
+		for label, path := range labelsFromPath {
+			found, err := findValues(path, u.UnstructuredContent())
+			if err != nil || len(found) == 0 {
+				continue
+			}
+			keys = append(keys, label)
+			values = append(values, fmt.Sprintf("%v", found[0]))
+		}
+	}
+
+	return keys[:len(keys):len(keys)], values[:len(values):len(values)]
+}
+
+// parseJSONPath parses a JSON path, the enclosing braces are optional
+func parseJSONPath(path string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(path, "{") {
+		if !strings.HasPrefix(path, ".") {
+			path = "." + path
+		}
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New("customresource").AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return nil, err
+	}
+	return jp, nil
+}
+
+// findValues returns the values found at the JSON path in the object content
+// A JSONPath object keeps state during evaluation, so it is parsed for every call.
+func findValues(path string, content map[string]interface{}) ([]interface{}, error) {
+	jp, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	results, err := jp.FindResults(content)
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.Kind() == reflect.Interface && value.IsNil() {
+				continue
+			}
+			values = append(values, value.Interface())
+		}
+	}
+	return values, nil
+}
+
+// toFloat converts a numeric, boolean, numeric string or RFC3339 timestamp value to a float
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, nil
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return float64(t.Unix()), nil
+		}
+		return 0, fmt.Errorf("cannot parse %q as a number or a timestamp", v)
+	default:
+		return 0, fmt.Errorf("unsupported value type %T", value)
+	}
+}
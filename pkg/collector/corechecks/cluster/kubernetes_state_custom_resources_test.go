@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	ksmstore "github.com/DataDog/datadog-agent/pkg/kubestatemetrics/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kube-state-metrics/pkg/metric"
+	generator "k8s.io/kube-state-metrics/pkg/metric_generator"
+)
+
+var rolloutConfig = CustomResourceConfig{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "rollouts",
+	LabelsFromPath: map[string]string{
+		"strategy": ".spec.strategy.type",
+	},
+	Metrics: []CustomResourceMetricConfig{
+		{
+			Name: "argo_rollout.replicas.available",
+			Path: ".status.availableReplicas",
+		},
+		{
+			Name: "argo_rollout.paused",
+			Path: "spec.paused",
+		},
+		{
+			Name: "argo_rollout.condition",
+			Type: "conditions",
+		},
+	},
+}
+
+func newRollout() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Rollout",
+			"metadata": map[string]interface{}{
+				"name":      "web",
+				"namespace": "prod",
+				"uid":       "a1b2c3",
+			},
+			"spec": map[string]interface{}{
+				"paused": true,
+				"strategy": map[string]interface{}{
+					"type": "canary",
+				},
+			},
+			"status": map[string]interface{}{
+				"availableReplicas": int64(3),
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True"},
+					map[string]interface{}{"type": "Progressing", "status": "False"},
+				},
+			},
+		},
+	}
+}
+
+func TestCustomResourceConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CustomResourceConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			config:  rolloutConfig,
+			wantErr: false,
+		},
+		{
+			name:    "missing resource",
+			config:  CustomResourceConfig{Version: "v1", Metrics: rolloutConfig.Metrics},
+			wantErr: true,
+		},
+		{
+			name:    "no metrics",
+			config:  CustomResourceConfig{Version: "v1", Resource: "certificates"},
+			wantErr: true,
+		},
+		{
+			name: "gauge without path",
+			config: CustomResourceConfig{Version: "v1", Resource: "certificates", Metrics: []CustomResourceMetricConfig{
+				{Name: "certificate.expiration"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unknown type",
+			config: CustomResourceConfig{Version: "v1", Resource: "certificates", Metrics: []CustomResourceMetricConfig{
+				{Name: "certificate.expiration", Type: "histogram", Path: ".status.notAfter"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid path",
+			config: CustomResourceConfig{Version: "v1", Resource: "certificates", Metrics: []CustomResourceMetricConfig{
+				{Name: "certificate.expiration", Path: "{.status[}"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestCustomResourceFamilyGenerators(t *testing.T) {
+	families, names := rolloutConfig.familyGenerators()
+	require.Len(t, families, 3)
+	assert.Equal(t, map[string]string{
+		"kube_customresource_argo_rollout_replicas_available": "kubernetes_state.argo_rollout.replicas.available",
+		"kube_customresource_argo_rollout_paused":             "kubernetes_state.argo_rollout.paused",
+		"kube_customresource_argo_rollout_condition":          "kubernetes_state.argo_rollout.condition",
+	}, names)
+
+	store := ksmstore.NewMetricsStore(generator.ComposeMetricGenFuncs(families), "argoproj.io/v1alpha1, Resource=rollouts")
+	require.NoError(t, store.Add(newRollout()))
+	metrics := store.Push(ksmstore.GetAllFamilies, ksmstore.GetAllMetrics)
+
+	commonLabels := map[string]string{
+		"customresource_kind": "rollout",
+		"customresource_name": "web",
+		"namespace":           "prod",
+		"strategy":            "canary",
+	}
+	assert.Equal(t, []ksmstore.DDMetric{{Labels: commonLabels, Val: 3}}, metrics["kube_customresource_argo_rollout_replicas_available"][0].ListMetrics)
+	assert.Equal(t, []ksmstore.DDMetric{{Labels: commonLabels, Val: 1}}, metrics["kube_customresource_argo_rollout_paused"][0].ListMetrics)
+
+	conditions := metrics["kube_customresource_argo_rollout_condition"][0].ListMetrics
+	assert.Len(t, conditions, 6)
+	active := map[string]string{}
+	for _, m := range conditions {
+		if m.Val == 1 {
+			active[m.Labels["condition"]] = m.Labels["status"]
+		}
+	}
+	assert.Equal(t, map[string]string{"Available": "true", "Progressing": "false"}, active)
+}
+
+func TestCustomResourceMissingValue(t *testing.T) {
+	rollout := newRollout()
+	unstructured.RemoveNestedField(rollout.Object, "status")
+
+	// Only the metrics based on the status are missing
+	families, _ := rolloutConfig.familyGenerators()
+	for _, i := range []int{0, 2} {
+		assert.Equal(t, &metric.Family{Name: families[i].Name, Type: metric.Gauge}, families[i].Generate(rollout))
+	}
+	assert.Len(t, families[1].Generate(rollout).Metrics, 1)
+}
+
+func TestToFloat(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    float64
+		wantErr bool
+	}{
+		{value: int64(2), want: 2},
+		{value: 1.5, want: 1.5},
+		{value: false, want: 0},
+		{value: "42", want: 42},
+		{value: "2020-06-01T00:00:00Z", want: 1590969600},
+		{value: "not a number", wantErr: true},
+		{value: map[string]interface{}{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toFloat(tt.value)
+		assert.Equal(t, tt.wantErr, err != nil)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestProcessCustomResourceMetrics(t *testing.T) {
+	config := &KSMConfig{LabelsMapper: defaultLabelsMapper}
+	kubeStateMetricsSCheck := newKSMCheck(core.NewCheckBase(kubeStateMetricsCheckName), config)
+	kubeStateMetricsSCheck.customResourceMetrics["kube_customresource_argo_rollout_replicas_available"] = "kubernetes_state.argo_rollout.replicas.available"
+	mocked := mocksender.NewMockSender(kubeStateMetricsSCheck.ID())
+	mocked.SetupAcceptAll()
+
+	metrics := map[string][]ksmstore.DDMetricsFam{
+		"kube_customresource_argo_rollout_replicas_available": {
+			{
+				Type: "argoproj.io/v1alpha1, Resource=rollouts",
+				Name: "kube_customresource_argo_rollout_replicas_available",
+				ListMetrics: []ksmstore.DDMetric{
+					{
+						Labels: map[string]string{"customresource_name": "web", "namespace": "prod"},
+						Val:    3,
+					},
+				},
+			},
+		},
+	}
+	kubeStateMetricsSCheck.processMetrics(mocked, metrics, []ksmstore.DDMetricsFam{})
+	mocked.AssertMetric(t, "Gauge", "kubernetes_state.argo_rollout.replicas.available", 3, "", []string{"customresource_name:web", "kube_namespace:prod"})
+}
@@ -53,14 +53,14 @@ func New(reporter event.Reporter, scheduler Scheduler, configDir string, options
 	}, nil
 }
 
-// RunChecks runs checks right away without scheduling
-func RunChecks(reporter event.Reporter, configDir string, options ...checks.BuilderOption) error {
+// RunChecks runs checks right away without scheduling, it returns the status of the checks
+func RunChecks(reporter event.Reporter, configDir string, options ...checks.BuilderOption) (compliance.CheckStatusList, error) {
 	builder, err := checks.NewBuilder(
 		reporter,
 		options...,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer builder.Close()
@@ -70,17 +70,20 @@ func RunChecks(reporter event.Reporter, configDir string, options ...checks.Buil
 		configDir: configDir,
 	}
 
-	return agent.RunChecks()
+	if err := agent.RunChecks(); err != nil {
+		return nil, err
+	}
+	return builder.GetCheckStatus(), nil
 }
 
-// RunChecksFromFile runs checks from the specified file with no scheduling
-func RunChecksFromFile(reporter event.Reporter, file string, options ...checks.BuilderOption) error {
+// RunChecksFromFile runs checks from the specified file with no scheduling, it returns the status of the checks
+func RunChecksFromFile(reporter event.Reporter, file string, options ...checks.BuilderOption) (compliance.CheckStatusList, error) {
 	builder, err := checks.NewBuilder(
 		reporter,
 		options...,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer builder.Close()
@@ -89,7 +92,10 @@ func RunChecksFromFile(reporter event.Reporter, file string, options ...checks.B
 		builder: builder,
 	}
 
-	return agent.RunChecksFromFile(file)
+	if err := agent.RunChecksFromFile(file); err != nil {
+		return nil, err
+	}
+	return builder.GetCheckStatus(), nil
 }
 
 // Run starts the Compliance Agent
@@ -205,7 +205,7 @@ func TestRunChecks(t *testing.T) {
 	dockerClient.On("Close").Return(nil).Once()
 	defer dockerClient.AssertExpectations(t)
 
-	err := RunChecks(
+	checksStatus, err := RunChecks(
 		reporter,
 		e.dir,
 		checks.WithMatchSuite(checks.IsFramework("cis-docker")),
@@ -215,6 +215,10 @@ func TestRunChecks(t *testing.T) {
 		checks.WithDockerClient(dockerClient),
 	)
 	assert.NoError(err)
+	assert.Len(checksStatus, 1)
+	assert.Equal("cis-docker-1", checksStatus[0].RuleID)
+	assert.Equal("cis-docker", checksStatus[0].Framework)
+	assert.NotNil(checksStatus[0].LastEvent)
 }
 
 func TestRunChecksFromFile(t *testing.T) {
@@ -250,7 +254,7 @@ func TestRunChecksFromFile(t *testing.T) {
 		"node-role.kubernetes.io/worker": "",
 	}
 
-	err := RunChecksFromFile(
+	checksStatus, err := RunChecksFromFile(
 		reporter,
 		filepath.Join(e.dir, "cis-kubernetes.yaml"),
 		checks.WithHostname("the-host"),
@@ -259,4 +263,7 @@ func TestRunChecksFromFile(t *testing.T) {
 		checks.WithNodeLabels(nodeLabels),
 	)
 	assert.NoError(err)
+	assert.NotEmpty(checksStatus)
+	assert.Equal("cis-kubernetes-1", checksStatus[0].RuleID)
+	assert.Equal("failed", checksStatus[0].LastEvent.Result)
 }
@@ -23,6 +23,8 @@ type CheckStatus struct {
 	Source      string
 	InitError   error
 	LastEvent   *event.Event
+	// Events holds the last event reported for each resource checked by the rule
+	Events []*event.Event
 }
 
 // CheckStatusList describes status for all configured checks
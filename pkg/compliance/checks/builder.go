@@ -114,6 +114,25 @@ func WithAuditClient(cli env.AuditClient) BuilderOption {
 	}
 }
 
+// WithSystemd configures using systemd checks
+func WithSystemd() BuilderOption {
+	return func(b *builder) error {
+		cli, err := newSystemdClient()
+		if err == nil {
+			b.systemdClient = cli
+		}
+		return err
+	}
+}
+
+// WithSystemdClient configures using specific systemd client
+func WithSystemdClient(cli env.SystemdClient) BuilderOption {
+	return func(b *builder) error {
+		b.systemdClient = cli
+		return nil
+	}
+}
+
 // WithKubernetesClient allows specific Kubernetes client
 func WithKubernetesClient(cli env.KubeClient) BuilderOption {
 	return func(b *builder) error {
@@ -225,10 +244,11 @@ type builder struct {
 	suiteMatcher SuiteMatcher
 	ruleMatcher  RuleMatcher
 
-	dockerClient env.DockerClient
-	auditClient  env.AuditClient
-	kubeClient   env.KubeClient
-	isLeaderFunc func() bool
+	dockerClient  env.DockerClient
+	auditClient   env.AuditClient
+	kubeClient    env.KubeClient
+	systemdClient env.SystemdClient
+	isLeaderFunc  func() bool
 
 	status *status
 }
@@ -244,6 +264,11 @@ func (b *builder) Close() error {
 			return err
 		}
 	}
+	if b.systemdClient != nil {
+		if err := b.systemdClient.Close(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -493,6 +518,10 @@ func (b *builder) KubeClient() env.KubeClient {
 	return b.kubeClient
 }
 
+func (b *builder) SystemdClient() env.SystemdClient {
+	return b.systemdClient
+}
+
 func (b *builder) Hostname() string {
 	return b.hostname
 }
@@ -22,6 +22,7 @@ type Clients interface {
 	DockerClient() DockerClient
 	AuditClient() AuditClient
 	KubeClient() KubeClient
+	SystemdClient() SystemdClient
 }
 
 // Configuration provides an abstraction for various environment methods used by checks
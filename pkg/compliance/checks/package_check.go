@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	dpkgStatusPath = "/var/lib/dpkg/status"
+	rpmDBPath      = "/var/lib/rpm"
+
+	// the epoch is only printed when set
+	rpmQueryFormat = `%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\n`
+)
+
+var packageReportedFields = []string{
+	compliance.PackageFieldName,
+	compliance.PackageFieldVersion,
+	compliance.PackageFieldInstalled,
+}
+
+// ErrPackageDatabaseNotFound is returned when neither a dpkg nor a rpm database can be found
+var ErrPackageDatabaseNotFound = errors.New("package database not found")
+
+func resolvePackage(ctx context.Context, e env.Env, id string, res compliance.Resource) (interface{}, error) {
+	if res.Package == nil {
+		return nil, fmt.Errorf("%s: expecting package resource in package check", id)
+	}
+
+	name := res.Package.Name
+
+	log.Debugf("%s: looking for package %s", id, name)
+
+	var (
+		pkg *packageInfo
+		err error
+	)
+
+	if path := e.NormalizeToHostRoot(dpkgStatusPath); fileExists(path) {
+		pkg, err = findDpkgPackageFromFile(path, name)
+	} else if path := e.NormalizeToHostRoot(rpmDBPath); fileExists(path) {
+		pkg, err = findRpmPackage(ctx, path, name)
+	} else {
+		err = ErrPackageDatabaseNotFound
+	}
+
+	if err != nil {
+		return nil, log.Errorf("%s: unable to look for package %s: %v", id, name, err)
+	}
+
+	instance := &eval.Instance{
+		Vars: eval.VarMap{
+			compliance.PackageFieldName:      name,
+			compliance.PackageFieldVersion:   "",
+			compliance.PackageFieldInstalled: pkg != nil,
+		},
+	}
+
+	if pkg != nil {
+		instance.Vars[compliance.PackageFieldVersion] = pkg.version
+		instance.Functions = eval.FunctionMap{
+			compliance.PackageFuncVersionCompare: packageVersionCompare(pkg.version),
+		}
+	}
+
+	return instance, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func findDpkgPackageFromFile(path string, name string) (*packageInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return findDpkgPackage(f, name)
+}
+
+func findRpmPackage(ctx context.Context, dbPath string, name string) (*packageInfo, error) {
+	exitCode, stdout, err := commandRunner(ctx, "rpm", []string{"--dbpath", dbPath, "-q", "--queryformat", rpmQueryFormat, name}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// rpm exits with 1 when the package is not installed
+	if exitCode == 1 {
+		return nil, nil
+	} else if exitCode != 0 {
+		return nil, fmt.Errorf("rpm exited with code %d", exitCode)
+	}
+
+	// several versions of a package may be installed, the first one is reported
+	version := strings.SplitN(strings.TrimSpace(string(stdout)), "\n", 2)[0]
+
+	return &packageInfo{
+		name:    name,
+		version: version,
+	}, nil
+}
+
+func packageVersionCompare(version string) eval.Function {
+	return func(_ *eval.Instance, args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf(`invalid number of arguments, expecting 1 got %d`, len(args))
+		}
+		other, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New(`expecting string value for version argument`)
+		}
+		return compareVersions(version, other), nil
+	}
+}
@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPackageCheckDpkg(t *testing.T) {
+	tests := []struct {
+		name      string
+		pkg       string
+		condition string
+
+		expectReport *compliance.Report
+	}{
+		{
+			name:      "package installed",
+			pkg:       "openssh-server",
+			condition: `package.installed && package.versionCompare("1:8.0") >= 0`,
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "openssh-server",
+					"package.version":   "1:8.2p1-4ubuntu0.1",
+					"package.installed": true,
+				},
+			},
+		},
+		{
+			name:      "package removed",
+			pkg:       "telnet",
+			condition: `!package.installed`,
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "telnet",
+					"package.version":   "",
+					"package.installed": false,
+				},
+			},
+		},
+		{
+			name:      "last package of the database",
+			pkg:       "auditd",
+			condition: `package.versionCompare("1:3.0") >= 0`,
+			expectReport: &compliance.Report{
+				Passed: false,
+				Data: event.Data{
+					"package.name":      "auditd",
+					"package.version":   "1:2.8.5-2ubuntu6",
+					"package.installed": true,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := &mocks.Env{}
+			env.On("NormalizeToHostRoot", dpkgStatusPath).Return("./testdata/package/dpkg/status")
+
+			resource := compliance.Resource{
+				Package: &compliance.Package{
+					Name: test.pkg,
+				},
+				Condition: test.condition,
+			}
+
+			packageCheck, err := newResourceCheck(env, "rule-id", resource)
+			assert.NoError(err)
+
+			result, err := packageCheck.check(env)
+			assert.NoError(err)
+			assert.Equal(test.expectReport, result)
+		})
+	}
+}
+
+func TestPackageCheckRpm(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(runner commandRunnerFunc) { commandRunner = runner }(commandRunner)
+	commandRunner = func(ctx context.Context, name string, args []string, captureStdout bool) (int, []byte, error) {
+		assert.Equal("rpm", name)
+		assert.Equal([]string{"--dbpath", "./testdata/package", "-q", "--queryformat", rpmQueryFormat, "openssh-server"}, args)
+		return 0, []byte("8.0p1-5.el8\n"), nil
+	}
+
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", dpkgStatusPath).Return("./testdata/package/none")
+	env.On("NormalizeToHostRoot", rpmDBPath).Return("./testdata/package")
+
+	resource := compliance.Resource{
+		Package: &compliance.Package{
+			Name: "openssh-server",
+		},
+		Condition: `package.versionCompare("8.0p1-4.el8") > 0`,
+	}
+
+	packageCheck, err := newResourceCheck(env, "rule-id", resource)
+	assert.NoError(err)
+
+	result, err := packageCheck.check(env)
+	assert.NoError(err)
+	assert.Equal(&compliance.Report{
+		Passed: true,
+		Data: event.Data{
+			"package.name":      "openssh-server",
+			"package.version":   "8.0p1-5.el8",
+			"package.installed": true,
+		},
+	}, result)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0-2", "1.0-10", -1},
+		{"1:1.0", "2.0", 1},
+		{"0:1.0", "1.0", 0},
+		{"2.8.5-2ubuntu6", "2.8.5-2ubuntu10", -1},
+		{"1.001", "1.1", 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, compareVersions(test.a, test.b), "%s <> %s", test.a, test.b)
+		assert.Equal(t, -test.expected, compareVersions(test.b, test.a), "%s <> %s", test.b, test.a)
+	}
+}
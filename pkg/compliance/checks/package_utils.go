@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type packageInfo struct {
+	name    string
+	version string
+}
+
+// findDpkgPackage returns the package with the given name from a dpkg status database,
+// nil is returned if the package is not installed
+func findDpkgPackage(r io.Reader, name string) (*packageInfo, error) {
+	var (
+		pkg       packageInfo
+		installed bool
+	)
+
+	bs := bufio.NewScanner(r)
+	bs.Buffer(make([]byte, 64*1024), 1024*1024)
+	for bs.Scan() {
+		line := bs.Bytes()
+
+		// packages are separated by an empty line
+		if len(bytes.TrimSpace(line)) == 0 {
+			if pkg.name == name && installed {
+				return &pkg, nil
+			}
+			pkg, installed = packageInfo{}, false
+			continue
+		}
+
+		// skip the continuation lines of multiline fields
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		parts := strings.SplitN(string(line), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		switch parts[0] {
+		case "Package":
+			pkg.name = value
+		case "Version":
+			pkg.version = value
+		case "Status":
+			installed = strings.HasSuffix(value, " installed")
+		}
+	}
+
+	if err := bs.Err(); err != nil {
+		return nil, err
+	}
+
+	if pkg.name == name && installed {
+		return &pkg, nil
+	}
+	return nil, nil
+}
+
+// compareVersions compares two package versions of the form [epoch:]upstream[-revision] following
+// the dpkg ordering, `~` sorting before anything. It returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	aEpoch, aUpstream, aRevision := splitVersion(a)
+	bEpoch, bUpstream, bRevision := splitVersion(b)
+
+	switch {
+	case aEpoch < bEpoch:
+		return -1
+	case aEpoch > bEpoch:
+		return 1
+	}
+
+	if result := compareVersionParts(aUpstream, bUpstream); result != 0 {
+		return result
+	}
+	return compareVersionParts(aRevision, bRevision)
+}
+
+func splitVersion(version string) (int, string, string) {
+	var epoch int
+	if i := strings.IndexByte(version, ':'); i >= 0 {
+		epoch, _ = strconv.Atoi(version[:i])
+		version = version[i+1:]
+	}
+
+	if i := strings.LastIndexByte(version, '-'); i >= 0 {
+		return epoch, version[:i], version[i+1:]
+	}
+	return epoch, version, ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// versionCharOrder returns the weight of a non digit character, the end of the string sorting
+// before any character but `~`
+func versionCharOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+
+	switch c := s[i]; {
+	case isDigit(c):
+		return 0
+	case isLetter(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+func compareVersionParts(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		// compare the non digit prefixes
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := versionCharOrder(a, i), versionCharOrder(b, j)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			i, j = i+1, j+1
+		}
+
+		// compare the numeric parts
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i, j = i+1, j+1
+		}
+
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+
+	return 0
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -120,6 +120,10 @@ func newResourceCheck(env env.Env, ruleID string, resource compliance.Resource)
 		if env.KubeClient() == nil {
 			return nil, log.Errorf("%s: kube client not initialized", ruleID)
 		}
+	case compliance.KindSystemd:
+		if env.SystemdClient() == nil {
+			return nil, log.Errorf("%s: systemd client not initialized", ruleID)
+		}
 	}
 
 	resolve, reportedFields, err := resourceKindToResolverAndFields(kind)
@@ -160,6 +164,12 @@ func resourceKindToResolverAndFields(kind compliance.ResourceKind) (resolveFunc,
 		return resolveDocker, dockerReportedFields, nil
 	case compliance.KindKubernetes:
 		return resolveKubeapiserver, kubeResourceReportedFields, nil
+	case compliance.KindSysctl:
+		return resolveSysctl, sysctlReportedFields, nil
+	case compliance.KindSystemd:
+		return resolveSystemd, systemdReportedFields, nil
+	case compliance.KindPackage:
+		return resolvePackage, packageReportedFields, nil
 	default:
 		return nil, nil, ErrResourceKindNotSupported
 	}
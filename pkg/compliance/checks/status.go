@@ -48,6 +48,14 @@ func (s *status) updateCheck(ruleID string, event *event.Event) {
 		return
 	}
 	stats.LastEvent = event
+
+	for i, e := range stats.Events {
+		if e.ResourceType == event.ResourceType && e.ResourceID == event.ResourceID {
+			stats.Events[i] = event
+			return
+		}
+	}
+	stats.Events = append(stats.Events, event)
 }
 
 func (s *status) getChecksStatus() compliance.CheckStatusList {
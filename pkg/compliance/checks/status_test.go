@@ -63,9 +63,30 @@ func TestStatus(t *testing.T) {
 				LastEvent: &event.Event{
 					Result: "passed",
 				},
+				Events: []*event.Event{
+					{
+						Result: "passed",
+					},
+				},
 			},
 		},
 		status.getChecksStatus(),
 	)
 
 }
+
+func TestStatusResourceEvents(t *testing.T) {
+	status := newStatus()
+	status.addCheck(&compliance.CheckStatus{RuleID: "rule-1"})
+
+	status.updateCheck("rule-1", &event.Event{ResourceType: "docker_container", ResourceID: "c1", Result: "failed"})
+	status.updateCheck("rule-1", &event.Event{ResourceType: "docker_container", ResourceID: "c2", Result: "passed"})
+	status.updateCheck("rule-1", &event.Event{ResourceType: "docker_container", ResourceID: "c1", Result: "passed"})
+
+	checks := status.getChecksStatus()
+	assert.Len(t, checks, 1)
+	assert.Equal(t, []*event.Event{
+		{ResourceType: "docker_container", ResourceID: "c1", Result: "passed"},
+		{ResourceType: "docker_container", ResourceID: "c2", Result: "passed"},
+	}, checks[0].Events)
+}
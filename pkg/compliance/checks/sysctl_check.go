@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const procSysPath = "/proc/sys"
+
+var sysctlReportedFields = []string{
+	compliance.SysctlFieldName,
+	compliance.SysctlFieldValue,
+}
+
+func resolveSysctl(_ context.Context, e env.Env, id string, res compliance.Resource) (interface{}, error) {
+	if res.Sysctl == nil {
+		return nil, fmt.Errorf("%s: expecting sysctl resource in sysctl check", id)
+	}
+
+	sysctl := res.Sysctl
+	if sysctl.Name == "" || strings.Contains(sysctl.Name, "..") {
+		return nil, fmt.Errorf("%s: invalid sysctl name `%s`", id, sysctl.Name)
+	}
+
+	path := e.NormalizeToHostRoot(filepath.Join(procSysPath, strings.Replace(sysctl.Name, ".", "/", -1)))
+
+	log.Debugf("%s: reading kernel parameter %s from %s", id, sysctl.Name, path)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eval.Instance{
+		Vars: eval.VarMap{
+			compliance.SysctlFieldName: sysctl.Name,
+			// values made of several fields are separated by tabs, they are normalized as single spaces
+			compliance.SysctlFieldValue: strings.Join(strings.Fields(string(data)), " "),
+		},
+	}, nil
+}
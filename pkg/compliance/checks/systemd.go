@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux
+
+package checks
+
+import (
+	"github.com/coreos/go-systemd/dbus"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+)
+
+func newSystemdClient() (env.SystemdClient, error) {
+	conn, err := dbus.New()
+	if err != nil {
+		return nil, err
+	}
+
+	return &systemdClient{
+		conn: conn,
+	}, nil
+}
+
+type systemdClient struct {
+	conn *dbus.Conn
+}
+
+// GetUnitProperties returns the properties of a systemd unit
+func (c *systemdClient) GetUnitProperties(unit string) (map[string]interface{}, error) {
+	return c.conn.GetUnitProperties(unit)
+}
+
+func (c *systemdClient) Close() error {
+	c.conn.Close()
+	return nil
+}
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var systemdReportedFields = []string{
+	compliance.SystemdFieldUnit,
+	compliance.SystemdFieldLoadState,
+	compliance.SystemdFieldActiveState,
+	compliance.SystemdFieldSubState,
+	compliance.SystemdFieldUnitFileState,
+}
+
+// systemd properties of a unit exposed by the fields of the systemd resource
+var systemdUnitProperties = map[string]string{
+	compliance.SystemdFieldLoadState:     "LoadState",
+	compliance.SystemdFieldActiveState:   "ActiveState",
+	compliance.SystemdFieldSubState:      "SubState",
+	compliance.SystemdFieldUnitFileState: "UnitFileState",
+}
+
+func resolveSystemd(_ context.Context, e env.Env, id string, res compliance.Resource) (interface{}, error) {
+	if res.Systemd == nil {
+		return nil, fmt.Errorf("%s: expecting systemd resource in systemd check", id)
+	}
+
+	unit := res.Systemd.Unit
+
+	client := e.SystemdClient()
+	if client == nil {
+		return nil, fmt.Errorf("systemd client not configured")
+	}
+
+	log.Debugf("%s: querying systemd unit %s", id, unit)
+
+	properties, err := client.GetUnitProperties(unit)
+	if err != nil {
+		return nil, log.Errorf("%s: unable to get properties of systemd unit %s: %v", id, unit, err)
+	}
+
+	vars := eval.VarMap{
+		compliance.SystemdFieldUnit: unit,
+	}
+
+	// a unit that doesn't exist is reported with the `not-found` load state
+	for field, property := range systemdUnitProperties {
+		value, _ := properties[property].(string)
+		vars[field] = value
+	}
+
+	return &eval.Instance{
+		Vars: vars,
+	}, nil
+}
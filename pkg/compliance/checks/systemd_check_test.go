@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSystemdCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		unit       string
+		condition  string
+		properties map[string]interface{}
+		err        error
+
+		expectReport *compliance.Report
+		expectError  bool
+	}{
+		{
+			name:      "unit active and enabled",
+			unit:      "auditd.service",
+			condition: `systemd.activeState == "active" && systemd.unitFileState == "enabled"`,
+			properties: map[string]interface{}{
+				"Id":            "auditd.service",
+				"LoadState":     "loaded",
+				"ActiveState":   "active",
+				"SubState":      "running",
+				"UnitFileState": "enabled",
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"systemd.unit":          "auditd.service",
+					"systemd.loadState":     "loaded",
+					"systemd.activeState":   "active",
+					"systemd.subState":      "running",
+					"systemd.unitFileState": "enabled",
+				},
+			},
+		},
+		{
+			name:      "unit not found",
+			unit:      "rsyncd.service",
+			condition: `systemd.loadState == "not-found" || systemd.unitFileState != "enabled"`,
+			properties: map[string]interface{}{
+				"LoadState":   "not-found",
+				"ActiveState": "inactive",
+				"SubState":    "dead",
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"systemd.unit":          "rsyncd.service",
+					"systemd.loadState":     "not-found",
+					"systemd.activeState":   "inactive",
+					"systemd.subState":      "dead",
+					"systemd.unitFileState": "",
+				},
+			},
+		},
+		{
+			name:        "dbus error",
+			unit:        "auditd.service",
+			condition:   `systemd.activeState == "active"`,
+			err:         errors.New("connection closed"),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			client := &mocks.SystemdClient{}
+			defer client.AssertExpectations(t)
+			client.On("GetUnitProperties", test.unit).Return(test.properties, test.err)
+
+			env := &mocks.Env{}
+			env.On("SystemdClient").Return(client)
+
+			resource := compliance.Resource{
+				Systemd: &compliance.SystemdUnit{
+					Unit: test.unit,
+				},
+				Condition: test.condition,
+			}
+
+			systemdCheck, err := newResourceCheck(env, "rule-id", resource)
+			assert.NoError(err)
+
+			result, err := systemdCheck.check(env)
+			assert.Equal(test.expectReport, result)
+			assert.Equal(test.expectError, err != nil)
+		})
+	}
+}
+
+func TestSystemdCheckNoClient(t *testing.T) {
+	assert := assert.New(t)
+
+	env := &mocks.Env{}
+	env.On("SystemdClient").Return(nil)
+
+	resource := compliance.Resource{
+		Systemd: &compliance.SystemdUnit{
+			Unit: "auditd.service",
+		},
+		Condition: `systemd.activeState == "active"`,
+	}
+
+	_, err := resolveSystemd(context.Background(), env, "rule-id", resource)
+	assert.Error(err)
+}
@@ -0,0 +1,297 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+// ExportFormat represents the format of an exported compliance report
+type ExportFormat string
+
+const (
+	// ExportFormatJSON is used for a report exported as a JSON document
+	ExportFormatJSON = ExportFormat("json")
+	// ExportFormatSARIF is used for a report exported as a SARIF 2.1.0 log
+	ExportFormatSARIF = ExportFormat("sarif")
+)
+
+const (
+	// NotRun is used to report a check that didn't run, because of an initialization error for instance
+	NotRun = "not_run"
+
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifTool    = "datadog-security-agent"
+)
+
+// ExportedCheck describes the result of a check in an exported report
+type ExportedCheck struct {
+	RuleID       string      `json:"rule_id"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description,omitempty"`
+	Framework    string      `json:"framework"`
+	Version      string      `json:"version,omitempty"`
+	Source       string      `json:"source,omitempty"`
+	ResourceType string      `json:"resource_type,omitempty"`
+	ResourceID   string      `json:"resource_id,omitempty"`
+	Result       string      `json:"result"`
+	Data         interface{} `json:"data,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// ExportSummary counts the checks of an exported report by result
+type ExportSummary struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+	Error  int `json:"error"`
+	NotRun int `json:"not_run"`
+}
+
+// ExportReport is a point-in-time document describing the results of compliance checks
+type ExportReport struct {
+	Hostname     string           `json:"hostname"`
+	AgentVersion string           `json:"agent_version"`
+	Timestamp    time.Time        `json:"timestamp"`
+	Summary      ExportSummary    `json:"summary"`
+	Checks       []*ExportedCheck `json:"checks"`
+}
+
+// NewExportReport returns a report of the given checks status, with a check result for each resource checked by a rule
+func NewExportReport(hostname, agentVersion string, timestamp time.Time, checks CheckStatusList) *ExportReport {
+	report := &ExportReport{
+		Hostname:     hostname,
+		AgentVersion: agentVersion,
+		Timestamp:    timestamp,
+		Checks:       make([]*ExportedCheck, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		events := check.Events
+		if len(events) == 0 && check.LastEvent != nil {
+			events = []*event.Event{check.LastEvent}
+		}
+
+		if check.InitError != nil || len(events) == 0 {
+			report.add(newExportedCheck(check, nil))
+			continue
+		}
+
+		for _, e := range events {
+			report.add(newExportedCheck(check, e))
+		}
+	}
+
+	return report
+}
+
+// newExportedCheck returns the result of a check for the given event, or a check that didn't run when nil
+func newExportedCheck(check *CheckStatus, e *event.Event) *ExportedCheck {
+	exported := &ExportedCheck{
+		RuleID:      check.RuleID,
+		Name:        check.Name,
+		Description: check.Description,
+		Framework:   check.Framework,
+		Version:     check.Version,
+		Source:      check.Source,
+		Result:      NotRun,
+	}
+
+	if check.InitError != nil {
+		exported.Error = check.InitError.Error()
+	} else if e != nil {
+		exported.ResourceType = e.ResourceType
+		exported.ResourceID = e.ResourceID
+		exported.Result = e.Result
+		exported.Data = e.Data
+
+		if data, ok := e.Data.(event.Data); ok && e.Result == event.Error {
+			exported.Error = fmt.Sprint(data["error"])
+			exported.Data = nil
+		}
+	}
+
+	return exported
+}
+
+func (r *ExportReport) add(exported *ExportedCheck) {
+	switch exported.Result {
+	case event.Passed:
+		r.Summary.Passed++
+	case event.Failed:
+		r.Summary.Failed++
+	case event.Error:
+		r.Summary.Error++
+	default:
+		r.Summary.NotRun++
+	}
+
+	r.Checks = append(r.Checks, exported)
+}
+
+// Write writes the report in the given format
+func (r *ExportReport) Write(w io.Writer, format ExportFormat) error {
+	var document interface{}
+	switch format {
+	case ExportFormatJSON:
+		document = r
+	case ExportFormatSARIF:
+		document = r.toSARIF()
+	default:
+		return fmt.Errorf("unknown report format `%s`", format)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription *sarifMessage          `json:"shortDescription,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Kind       string                 `json:"kind"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name    string      `json:"name"`
+			Version string      `json:"version,omitempty"`
+			Rules   []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool                   `json:"executionSuccessful"`
+	EndTimeUTC          string                 `json:"endTimeUtc"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifResultKind returns the kind and the level of a SARIF result for a check result.
+// The level of a result must be "none" unless its kind is "fail".
+func sarifResultKind(result string) (string, string) {
+	switch result {
+	case event.Passed:
+		return "pass", "none"
+	case event.Failed:
+		return "fail", "error"
+	case event.Error:
+		return "review", "none"
+	default:
+		return "notApplicable", "none"
+	}
+}
+
+func (r *ExportReport) toSARIF() *sarifLog {
+	var run sarifRun
+	run.Tool.Driver.Name = sarifTool
+	run.Tool.Driver.Version = r.AgentVersion
+	run.Tool.Driver.Rules = make([]sarifRule, 0, len(r.Checks))
+	run.Results = make([]sarifResult, 0, len(r.Checks))
+	run.Invocations = []sarifInvocation{{
+		ExecutionSuccessful: true,
+		EndTimeUTC:          r.Timestamp.UTC().Format(time.RFC3339),
+		Properties: map[string]interface{}{
+			"hostname": r.Hostname,
+		},
+	}}
+
+	// a rule is described once, with a result for each of its resources
+	ruleIndexes := make(map[string]int)
+	for _, check := range r.Checks {
+		ruleIndex, exists := ruleIndexes[check.RuleID]
+		if !exists {
+			rule := sarifRule{
+				ID:   check.RuleID,
+				Name: check.Name,
+				Properties: map[string]interface{}{
+					"framework": check.Framework,
+					"version":   check.Version,
+					"source":    check.Source,
+				},
+			}
+			if check.Description != "" {
+				rule.ShortDescription = &sarifMessage{Text: check.Description}
+			}
+
+			ruleIndex = len(run.Tool.Driver.Rules)
+			ruleIndexes[check.RuleID] = ruleIndex
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		kind, level := sarifResultKind(check.Result)
+		result := sarifResult{
+			RuleID:    check.RuleID,
+			RuleIndex: ruleIndex,
+			Kind:      kind,
+			Level:     level,
+			Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", check.RuleID, check.Result)},
+		}
+
+		if check.Error != "" {
+			result.Message.Text += ": " + check.Error
+		}
+		if check.ResourceID != "" {
+			result.Locations = []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name: check.ResourceID,
+					Kind: check.ResourceType,
+				}},
+			}}
+		}
+		if check.Data != nil {
+			result.Properties = map[string]interface{}{
+				"data": check.Data,
+			}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	return &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+}
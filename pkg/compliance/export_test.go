@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+var testChecksStatus = CheckStatusList{
+	{
+		RuleID:      "cis-docker-1",
+		Name:        "cis-docker-1: Ensure the container host has been hardened",
+		Description: "Ensure the container host has been hardened",
+		Version:     "1.2.0",
+		Framework:   "cis-docker",
+		Source:      "cis-docker.yaml",
+		LastEvent: &event.Event{
+			AgentRuleID:  "cis-docker-1",
+			ResourceID:   "the-host",
+			ResourceType: "docker_daemon",
+			Result:       event.Passed,
+			Data:         event.Data{"file.path": "/etc/docker/daemon.json", "file.permissions": 0644},
+		},
+	},
+	{
+		RuleID:    "cis-docker-2",
+		Name:      "cis-docker-2",
+		Framework: "cis-docker",
+		LastEvent: &event.Event{
+			AgentRuleID:  "cis-docker-2",
+			ResourceID:   "the-container",
+			ResourceType: "docker_container",
+			Result:       event.Passed,
+		},
+		Events: []*event.Event{
+			{
+				AgentRuleID:  "cis-docker-2",
+				ResourceID:   "the-host",
+				ResourceType: "docker_daemon",
+				Result:       event.Failed,
+				Data:         event.Data{"process.name": "dockerd"},
+			},
+			{
+				AgentRuleID:  "cis-docker-2",
+				ResourceID:   "the-container",
+				ResourceType: "docker_container",
+				Result:       event.Passed,
+			},
+		},
+	},
+	{
+		RuleID:    "cis-docker-3",
+		Name:      "cis-docker-3",
+		Framework: "cis-docker",
+		LastEvent: &event.Event{
+			AgentRuleID: "cis-docker-3",
+			Result:      event.Error,
+			Data:        event.Data{"error": "process not found"},
+		},
+	},
+	{
+		RuleID:    "cis-docker-4",
+		Name:      "cis-docker-4",
+		Framework: "cis-docker",
+		InitError: errors.New("docker client not initialized"),
+	},
+}
+
+func TestExportReport(t *testing.T) {
+	assert := assert.New(t)
+
+	timestamp := time.Date(2020, 10, 18, 10, 0, 0, 0, time.UTC)
+	report := NewExportReport("the-host", "7.24.0", timestamp, testChecksStatus)
+
+	assert.Equal(ExportSummary{Passed: 2, Failed: 1, Error: 1, NotRun: 1}, report.Summary)
+	assert.Len(report.Checks, 5)
+
+	assert.Equal("docker_daemon", report.Checks[0].ResourceType)
+	assert.Equal(event.Data{"file.path": "/etc/docker/daemon.json", "file.permissions": 0644}, report.Checks[0].Data)
+	// a check result is reported for each resource of a rule
+	assert.Equal("cis-docker-2", report.Checks[1].RuleID)
+	assert.Equal("the-host", report.Checks[1].ResourceID)
+	assert.Equal(event.Failed, report.Checks[1].Result)
+	assert.Equal("cis-docker-2", report.Checks[2].RuleID)
+	assert.Equal("the-container", report.Checks[2].ResourceID)
+	assert.Equal(event.Passed, report.Checks[2].Result)
+	assert.Equal("process not found", report.Checks[3].Error)
+	assert.Nil(report.Checks[3].Data)
+	assert.Equal(NotRun, report.Checks[4].Result)
+	assert.Equal("docker client not initialized", report.Checks[4].Error)
+
+	var buf bytes.Buffer
+	assert.NoError(report.Write(&buf, ExportFormatJSON))
+
+	var decoded ExportReport
+	assert.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(report.Summary, decoded.Summary)
+	assert.Equal("cis-docker-1", decoded.Checks[0].RuleID)
+	assert.True(decoded.Timestamp.Equal(timestamp))
+
+	assert.Error(report.Write(&buf, ExportFormat("xml")))
+}
+
+func TestExportReportSARIF(t *testing.T) {
+	assert := assert.New(t)
+
+	report := NewExportReport("the-host", "7.24.0", time.Now(), testChecksStatus)
+
+	var buf bytes.Buffer
+	assert.NoError(report.Write(&buf, ExportFormatSARIF))
+
+	var log sarifLog
+	assert.NoError(json.Unmarshal(buf.Bytes(), &log))
+
+	assert.Equal(sarifVersion, log.Version)
+	assert.Len(log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(sarifTool, run.Tool.Driver.Name)
+	assert.Equal("7.24.0", run.Tool.Driver.Version)
+	assert.Len(run.Tool.Driver.Rules, 4)
+	assert.Equal("Ensure the container host has been hardened", run.Tool.Driver.Rules[0].ShortDescription.Text)
+	assert.Equal("cis-docker", run.Tool.Driver.Rules[0].Properties["framework"])
+	assert.Equal("the-host", run.Invocations[0].Properties["hostname"])
+
+	expected := []struct {
+		ruleID      string
+		ruleIndex   int
+		kind, level string
+	}{
+		{"cis-docker-1", 0, "pass", "none"},
+		{"cis-docker-2", 1, "fail", "error"},
+		{"cis-docker-2", 1, "pass", "none"},
+		{"cis-docker-3", 2, "review", "none"},
+		{"cis-docker-4", 3, "notApplicable", "none"},
+	}
+
+	assert.Len(run.Results, len(expected))
+	for i, result := range run.Results {
+		assert.Equal(expected[i].ruleIndex, result.RuleIndex)
+		assert.Equal(expected[i].ruleID, result.RuleID)
+		assert.Equal(expected[i].kind, result.Kind)
+		assert.Equal(expected[i].level, result.Level)
+	}
+
+	assert.Equal("the-host", run.Results[0].Locations[0].LogicalLocations[0].Name)
+	assert.Equal("docker_daemon", run.Results[0].Locations[0].LogicalLocations[0].Kind)
+	assert.Equal("the-container", run.Results[2].Locations[0].LogicalLocations[0].Name)
+	assert.Empty(run.Results[3].Locations)
+	assert.Equal("cis-docker-3: error: process not found", run.Results[3].Message.Text)
+}
@@ -59,3 +59,19 @@ func (_m *Clients) KubeClient() env.KubeClient {
 
 	return r0
 }
+
+// SystemdClient provides a mock function with given fields:
+func (_m *Clients) SystemdClient() env.SystemdClient {
+	ret := _m.Called()
+
+	var r0 env.SystemdClient
+	if rf, ok := ret.Get(0).(func() env.SystemdClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(env.SystemdClient)
+		}
+	}
+
+	return r0
+}
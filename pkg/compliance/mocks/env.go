@@ -172,3 +172,19 @@ func (_m *Env) Reporter() event.Reporter {
 
 	return r0
 }
+
+// SystemdClient provides a mock function with given fields:
+func (_m *Env) SystemdClient() env.SystemdClient {
+	ret := _m.Called()
+
+	var r0 env.SystemdClient
+	if rf, ok := ret.Get(0).(func() env.SystemdClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(env.SystemdClient)
+		}
+	}
+
+	return r0
+}
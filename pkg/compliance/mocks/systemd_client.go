@@ -0,0 +1,47 @@
+// Code generated by mockery v2.2.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// SystemdClient is an autogenerated mock type for the SystemdClient type
+type SystemdClient struct {
+	mock.Mock
+}
+
+// Close provides a mock function with given fields:
+func (_m *SystemdClient) Close() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUnitProperties provides a mock function with given fields: unit
+func (_m *SystemdClient) GetUnitProperties(unit string) (map[string]interface{}, error) {
+	ret := _m.Called(unit)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(string) map[string]interface{}); ok {
+		r0 = rf(unit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(unit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
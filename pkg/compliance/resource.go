@@ -32,6 +32,12 @@ const (
 	KindKubernetes = ResourceKind("kubernetes")
 	// KindCustom is used for a Custom check
 	KindCustom = ResourceKind("custom")
+	// KindSysctl is used for a Sysctl resource
+	KindSysctl = ResourceKind("sysctl")
+	// KindSystemd is used for a SystemdUnit resource
+	KindSystemd = ResourceKind("systemd")
+	// KindPackage is used for a Package resource
+	KindPackage = ResourceKind("package")
 )
 
 // Resource describes supported resource types observed by a Rule
@@ -44,6 +50,9 @@ type Resource struct {
 	Docker        *DockerResource     `yaml:"docker,omitempty"`
 	KubeApiserver *KubernetesResource `yaml:"kubeApiserver,omitempty"`
 	Custom        *Custom             `yaml:"custom,omitempty"`
+	Sysctl        *Sysctl             `yaml:"sysctl,omitempty"`
+	Systemd       *SystemdUnit        `yaml:"systemd,omitempty"`
+	Package       *Package            `yaml:"package,omitempty"`
 	Condition     string              `yaml:"condition"`
 	Fallback      *Fallback           `yaml:"fallback,omitempty"`
 }
@@ -67,6 +76,12 @@ func (r *Resource) Kind() ResourceKind {
 		return KindKubernetes
 	case r.Custom != nil:
 		return KindCustom
+	case r.Sysctl != nil:
+		return KindSysctl
+	case r.Systemd != nil:
+		return KindSystemd
+	case r.Package != nil:
+		return KindPackage
 	default:
 		return KindInvalid
 	}
@@ -257,3 +272,42 @@ type Custom struct {
 	Name      string            `yaml:"name"`
 	Variables map[string]string `yaml:"variables,omitempty"`
 }
+
+// Fields available for Sysctl
+const (
+	SysctlFieldName  = "sysctl.name"
+	SysctlFieldValue = "sysctl.value"
+)
+
+// Sysctl describes a kernel parameter resource
+type Sysctl struct {
+	Name string `yaml:"name"`
+}
+
+// Fields available for SystemdUnit
+const (
+	SystemdFieldUnit          = "systemd.unit"
+	SystemdFieldLoadState     = "systemd.loadState"
+	SystemdFieldActiveState   = "systemd.activeState"
+	SystemdFieldSubState      = "systemd.subState"
+	SystemdFieldUnitFileState = "systemd.unitFileState"
+)
+
+// SystemdUnit describes a systemd unit resource
+type SystemdUnit struct {
+	Unit string `yaml:"unit"`
+}
+
+// Fields & functions available for Package
+const (
+	PackageFieldName      = "package.name"
+	PackageFieldVersion   = "package.version"
+	PackageFieldInstalled = "package.installed"
+
+	PackageFuncVersionCompare = "package.versionCompare"
+)
+
+// Package describes a package resource from the dpkg or rpm database
+type Package struct {
+	Name string `yaml:"name"`
+}
@@ -62,6 +62,25 @@ docker:
 condition: docker.template("{{ $.Config.Healthcheck }}") != ""
 `
 
+const testResourceSysctl = `
+sysctl:
+  name: net.ipv4.ip_forward
+condition: sysctl.value == "0"
+`
+
+const testResourceSystemd = `
+systemd:
+  unit: auditd.service
+condition: systemd.activeState == "active"
+`
+
+const testResourcePackage = `
+package:
+  name: telnet
+condition: >-
+  !package.installed
+`
+
 func TestResources(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -150,6 +169,36 @@ func TestResources(t *testing.T) {
 				Condition: `docker.template("{{ $.Config.Healthcheck }}") != ""`,
 			},
 		},
+		{
+			name:  "sysctl",
+			input: testResourceSysctl,
+			expected: Resource{
+				Sysctl: &Sysctl{
+					Name: "net.ipv4.ip_forward",
+				},
+				Condition: `sysctl.value == "0"`,
+			},
+		},
+		{
+			name:  "systemd",
+			input: testResourceSystemd,
+			expected: Resource{
+				Systemd: &SystemdUnit{
+					Unit: "auditd.service",
+				},
+				Condition: `systemd.activeState == "active"`,
+			},
+		},
+		{
+			name:  "package",
+			input: testResourcePackage,
+			expected: Resource{
+				Package: &Package{
+					Name: "telnet",
+				},
+				Condition: `!package.installed`,
+			},
+		},
 	}
 
 	for _, test := range tests {
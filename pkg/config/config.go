@@ -351,6 +351,12 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("statsd_forward_port", 0)
 	config.BindEnvAndSetDefault("statsd_metric_namespace", "")
 	config.BindEnvAndSetDefault("statsd_metric_namespace_blacklist", StandardStatsdPrefixes)
+	// Cluster-level aggregation of DogStatsD metrics
+	config.BindEnvAndSetDefault("cluster_aggregation.enabled", false) // Cluster Agent side, runs a DogStatsD server
+	config.BindEnvAndSetDefault("cluster_aggregation.forward.host", "")
+	config.BindEnvAndSetDefault("cluster_aggregation.forward.port", 8125)
+	config.BindEnvAndSetDefault("cluster_aggregation.forward.prefixes", []string{})
+	config.BindEnvAndSetDefault("cluster_aggregation.forward.tags", []string{})
 	// Autoconfig
 	config.BindEnvAndSetDefault("autoconf_template_dir", "/datadog/check_configs")
 	config.BindEnvAndSetDefault("exclude_pause_container", true)
@@ -399,6 +405,7 @@ func InitConfig(config Config) {
 
 	config.BindEnvAndSetDefault("kubelet_tls_verify", true)
 	config.BindEnvAndSetDefault("collect_kubernetes_events", false)
+	config.BindEnvAndSetDefault("kubernetes_events_as_logs", false)
 	config.BindEnvAndSetDefault("kubelet_client_ca", "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt")
 
 	config.BindEnvAndSetDefault("kubelet_auth_token_path", "")
@@ -413,6 +420,7 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("kubernetes_metadata_tag_update_freq", 60) // Polling frequency of the Agent to the DCA in seconds (gets the local cache if the DCA is disabled)
 	config.BindEnvAndSetDefault("kubernetes_apiserver_client_timeout", 10)
 	config.BindEnvAndSetDefault("kubernetes_map_services_on_ip", false) // temporary opt-out of the new mapping logic
+	config.BindEnvAndSetDefault("kubernetes_collect_pod_ips", false)    // serve the metadata of the pods owning an IP from the cluster agent
 	config.BindEnvAndSetDefault("kubernetes_apiserver_use_protobuf", false)
 
 	config.SetKnown("prometheus_scrape.checks") // defines any extra prometheus/openmetrics check configurations to be handled by the prometheus config provider
@@ -605,6 +613,10 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("cluster_checks.extra_tags", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.advanced_dispatching_enabled", false)
 	config.BindEnvAndSetDefault("cluster_checks.clc_runners_port", 5005)
+	config.BindEnvAndSetDefault("cluster_checks.persist_dispatch_state", false)
+	config.BindEnvAndSetDefault("cluster_checks.state_configmap_name", "datadog-cluster-checks-state")
+	config.BindEnvAndSetDefault("cluster_checks.state_restore_grace_period", 60)   // value in seconds, should match the grace time of the node-agents
+	config.BindEnvAndSetDefault("cluster_checks.node_labels", map[string]string{}) // labels reported to the cluster agent in addition to the node labels, to honor placement constraints
 	// Cluster check runner
 	config.BindEnvAndSetDefault("clc_runner_enabled", false)
 	config.BindEnvAndSetDefault("clc_runner_host", "") // must be set using the Kubernetes downward API
@@ -624,7 +636,13 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("admission_controller.inject_config.endpoint", "/injectconfig")
 	config.BindEnvAndSetDefault("admission_controller.inject_tags.enabled", true)
 	config.BindEnvAndSetDefault("admission_controller.inject_tags.endpoint", "/injecttags")
-	config.BindEnvAndSetDefault("admission_controller.pod_owners_cache_validity", 10) // in minutes
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.endpoint", "/injectlib")
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.container_registry", "gcr.io/datadoghq")
+	config.BindEnvAndSetDefault("admission_controller.validate_ad_annotations.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.validate_ad_annotations.endpoint", "/validateadannotations")
+	config.BindEnvAndSetDefault("admission_controller.validate_ad_annotations.reject", false) // reject pods with invalid annotations instead of only reporting them
+	config.BindEnvAndSetDefault("admission_controller.pod_owners_cache_validity", 10)         // in minutes
 
 	// Telemetry
 	// Enable telemetry metrics on the internals of the Agent.
@@ -708,9 +726,14 @@ func InitConfig(config Config) {
 	config.SetKnown("system_probe_config.enable_tcp_queue_length")
 	config.SetKnown("system_probe_config.enable_oom_kill")
 	config.SetKnown("system_probe_config.enable_tracepoints")
+	config.SetKnown("system_probe_config.enable_connection_aggregation")
+	config.SetKnown("system_probe_config.replay_record_file")
+	config.SetKnown("system_probe_config.replay_record_max_size")
 	config.SetKnown("system_probe_config.windows.enable_monotonic_count")
 	config.SetKnown("system_probe_config.windows.driver_buffer_size")
 	config.SetKnown("network_config.enabled")
+	config.SetKnown("network_config.enable_kubernetes_resolution")
+	config.SetKnown("network_config.kubernetes_resolution_retention_in_s")
 
 	// Network
 	config.BindEnv("network.id") //nolint:errcheck
@@ -741,6 +764,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("runtime_security_config.run_path", defaultRunPath)
 	config.BindEnvAndSetDefault("runtime_security_config.event_server.burst", 40)
 	config.BindEnvAndSetDefault("runtime_security_config.event_server.rate", 10)
+	config.BindEnvAndSetDefault("runtime_security_config.envs_denylist", []string{})
+	config.SetKnown("runtime_security_config.sinks") // defines the local sinks, file, syslog or webhook, the security events are written to
 
 	// command line options
 	config.SetKnown("cmd.check.fullsketches")
@@ -0,0 +1,306 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package dogstatsd
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/ckey"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	clusterForwarderFlushInterval = 100 * time.Millisecond
+	// clusterCounterInterval is the interval, in seconds, over which the counters received
+	// by the Cluster Agent are counted, the bucket size of the aggregator
+	clusterCounterInterval = 10
+	// lastValuesExpirationInterval is how often the values older than their max age are removed
+	lastValuesExpirationInterval = time.Minute
+)
+
+var (
+	tlmClusterForwarded = telemetry.NewCounter("dogstatsd", "cluster_forwarded",
+		[]string{"state"}, "Count of metric samples forwarded to the Cluster Agent for cluster-level aggregation")
+)
+
+// clusterForwarder forwards the metric samples matching a prefix or a tag
+// to the DogStatsD endpoint of the Cluster Agent, instead of aggregating them locally.
+type clusterForwarder struct {
+	prefixes        []string
+	tags            []string
+	defaultHostname string
+	conn            net.Conn
+	messages        chan []byte
+	maxPacketSize   int
+	stopChan        chan struct{}
+}
+
+// newClusterForwarder returns a clusterForwarder, or nil if cluster-level aggregation forwarding is not configured
+func newClusterForwarder(defaultHostname string) (*clusterForwarder, error) {
+	host := config.Datadog.GetString("cluster_aggregation.forward.host")
+	port := config.Datadog.GetInt("cluster_aggregation.forward.port")
+	prefixes := config.Datadog.GetStringSlice("cluster_aggregation.forward.prefixes")
+	tags := config.Datadog.GetStringSlice("cluster_aggregation.forward.tags")
+	if host == "" || port == 0 || (len(prefixes) == 0 && len(tags) == 0) {
+		return nil, nil
+	}
+
+	conn, err := net.Dial("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the cluster aggregation endpoint: %s", err)
+	}
+
+	f := &clusterForwarder{
+		prefixes:        prefixes,
+		tags:            tags,
+		defaultHostname: defaultHostname,
+		conn:            conn,
+		messages:        make(chan []byte, config.Datadog.GetInt("dogstatsd_queue_size")),
+		maxPacketSize:   config.Datadog.GetInt("dogstatsd_buffer_size"),
+		stopChan:        make(chan struct{}),
+	}
+	go f.run()
+	return f, nil
+}
+
+// matches returns true if the sample must be aggregated by the Cluster Agent
+func (f *clusterForwarder) matches(sample *metrics.MetricSample) bool {
+	for _, prefix := range f.prefixes {
+		if strings.HasPrefix(sample.Name, prefix) {
+			return true
+		}
+	}
+	for _, filter := range f.tags {
+		for _, tag := range sample.Tags {
+			if tag == filter || (!strings.Contains(filter, ":") && strings.HasPrefix(tag, filter+":")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forward queues a sample to be sent to the Cluster Agent, it never blocks
+func (f *clusterForwarder) forward(sample *metrics.MetricSample) {
+	select {
+	case f.messages <- formatClusterSample(sample, f.defaultHostname):
+		tlmClusterForwarded.Inc("ok")
+	default:
+		tlmClusterForwarded.Inc("dropped")
+	}
+}
+
+// run batches the queued messages into packets sent to the Cluster Agent
+func (f *clusterForwarder) run() {
+	ticker := time.NewTicker(clusterForwarderFlushInterval)
+	defer ticker.Stop()
+
+	var packet bytes.Buffer
+	flush := func() {
+		if packet.Len() == 0 {
+			return
+		}
+		if _, err := f.conn.Write(packet.Bytes()); err != nil {
+			log.Debugf("Dogstatsd: could not forward metrics to the cluster aggregation endpoint: %s", err)
+		}
+		packet.Reset()
+	}
+
+	for {
+		select {
+		case message := <-f.messages:
+			if packet.Len() > 0 && packet.Len()+len(message)+1 > f.maxPacketSize {
+				flush()
+			}
+			if packet.Len() > 0 {
+				packet.WriteByte('\n')
+			}
+			packet.Write(message)
+		case <-ticker.C:
+			flush()
+		case <-f.stopChan:
+			flush()
+			f.conn.Close()
+			return
+		}
+	}
+}
+
+func (f *clusterForwarder) stop() {
+	close(f.stopChan)
+}
+
+// formatClusterSample serializes a sample in the DogStatsD format.
+// The host tag is always set, empty for samples using the default hostname,
+// so that the Cluster Agent aggregates them without host.
+func formatClusterSample(sample *metrics.MetricSample, defaultHostname string) []byte {
+	var b bytes.Buffer
+	b.WriteString(sample.Name)
+	b.WriteByte(':')
+	if sample.Mtype == metrics.SetType {
+		b.WriteString(sample.RawValue)
+	} else {
+		b.WriteString(strconv.FormatFloat(sample.Value, 'f', -1, 64))
+	}
+	b.WriteByte('|')
+	b.WriteString(clusterSampleType(sample.Mtype))
+	if sample.SampleRate > 0 && sample.SampleRate < 1 {
+		b.WriteString("|@")
+		b.WriteString(strconv.FormatFloat(sample.SampleRate, 'f', -1, 64))
+	}
+	b.WriteString("|#")
+	b.WriteString(hostTagPrefix)
+	if sample.Host != defaultHostname {
+		b.WriteString(sample.Host)
+	}
+	for _, tag := range sample.Tags {
+		b.WriteByte(',')
+		b.WriteString(tag)
+	}
+	return b.Bytes()
+}
+
+func clusterSampleType(mtype metrics.MetricType) string {
+	switch mtype {
+	case metrics.CounterType:
+		return "c"
+	case metrics.HistogramType:
+		return "h"
+	case metrics.DistributionType:
+		return "d"
+	case metrics.SetType:
+		return "s"
+	default:
+		return "g"
+	}
+}
+
+type lastValue struct {
+	mtype     metrics.MetricType
+	value     float64
+	timestamp time.Time
+	// intervalStart and current are the start and count of the ongoing interval of a counter
+	intervalStart int64
+	current       float64
+}
+
+// rollCounter makes the value of a counter the count of its last complete interval
+func (v *lastValue) rollCounter(now time.Time) {
+	start := now.Unix() - now.Unix()%clusterCounterInterval
+	if v.intervalStart == start {
+		return
+	}
+	if v.intervalStart == start-clusterCounterInterval {
+		v.value = v.current
+	} else {
+		v.value = 0
+	}
+	v.intervalStart = start
+	v.current = 0
+}
+
+// lastValues keeps the last value of the gauges and counters received by the Cluster Agent DogStatsD,
+// indexed by the context of the series they are aggregated in. Series which didn't receive
+// any sample for longer than maxAge are removed, their values are too old to be used.
+type lastValues struct {
+	sync.Mutex
+	values         map[ckey.ContextKey]lastValue
+	keyGen         *ckey.KeyGenerator
+	maxAge         time.Duration
+	lastExpiration time.Time
+}
+
+func newLastValues(maxAge time.Duration) *lastValues {
+	return &lastValues{
+		values: make(map[ckey.ContextKey]lastValue),
+		keyGen: ckey.NewKeyGenerator(),
+		maxAge: maxAge,
+	}
+}
+
+// lastValuesMaxAge returns the age after which a value isn't used by the external metrics provider anymore
+func lastValuesMaxAge() time.Duration {
+	maxAge := math.Max(config.Datadog.GetFloat64("external_metrics_provider.max_age"), 3*config.Datadog.GetFloat64("external_metrics_provider.rollup"))
+	return time.Duration(maxAge) * time.Second
+}
+
+// expire removes the series whose last sample is older than maxAge, l must be locked
+func (l *lastValues) expire(now time.Time) {
+	if now.Sub(l.lastExpiration) < lastValuesExpirationInterval {
+		return
+	}
+	l.lastExpiration = now
+	for key, v := range l.values {
+		if now.Sub(v.timestamp) > l.maxAge {
+			delete(l.values, key)
+		}
+	}
+}
+
+func (l *lastValues) store(sample *metrics.MetricSample, now time.Time) {
+	if sample.Mtype != metrics.GaugeType && sample.Mtype != metrics.CounterType {
+		return
+	}
+	tags := util.SortUniqInPlace(append([]string(nil), sample.Tags...))
+
+	l.Lock()
+	defer l.Unlock()
+	l.expire(now)
+	key := l.keyGen.Generate(sample.Name, sample.Host, tags)
+	v := l.values[key]
+	v.mtype = sample.Mtype
+	v.timestamp = now
+	if sample.Mtype == metrics.GaugeType {
+		v.value = sample.Value
+	} else {
+		v.rollCounter(now)
+		sampleRate := sample.SampleRate
+		if sampleRate <= 0 {
+			sampleRate = 1
+		}
+		v.current += sample.Value / sampleRate
+	}
+	l.values[key] = v
+}
+
+func (l *lastValues) get(metric string, tags []string, now time.Time) (float64, time.Time, error) {
+	tags = util.SortUniqInPlace(append([]string(nil), tags...))
+
+	l.Lock()
+	defer l.Unlock()
+	key := l.keyGen.Generate(metric, "", tags)
+	v, found := l.values[key]
+	if !found {
+		return 0, time.Time{}, fmt.Errorf("no value received for metric: %s, tags: %v", metric, tags)
+	}
+	if v.mtype == metrics.CounterType {
+		v.rollCounter(now)
+		l.values[key] = v
+	}
+	return v.value, v.timestamp, nil
+}
+
+// GetMetricValue returns the value of the series aggregated without host with the given name
+// and tags by the Cluster Agent DogStatsD, and the time its last sample was received: the last
+// value of a gauge, or the count of a counter over the last complete interval.
+// Only available with cluster-level aggregation enabled.
+func (s *Server) GetMetricValue(metric string, tags []string) (float64, time.Time, error) {
+	if s.lastValues == nil {
+		return 0, time.Time{}, fmt.Errorf("cluster-level aggregation is not enabled")
+	}
+	return s.lastValues.get(metric, tags, time.Now())
+}
@@ -0,0 +1,175 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package dogstatsd
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func TestClusterForwarderMatches(t *testing.T) {
+	f := &clusterForwarder{
+		prefixes: []string{"jobs."},
+		tags:     []string{"aggregation:cluster", "batch_job"},
+	}
+
+	assert.True(t, f.matches(&metrics.MetricSample{Name: "jobs.processed"}))
+	assert.True(t, f.matches(&metrics.MetricSample{Name: "queue.depth", Tags: []string{"env:prod", "aggregation:cluster"}}))
+	assert.True(t, f.matches(&metrics.MetricSample{Name: "queue.depth", Tags: []string{"batch_job:nightly"}}))
+	assert.True(t, f.matches(&metrics.MetricSample{Name: "queue.depth", Tags: []string{"batch_job"}}))
+	assert.False(t, f.matches(&metrics.MetricSample{Name: "queue.depth", Tags: []string{"aggregation:node", "batch_jobs:nightly"}}))
+}
+
+func TestFormatClusterSample(t *testing.T) {
+	assert.Equal(t, "jobs.processed:2|c|@0.5|#host:,env:prod", string(formatClusterSample(&metrics.MetricSample{
+		Name:       "jobs.processed",
+		Value:      2,
+		Mtype:      metrics.CounterType,
+		SampleRate: 0.5,
+		Host:       "node1",
+		Tags:       []string{"env:prod"},
+	}, "node1")))
+	assert.Equal(t, "jobs.users:alice|s|#host:custom", string(formatClusterSample(&metrics.MetricSample{
+		Name:       "jobs.users",
+		RawValue:   "alice",
+		Mtype:      metrics.SetType,
+		SampleRate: 1,
+		Host:       "custom",
+	}, "node1")))
+	assert.Equal(t, "jobs.duration:1.5|h|#host:", string(formatClusterSample(&metrics.MetricSample{
+		Name:       "jobs.duration",
+		Value:      1.5,
+		Mtype:      metrics.HistogramType,
+		SampleRate: 1,
+		Host:       "node1",
+	}, "node1")))
+}
+
+func TestClusterForwarding(t *testing.T) {
+	clusterAgent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer clusterAgent.Close()
+	_, clusterAgentPort, err := net.SplitHostPort(clusterAgent.LocalAddr().String())
+	require.NoError(t, err)
+
+	port, err := getAvailableUDPPort()
+	require.NoError(t, err)
+	config.Datadog.SetDefault("dogstatsd_port", port)
+	config.Datadog.Set("cluster_aggregation.forward.host", "127.0.0.1")
+	config.Datadog.Set("cluster_aggregation.forward.port", clusterAgentPort)
+	config.Datadog.Set("cluster_aggregation.forward.prefixes", []string{"jobs."})
+	defer config.Datadog.Set("cluster_aggregation.forward.host", "")
+
+	agg := mockAggregator()
+	metricOut, _, _ := agg.GetBufferedChannels()
+	s, err := NewServer(agg)
+	require.NoError(t, err, "cannot start DSD")
+	defer s.Stop()
+	require.NotNil(t, s.clusterForwarder)
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(t, err, "cannot connect to DSD socket")
+	defer conn.Close()
+
+	conn.Write([]byte("jobs.processed:1|c|#env:prod\ndaemon:666|g"))
+
+	// Only the metric not matching is aggregated locally
+	select {
+	case res := <-metricOut:
+		require.Len(t, res, 1)
+		assert.Equal(t, "daemon", res[0].Name)
+	case <-time.After(2 * time.Second):
+		assert.FailNow(t, "Timeout on receive channel")
+	}
+
+	buffer := make([]byte, 1024)
+	clusterAgent.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := clusterAgent.ReadFrom(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, "jobs.processed:1|c|#host:,env:prod", string(buffer[:n]))
+}
+
+func TestGetMetricValue(t *testing.T) {
+	s := &Server{}
+	_, _, err := s.GetMetricValue("jobs.queue.depth", nil)
+	assert.Error(t, err)
+
+	now := time.Now()
+	s.lastValues = newLastValues(2 * time.Minute)
+	s.lastValues.store(&metrics.MetricSample{Name: "jobs.queue.depth", Value: 3, Mtype: metrics.GaugeType}, now)
+	s.lastValues.store(&metrics.MetricSample{Name: "jobs.queue.depth", Value: 7, Mtype: metrics.GaugeType}, now)
+	s.lastValues.store(&metrics.MetricSample{Name: "jobs.queue.depth", Value: 2, Mtype: metrics.GaugeType, Tags: []string{"queue:low", "env:prod"}}, now)
+	s.lastValues.store(&metrics.MetricSample{Name: "jobs.queue.depth", Value: 5, Mtype: metrics.GaugeType, Host: "node1"}, now)
+	s.lastValues.store(&metrics.MetricSample{Name: "jobs.duration", Value: 1, Mtype: metrics.HistogramType}, now)
+
+	value, timestamp, err := s.GetMetricValue("jobs.queue.depth", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 7.0, value)
+	assert.WithinDuration(t, now, timestamp, time.Minute)
+
+	// Series are looked up by name and tags, in any order
+	value, _, err = s.GetMetricValue("jobs.queue.depth", []string{"env:prod", "queue:low"})
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, value)
+
+	_, _, err = s.GetMetricValue("jobs.queue.depth", []string{"queue:low"})
+	assert.Error(t, err)
+
+	_, _, err = s.GetMetricValue("jobs.duration", nil)
+	assert.Error(t, err)
+}
+
+func TestGetCounterValue(t *testing.T) {
+	l := newLastValues(2 * time.Minute)
+	start := time.Unix(1000*clusterCounterInterval, 0)
+
+	l.store(&metrics.MetricSample{Name: "jobs.processed", Value: 2, Mtype: metrics.CounterType, SampleRate: 1}, start)
+	l.store(&metrics.MetricSample{Name: "jobs.processed", Value: 1, Mtype: metrics.CounterType, SampleRate: 0.5}, start.Add(time.Second))
+
+	// The count of the ongoing interval is not known yet
+	value, _, err := l.get("jobs.processed", nil, start.Add(2*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, value)
+
+	// The count of the last complete interval is used
+	l.store(&metrics.MetricSample{Name: "jobs.processed", Value: 1, Mtype: metrics.CounterType, SampleRate: 1}, start.Add(clusterCounterInterval*time.Second))
+	value, timestamp, err := l.get("jobs.processed", nil, start.Add((clusterCounterInterval+1)*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, value)
+	assert.Equal(t, start.Add(clusterCounterInterval*time.Second), timestamp)
+
+	// An interval without samples counts 0
+	value, _, err = l.get("jobs.processed", nil, start.Add(3*clusterCounterInterval*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, value)
+}
+
+func TestLastValuesExpiration(t *testing.T) {
+	l := newLastValues(2 * time.Minute)
+	start := time.Now()
+
+	l.store(&metrics.MetricSample{Name: "jobs.queue.depth", Value: 3, Mtype: metrics.GaugeType}, start)
+	l.store(&metrics.MetricSample{Name: "jobs.running", Value: 1, Mtype: metrics.GaugeType}, start)
+
+	// Only the series which keep receiving samples are kept after their max age
+	l.store(&metrics.MetricSample{Name: "jobs.running", Value: 2, Mtype: metrics.GaugeType}, start.Add(2*time.Minute))
+	l.store(&metrics.MetricSample{Name: "jobs.running", Value: 3, Mtype: metrics.GaugeType}, start.Add(3*time.Minute))
+	assert.Len(t, l.values, 1)
+
+	_, _, err := l.get("jobs.queue.depth", nil, start.Add(3*time.Minute))
+	assert.Error(t, err)
+	value, _, err := l.get("jobs.running", nil, start.Add(3*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, value)
+}
@@ -46,12 +46,13 @@ type UDPListener struct {
 	buffer          []byte
 }
 
-// NewUDPListener returns an idle UDP Statsd listener
-func NewUDPListener(packetOut chan Packets, sharedPacketPool *PacketPool) (*UDPListener, error) {
+// NewUDPListener returns an idle UDP Statsd listener, listening to all network
+// interfaces if nonLocalTraffic is true, to bind_host otherwise
+func NewUDPListener(packetOut chan Packets, sharedPacketPool *PacketPool, nonLocalTraffic bool) (*UDPListener, error) {
 	var err error
 	var url string
 
-	if config.Datadog.GetBool("dogstatsd_non_local_traffic") == true {
+	if nonLocalTraffic {
 		// Listen to all network interfaces
 		url = fmt.Sprintf(":%d", config.Datadog.GetInt("dogstatsd_port"))
 	} else {
@@ -22,7 +22,7 @@ import (
 var packetPoolUDP = NewPacketPool(config.Datadog.GetInt("dogstatsd_buffer_size"))
 
 func TestNewUDPListener(t *testing.T) {
-	s, err := NewUDPListener(nil, packetPoolUDP)
+	s, err := NewUDPListener(nil, packetPoolUDP, false)
 	assert.NotNil(t, s)
 	assert.Nil(t, err)
 
@@ -33,8 +33,7 @@ func TestStartStopUDPListener(t *testing.T) {
 	port, err := getAvailableUDPPort()
 	require.Nil(t, err)
 	config.Datadog.SetDefault("dogstatsd_port", port)
-	config.Datadog.SetDefault("dogstatsd_non_local_traffic", false)
-	s, err := NewUDPListener(nil, packetPoolUDP)
+	s, err := NewUDPListener(nil, packetPoolUDP, false)
 	require.NotNil(t, s)
 
 	assert.Nil(t, err)
@@ -64,8 +63,7 @@ func TestUDPNonLocal(t *testing.T) {
 	port, err := getAvailableUDPPort()
 	require.Nil(t, err)
 	config.Datadog.SetDefault("dogstatsd_port", port)
-	config.Datadog.SetDefault("dogstatsd_non_local_traffic", true)
-	s, err := NewUDPListener(nil, packetPoolUDP)
+	s, err := NewUDPListener(nil, packetPoolUDP, true)
 	assert.Nil(t, err)
 	require.NotNil(t, s)
 
@@ -88,8 +86,7 @@ func TestUDPLocalOnly(t *testing.T) {
 	port, err := getAvailableUDPPort()
 	require.Nil(t, err)
 	config.Datadog.SetDefault("dogstatsd_port", port)
-	config.Datadog.SetDefault("dogstatsd_non_local_traffic", false)
-	s, err := NewUDPListener(nil, packetPoolUDP)
+	s, err := NewUDPListener(nil, packetPoolUDP, false)
 	assert.Nil(t, err)
 	require.NotNil(t, s)
 
@@ -117,7 +114,7 @@ func TestUDPReceive(t *testing.T) {
 	config.Datadog.SetDefault("dogstatsd_port", port)
 
 	packetChannel := make(chan Packets)
-	s, err := NewUDPListener(packetChannel, packetPoolUDP)
+	s, err := NewUDPListener(packetChannel, packetPoolUDP, false)
 	require.NotNil(t, s)
 	assert.Nil(t, err)
 
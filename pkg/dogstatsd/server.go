@@ -31,6 +31,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	telemetry_utils "github.com/DataDog/datadog-agent/pkg/telemetry/utils"
 	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -88,6 +89,10 @@ type Server struct {
 	// NOTE(remy): this should probably be dropped and use a throttler logger, see
 	// package (pkg/trace/logutils) for a possible throttler implemetation.
 	disableVerboseLogs bool
+	// clusterForwarder forwards selected metrics to the Cluster Agent for cluster-level aggregation
+	clusterForwarder *clusterForwarder
+	// lastValues keeps the last gauge values when running in the Cluster Agent
+	lastValues *lastValues
 }
 
 // metricStat holds how many times a metric has been
@@ -156,7 +161,9 @@ func NewServer(aggregator *aggregator.BufferedAggregator) (*Server, error) {
 		}
 	}
 	if config.Datadog.GetInt("dogstatsd_port") > 0 {
-		udpListener, err := listeners.NewUDPListener(packetsChannel, sharedPacketPool)
+		// The Cluster Agent only receives the metrics forwarded by node agents, over the network
+		nonLocalTraffic := config.Datadog.GetBool("dogstatsd_non_local_traffic") || flavor.GetFlavor() == flavor.ClusterAgent
+		udpListener, err := listeners.NewUDPListener(packetsChannel, sharedPacketPool, nonLocalTraffic)
 		if err != nil {
 			log.Errorf(err.Error())
 		} else {
@@ -184,6 +191,11 @@ func NewServer(aggregator *aggregator.BufferedAggregator) (*Server, error) {
 		metricPrefix = metricPrefix + "."
 	}
 	metricPrefixBlacklist := config.Datadog.GetStringSlice("statsd_metric_namespace_blacklist")
+	if metricPrefix != "" && flavor.GetFlavor() == flavor.ClusterAgent {
+		// The metrics received by the Cluster Agent are forwarded by node agents, which already namespaced them
+		log.Infof("Dogstatsd: ignoring statsd_metric_namespace, the metrics forwarded by node agents are already namespaced")
+		metricPrefix = ""
+	}
 
 	defaultHostname, err := util.GetHostname()
 	if err != nil {
@@ -242,6 +254,21 @@ func NewServer(aggregator *aggregator.BufferedAggregator) (*Server, error) {
 		}
 	}
 
+	// cluster-level aggregation
+	// ----------------------
+
+	if flavor.GetFlavor() == flavor.ClusterAgent {
+		s.lastValues = newLastValues(lastValuesMaxAge())
+	} else {
+		clusterForwarder, err := newClusterForwarder(defaultHostname)
+		if err != nil {
+			log.Warnf("Dogstatsd: %s", err)
+		} else if clusterForwarder != nil {
+			log.Infof("Dogstatsd: forwarding metrics matching prefixes %v or tags %v for cluster-level aggregation", clusterForwarder.prefixes, clusterForwarder.tags)
+			s.clusterForwarder = clusterForwarder
+		}
+	}
+
 	// start the workers processing the packets read on the socket
 	// ----------------------
 
@@ -401,6 +428,13 @@ func (s *Server) parsePackets(batcher *batcher, parser *parser, packets []*liste
 				if atomic.LoadUint64(&s.Debug.Enabled) == 1 {
 					s.storeMetricStats(sample)
 				}
+				if s.clusterForwarder != nil && s.clusterForwarder.matches(&sample) {
+					s.clusterForwarder.forward(&sample)
+					continue
+				}
+				if s.lastValues != nil {
+					s.lastValues.store(&sample, time.Now())
+				}
 				batcher.appendSample(sample)
 				if s.histToDist && sample.Mtype == metrics.HistogramType {
 					distSample := sample.Copy()
@@ -482,6 +516,9 @@ func (s *Server) Stop() {
 	if s.Statistics != nil {
 		s.Statistics.Stop()
 	}
+	if s.clusterForwarder != nil {
+		s.clusterForwarder.stop()
+	}
 	s.health.Deregister() //nolint:errcheck
 	s.Started = false
 }
@@ -36,6 +36,7 @@ func NewManager(closedHandler *PerfHandler) *manager.Manager {
 			{Name: string(ConnMap)},
 			{Name: string(TcpStatsMap)},
 			{Name: string(TcpCloseBatchMap)},
+			{Name: "tcp_ongoing_connect_pid"},
 			{Name: "udp_recv_sock"},
 			{Name: string(PortBindingsMap)},
 			{Name: string(UdpPortBindingsMap)},
@@ -63,12 +64,15 @@ func NewManager(closedHandler *PerfHandler) *manager.Manager {
 			{Section: string(TCPClose)},
 			{Section: string(TCPCloseReturn), KProbeMaxActive: maxActive},
 			{Section: string(TCPSetState)},
+			{Section: string(TCPConnect)},
 			{Section: string(IPMakeSkb)},
 			{Section: string(IP6MakeSkb)},
 			{Section: string(UDPRecvMsg)},
 			{Section: string(UDPRecvMsgPre410), MatchFuncName: "^udp_recvmsg$"},
 			{Section: string(UDPRecvMsgReturn), KProbeMaxActive: maxActive},
 			{Section: string(TCPRetransmit)},
+			{Section: string(TCPSendActiveReset)},
+			{Section: string(TCPReset)},
 			{Section: string(InetCskAcceptReturn), KProbeMaxActive: maxActive},
 			{Section: string(TCPv4DestroySock)},
 			{Section: string(UDPDestroySock)},
@@ -34,6 +34,8 @@ const (
 
 	// TCPSetState traces the tcp_set_state() kernel function
 	TCPSetState ProbeName = "kprobe/tcp_set_state"
+	// TCPConnect traces the tcp_connect() kernel function, called when a connection is initiated
+	TCPConnect ProbeName = "kprobe/tcp_connect"
 
 	// TCPCleanupRBuf traces the tcp_cleanup_rbuf() system call
 	TCPCleanupRBuf ProbeName = "kprobe/tcp_cleanup_rbuf"
@@ -59,6 +61,11 @@ const (
 	// TCPRetransmit traces the return value for the tcp_retransmit_skb() system call
 	TCPRetransmit ProbeName = "kprobe/tcp_retransmit_skb"
 
+	// TCPSendActiveReset traces the tcp_send_active_reset() kernel function, called when a TCP reset is sent
+	TCPSendActiveReset ProbeName = "kprobe/tcp_send_active_reset"
+	// TCPReset traces the tcp_reset() kernel function, called when a TCP reset is received
+	TCPReset ProbeName = "kprobe/tcp_reset"
+
 	// InetCskAcceptReturn traces the return value for the inet_csk_accept syscall
 	InetCskAcceptReturn ProbeName = "kretprobe/inet_csk_accept"
 
@@ -82,6 +82,9 @@ static __always_inline int guess_offsets(tracer_status_t* status, struct sock* s
     case GUESS_FAMILY:
         bpf_probe_read(&new_status.family, sizeof(new_status.family), ((char*)skp) + status->offset_family);
         break;
+    case GUESS_SKC_STATE:
+        bpf_probe_read(&new_status.skc_state, sizeof(new_status.skc_state), ((char*)skp) + status->offset_skc_state);
+        break;
     case GUESS_SPORT:
         bpf_probe_read(&new_status.sport, sizeof(new_status.sport), ((char*)skp) + status->offset_sport);
         break;
@@ -23,6 +23,7 @@ static const __u8 GUESS_SADDR_FL4 = 8;
 static const __u8 GUESS_DADDR_FL4 = 9;
 static const __u8 GUESS_SPORT_FL4 = 10;
 static const __u8 GUESS_DPORT_FL4 = 11;
+static const __u8 GUESS_SKC_STATE = 12;
 
 static const __u8 TRACER_STATE_UNINITIALIZED = 0;
 static const __u8 TRACER_STATE_CHECKING = 1;
@@ -52,6 +53,7 @@ typedef struct {
     __u64 offset_daddr_fl4;
     __u64 offset_sport_fl4;
     __u64 offset_dport_fl4;
+    __u64 offset_skc_state;
 
     __u64 err;
 
@@ -68,6 +70,7 @@ typedef struct {
     __u32 daddr_fl4;
     __u16 sport_fl4;
     __u16 dport_fl4;
+    __u8 skc_state;
 
     __u8 ipv6_enabled;
     __u8 fl4_offsets;
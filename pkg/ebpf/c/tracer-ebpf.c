@@ -19,7 +19,7 @@
 #define LOAD_CONSTANT(param, var) asm("%0 = " param " ll" \
                                       : "=r"(var))
 
-enum telemetry_counter{tcp_sent_miscounts, missed_tcp_close, udp_send_processed, udp_send_missed};
+enum telemetry_counter{tcp_sent_miscounts, missed_tcp_close, udp_send_processed, udp_send_missed, tcp_failed_connect, tcp_reset_sent, tcp_reset_received};
 
 /* This is a key/value store with the keys being a conn_tuple_t for send & recv calls
  * and the values being conn_stats_ts_t *.
@@ -159,6 +159,20 @@ struct bpf_map_def SEC("maps/unbound_sockets") unbound_sockets = {
     .namespace = "",
 };
 
+/* This map is used to attribute failed connection attempts to the process which initiated them,
+ * as the connection is only known to have failed in softirq context.
+ * Key: the struct sock* of a connecting TCP socket
+ * Value: the PID returned by bpf_get_current_pid_tgid() when the connection was initiated
+ */
+struct bpf_map_def SEC("maps/tcp_ongoing_connect_pid") tcp_ongoing_connect_pid = {
+    .type = BPF_MAP_TYPE_HASH,
+    .key_size = sizeof(void*),
+    .value_size = sizeof(__u64),
+    .max_entries = 8192,
+    .pinning = 0,
+    .namespace = "",
+};
+
 /* This map is used for telemetry in kernelspace
  * only key 0 is used
  * value is a telemetry object
@@ -208,6 +222,12 @@ static __always_inline __u64 offset_family() {
     return val;
 }
 
+static __always_inline __u64 offset_skc_state() {
+    __u64 val = 0;
+    LOAD_CONSTANT("offset_skc_state", val);
+    return val;
+}
+
 static __always_inline __u64 offset_saddr() {
     __u64 val = 0;
     LOAD_CONSTANT("offset_saddr", val);
@@ -427,6 +447,18 @@ static __always_inline void update_tcp_stats(conn_tuple_t* t, tcp_stats_t stats)
     if (stats.state_transitions > 0) {
         val->state_transitions |= stats.state_transitions;
     }
+
+    if (stats.failed_connects > 0) {
+        __sync_fetch_and_add(&val->failed_connects, stats.failed_connects);
+    }
+
+    if (stats.resets_sent > 0) {
+        __sync_fetch_and_add(&val->resets_sent, stats.resets_sent);
+    }
+
+    if (stats.resets_received > 0) {
+        __sync_fetch_and_add(&val->resets_received, stats.resets_received);
+    }
 }
 
 static __always_inline void increment_telemetry_count(enum telemetry_counter counter_name) {
@@ -452,6 +484,15 @@ static __always_inline void increment_telemetry_count(enum telemetry_counter cou
         case udp_send_missed:
             __sync_fetch_and_add(&val->udp_sends_missed, 1);
             break;
+        case tcp_failed_connect:
+            __sync_fetch_and_add(&val->tcp_failed_connects, 1);
+            break;
+        case tcp_reset_sent:
+            __sync_fetch_and_add(&val->tcp_resets_sent, 1);
+            break;
+        case tcp_reset_received:
+            __sync_fetch_and_add(&val->tcp_resets_received, 1);
+            break;
     }
     return;
 }
@@ -483,6 +524,9 @@ static __always_inline void cleanup_tcp_conn(struct pt_regs* __attribute__((unus
     if (cst != NULL) {
         cst->timestamp = bpf_ktime_get_ns();
         conn.conn_stats = *cst;
+    } else {
+        // Connections which never sent nor received data, such as failed connection attempts
+        conn.conn_stats.timestamp = bpf_ktime_get_ns();
     }
 
     // Batch TCP closed connections before generating a perf event
@@ -544,6 +588,27 @@ static __always_inline int handle_retransmit(struct sock* sk) {
     return 0;
 }
 
+static __always_inline int handle_reset(struct sock* sk, bool sent) {
+    conn_tuple_t t = {};
+    u64 zero = 0;
+
+    if (!read_conn_tuple(&t, sk, zero, CONN_TYPE_TCP)) {
+        return 0;
+    }
+
+    tcp_stats_t stats = {};
+    if (sent) {
+        stats.resets_sent = 1;
+        increment_telemetry_count(tcp_reset_sent);
+    } else {
+        stats.resets_received = 1;
+        increment_telemetry_count(tcp_reset_received);
+    }
+    update_tcp_stats(&t, stats);
+
+    return 0;
+}
+
 static __always_inline void handle_tcp_stats(conn_tuple_t* t, struct sock* sk) {
     u32 rtt = 0, rtt_var = 0;
     bpf_probe_read(&rtt, sizeof(rtt), ((char*)sk) + offset_rtt());
@@ -803,28 +868,84 @@ int kprobe__tcp_retransmit_skb(struct pt_regs* ctx) {
     return handle_retransmit(sk);
 }
 
+SEC("kprobe/tcp_connect")
+int kprobe__tcp_connect(struct pt_regs* ctx) {
+    struct sock* sk = (struct sock*)PT_REGS_PARM1(ctx);
+    u64 pid_tgid = bpf_get_current_pid_tgid();
+    log_debug("kprobe/tcp_connect: pid_tgid: %d\n", pid_tgid);
+
+    bpf_map_update_elem(&tcp_ongoing_connect_pid, &sk, &pid_tgid, BPF_ANY);
+    return 0;
+}
+
 SEC("kprobe/tcp_set_state")
 int kprobe__tcp_set_state(struct pt_regs* ctx) {
     u8 state = (u8)PT_REGS_PARM2(ctx);
 
-    // For now we're tracking only TCP_ESTABLISHED
-    if (state != TCP_ESTABLISHED) {
+    // For now we're tracking only TCP_ESTABLISHED, and TCP_CLOSE for failed connection attempts
+    if (state != TCP_ESTABLISHED && state != TCP_CLOSE) {
         return 0;
     }
 
+    // The state may change in softirq context, where the current PID is unrelated to the socket:
+    // the TCP stats are keyed by the connection tuple only
     struct sock* sk = (struct sock*)PT_REGS_PARM1(ctx);
-    u64 pid_tgid = bpf_get_current_pid_tgid();
+    u64 zero = 0;
+    if (state == TCP_ESTABLISHED) {
+        bpf_map_delete_elem(&tcp_ongoing_connect_pid, &sk);
+
+        conn_tuple_t t = {};
+        if (!read_conn_tuple(&t, sk, zero, CONN_TYPE_TCP)) {
+            return 0;
+        }
+
+        tcp_stats_t stats = { .state_transitions = (1 << state) };
+        update_tcp_stats(&t, stats);
+        return 0;
+    }
+
+    // tcp_set_state is called before the state is updated, so skc_state holds the previous state
+    u8 old_state = 0;
+    bpf_probe_read(&old_state, sizeof(old_state), ((char*)sk) + offset_skc_state());
+    if (old_state != TCP_SYN_SENT) {
+        return 0;
+    }
+
+    // A failed connection attempt never sent or received data, so it has no conn_stats entry
+    // and would never be reported: report it as closed right away, while its tuple is still set,
+    // and attributed to the process which initiated it
+    u64* pid_tgid = bpf_map_lookup_elem(&tcp_ongoing_connect_pid, &sk);
     conn_tuple_t t = {};
-    if (!read_conn_tuple(&t, sk, pid_tgid, CONN_TYPE_TCP)) {
+    if (!read_conn_tuple(&t, sk, pid_tgid != NULL ? *pid_tgid : zero, CONN_TYPE_TCP)) {
+        bpf_map_delete_elem(&tcp_ongoing_connect_pid, &sk);
         return 0;
     }
+    bpf_map_delete_elem(&tcp_ongoing_connect_pid, &sk);
 
-    tcp_stats_t stats = { .state_transitions = (1 << state) };
+    tcp_stats_t stats = { .failed_connects = 1 };
     update_tcp_stats(&t, stats);
+    increment_telemetry_count(tcp_failed_connect);
 
+    cleanup_tcp_conn(ctx, &t);
     return 0;
 }
 
+SEC("kprobe/tcp_send_active_reset")
+int kprobe__tcp_send_active_reset(struct pt_regs* ctx) {
+    struct sock* sk = (struct sock*)PT_REGS_PARM1(ctx);
+    log_debug("kprobe/tcp_send_active_reset\n");
+
+    return handle_reset(sk, true);
+}
+
+SEC("kprobe/tcp_reset")
+int kprobe__tcp_reset(struct pt_regs* ctx) {
+    struct sock* sk = (struct sock*)PT_REGS_PARM1(ctx);
+    log_debug("kprobe/tcp_reset\n");
+
+    return handle_reset(sk, false);
+}
+
 SEC("kretprobe/inet_csk_accept")
 int kretprobe__inet_csk_accept(struct pt_regs* ctx) {
     struct sock* newsk = (struct sock*)PT_REGS_RC(ctx);
@@ -45,6 +45,12 @@ typedef struct {
     __u32 rtt;
     __u32 rtt_var;
 
+    // Number of connection attempts which were refused or timed out (SYN_SENT -> CLOSE)
+    __u32 failed_connects;
+    // Number of TCP resets sent and received
+    __u32 resets_sent;
+    __u32 resets_received;
+
     // Bit mask containing all TCP state transitions tracked by our tracer
     __u16 state_transitions;
 } tcp_stats_t;
@@ -80,6 +86,9 @@ typedef struct {
     __u64 missed_tcp_close;
     __u64 udp_sends_processed;
     __u64 udp_sends_missed;
+    __u64 tcp_failed_connects;
+    __u64 tcp_resets_sent;
+    __u64 tcp_resets_received;
 } telemetry_t;
 
 #define PORT_LISTENING 1
@@ -103,6 +103,17 @@ type Config struct {
 	// EnableTracepoints enables use of tracepoints instead of kprobes for probing syscalls (if available on system)
 	EnableTracepoints bool
 
+	// EnableConnectionAggregation collapses outgoing connections from the same process to the same remote
+	// address and port into a single connection before they are returned to clients
+	EnableConnectionAggregation bool
+
+	// ReplayRecordFile is the path of the file where the tracer events are recorded, so that they can
+	// be replayed with `system-probe -replay`. Recording is disabled when empty.
+	ReplayRecordFile string
+
+	// ReplayRecordMaxSize is the size, in bytes, after which no more tracer events are recorded. 0 means no limit.
+	ReplayRecordMaxSize int64
+
 	// EnableMonotonicCount (Windows only) determines if we will calculate send/recv bytes of connections with headers and retransmits
 	EnableMonotonicCount bool
 
@@ -138,6 +149,7 @@ func NewDefaultConfig() *Config {
 		CollectDNSStats:      true,
 		DNSTimeout:           15 * time.Second,
 		OffsetGuessThreshold: 400,
+		ReplayRecordMaxSize:  100 * 1024 * 1024,
 		EnableMonotonicCount: false,
 	}
 }
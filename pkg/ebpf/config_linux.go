@@ -28,9 +28,12 @@ func (c *Config) EnabledProbes(pre410Kernel bool) (map[bytecode.ProbeName]struct
 		enabled[bytecode.TCPClose] = struct{}{}
 		enabled[bytecode.TCPCloseReturn] = struct{}{}
 		enabled[bytecode.TCPRetransmit] = struct{}{}
+		enabled[bytecode.TCPSendActiveReset] = struct{}{}
+		enabled[bytecode.TCPReset] = struct{}{}
 		enabled[bytecode.InetCskAcceptReturn] = struct{}{}
 		enabled[bytecode.TCPv4DestroySock] = struct{}{}
 		enabled[bytecode.TCPSetState] = struct{}{}
+		enabled[bytecode.TCPConnect] = struct{}{}
 
 		if c.BPFDebug {
 			enabled[bytecode.TCPSendMsgReturn] = struct{}{}
@@ -170,6 +170,10 @@ type ConnStatsWithTimestamp C.conn_stats_ts_t
 __u32 retransmits;
 __u32 rtt;
 __u32 rtt_var;
+__u32 failed_connects;
+__u32 resets_sent;
+__u32 resets_received;
+__u16 state_transitions;
 */
 type TCPStats C.tcp_stats_t
 
@@ -224,22 +228,25 @@ func connStats(t *ConnTuple, s *ConnStatsWithTimestamp, tcpStats *TCPStats) netw
 	}
 
 	return network.ConnectionStats{
-		Pid:                     uint32(t.pid),
-		Type:                    connType(metadata),
-		Family:                  family,
-		NetNS:                   uint32(t.netns),
-		Source:                  source,
-		Dest:                    dest,
-		SPort:                   uint16(t.sport),
-		DPort:                   uint16(t.dport),
-		MonotonicSentBytes:      uint64(s.sent_bytes),
-		MonotonicRecvBytes:      uint64(s.recv_bytes),
-		MonotonicRetransmits:    uint32(tcpStats.retransmits),
-		MonotonicTCPEstablished: uint32(tcpStats.state_transitions >> C.TCP_ESTABLISHED & 1),
-		MonotonicTCPClosed:      uint32(tcpStats.state_transitions >> C.TCP_CLOSE & 1),
-		RTT:                     uint32(tcpStats.rtt),
-		RTTVar:                  uint32(tcpStats.rtt_var),
-		LastUpdateEpoch:         uint64(s.timestamp),
+		Pid:                        uint32(t.pid),
+		Type:                       connType(metadata),
+		Family:                     family,
+		NetNS:                      uint32(t.netns),
+		Source:                     source,
+		Dest:                       dest,
+		SPort:                      uint16(t.sport),
+		DPort:                      uint16(t.dport),
+		MonotonicSentBytes:         uint64(s.sent_bytes),
+		MonotonicRecvBytes:         uint64(s.recv_bytes),
+		MonotonicRetransmits:       uint32(tcpStats.retransmits),
+		MonotonicTCPEstablished:    uint32(tcpStats.state_transitions >> C.TCP_ESTABLISHED & 1),
+		MonotonicTCPClosed:         uint32(tcpStats.state_transitions >> C.TCP_CLOSE & 1),
+		MonotonicTCPFailedConnects: uint32(tcpStats.failed_connects),
+		MonotonicTCPResetsSent:     uint32(tcpStats.resets_sent),
+		MonotonicTCPResetsReceived: uint32(tcpStats.resets_received),
+		RTT:                        uint32(tcpStats.rtt),
+		RTTVar:                     uint32(tcpStats.rtt_var),
+		LastUpdateEpoch:            uint64(s.timestamp),
 	}
 }
 
@@ -70,6 +70,7 @@ const (
 	guessDaddrFl4 = 9
 	guessSportFl4 = 10
 	guessDportFl4 = 11
+	guessSkcState = 12
 )
 
 const (
@@ -97,6 +98,8 @@ var whatString = map[C.__u64]string{
 	guessDaddrFl4: "destination address flowi4",
 	guessSportFl4: "source port flowi4",
 	guessDportFl4: "destination port flowi4",
+
+	guessSkcState: "socket state",
 }
 
 const (
@@ -124,6 +127,11 @@ type fieldValues struct {
 	rttVar    uint32
 	daddrIPv6 [4]uint32
 
+	// skcState is the state of the socket the last event was generated for, which is the
+	// listening socket rather than the established connection when skcStateListen is set
+	skcState       uint8
+	skcStateListen bool
+
 	// Used for guessing offsets in struct flowi4
 	saddrFl4 uint32
 	daddrFl4 uint32
@@ -307,13 +315,27 @@ func checkAndUpdateCurrentOffset(mp *ebpf.Map, status *tracerStatus, expected *f
 		status.daddr = C.__u32(expected.daddr)
 	case guessFamily:
 		if status.family == C.__u16(expected.family) {
-			logAndAdvance(status, status.offset_family, guessSport)
-			// we know the sport ((struct inet_sock)->inet_sport) is
-			// after the family field, so we start from there
+			logAndAdvance(status, status.offset_family, guessSkcState)
+			// we know the state ((struct sock_common)->skc_state) and the sport
+			// ((struct inet_sock)->inet_sport) are after the family field, so we start from there
+			status.offset_skc_state = status.offset_family
 			status.offset_sport = status.offset_family
 			break
 		}
 		status.offset_family++
+	case guessSkcState:
+		// The offset must hold the state of both the established connection and
+		// the listening socket, which are different, to be a match
+		if status.skc_state == C.__u8(expected.skcState) {
+			if expected.skcStateListen {
+				logAndAdvance(status, status.offset_skc_state, guessSport)
+				break
+			}
+			expected.skcStateListen = true
+			break
+		}
+		status.offset_skc_state++
+		expected.skcStateListen = false
 	case guessSport:
 		if status.sport == C.__u16(htons(expected.sport)) {
 			logAndAdvance(status, status.offset_sport, guessDport)
@@ -527,6 +549,7 @@ func guessOffsets(m *manager.Manager, cfg *Config) ([]manager.ConstantEditor, er
 		if uint64(status.offset_saddr) >= threshold || uint64(status.offset_daddr) >= threshold ||
 			status.offset_sport >= thresholdInetSock || uint64(status.offset_dport) >= threshold ||
 			uint64(status.offset_netns) >= threshold || uint64(status.offset_family) >= threshold ||
+			uint64(status.offset_skc_state) >= threshold ||
 			uint64(status.offset_daddr_ipv6) >= threshold || status.offset_rtt >= thresholdInetSock {
 			return nil, fmt.Errorf("overflow while guessing %v, bailing out", whatString[status.what])
 		}
@@ -544,6 +567,7 @@ func getConstantEditors(status *tracerStatus) []manager.ConstantEditor {
 		{Name: "offset_netns", Value: uint64(status.offset_netns)},
 		{Name: "offset_ino", Value: uint64(status.offset_ino)},
 		{Name: "offset_family", Value: uint64(status.offset_family)},
+		{Name: "offset_skc_state", Value: uint64(status.offset_skc_state)},
 		{Name: "offset_rtt", Value: uint64(status.offset_rtt)},
 		{Name: "offset_rtt_var", Value: uint64(status.offset_rtt_var)},
 		{Name: "offset_daddr_ipv6", Value: uint64(status.offset_daddr_ipv6)},
@@ -611,6 +635,24 @@ func (e *eventGenerator) Generate(status *tracerStatus, expected *fieldValues) e
 		return err
 	}
 
+	if status.what == guessSkcState {
+		// The state of the socket is retrieved along the event, as it changes from one socket to the other
+		var (
+			tcpInfo *unix.TCPInfo
+			err     error
+		)
+		if expected.skcStateListen {
+			tcpInfo, err = socketTCPInfo(e.listener.(*net.TCPListener))
+		} else {
+			tcpInfo, err = tcpGetInfo(e.conn)
+		}
+		if err != nil {
+			return err
+		}
+		expected.skcState = tcpInfo.State
+		return nil
+	}
+
 	// This triggers the KProbe handler attached to `tcp_getsockopt`
 	_, err := tcpGetInfo(e.conn)
 	return err
@@ -662,8 +704,12 @@ func tcpGetInfo(conn net.Conn) (*unix.TCPInfo, error) {
 	if !ok {
 		return nil, errors.New("not a TCPConn")
 	}
+	return socketTCPInfo(tcpConn)
+}
 
-	file, err := tcpConn.File()
+// socketTCPInfo returns the TCP_INFO of a TCP connection or listener
+func socketTCPInfo(socket interface{ File() (*os.File, error) }) (*unix.TCPInfo, error) {
+	file, err := socket.File()
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +24,7 @@ import (
 
 var (
 	expvarEndpoints map[string]*expvar.Map
-	expvarTypes     = []string{"conntrack", "state", "tracer", "ebpf", "kprobes", "dns"}
+	expvarTypes     = []string{"conntrack", "state", "tracer", "ebpf", "kprobes", "dns", "aggregation"}
 )
 
 func init() {
@@ -47,6 +47,12 @@ type Tracer struct {
 
 	reverseDNS network.ReverseDNS
 
+	// aggregator is nil when connection aggregation is disabled
+	aggregator *network.ConnectionAggregator
+
+	// recorder is nil when the tracer events are not recorded
+	recorder *network.ReplayRecorder
+
 	perfMap      *manager.PerfMap
 	perfHandler  *bytecode.PerfHandler
 	batchManager *PerfBatchManager
@@ -183,6 +189,14 @@ func NewTracer(config *Config) (*Tracer, error) {
 		return nil, fmt.Errorf("failed to init ebpf manager: %v", err)
 	}
 
+	var recorder *network.ReplayRecorder
+	if config.ReplayRecordFile != "" {
+		if recorder, err = network.NewReplayRecorder(config.ReplayRecordFile, config.ReplayRecordMaxSize); err != nil {
+			return nil, fmt.Errorf("could not create replay record file: %s", err)
+		}
+		log.Infof("recording tracer events to %s", config.ReplayRecordFile)
+	}
+
 	reverseDNS := network.NewNullReverseDNS()
 	if enableSocketFilter {
 		filter, _ := m.GetProbe(manager.ProbeIdentificationPair{Section: string(bytecode.SocketDnsFilter)})
@@ -196,6 +210,7 @@ func NewTracer(config *Config) (*Tracer, error) {
 			config.CollectDNSStats,
 			config.CollectLocalDNS,
 			config.DNSTimeout,
+			recorder,
 		); err == nil {
 			reverseDNS = snooper
 		} else {
@@ -244,6 +259,11 @@ func NewTracer(config *Config) (*Tracer, error) {
 		perfHandler:    perfHandler,
 		flushIdle:      make(chan chan struct{}),
 		stop:           make(chan struct{}),
+		recorder:       recorder,
+	}
+
+	if config.EnableConnectionAggregation {
+		tr.aggregator = network.NewConnectionAggregator()
 	}
 
 	tr.perfMap, tr.batchManager, err = tr.initPerfPolling(perfHandler)
@@ -423,6 +443,9 @@ func (t *Tracer) storeClosedConn(cs network.ConnectionStats) {
 
 	atomic.AddInt64(&t.closedConns, 1)
 	cs.IPTranslation = t.conntracker.GetTranslationForConn(cs)
+	if t.recorder != nil {
+		t.recorder.RecordClosedConnection(cs)
+	}
 	t.state.StoreClosedConnection(cs)
 	if cs.IPTranslation != nil {
 		t.conntracker.DeleteTranslation(cs)
@@ -437,6 +460,11 @@ func (t *Tracer) Stop() {
 	t.perfHandler.Stop()
 	close(t.flushIdle)
 	t.conntracker.Close()
+	if t.recorder != nil {
+		if err := t.recorder.Close(); err != nil {
+			log.Warnf("error closing replay record file: %s", err)
+		}
+	}
 }
 
 func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, error) {
@@ -460,7 +488,15 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	t.flushIdle <- done
 	<-done
 
+	if t.recorder != nil {
+		t.recorder.RecordActiveConnections(latestConns, latestTime)
+		t.recorder.RecordGetConnections(clientID)
+	}
+
 	conns := t.state.Connections(clientID, latestTime, latestConns, t.reverseDNS.GetDNSStats())
+	if t.aggregator != nil {
+		conns = t.aggregator.Aggregate(conns)
+	}
 	names := t.reverseDNS.Resolve(conns)
 	tm := t.getConnTelemetry(len(latestConns))
 
@@ -499,6 +535,15 @@ func (t *Tracer) getConnTelemetry(mapSize int) *network.ConnectionsTelemetry {
 	if usm, ok := ebpfStats["udp_sends_missed"]; ok {
 		tm.MonotonicUDPSendsMissed = usm
 	}
+	if fc, ok := ebpfStats["tcp_failed_connects"]; ok {
+		tm.MonotonicTCPFailedConnects = fc
+	}
+	if rs, ok := ebpfStats["tcp_resets_sent"]; ok {
+		tm.MonotonicTCPResetsSent = rs
+	}
+	if rr, ok := ebpfStats["tcp_resets_received"]; ok {
+		tm.MonotonicTCPResetsReceived = rr
+	}
 
 	return tm
 }
@@ -696,6 +741,9 @@ func (t *Tracer) getEbpfTelemetry() map[string]int64 {
 		"missed_tcp_close":    int64(telemetry.missed_tcp_close),
 		"udp_sends_processed": int64(telemetry.udp_sends_processed),
 		"udp_sends_missed":    int64(telemetry.udp_sends_missed),
+		"tcp_failed_connects": int64(telemetry.tcp_failed_connects),
+		"tcp_resets_sent":     int64(telemetry.tcp_resets_sent),
+		"tcp_resets_received": int64(telemetry.tcp_resets_received),
 	}
 }
 
@@ -744,7 +792,7 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 	stateStats := t.state.GetStats()
 	conntrackStats := t.conntracker.GetStats()
 
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"conntrack": conntrackStats,
 		"state":     stateStats,
 		"tracer": map[string]int64{
@@ -757,7 +805,13 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 		"ebpf":    t.getEbpfTelemetry(),
 		"kprobes": GetProbeStats(),
 		"dns":     t.reverseDNS.GetStats(),
-	}, nil
+	}
+
+	if t.aggregator != nil {
+		stats["aggregation"] = t.aggregator.GetStats()
+	}
+
+	return stats, nil
 }
 
 // DebugNetworkState returns a map with the current tracer's internal state, for debugging
@@ -594,6 +594,34 @@ type AddrPair struct {
 	remote net.Addr
 }
 
+func TestTCPFailedConnect(t *testing.T) {
+	tr, err := NewTracer(NewDefaultConfig())
+	require.NoError(t, err)
+	defer tr.Stop()
+
+	// Grab a free port, with nothing listening on it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	l.Close()
+
+	_, err = net.DialTimeout("tcp", addr, time.Second)
+	require.Error(t, err)
+
+	// The refused connection attempt never sent nor received data, it is still reported
+	var failed []network.ConnectionStats
+	require.Eventually(t, func() bool {
+		failed = searchConnections(getConnections(t, tr), func(c network.ConnectionStats) bool {
+			return c.Type == network.TCP && int(c.DPort) == addrPort(addr) && c.MonotonicTCPFailedConnects > 0
+		})
+		return len(failed) > 0
+	}, 3*time.Second, 100*time.Millisecond)
+
+	assert.Equal(t, uint32(1), failed[0].MonotonicTCPFailedConnects)
+	assert.Equal(t, os.Getpid(), int(failed[0].Pid))
+	assert.Equal(t, network.OUTGOING, failed[0].Direction)
+}
+
 func TestTCPShortlived(t *testing.T) {
 	// Enable BPF-based system probe
 	cfg := NewDefaultConfig()
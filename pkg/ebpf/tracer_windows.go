@@ -19,7 +19,7 @@ const (
 
 var (
 	expvarEndpoints map[string]*expvar.Map
-	expvarTypes     = []string{"state", "driver_total_flow_stats", "driver_flow_handle_stats", "total_flows", "open_flows", "closed_flows", "more_data_errors"}
+	expvarTypes     = []string{"state", "driver_total_flow_stats", "driver_flow_handle_stats", "total_flows", "open_flows", "closed_flows", "more_data_errors", "aggregation"}
 )
 
 func init() {
@@ -38,6 +38,9 @@ type Tracer struct {
 	reverseDNS      network.ReverseDNS
 	bufferLock      sync.Mutex
 
+	// aggregator is nil when connection aggregation is disabled
+	aggregator *network.ConnectionAggregator
+
 	// buffers
 	connStatsActive []network.ConnectionStats
 	connStatsClosed []network.ConnectionStats
@@ -75,6 +78,10 @@ func NewTracer(config *Config) (*Tracer, error) {
 		driverBuffer:    make([]uint8, config.DriverBufferSize),
 	}
 
+	if config.EnableConnectionAggregation {
+		tr.aggregator = network.NewConnectionAggregator()
+	}
+
 	go tr.expvarStats(tr.stopChan)
 	return tr, nil
 }
@@ -142,6 +149,9 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	// check for expired clients in the state
 	t.state.RemoveExpiredClients(time.Now())
 	conns := t.state.Connections(clientID, uint64(time.Now().Nanosecond()), activeConnStats, t.reverseDNS.GetDNSStats())
+	if t.aggregator != nil {
+		conns = t.aggregator.Aggregate(conns)
+	}
 	t.connStatsActive = t.resizeConnectionStatBuffer(len(activeConnStats), t.connStatsActive)
 	t.connStatsClosed = t.resizeConnectionStatBuffer(len(closedConnStats), t.connStatsClosed)
 	t.driverBuffer = t.resizeDriverBuffer(bytesRead, t.driverBuffer)
@@ -178,7 +188,7 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 
 	stateStats := t.state.GetStats()
 
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"state":                    stateStats,
 		"total_flows":              driverStats["total_flows"],
 		"open_flows":               driverStats["open_flows"],
@@ -186,7 +196,13 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 		"more_data_errors":         driverStats["more_data_errors"],
 		"driver_total_flow_stats":  driverStats["driver_total_flow_stats"],
 		"driver_flow_handle_stats": driverStats["driver_flow_handle_stats"],
-	}, nil
+	}
+
+	if t.aggregator != nil {
+		stats["aggregation"] = t.aggregator.GetStats()
+	}
+
+	return stats, nil
 }
 
 // DebugNetworkState returns a map with the current tracer's internal state, for debugging
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/fatih/color"
@@ -100,6 +101,13 @@ func GetClusterChecks(w io.Writer) error {
 			continue
 		}
 		fmt.Fprintln(w, fmt.Sprintf("\n===== Checks on %s =====", color.HiMagentaString(node.Name)))
+		if len(node.Labels) > 0 {
+			labels := make([]string, 0, len(node.Labels))
+			for _, k := range sortedKeys(node.Labels) {
+				labels = append(labels, fmt.Sprintf("%s=%s", k, node.Labels[k]))
+			}
+			fmt.Fprintln(w, fmt.Sprintf("%s: %s", color.BlueString("Node labels"), strings.Join(labels, ", ")))
+		}
 		for _, c := range node.Configs {
 			PrintConfig(w, c)
 		}
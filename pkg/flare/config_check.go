@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/fatih/color"
 
@@ -140,9 +141,28 @@ func PrintConfig(w io.Writer, c integration.Config) {
 			fmt.Fprintln(w, fmt.Sprintf("* %s", color.CyanString(id)))
 		}
 	}
+	if c.Placement != nil {
+		fmt.Fprintln(w, fmt.Sprintf("%s:", color.BlueString("Placement constraints")))
+		for _, k := range sortedKeys(c.Placement.NodeSelector) {
+			fmt.Fprintln(w, fmt.Sprintf("* node selector: %s", color.CyanString("%s=%s", k, c.Placement.NodeSelector[k])))
+		}
+		if c.Placement.SpreadBy != "" {
+			fmt.Fprintln(w, fmt.Sprintf("* spread by: %s", color.CyanString(c.Placement.SpreadBy)))
+		}
+	}
 	if c.NodeName != "" {
 		state := fmt.Sprintf("dispatched to %s", c.NodeName)
 		fmt.Fprintln(w, fmt.Sprintf("%s: %s", color.BlueString("State"), color.CyanString(state)))
 	}
 	fmt.Fprintln(w, "===")
 }
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
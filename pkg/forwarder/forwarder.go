@@ -33,6 +33,16 @@ const (
 	PayloadTypeService = "service"
 	// PayloadTypeNode is the name of the node payload type
 	PayloadTypeNode = "node"
+	// PayloadTypeStatefulSet is the name of the stateful set payload type
+	PayloadTypeStatefulSet = "statefulset"
+	// PayloadTypeDaemonSet is the name of the daemon set payload type
+	PayloadTypeDaemonSet = "daemonset"
+	// PayloadTypeJob is the name of the job payload type
+	PayloadTypeJob = "job"
+	// PayloadTypeCronJob is the name of the cron job payload type
+	PayloadTypeCronJob = "cronjob"
+	// PayloadTypePersistentVolumeClaim is the name of the persistent volume claim payload type
+	PayloadTypePersistentVolumeClaim = "persistentvolumeclaim"
 )
 
 var (
@@ -58,6 +68,11 @@ var (
 	transactionsIntakeReplicaSet  = expvar.Int{}
 	transactionsIntakeService     = expvar.Int{}
 	transactionsIntakeNode        = expvar.Int{}
+	transactionsIntakeStatefulSet = expvar.Int{}
+	transactionsIntakeDaemonSet   = expvar.Int{}
+	transactionsIntakeJob         = expvar.Int{}
+	transactionsIntakeCronJob     = expvar.Int{}
+	transactionsIntakePVC         = expvar.Int{}
 
 	tlm = telemetry.NewCounter("forwarder", "transactions",
 		[]string{"endpoint", "route"}, "Forwarder telemetry")
@@ -114,6 +129,11 @@ func initOrchestratorExpVars() {
 	transactionsExpvars.Set("ReplicaSets", &transactionsIntakeReplicaSet)
 	transactionsExpvars.Set("Services", &transactionsIntakeService)
 	transactionsExpvars.Set("Nodes", &transactionsIntakeNode)
+	transactionsExpvars.Set("StatefulSets", &transactionsIntakeStatefulSet)
+	transactionsExpvars.Set("DaemonSets", &transactionsIntakeDaemonSet)
+	transactionsExpvars.Set("Jobs", &transactionsIntakeJob)
+	transactionsExpvars.Set("CronJobs", &transactionsIntakeCronJob)
+	transactionsExpvars.Set("PersistentVolumeClaims", &transactionsIntakePVC)
 }
 
 const (
@@ -503,6 +523,16 @@ func (f *DefaultForwarder) SubmitOrchestratorChecks(payload Payloads, extra http
 		transactionsIntakeService.Add(1)
 	case PayloadTypeNode:
 		transactionsIntakeNode.Add(1)
+	case PayloadTypeStatefulSet:
+		transactionsIntakeStatefulSet.Add(1)
+	case PayloadTypeDaemonSet:
+		transactionsIntakeDaemonSet.Add(1)
+	case PayloadTypeJob:
+		transactionsIntakeJob.Add(1)
+	case PayloadTypeCronJob:
+		transactionsIntakeCronJob.Add(1)
+	case PayloadTypePersistentVolumeClaim:
+		transactionsIntakePVC.Add(1)
 	}
 
 	return f.submitProcessLikePayload(orchestratorEndpoint, payload, extra, true)
@@ -15,14 +15,20 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/kubestatemetrics/store"
 
 	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	ksmbuild "k8s.io/kube-state-metrics/pkg/builder"
 	ksmtypes "k8s.io/kube-state-metrics/pkg/builder/types"
 	"k8s.io/kube-state-metrics/pkg/metric_generator"
 	"k8s.io/kube-state-metrics/pkg/options"
-	"k8s.io/kube-state-metrics/pkg/watch"
+	ksmwatch "k8s.io/kube-state-metrics/pkg/watch"
 )
 
 // Builder struct represents the metric store generator
@@ -31,11 +37,12 @@ type Builder struct {
 	ksmBuilder ksmtypes.BuilderInterface
 
 	kubeClient    clientset.Interface
+	dynamicClient dynamic.Interface
 	vpaClient     vpaclientset.Interface
 	namespaces    options.NamespaceList
 	ctx           context.Context
 	allowDenyList ksmtypes.AllowDenyLister
-	metrics       *watch.ListWatchMetrics
+	metrics       *ksmwatch.ListWatchMetrics
 	shard         int32
 	totalShards   int
 
@@ -75,6 +82,11 @@ func (b *Builder) WithKubeClient(c clientset.Interface) {
 	b.ksmBuilder.WithKubeClient(c)
 }
 
+// WithDynamicClient sets the dynamicClient property of a Builder so that custom resources can be watched.
+func (b *Builder) WithDynamicClient(c dynamic.Interface) {
+	b.dynamicClient = c
+}
+
 // WithVPAClient sets the vpaClient property of a Builder so that the verticalpodautoscaler collector can query VPA objects.
 func (b *Builder) WithVPAClient(c vpaclientset.Interface) {
 	b.vpaClient = c
@@ -84,7 +96,7 @@ func (b *Builder) WithVPAClient(c vpaclientset.Interface) {
 // WithMetrics sets the metrics property of a Builder.
 func (b *Builder) WithMetrics(r *prometheus.Registry) {
 	b.ksmBuilder.WithMetrics(r)
-	b.metrics = watch.NewListWatchMetrics(r)
+	b.metrics = ksmwatch.NewListWatchMetrics(r)
 }
 
 // WithEnabledResources sets the enabledResources property of a Builder.
@@ -134,6 +146,29 @@ func (b *Builder) GenerateStore(metricFamilies []generator.FamilyGenerator,
 	return store
 }
 
+// GenerateCustomResourceStore generates a new Metrics Store for the metric families of a custom resource.
+// The custom resource objects are watched as unstructured objects through the dynamic client.
+func (b *Builder) GenerateCustomResourceStore(metricFamilies []generator.FamilyGenerator, gvr schema.GroupVersionResource) cache.Store {
+	composedMetricGenFuncs := generator.ComposeMetricGenFuncs(metricFamilies)
+	store := store.NewMetricsStore(
+		composedMetricGenFuncs,
+		// Used later on to identify the Type of resource.
+		gvr.String(),
+	)
+	listWatchFunc := func(_ clientset.Interface, ns string) cache.ListerWatcher {
+		return &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return b.dynamicClient.Resource(gvr).Namespace(ns).List(opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return b.dynamicClient.Resource(gvr).Namespace(ns).Watch(opts)
+			},
+		}
+	}
+	b.reflectorPerNamespace(&unstructured.Unstructured{}, store, listWatchFunc)
+	return store
+}
+
 // reflectorPerNamespace creates a Kubernetes client-go reflector with the given
 // listWatchFunc for each given namespace and registers it with the given store.
 func (b *Builder) reflectorPerNamespace(
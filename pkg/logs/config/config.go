@@ -14,6 +14,7 @@ import (
 
 	coreConfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/snmp/traps"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/eventlogs"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -23,6 +24,9 @@ const ContainerCollectAll = "container_collect_all"
 // SnmpTraps is the name of the integration that collects logs from SNMP traps received by the Agent
 const SnmpTraps = "snmp_traps"
 
+// KubernetesEvents is the name of the integration that collects logs from Kubernetes events
+const KubernetesEvents = "kubernetes_events"
+
 // logs-intake endpoint prefix.
 const (
 	tcpEndpointPrefix  = "agent-intake.logs."
@@ -73,6 +77,19 @@ func SNMPTrapsSource() *LogSource {
 	return nil
 }
 
+// KubernetesEventsSource returns a source to forward Kubernetes events as logs.
+func KubernetesEventsSource() *LogSource {
+	if eventlogs.IsEnabled() {
+		// source to forward Kubernetes events as logs.
+		return NewLogSource(KubernetesEvents, &LogsConfig{
+			Type:    KubeEventsType,
+			Service: "kubernetes",
+			Source:  "kubernetes",
+		})
+	}
+	return nil
+}
+
 // GlobalProcessingRules returns the global processing rules to apply to all logs.
 func GlobalProcessingRules() ([]*ProcessingRule, error) {
 	var rules []*ProcessingRule
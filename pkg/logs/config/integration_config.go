@@ -19,6 +19,7 @@ const (
 	JournaldType     = "journald"
 	WindowsEventType = "windows_event"
 	SnmpTrapsType    = "snmp_traps"
+	KubeEventsType   = "kubernetes_events"
 
 	// UTF16BE for UTF-16 Big endian encoding
 	UTF16BE string = "utf-16-be"
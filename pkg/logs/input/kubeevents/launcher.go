@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package kubeevents
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/eventlogs"
+)
+
+// Launcher forwards the Kubernetes event logs based on configuration.
+type Launcher struct {
+	pipelineProvider pipeline.Provider
+	sources          chan *config.LogSource
+	tailer           *Tailer
+	stop             chan interface{}
+}
+
+// NewLauncher returns an initialized Launcher
+func NewLauncher(sources *config.LogSources, pipelineProvider pipeline.Provider) *Launcher {
+	return &Launcher{
+		pipelineProvider: pipelineProvider,
+		sources:          sources.GetAddedForType(config.KubeEventsType),
+		stop:             make(chan interface{}, 1),
+	}
+}
+
+// Start starts the launcher.
+func (l *Launcher) Start() {
+	go l.run()
+}
+
+func (l *Launcher) startNewTailer(source *config.LogSource, inputChan eventlogs.LogsChannel) {
+	outputChan := l.pipelineProvider.NextPipelineChan()
+	l.tailer = NewTailer(source, inputChan, outputChan)
+	l.tailer.Start()
+	eventlogs.SetConsumed(true)
+}
+
+func (l *Launcher) run() {
+	for {
+		select {
+		case source := <-l.sources:
+			if l.tailer == nil {
+				l.startNewTailer(source, eventlogs.GetLogsChannel())
+				source.Status.Success()
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the launcher and its running tailer.
+func (l *Launcher) Stop() {
+	l.stop <- true
+	if l.tailer != nil {
+		eventlogs.SetConsumed(false)
+		l.tailer.Stop()
+		l.tailer = nil
+	}
+}
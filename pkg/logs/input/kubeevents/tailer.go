@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package kubeevents
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/eventlogs"
+)
+
+// Tailer consumes a stream of Kubernetes event logs, and sends them to a stream of log messages.
+type Tailer struct {
+	source     *config.LogSource
+	inputChan  eventlogs.LogsChannel
+	outputChan chan *message.Message
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewTailer returns a new Tailer
+func NewTailer(source *config.LogSource, inputChan eventlogs.LogsChannel, outputChan chan *message.Message) *Tailer {
+	return &Tailer{
+		source:     source,
+		inputChan:  inputChan,
+		outputChan: outputChan,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start starts the tailer.
+func (t *Tailer) Start() {
+	go t.run()
+}
+
+// Stop stops the tailer and waits for the log being processed to be sent.
+// The input channel is shared with the check submitting the logs and is never closed.
+func (t *Tailer) Stop() {
+	close(t.stop)
+	<-t.done
+}
+
+func (t *Tailer) run() {
+	defer close(t.done)
+
+	for {
+		select {
+		case l := <-t.inputChan:
+			origin := message.NewOrigin(t.source)
+			origin.SetTags(l.Tags)
+			t.outputChan <- message.NewMessage(l.Content, origin, l.Status)
+		case <-t.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package kubeevents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/eventlogs"
+)
+
+func TestKubeEventsShouldReceiveMessages(t *testing.T) {
+	inputChan := make(eventlogs.LogsChannel, 1)
+	outputChan := make(chan *message.Message)
+	tailer := NewTailer(config.NewLogSource("test", &config.LogsConfig{}), inputChan, outputChan)
+	tailer.Start()
+
+	l := &eventlogs.Log{
+		Content: []byte(`{"message":"Back-off restarting failed container","reason":"BackOff"}`),
+		Tags:    []string{"kube_namespace:default", "pod_name:redis"},
+		Status:  message.StatusWarning,
+	}
+
+	inputChan <- l
+
+	var msg *message.Message
+	select {
+	case msg = <-outputChan:
+		break
+	case <-time.After(1 * time.Second):
+		t.Error("Message not received")
+		return
+	}
+
+	assert.Equal(t, message.StatusWarning, msg.GetStatus())
+	assert.Equal(t, l.Content, msg.Content)
+	assert.Equal(t, l.Tags, msg.Origin.Tags())
+
+	tailer.Stop()
+}
@@ -93,6 +93,12 @@ func Start(getAC func() *autodiscovery.AutoConfig) error {
 		sources.AddSource(source)
 	}
 
+	// add Kubernetes events source forwarding Kubernetes events as logs if enabled.
+	if source := config.KubernetesEventsSource(); source != nil {
+		log.Debug("Adding Kubernetes events source to the Logs Agent")
+		sources.AddSource(source)
+	}
+
 	// adds the source collecting logs from all containers if enabled,
 	// but ensure that it is enabled after the AutoConfig initialization
 	if source := config.ContainerCollectAllSource(); source != nil {
@@ -0,0 +1,151 @@
+package network
+
+import (
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+// aggregationKey identifies connections which only differ by their (ephemeral) source port
+type aggregationKey struct {
+	pid       uint32
+	netNS     uint32
+	source    util.Address
+	dest      util.Address
+	dport     uint16
+	connType  ConnectionType
+	family    ConnectionFamily
+	direction ConnectionDirection
+}
+
+// ConnectionAggregator collapses outgoing connections from the same process to the same
+// remote address and port into a single connection, in order to reduce the size of the
+// payloads sent to clients on hosts with a large number of short-lived client connections.
+//
+// Aggregated connections are reported with a zero source port, so that they are identified by
+// the same address from one call to the next whichever of their members are still open.
+// Only the deltas of their counters are meaningful: the monotonic counters of their members,
+// which come and go between two calls, are summed as well but are not encoded in payloads.
+type ConnectionAggregator struct {
+	// Telemetry
+	connsReceived   int64
+	connsAggregated int64
+}
+
+// NewConnectionAggregator creates a new ConnectionAggregator
+func NewConnectionAggregator() *ConnectionAggregator {
+	return &ConnectionAggregator{}
+}
+
+// Aggregate collapses the given connections, summing their counters.
+// The given slice is modified in place and must not be used afterwards.
+func (a *ConnectionAggregator) Aggregate(conns []ConnectionStats) []ConnectionStats {
+	if len(conns) == 0 {
+		return conns
+	}
+
+	indexes := make(map[aggregationKey]int, len(conns))
+	aggregated := conns[:0]
+	for _, c := range conns {
+		if !isAggregatable(c) {
+			aggregated = append(aggregated, c)
+			continue
+		}
+
+		key := aggregationKey{
+			pid:       c.Pid,
+			netNS:     c.NetNS,
+			source:    c.Source,
+			dest:      c.Dest,
+			dport:     c.DPort,
+			connType:  c.Type,
+			family:    c.Family,
+			direction: c.Direction,
+		}
+
+		c.SPort = 0
+		if i, ok := indexes[key]; ok {
+			mergeAggregatedConnection(&aggregated[i], c)
+			continue
+		}
+
+		indexes[key] = len(aggregated)
+		aggregated = append(aggregated, c)
+	}
+
+	a.updateTelemetry(len(conns), len(aggregated))
+	return aggregated
+}
+
+// GetStats returns telemetry about the connections aggregation
+func (a *ConnectionAggregator) GetStats() map[string]int64 {
+	received := atomic.LoadInt64(&a.connsReceived)
+	aggregated := atomic.LoadInt64(&a.connsAggregated)
+
+	// Percentage of connections removed by all the aggregation passes
+	var ratio int64
+	if received > 0 {
+		ratio = 100 * (received - aggregated) / received
+	}
+
+	return map[string]int64{
+		"conns_received":     received,
+		"conns_aggregated":   aggregated,
+		"collapse_ratio_pct": ratio,
+	}
+}
+
+func (a *ConnectionAggregator) updateTelemetry(received, aggregated int) {
+	atomic.AddInt64(&a.connsReceived, int64(received))
+	atomic.AddInt64(&a.connsAggregated, int64(aggregated))
+}
+
+// isAggregatable returns whether a connection can be merged with similar ones.
+// Only outgoing connections have an ephemeral source port, and NAT'd connections
+// are left untouched as their translation is specific to each source port.
+func isAggregatable(c ConnectionStats) bool {
+	return c.Direction == OUTGOING && c.IPTranslation == nil && !c.IntraHost
+}
+
+func mergeAggregatedConnection(dst *ConnectionStats, src ConnectionStats) {
+	dst.MonotonicSentBytes += src.MonotonicSentBytes
+	dst.LastSentBytes += src.LastSentBytes
+	dst.MonotonicRecvBytes += src.MonotonicRecvBytes
+	dst.LastRecvBytes += src.LastRecvBytes
+	dst.MonotonicRetransmits += src.MonotonicRetransmits
+	dst.LastRetransmits += src.LastRetransmits
+	dst.MonotonicTCPEstablished += src.MonotonicTCPEstablished
+	dst.LastTCPEstablished += src.LastTCPEstablished
+	dst.MonotonicTCPClosed += src.MonotonicTCPClosed
+	dst.LastTCPClosed += src.LastTCPClosed
+	dst.MonotonicTCPFailedConnects += src.MonotonicTCPFailedConnects
+	dst.LastTCPFailedConnects += src.LastTCPFailedConnects
+	dst.MonotonicTCPResetsSent += src.MonotonicTCPResetsSent
+	dst.LastTCPResetsSent += src.LastTCPResetsSent
+	dst.MonotonicTCPResetsReceived += src.MonotonicTCPResetsReceived
+	dst.LastTCPResetsReceived += src.LastTCPResetsReceived
+
+	// The RTT of the aggregate is the one of its most recently updated member, or the
+	// highest one among those updated last, so that it doesn't depend on their order
+	if src.LastUpdateEpoch > dst.LastUpdateEpoch || (src.LastUpdateEpoch == dst.LastUpdateEpoch && src.RTT > dst.RTT) {
+		dst.LastUpdateEpoch = src.LastUpdateEpoch
+		dst.RTT = src.RTT
+		dst.RTTVar = src.RTTVar
+	}
+
+	dst.DNSSuccessfulResponses += src.DNSSuccessfulResponses
+	dst.DNSFailedResponses += src.DNSFailedResponses
+	dst.DNSTimeouts += src.DNSTimeouts
+	dst.DNSSuccessLatencySum += src.DNSSuccessLatencySum
+	dst.DNSFailureLatencySum += src.DNSFailureLatencySum
+	if len(src.DNSCountByRcode) > 0 {
+		counts := make(map[uint32]uint32, len(dst.DNSCountByRcode)+len(src.DNSCountByRcode))
+		for rcode, count := range dst.DNSCountByRcode {
+			counts[rcode] = count
+		}
+		for rcode, count := range src.DNSCountByRcode {
+			counts[rcode] += count
+		}
+		dst.DNSCountByRcode = counts
+	}
+}
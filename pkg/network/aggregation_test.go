@@ -0,0 +1,150 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOutgoingConn(sport uint16, sent, recv uint64, epoch uint64) ConnectionStats {
+	return ConnectionStats{
+		Pid:                42,
+		Source:             util.AddressFromString("10.0.0.1"),
+		Dest:               util.AddressFromString("10.0.0.2"),
+		SPort:              sport,
+		DPort:              443,
+		Type:               TCP,
+		Family:             AFINET,
+		Direction:          OUTGOING,
+		MonotonicSentBytes: sent,
+		LastSentBytes:      sent,
+		MonotonicRecvBytes: recv,
+		LastRecvBytes:      recv,
+		LastRetransmits:    1,
+		LastTCPEstablished: 1,
+		LastUpdateEpoch:    epoch,
+		RTT:                uint32(epoch),
+	}
+}
+
+func TestAggregateConnections(t *testing.T) {
+	incoming := newOutgoingConn(8080, 1, 1, 1)
+	incoming.Direction = INCOMING
+
+	nat := newOutgoingConn(50003, 1, 1, 1)
+	nat.IPTranslation = &IPTranslation{ReplSrcIP: util.AddressFromString("10.0.0.2")}
+
+	otherPort := newOutgoingConn(50004, 1, 1, 1)
+	otherPort.DPort = 80
+
+	conns := []ConnectionStats{
+		newOutgoingConn(50000, 10, 100, 1),
+		newOutgoingConn(50001, 20, 200, 3),
+		newOutgoingConn(50002, 30, 300, 2),
+		incoming,
+		nat,
+		otherPort,
+	}
+
+	a := NewConnectionAggregator()
+	aggregated := a.Aggregate(conns)
+	require.Len(t, aggregated, 4)
+
+	c := aggregated[0]
+	assert.Equal(t, uint64(60), c.LastSentBytes)
+	assert.Equal(t, uint64(600), c.LastRecvBytes)
+	assert.Equal(t, uint32(3), c.LastRetransmits)
+	assert.Equal(t, uint32(3), c.LastTCPEstablished)
+	assert.Equal(t, uint64(3), c.LastUpdateEpoch)
+	// The RTT is the one of the most recently updated connection
+	assert.Equal(t, uint32(3), c.RTT)
+	assert.Equal(t, uint16(0), c.SPort)
+
+	assert.Equal(t, INCOMING, aggregated[1].Direction)
+	assert.NotNil(t, aggregated[2].IPTranslation)
+	assert.Equal(t, uint16(80), aggregated[3].DPort)
+	assert.Equal(t, uint16(0), aggregated[3].SPort)
+	// Connections which can't be aggregated keep their source port
+	assert.Equal(t, uint16(8080), aggregated[1].SPort)
+	assert.Equal(t, uint16(50003), aggregated[2].SPort)
+
+	stats := a.GetStats()
+	assert.Equal(t, int64(6), stats["conns_received"])
+	assert.Equal(t, int64(4), stats["conns_aggregated"])
+	assert.Equal(t, int64(33), stats["collapse_ratio_pct"])
+}
+
+func TestAggregateConnectionsOrder(t *testing.T) {
+	conns := []ConnectionStats{
+		newOutgoingConn(50002, 30, 300, 2),
+		newOutgoingConn(50001, 20, 200, 3),
+		newOutgoingConn(50000, 10, 100, 1),
+	}
+	reversed := []ConnectionStats{conns[2], conns[1], conns[0]}
+
+	assert.Equal(t, NewConnectionAggregator().Aggregate(reversed), NewConnectionAggregator().Aggregate(conns))
+}
+
+func TestAggregateConnectionsStableAddress(t *testing.T) {
+	a := NewConnectionAggregator()
+
+	first := a.Aggregate([]ConnectionStats{
+		newOutgoingConn(50000, 10, 100, 1),
+		newOutgoingConn(50001, 20, 200, 1),
+	})
+	require.Len(t, first, 1)
+
+	// The connection with the lowest source port is closed and a new one is opened:
+	// the aggregate is still reported with the same address
+	second := a.Aggregate([]ConnectionStats{
+		newOutgoingConn(50001, 5, 50, 2),
+		newOutgoingConn(50002, 1, 10, 2),
+	})
+	require.Len(t, second, 1)
+	assert.Equal(t, first[0].SPort, second[0].SPort)
+	assert.Equal(t, uint64(6), second[0].LastSentBytes)
+	assert.Equal(t, uint64(60), second[0].LastRecvBytes)
+}
+
+func TestAggregateConnectionsCollapseRatio(t *testing.T) {
+	a := NewConnectionAggregator()
+	a.Aggregate([]ConnectionStats{
+		newOutgoingConn(50000, 1, 1, 1),
+		newOutgoingConn(50001, 1, 1, 1),
+		newOutgoingConn(50002, 1, 1, 1),
+		newOutgoingConn(50003, 1, 1, 1),
+	})
+	otherPort := newOutgoingConn(50004, 1, 1, 1)
+	otherPort.DPort = 80
+	a.Aggregate([]ConnectionStats{newOutgoingConn(50000, 1, 1, 1), otherPort})
+
+	// 6 connections received in total, collapsed into 3
+	stats := a.GetStats()
+	assert.Equal(t, int64(6), stats["conns_received"])
+	assert.Equal(t, int64(3), stats["conns_aggregated"])
+	assert.Equal(t, int64(50), stats["collapse_ratio_pct"])
+}
+
+func TestAggregateConnectionsDNSStats(t *testing.T) {
+	c1 := newOutgoingConn(50000, 1, 1, 1)
+	c1.DNSSuccessfulResponses = 1
+	c1.DNSCountByRcode = map[uint32]uint32{0: 1}
+	c2 := newOutgoingConn(50001, 1, 1, 2)
+	c2.DNSFailedResponses = 1
+	c2.DNSCountByRcode = map[uint32]uint32{0: 2, 3: 1}
+
+	aggregated := NewConnectionAggregator().Aggregate([]ConnectionStats{c1, c2})
+	require.Len(t, aggregated, 1)
+	assert.Equal(t, uint32(1), aggregated[0].DNSSuccessfulResponses)
+	assert.Equal(t, uint32(1), aggregated[0].DNSFailedResponses)
+	assert.Equal(t, map[uint32]uint32{0: 3, 3: 1}, aggregated[0].DNSCountByRcode)
+}
+
+func TestAggregateNoConnections(t *testing.T) {
+	a := NewConnectionAggregator()
+	assert.Empty(t, a.Aggregate(nil))
+	assert.Equal(t, int64(0), a.GetStats()["conns_received"])
+}
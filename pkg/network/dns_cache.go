@@ -45,6 +45,11 @@ func newReverseDNSCache(size int, ttl, expirationPeriod time.Duration) *reverseD
 		maxDomainsPerIP:   1000,
 	}
 
+	// Without expiration period, the cache is expired by the caller
+	if expirationPeriod <= 0 {
+		return cache
+	}
+
 	ticker := time.NewTicker(expirationPeriod)
 	go func() {
 		for {
@@ -166,7 +171,7 @@ func (c *reverseDNSCache) Stats() map[string]int64 {
 }
 
 func (c *reverseDNSCache) Close() {
-	c.exit <- struct{}{}
+	close(c.exit)
 }
 
 func (c *reverseDNSCache) Expire(now time.Time) {
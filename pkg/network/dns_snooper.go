@@ -34,6 +34,11 @@ type SocketFilterSnooper struct {
 	wg              sync.WaitGroup
 	collectLocalDNS bool
 
+	// now returns the current time, it is the recorded time when replaying DNS traffic
+	now func() time.Time
+	// recorder records the captured packets, if any
+	recorder *ReplayRecorder
+
 	// cache translation object to avoid allocations
 	translation *translation
 
@@ -58,6 +63,7 @@ func NewSocketFilterSnooper(
 	collectDNSStats bool,
 	collectLocalDNS bool,
 	dnsTimeout time.Duration,
+	recorder *ReplayRecorder,
 ) (*SocketFilterSnooper, error) {
 
 	var (
@@ -76,20 +82,9 @@ func NewSocketFilterSnooper(
 		return nil, srcErr
 	}
 
-	cache := newReverseDNSCache(dnsCacheSize, dnsCacheTTL, dnsCacheExpirationPeriod)
-	var statKeeper *dnsStatKeeper
-	if collectDNSStats {
-		statKeeper = newDNSStatkeeper(dnsTimeout)
-	}
-	snooper := &SocketFilterSnooper{
-		source:          packetSrc,
-		parser:          newDNSParser(collectDNSStats),
-		cache:           cache,
-		statKeeper:      statKeeper,
-		translation:     new(translation),
-		exit:            make(chan struct{}),
-		collectLocalDNS: collectLocalDNS,
-	}
+	snooper := newSnooper(collectDNSStats, collectLocalDNS, dnsTimeout, nil)
+	snooper.source = packetSrc
+	snooper.recorder = recorder
 
 	// Start consuming packets
 	snooper.wg.Add(1)
@@ -108,9 +103,49 @@ func NewSocketFilterSnooper(
 	return snooper, nil
 }
 
+// newSnooper returns a SocketFilterSnooper without a packet source, which can be fed using processPacket.
+// With a nil clock, the DNS cache and stats expire in the background based on the wall-clock time,
+// otherwise they only expire when calling expire.
+func newSnooper(collectDNSStats bool, collectLocalDNS bool, dnsTimeout time.Duration, clock func() time.Time) *SocketFilterSnooper {
+	var (
+		cache      *reverseDNSCache
+		statKeeper *dnsStatKeeper
+	)
+	if clock == nil {
+		clock = time.Now
+		cache = newReverseDNSCache(dnsCacheSize, dnsCacheTTL, dnsCacheExpirationPeriod)
+		if collectDNSStats {
+			statKeeper = newDNSStatkeeper(dnsTimeout)
+		}
+	} else {
+		cache = newReverseDNSCache(dnsCacheSize, dnsCacheTTL, 0)
+		if collectDNSStats {
+			statKeeper = newUnexpiredDNSStatkeeper(dnsTimeout)
+		}
+	}
+
+	return &SocketFilterSnooper{
+		parser:          newDNSParser(collectDNSStats),
+		cache:           cache,
+		statKeeper:      statKeeper,
+		translation:     new(translation),
+		exit:            make(chan struct{}),
+		collectLocalDNS: collectLocalDNS,
+		now:             clock,
+	}
+}
+
 // Resolve IPs to DNS addresses
 func (s *SocketFilterSnooper) Resolve(connections []ConnectionStats) map[util.Address][]string {
-	return s.cache.Get(connections, time.Now())
+	return s.cache.Get(connections, s.now())
+}
+
+// expire removes the expired DNS cache entries and DNS queries without response
+func (s *SocketFilterSnooper) expire(now time.Time) {
+	s.cache.Expire(now)
+	if s.statKeeper != nil {
+		s.statKeeper.removeExpiredStates(now.Add(-s.statKeeper.expirationPeriod))
+	}
 }
 
 func (s *SocketFilterSnooper) GetDNSStats() map[dnsKey]dnsStats {
@@ -131,7 +166,7 @@ func (s *SocketFilterSnooper) GetStats() map[string]int64 {
 	stats["queries"] = atomic.LoadInt64(&s.queries)
 	stats["successes"] = atomic.LoadInt64(&s.successes)
 	stats["errors"] = atomic.LoadInt64(&s.errors)
-	stats["timestamp_micro_secs"] = time.Now().UnixNano() / 1000
+	stats["timestamp_micro_secs"] = s.now().UnixNano() / 1000
 	return stats
 }
 
@@ -139,7 +174,9 @@ func (s *SocketFilterSnooper) GetStats() map[string]int64 {
 func (s *SocketFilterSnooper) Close() {
 	close(s.exit)
 	s.wg.Wait()
-	s.source.Close()
+	if s.source != nil {
+		s.source.Close()
+	}
 	s.cache.Close()
 	if s.statKeeper != nil {
 		s.statKeeper.Close()
@@ -173,7 +210,7 @@ func (s *SocketFilterSnooper) processPacket(data []byte, ts time.Time) {
 	}
 
 	if pktInfo.pktType == SuccessfulResponse {
-		s.cache.Add(t, time.Now())
+		s.cache.Add(t, s.now())
 		atomic.AddInt64(&s.successes, 1)
 	} else if pktInfo.pktType == FailedResponse {
 		atomic.AddInt64(&s.errors, 1)
@@ -194,6 +231,9 @@ func (s *SocketFilterSnooper) pollPackets() {
 		}
 
 		if err == nil {
+			if s.recorder != nil {
+				s.recorder.RecordDNSPacket(data, captureInfo.Timestamp)
+			}
 			s.processPacket(data, captureInfo.Timestamp)
 			continue
 		}
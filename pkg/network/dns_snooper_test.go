@@ -92,6 +92,7 @@ func getSnooper(
 		collectStats,
 		collectLocalDNS,
 		dnsTimeout,
+		nil,
 	)
 	require.NoError(t, err)
 	return mgr, reverseDNS
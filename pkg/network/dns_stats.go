@@ -63,13 +63,7 @@ type dnsStatKeeper struct {
 }
 
 func newDNSStatkeeper(timeout time.Duration) *dnsStatKeeper {
-	statsKeeper := &dnsStatKeeper{
-		stats:            make(map[dnsKey]dnsStats),
-		state:            make(map[stateKey]uint64),
-		expirationPeriod: timeout,
-		exit:             make(chan struct{}),
-		maxSize:          MaxStateMapSize,
-	}
+	statsKeeper := newUnexpiredDNSStatkeeper(timeout)
 
 	ticker := time.NewTicker(statsKeeper.expirationPeriod)
 	go func() {
@@ -86,6 +80,17 @@ func newDNSStatkeeper(timeout time.Duration) *dnsStatKeeper {
 	return statsKeeper
 }
 
+// newUnexpiredDNSStatkeeper returns a dnsStatKeeper whose states are expired by the caller
+func newUnexpiredDNSStatkeeper(timeout time.Duration) *dnsStatKeeper {
+	return &dnsStatKeeper{
+		stats:            make(map[dnsKey]dnsStats),
+		state:            make(map[stateKey]uint64),
+		expirationPeriod: timeout,
+		exit:             make(chan struct{}),
+		maxSize:          MaxStateMapSize,
+	}
+}
+
 func microSecs(t time.Time) uint64 {
 	return uint64(t.UnixNano() / 1000)
 }
@@ -180,5 +185,5 @@ func (d *dnsStatKeeper) removeExpiredStates(earliestTs time.Time) {
 }
 
 func (d *dnsStatKeeper) Close() {
-	d.exit <- struct{}{}
+	close(d.exit)
 }
@@ -80,6 +80,16 @@ func TestSerialization(t *testing.T) {
 		},
 	}
 
+	// The JSON encoding renders default values, which are decoded as empty rather than nil
+	jsonConn := *out.Conns[0]
+	jsonConn.DnsStatsByDomain = map[int32]*model.DNSStats{}
+	jsonConn.DnsStatsByDomainByQueryType = map[int32]*model.DNSStatsByQueryType{}
+	jsonOut := *out
+	jsonOut.Conns = []*model.Connection{&jsonConn}
+	jsonOut.Domains = []string{}
+	jsonOut.Routes = []*model.Route{}
+	jsonOut.CompilationTelemetryByAsset = map[string]*model.RuntimeCompilationTelemetry{}
+
 	t.Run("requesting application/json serialization", func(t *testing.T) {
 		assert := assert.New(t)
 		marshaler := GetMarshaler("application/json")
@@ -91,7 +101,7 @@ func TestSerialization(t *testing.T) {
 		unmarshaler := GetUnmarshaler("application/json")
 		result, err := unmarshaler.Unmarshal(blob)
 		require.NoError(t, err)
-		assert.Equal(out, result)
+		assert.Equal(&jsonOut, result)
 	})
 
 	t.Run("requesting empty serialization", func(t *testing.T) {
@@ -106,7 +116,7 @@ func TestSerialization(t *testing.T) {
 		unmarshaler := GetUnmarshaler("")
 		result, err := unmarshaler.Unmarshal(blob)
 		require.NoError(t, err)
-		assert.Equal(out, result)
+		assert.Equal(&jsonOut, result)
 	})
 
 	t.Run("requesting application/protobuf serialization", func(t *testing.T) {
@@ -136,7 +146,7 @@ func TestSerialization(t *testing.T) {
 		unmarshaler := GetUnmarshaler("application/json")
 		result, err := unmarshaler.Unmarshal(blob)
 		require.NoError(t, err)
-		assert.Equal(out, result)
+		assert.Equal(&jsonOut, result)
 	})
 
 	t.Run("render default values with application/json", func(t *testing.T) {
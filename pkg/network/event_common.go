@@ -96,6 +96,9 @@ type ConnectionsTelemetry struct {
 	MonotonicUDPSendsProcessed         int64
 	MonotonicUDPSendsMissed            int64
 	ConntrackSamplingPercent           int64
+	MonotonicTCPFailedConnects         int64
+	MonotonicTCPResetsSent             int64
+	MonotonicTCPResetsReceived         int64
 }
 
 // ConnectionStats stores statistics for a single connection.  Field order in the struct should be 8-byte aligned
@@ -130,6 +133,15 @@ type ConnectionStats struct {
 	MonotonicTCPClosed uint32
 	LastTCPClosed      uint32
 
+	// MonotonicTCPFailedConnects counts the connection attempts that were refused or timed out
+	MonotonicTCPFailedConnects uint32
+	LastTCPFailedConnects      uint32
+
+	MonotonicTCPResetsSent     uint32
+	LastTCPResetsSent          uint32
+	MonotonicTCPResetsReceived uint32
+	LastTCPResetsReceived      uint32
+
 	Pid   uint32
 	NetNS uint32
 
@@ -254,6 +266,15 @@ func ConnectionSummary(c ConnectionStats, names map[util.Address][]string) strin
 			time.Duration(c.RTT)*time.Microsecond,
 			time.Duration(c.RTTVar)*time.Microsecond,
 		)
+
+		if c.MonotonicTCPFailedConnects > 0 || c.MonotonicTCPResetsSent > 0 || c.MonotonicTCPResetsReceived > 0 {
+			str += fmt.Sprintf(
+				", %d failed connects (+%d), %d resets sent (+%d), %d resets received (+%d)",
+				c.MonotonicTCPFailedConnects, c.LastTCPFailedConnects,
+				c.MonotonicTCPResetsSent, c.LastTCPResetsSent,
+				c.MonotonicTCPResetsReceived, c.LastTCPResetsReceived,
+			)
+		}
 	}
 
 	return str
@@ -0,0 +1,383 @@
+// +build linux_bpf
+
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// ReplayEventType is the type of a recorded tracer event
+type ReplayEventType string
+
+const (
+	// ReplayActiveConnections is a snapshot of the active connections read from the eBPF maps
+	ReplayActiveConnections ReplayEventType = "active"
+	// ReplayClosedConnections contains closed connections received through the perf buffer
+	ReplayClosedConnections ReplayEventType = "closed"
+	// ReplayDNSPacket contains a raw packet captured by the DNS socket filter
+	ReplayDNSPacket ReplayEventType = "dns"
+	// ReplayGetConnections is a request for connections made by a client
+	ReplayGetConnections ReplayEventType = "get"
+)
+
+// ReplayEvent is a single recorded tracer event
+type ReplayEvent struct {
+	Type ReplayEventType `json:"type"`
+	// ClientID is the client requesting connections, for ReplayGetConnections events
+	ClientID string `json:"client_id,omitempty"`
+	// Timestamp is the latest eBPF timestamp for ReplayActiveConnections events,
+	// and the capture time in nanoseconds for ReplayDNSPacket events
+	Timestamp uint64 `json:"timestamp,omitempty"`
+	// Time is the wall-clock time in nanoseconds when the event was recorded,
+	// it drives the expiration of the DNS cache and stats during the replay
+	Time int64 `json:"time,omitempty"`
+	// Conns are the recorded connections for ReplayActiveConnections and ReplayClosedConnections events
+	Conns []RecordedConnection `json:"conns,omitempty"`
+	// Packet is the raw ethernet frame for ReplayDNSPacket events
+	Packet []byte `json:"packet,omitempty"`
+}
+
+// RecordedConnection is the serializable form of the ConnectionStats produced by the tracer
+type RecordedConnection struct {
+	Pid    uint32 `json:"pid"`
+	NetNS  uint32 `json:"netns,omitempty"`
+	Type   string `json:"type"`
+	Family string `json:"family"`
+	Source string `json:"source"`
+	Dest   string `json:"dest"`
+	SPort  uint16 `json:"sport"`
+	DPort  uint16 `json:"dport"`
+
+	MonotonicSentBytes      uint64 `json:"sent_bytes,omitempty"`
+	MonotonicRecvBytes      uint64 `json:"recv_bytes,omitempty"`
+	MonotonicRetransmits    uint32 `json:"retransmits,omitempty"`
+	MonotonicTCPEstablished uint32 `json:"tcp_established,omitempty"`
+	MonotonicTCPClosed      uint32 `json:"tcp_closed,omitempty"`
+	RTT                     uint32 `json:"rtt,omitempty"`
+	RTTVar                  uint32 `json:"rtt_var,omitempty"`
+	LastUpdateEpoch         uint64 `json:"last_update_epoch"`
+
+	MonotonicTCPFailedConnects uint32 `json:"tcp_failed_connects,omitempty"`
+	MonotonicTCPResetsSent     uint32 `json:"tcp_resets_sent,omitempty"`
+	MonotonicTCPResetsReceived uint32 `json:"tcp_resets_received,omitempty"`
+
+	Direction     string                 `json:"direction,omitempty"`
+	IPTranslation *RecordedIPTranslation `json:"ip_translation,omitempty"`
+}
+
+// RecordedIPTranslation is the serializable form of an IPTranslation
+type RecordedIPTranslation struct {
+	ReplSrcIP   string `json:"repl_src_ip"`
+	ReplDstIP   string `json:"repl_dst_ip"`
+	ReplSrcPort uint16 `json:"repl_src_port"`
+	ReplDstPort uint16 `json:"repl_dst_port"`
+}
+
+// ConnectionStats converts a RecordedConnection back to the ConnectionStats returned by the tracer
+func (r RecordedConnection) ConnectionStats() (ConnectionStats, error) {
+	c := ConnectionStats{
+		Pid:                     r.Pid,
+		NetNS:                   r.NetNS,
+		SPort:                   r.SPort,
+		DPort:                   r.DPort,
+		MonotonicSentBytes:      r.MonotonicSentBytes,
+		MonotonicRecvBytes:      r.MonotonicRecvBytes,
+		MonotonicRetransmits:    r.MonotonicRetransmits,
+		MonotonicTCPEstablished: r.MonotonicTCPEstablished,
+		MonotonicTCPClosed:      r.MonotonicTCPClosed,
+		RTT:                     r.RTT,
+		RTTVar:                  r.RTTVar,
+		LastUpdateEpoch:         r.LastUpdateEpoch,
+
+		MonotonicTCPFailedConnects: r.MonotonicTCPFailedConnects,
+		MonotonicTCPResetsSent:     r.MonotonicTCPResetsSent,
+		MonotonicTCPResetsReceived: r.MonotonicTCPResetsReceived,
+	}
+
+	switch strings.ToLower(r.Type) {
+	case "tcp":
+		c.Type = TCP
+	case "udp":
+		c.Type = UDP
+	default:
+		return c, fmt.Errorf("invalid connection type: %s", r.Type)
+	}
+
+	switch strings.ToLower(r.Family) {
+	case "v4":
+		c.Family = AFINET
+	case "v6":
+		c.Family = AFINET6
+	default:
+		return c, fmt.Errorf("invalid connection family: %s", r.Family)
+	}
+
+	if c.Source = util.AddressFromString(r.Source); c.Source == nil {
+		return c, fmt.Errorf("invalid source address: %s", r.Source)
+	}
+	if c.Dest = util.AddressFromString(r.Dest); c.Dest == nil {
+		return c, fmt.Errorf("invalid destination address: %s", r.Dest)
+	}
+
+	switch strings.ToLower(r.Direction) {
+	case "":
+	case "incoming":
+		c.Direction = INCOMING
+	case "outgoing":
+		c.Direction = OUTGOING
+	case "local":
+		c.Direction = LOCAL
+	case "none":
+		c.Direction = NONE
+	default:
+		return c, fmt.Errorf("invalid connection direction: %s", r.Direction)
+	}
+
+	if t := r.IPTranslation; t != nil {
+		c.IPTranslation = &IPTranslation{
+			ReplSrcIP:   util.AddressFromString(t.ReplSrcIP),
+			ReplDstIP:   util.AddressFromString(t.ReplDstIP),
+			ReplSrcPort: t.ReplSrcPort,
+			ReplDstPort: t.ReplDstPort,
+		}
+		if c.IPTranslation.ReplSrcIP == nil || c.IPTranslation.ReplDstIP == nil {
+			return c, fmt.Errorf("invalid IP translation: %s -> %s", t.ReplSrcIP, t.ReplDstIP)
+		}
+	}
+
+	return c, nil
+}
+
+// RecordConnection converts a ConnectionStats into its serializable form
+func RecordConnection(c ConnectionStats) RecordedConnection {
+	r := RecordedConnection{
+		Pid:                     c.Pid,
+		NetNS:                   c.NetNS,
+		Type:                    strings.ToLower(c.Type.String()),
+		Family:                  c.Family.String(),
+		Source:                  c.Source.String(),
+		Dest:                    c.Dest.String(),
+		SPort:                   c.SPort,
+		DPort:                   c.DPort,
+		MonotonicSentBytes:      c.MonotonicSentBytes,
+		MonotonicRecvBytes:      c.MonotonicRecvBytes,
+		MonotonicRetransmits:    c.MonotonicRetransmits,
+		MonotonicTCPEstablished: c.MonotonicTCPEstablished,
+		MonotonicTCPClosed:      c.MonotonicTCPClosed,
+		RTT:                     c.RTT,
+		RTTVar:                  c.RTTVar,
+		LastUpdateEpoch:         c.LastUpdateEpoch,
+
+		MonotonicTCPFailedConnects: c.MonotonicTCPFailedConnects,
+		MonotonicTCPResetsSent:     c.MonotonicTCPResetsSent,
+		MonotonicTCPResetsReceived: c.MonotonicTCPResetsReceived,
+	}
+
+	// The zero value is not a valid direction
+	if c.Direction != 0 {
+		r.Direction = c.Direction.String()
+	}
+
+	if t := c.IPTranslation; t != nil {
+		r.IPTranslation = &RecordedIPTranslation{
+			ReplSrcIP:   t.ReplSrcIP.String(),
+			ReplDstIP:   t.ReplDstIP.String(),
+			ReplSrcPort: t.ReplSrcPort,
+			ReplDstPort: t.ReplDstPort,
+		}
+	}
+
+	return r
+}
+
+// ReadReplayFile reads the newline-delimited JSON records of a file written by a ReplayRecorder.
+// A truncated last record, left by a system-probe which didn't exit cleanly, is ignored.
+func ReadReplayFile(path string) ([]ReplayEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []ReplayEvent
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		content, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("could not read replay file %s: %s", path, err)
+		}
+		complete := err == nil
+
+		if len(bytes.TrimSpace(content)) > 0 {
+			var e ReplayEvent
+			if jsonErr := json.Unmarshal(content, &e); jsonErr != nil {
+				if !complete {
+					return events, nil
+				}
+				return nil, fmt.Errorf("could not parse line %d of replay file %s: %s", line, path, jsonErr)
+			}
+			events = append(events, e)
+		}
+
+		if !complete {
+			return events, nil
+		}
+	}
+}
+
+// ReadPcapFile reads the packets of a pcap file (with an ethernet link type) as DNS replay events
+func ReadPcapFile(path string) ([]ReplayEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader, err := pcapgo.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not read pcap file %s: %s", path, err)
+	}
+
+	var events []ReplayEvent
+	for {
+		data, ci, err := reader.ReadPacketData()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read packet from %s: %s", path, err)
+		}
+
+		events = append(events, ReplayEvent{
+			Type:      ReplayDNSPacket,
+			Timestamp: uint64(ci.Timestamp.UnixNano()),
+			Packet:    data,
+		})
+	}
+}
+
+// Replayer feeds recorded tracer events into a State and a DNS snooper, so that the
+// network state logic can be exercised deterministically without a kernel tracer.
+type Replayer struct {
+	state   State
+	snooper *SocketFilterSnooper
+
+	active     []ConnectionStats
+	latestTime uint64
+
+	// now is the recorded time of the last replayed event
+	now            time.Time
+	lastExpiration time.Time
+}
+
+// NewReplayer creates a new Replayer using the given state
+func NewReplayer(state State, collectDNSStats bool, collectLocalDNS bool, dnsTimeout time.Duration) *Replayer {
+	r := &Replayer{state: state}
+	r.snooper = newSnooper(collectDNSStats, collectLocalDNS, dnsTimeout, func() time.Time { return r.now })
+	return r
+}
+
+// Replay processes the given events in order, and returns the connections returned
+// for each ReplayGetConnections event
+func (r *Replayer) Replay(events []ReplayEvent) ([]*Connections, error) {
+	var results []*Connections
+	for i, e := range events {
+		r.advance(e)
+
+		switch e.Type {
+		case ReplayActiveConnections:
+			active, err := toConnectionStats(e.Conns)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %s", i, err)
+			}
+			r.active = active
+			r.latestTime = e.Timestamp
+		case ReplayClosedConnections:
+			closed, err := toConnectionStats(e.Conns)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %s", i, err)
+			}
+			for _, c := range closed {
+				r.state.StoreClosedConnection(c)
+			}
+		case ReplayDNSPacket:
+			r.snooper.processPacket(e.Packet, time.Unix(0, int64(e.Timestamp)))
+		case ReplayGetConnections:
+			results = append(results, r.getConnections(e.ClientID))
+		default:
+			return nil, fmt.Errorf("event %d: unknown event type %q", i, e.Type)
+		}
+	}
+	return results, nil
+}
+
+// GetDNSStats returns the stats of the DNS snooper
+func (r *Replayer) GetDNSStats() map[string]int64 {
+	return r.snooper.GetStats()
+}
+
+// Close releases the resources used by the replayer
+func (r *Replayer) Close() {
+	r.snooper.Close()
+}
+
+// advance moves the replay clock to the recorded time of an event, and expires the DNS
+// cache and stats as often as the DNS snooper does
+func (r *Replayer) advance(e ReplayEvent) {
+	var t time.Time
+	switch {
+	case e.Time != 0:
+		t = time.Unix(0, e.Time)
+	case e.Type == ReplayDNSPacket && e.Timestamp != 0:
+		t = time.Unix(0, int64(e.Timestamp))
+	default:
+		return
+	}
+
+	if !t.After(r.now) {
+		return
+	}
+	r.now = t
+
+	if r.lastExpiration.IsZero() {
+		r.lastExpiration = t
+	} else if r.now.Sub(r.lastExpiration) >= dnsCacheExpirationPeriod {
+		r.snooper.expire(r.now)
+		r.lastExpiration = r.now
+	}
+}
+
+func (r *Replayer) getConnections(clientID string) *Connections {
+	if clientID == "" {
+		clientID = DEBUGCLIENT
+	}
+
+	// The state may keep references to the active connections, so give it a copy
+	active := make([]ConnectionStats, len(r.active))
+	copy(active, r.active)
+
+	conns := r.state.Connections(clientID, r.latestTime, active, r.snooper.GetDNSStats())
+	return &Connections{Conns: conns, DNS: r.snooper.Resolve(conns)}
+}
+
+func toConnectionStats(recorded []RecordedConnection) ([]ConnectionStats, error) {
+	conns := make([]ConnectionStats, 0, len(recorded))
+	for _, rc := range recorded {
+		c, err := rc.ConnectionStats()
+		if err != nil {
+			return nil, err
+		}
+		conns = append(conns, c)
+	}
+	return conns, nil
+}
@@ -0,0 +1,174 @@
+// +build linux_bpf
+
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// replayQueueSize is the number of events which can be waiting to be written before new ones are dropped
+	replayQueueSize = 4096
+	// replayFlushInterval is how often the recorded events are flushed to the file
+	replayFlushInterval = time.Second
+)
+
+// ReplayRecorder writes the tracer events to a file which can be read with ReadReplayFile.
+// Events are queued and written by a separate goroutine so that recording doesn't slow down the
+// tracer: when the queue is full, events are dropped.
+type ReplayRecorder struct {
+	file    *os.File
+	writer  *bufio.Writer
+	maxSize int64
+	size    int64
+	full    bool
+
+	// mux protects the queue from being closed while an event is sent
+	mux     sync.RWMutex
+	closed  bool
+	events  chan ReplayEvent
+	done    chan struct{}
+	dropped int64
+}
+
+// NewReplayRecorder creates a file recording the tracer events as newline-delimited JSON records.
+// Recording stops once the file reaches maxSize bytes; a maxSize of 0 means no limit.
+func NewReplayRecorder(path string, maxSize int64) (*ReplayRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &ReplayRecorder{
+		file:    f,
+		writer:  bufio.NewWriter(f),
+		maxSize: maxSize,
+		events:  make(chan ReplayEvent, replayQueueSize),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// RecordActiveConnections records a snapshot of the active connections and the latest eBPF timestamp
+func (r *ReplayRecorder) RecordActiveConnections(conns []ConnectionStats, latestTime uint64) {
+	r.record(ReplayEvent{Type: ReplayActiveConnections, Timestamp: latestTime, Conns: recordConnections(conns)})
+}
+
+// RecordClosedConnection records a closed connection
+func (r *ReplayRecorder) RecordClosedConnection(conn ConnectionStats) {
+	r.record(ReplayEvent{Type: ReplayClosedConnections, Conns: []RecordedConnection{RecordConnection(conn)}})
+}
+
+// RecordDNSPacket records a packet captured by the DNS socket filter
+func (r *ReplayRecorder) RecordDNSPacket(data []byte, ts time.Time) {
+	// The packet data is only valid until the next capture, so it is copied before being queued
+	packet := make([]byte, len(data))
+	copy(packet, data)
+	r.record(ReplayEvent{Type: ReplayDNSPacket, Timestamp: uint64(ts.UnixNano()), Packet: packet})
+}
+
+// RecordGetConnections records a request for connections made by a client
+func (r *ReplayRecorder) RecordGetConnections(clientID string) {
+	r.record(ReplayEvent{Type: ReplayGetConnections, ClientID: clientID})
+}
+
+// Close writes the queued events and closes the file
+func (r *ReplayRecorder) Close() error {
+	r.mux.Lock()
+	if r.closed {
+		r.mux.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.events)
+	r.mux.Unlock()
+
+	<-r.done
+	if dropped := atomic.LoadInt64(&r.dropped); dropped > 0 {
+		log.Warnf("dropped %d replay events because the recording queue was full", dropped)
+	}
+
+	if err := r.writer.Flush(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+func (r *ReplayRecorder) record(e ReplayEvent) {
+	e.Time = time.Now().UnixNano()
+
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	if r.closed {
+		return
+	}
+	select {
+	case r.events <- e:
+	default:
+		atomic.AddInt64(&r.dropped, 1)
+	}
+}
+
+func (r *ReplayRecorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(replayFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e, ok := <-r.events:
+			if !ok {
+				return
+			}
+			r.write(e)
+		case <-ticker.C:
+			if err := r.writer.Flush(); err != nil {
+				log.Warnf("could not flush replay events: %s", err)
+			}
+		}
+	}
+}
+
+func (r *ReplayRecorder) write(e ReplayEvent) {
+	if r.full {
+		return
+	}
+
+	content, err := json.Marshal(e)
+	if err != nil {
+		log.Warnf("could not record %s event: %s", e.Type, err)
+		return
+	}
+	content = append(content, '\n')
+
+	if r.maxSize > 0 && r.size+int64(len(content)) > r.maxSize {
+		log.Warnf("replay file reached its maximum size of %d bytes, no more events will be recorded", r.maxSize)
+		r.full = true
+		return
+	}
+
+	n, err := r.writer.Write(content)
+	r.size += int64(n)
+	if err != nil {
+		log.Warnf("could not record %s event: %s", e.Type, err)
+	}
+}
+
+func recordConnections(conns []ConnectionStats) []RecordedConnection {
+	recorded := make([]RecordedConnection, 0, len(conns))
+	for _, c := range conns {
+		recorded = append(recorded, RecordConnection(c))
+	}
+	return recorded
+}
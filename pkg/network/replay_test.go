@@ -0,0 +1,293 @@
+// +build linux_bpf
+
+package network
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildDNSPacket(t *testing.T, response bool) []byte {
+	client, server := net.ParseIP("10.0.0.1"), net.ParseIP("8.8.8.8")
+
+	ip := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: client, DstIP: server}
+	udp := &layers.UDP{SrcPort: 53000, DstPort: 53}
+	if response {
+		ip.SrcIP, ip.DstIP = server, client
+		udp.SrcPort, udp.DstPort = 53, 53000
+	}
+	require.NoError(t, udp.SetNetworkLayerForChecksum(ip))
+
+	dns := &layers.DNS{
+		ID:        1,
+		QR:        response,
+		OpCode:    layers.DNSOpCodeQuery,
+		Questions: []layers.DNSQuestion{{Name: []byte("example.com"), Type: layers.DNSTypeA, Class: layers.DNSClassIN}},
+	}
+	if response {
+		dns.Answers = []layers.DNSResourceRecord{{
+			Name:  []byte("example.com"),
+			Type:  layers.DNSTypeA,
+			Class: layers.DNSClassIN,
+			TTL:   60,
+			IP:    net.ParseIP("10.0.0.2"),
+		}}
+	}
+
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 1},
+		DstMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 2},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ip, udp, dns))
+	return buf.Bytes()
+}
+
+func TestReplayConnectionsAndDNS(t *testing.T) {
+	conn := RecordedConnection{
+		Pid:                42,
+		Type:               "tcp",
+		Family:             "v4",
+		Source:             "10.0.0.1",
+		Dest:               "10.0.0.2",
+		SPort:              50000,
+		DPort:              443,
+		MonotonicSentBytes: 100,
+		LastUpdateEpoch:    1,
+	}
+	updated := conn
+	updated.MonotonicSentBytes = 150
+	updated.LastUpdateEpoch = 2
+
+	closed := conn
+	closed.SPort = 50001
+	closed.MonotonicSentBytes = 10
+
+	now := time.Now().UnixNano()
+	events := []ReplayEvent{
+		// The first get only registers the client
+		{Type: ReplayGetConnections, ClientID: "1"},
+		{Type: ReplayDNSPacket, Timestamp: uint64(now), Packet: buildDNSPacket(t, false)},
+		{Type: ReplayDNSPacket, Timestamp: uint64(now + int64(time.Millisecond)), Packet: buildDNSPacket(t, true)},
+		{Type: ReplayActiveConnections, Timestamp: 1, Conns: []RecordedConnection{conn}},
+		{Type: ReplayGetConnections, ClientID: "1"},
+		{Type: ReplayActiveConnections, Timestamp: 2, Conns: []RecordedConnection{updated}},
+		{Type: ReplayClosedConnections, Conns: []RecordedConnection{closed}},
+		{Type: ReplayGetConnections, ClientID: "1"},
+	}
+
+	r := NewReplayer(newDefaultState(), true, false, 15*time.Second)
+	defer r.Close()
+
+	results, err := r.Replay(events)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Empty(t, results[0].Conns)
+
+	require.Len(t, results[1].Conns, 1)
+	assert.Equal(t, uint64(100), results[1].Conns[0].LastSentBytes)
+	assert.Equal(t, []string{"example.com"}, results[1].DNS[util.AddressFromString("10.0.0.2")])
+
+	require.Len(t, results[2].Conns, 2)
+	for _, c := range results[2].Conns {
+		switch c.SPort {
+		case 50000:
+			assert.Equal(t, uint64(50), c.LastSentBytes)
+		case 50001:
+			assert.Equal(t, uint64(10), c.LastSentBytes)
+		default:
+			t.Errorf("unexpected connection %s", c)
+		}
+	}
+
+	stats := r.GetDNSStats()
+	assert.Equal(t, int64(1), stats["queries"])
+	assert.Equal(t, int64(1), stats["successes"])
+}
+
+func TestReplayInvalidEvents(t *testing.T) {
+	r := NewReplayer(newDefaultState(), false, false, 15*time.Second)
+	defer r.Close()
+
+	_, err := r.Replay([]ReplayEvent{{Type: "unknown"}})
+	assert.Error(t, err)
+
+	_, err = r.Replay([]ReplayEvent{{
+		Type:  ReplayActiveConnections,
+		Conns: []RecordedConnection{{Type: "sctp", Family: "v4", Source: "10.0.0.1", Dest: "10.0.0.2"}},
+	}})
+	assert.Error(t, err)
+}
+
+func TestReadReplayFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := ConnectionStats{
+		Pid:    42,
+		Type:   UDP,
+		Family: AFINET6,
+		Source: util.AddressFromString("::1"),
+		Dest:   util.AddressFromString("::2"),
+		SPort:  1000,
+		DPort:  53,
+	}
+	events := []ReplayEvent{{Type: ReplayClosedConnections, Conns: []RecordedConnection{RecordConnection(c)}}}
+
+	content, err := json.Marshal(events[0])
+	require.NoError(t, err)
+	// The last record is truncated, as if the system-probe was killed while writing it
+	content = append(content, '\n')
+	content = append(content, content[:len(content)/2]...)
+	path := filepath.Join(dir, "events.json")
+	require.NoError(t, ioutil.WriteFile(path, content, 0644))
+
+	read, err := ReadReplayFile(path)
+	require.NoError(t, err)
+	require.Len(t, read, 1)
+	require.Len(t, read[0].Conns, 1)
+
+	converted, err := read[0].Conns[0].ConnectionStats()
+	require.NoError(t, err)
+	assert.Equal(t, c, converted)
+}
+
+func TestRecordConnectionRoundTrip(t *testing.T) {
+	c := ConnectionStats{
+		Pid:       42,
+		Type:      TCP,
+		Family:    AFINET,
+		Source:    util.AddressFromString("10.0.0.1"),
+		Dest:      util.AddressFromString("10.0.0.2"),
+		SPort:     50000,
+		DPort:     443,
+		Direction: OUTGOING,
+		IPTranslation: &IPTranslation{
+			ReplSrcIP:   util.AddressFromString("10.0.0.2"),
+			ReplDstIP:   util.AddressFromString("192.168.0.1"),
+			ReplSrcPort: 443,
+			ReplDstPort: 40000,
+		},
+		MonotonicTCPFailedConnects: 1,
+		MonotonicTCPResetsSent:     2,
+		MonotonicTCPResetsReceived: 3,
+	}
+
+	converted, err := RecordConnection(c).ConnectionStats()
+	require.NoError(t, err)
+	assert.Equal(t, c, converted)
+}
+
+func TestReplayDNSExpiration(t *testing.T) {
+	conn := RecordedConnection{
+		Pid:    42,
+		Type:   "tcp",
+		Family: "v4",
+		Source: "10.0.0.1",
+		Dest:   "10.0.0.2",
+		SPort:  50000,
+		DPort:  443,
+	}
+
+	// The recorded times are far in the past, DNS entries must only expire based on them
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) int64 { return start.Add(d).UnixNano() }
+	events := []ReplayEvent{
+		{Type: ReplayDNSPacket, Timestamp: uint64(at(0)), Packet: buildDNSPacket(t, false)},
+		{Type: ReplayDNSPacket, Timestamp: uint64(at(time.Millisecond)), Packet: buildDNSPacket(t, true)},
+		{Type: ReplayActiveConnections, Timestamp: 1, Conns: []RecordedConnection{conn}, Time: at(time.Second)},
+		{Type: ReplayGetConnections, ClientID: "1", Time: at(time.Second)},
+		{Type: ReplayGetConnections, ClientID: "1", Time: at(dnsCacheTTL + 2*dnsCacheExpirationPeriod)},
+	}
+
+	r := NewReplayer(newDefaultState(), false, false, 15*time.Second)
+	defer r.Close()
+
+	results, err := r.Replay(events)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{"example.com"}, results[0].DNS[util.AddressFromString("10.0.0.2")])
+	assert.Empty(t, results[1].DNS)
+}
+
+func TestReplayRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := ConnectionStats{
+		Pid:       42,
+		Type:      TCP,
+		Family:    AFINET,
+		Source:    util.AddressFromString("10.0.0.1"),
+		Dest:      util.AddressFromString("10.0.0.2"),
+		SPort:     50000,
+		DPort:     443,
+		Direction: OUTGOING,
+	}
+
+	path := filepath.Join(dir, "events.json")
+	recorder, err := NewReplayRecorder(path, 0)
+	require.NoError(t, err)
+	recorder.RecordDNSPacket(buildDNSPacket(t, false), time.Now())
+	recorder.RecordClosedConnection(c)
+	recorder.RecordActiveConnections([]ConnectionStats{c}, 10)
+	recorder.RecordGetConnections("1")
+	require.NoError(t, recorder.Close())
+
+	events, err := ReadReplayFile(path)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	for _, e := range events {
+		assert.NotZero(t, e.Time)
+	}
+	assert.Equal(t, ReplayDNSPacket, events[0].Type)
+	assert.Equal(t, buildDNSPacket(t, false), events[0].Packet)
+	assert.Equal(t, ReplayClosedConnections, events[1].Type)
+	assert.Equal(t, ReplayActiveConnections, events[2].Type)
+	assert.Equal(t, uint64(10), events[2].Timestamp)
+	assert.Equal(t, ReplayGetConnections, events[3].Type)
+	assert.Equal(t, "1", events[3].ClientID)
+
+	converted, err := events[2].Conns[0].ConnectionStats()
+	require.NoError(t, err)
+	assert.Equal(t, c, converted)
+}
+
+func TestReplayRecorderMaxSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	content, err := json.Marshal(ReplayEvent{Type: ReplayGetConnections, ClientID: "1", Time: time.Now().UnixNano()})
+	require.NoError(t, err)
+
+	// Leave room for two records only
+	path := filepath.Join(dir, "events.json")
+	recorder, err := NewReplayRecorder(path, int64(2*(len(content)+1)+1))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		recorder.RecordGetConnections("1")
+	}
+	require.NoError(t, recorder.Close())
+
+	events, err := ReadReplayFile(path)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
@@ -71,6 +71,9 @@ type stats struct {
 	totalRetransmits    uint32
 	totalTCPEstablished uint32
 	totalTCPClosed      uint32
+	totalFailedConnects uint32
+	totalResetsSent     uint32
+	totalResetsReceived uint32
 }
 
 type client struct {
@@ -152,6 +155,9 @@ func (ns *networkState) Connections(
 			c.LastRetransmits = 0
 			c.LastTCPEstablished = 0
 			c.LastTCPClosed = 0
+			c.LastTCPFailedConnects = 0
+			c.LastTCPResetsSent = 0
+			c.LastTCPResetsReceived = 0
 		}
 
 		ns.determineConnectionIntraHost(latestConns)
@@ -268,6 +274,9 @@ func (ns *networkState) StoreClosedConnection(conn ConnectionStats) {
 			prev.MonotonicRetransmits += conn.MonotonicRetransmits
 			prev.MonotonicTCPEstablished += conn.MonotonicTCPEstablished
 			prev.MonotonicTCPClosed += conn.MonotonicTCPClosed
+			prev.MonotonicTCPFailedConnects += conn.MonotonicTCPFailedConnects
+			prev.MonotonicTCPResetsSent += conn.MonotonicTCPResetsSent
+			prev.MonotonicTCPResetsReceived += conn.MonotonicTCPResetsReceived
 			// Also update the timestamp
 			prev.LastUpdateEpoch = conn.LastUpdateEpoch
 			client.closedConnections[string(key)] = prev
@@ -343,6 +352,9 @@ func (ns *networkState) mergeConnections(id string, active map[string]*Connectio
 				closedConn.MonotonicRetransmits += activeConn.MonotonicRetransmits
 				closedConn.MonotonicTCPEstablished += activeConn.MonotonicTCPEstablished
 				closedConn.MonotonicTCPClosed += activeConn.MonotonicTCPClosed
+				closedConn.MonotonicTCPFailedConnects += activeConn.MonotonicTCPFailedConnects
+				closedConn.MonotonicTCPResetsSent += activeConn.MonotonicTCPResetsSent
+				closedConn.MonotonicTCPResetsReceived += activeConn.MonotonicTCPResetsReceived
 
 				ns.createStatsForKey(client, key)
 				ns.updateConnWithStatWithActiveConn(client, key, *activeConn, &closedConn)
@@ -398,6 +410,9 @@ func (ns *networkState) updateConnWithStatWithActiveConn(client *client, key str
 		closed.LastRetransmits = closed.MonotonicRetransmits - st.totalRetransmits
 		closed.LastTCPEstablished = closed.LastTCPEstablished - st.totalTCPEstablished
 		closed.LastTCPClosed = closed.LastTCPClosed - st.totalTCPClosed
+		closed.LastTCPFailedConnects = closed.MonotonicTCPFailedConnects - st.totalFailedConnects
+		closed.LastTCPResetsSent = closed.MonotonicTCPResetsSent - st.totalResetsSent
+		closed.LastTCPResetsReceived = closed.MonotonicTCPResetsReceived - st.totalResetsReceived
 
 		// Update stats object with latest values
 		st.totalSent = active.MonotonicSentBytes
@@ -405,12 +420,18 @@ func (ns *networkState) updateConnWithStatWithActiveConn(client *client, key str
 		st.totalRetransmits = active.MonotonicRetransmits
 		st.totalTCPEstablished = active.MonotonicTCPEstablished
 		st.totalTCPClosed = active.MonotonicTCPClosed
+		st.totalFailedConnects = active.MonotonicTCPFailedConnects
+		st.totalResetsSent = active.MonotonicTCPResetsSent
+		st.totalResetsReceived = active.MonotonicTCPResetsReceived
 	} else {
 		closed.LastSentBytes = closed.MonotonicSentBytes
 		closed.LastRecvBytes = closed.MonotonicRecvBytes
 		closed.LastRetransmits = closed.MonotonicRetransmits
 		closed.LastTCPEstablished = closed.MonotonicTCPEstablished
 		closed.LastTCPClosed = closed.MonotonicTCPClosed
+		closed.LastTCPFailedConnects = closed.MonotonicTCPFailedConnects
+		closed.LastTCPResetsSent = closed.MonotonicTCPResetsSent
+		closed.LastTCPResetsReceived = closed.MonotonicTCPResetsReceived
 	}
 }
 
@@ -424,6 +445,9 @@ func (ns *networkState) updateConnWithStats(client *client, key string, c *Conne
 		c.LastRetransmits = c.MonotonicRetransmits - st.totalRetransmits
 		c.LastTCPEstablished = c.MonotonicTCPEstablished - st.totalTCPEstablished
 		c.LastTCPClosed = c.MonotonicTCPClosed - st.totalTCPClosed
+		c.LastTCPFailedConnects = c.MonotonicTCPFailedConnects - st.totalFailedConnects
+		c.LastTCPResetsSent = c.MonotonicTCPResetsSent - st.totalResetsSent
+		c.LastTCPResetsReceived = c.MonotonicTCPResetsReceived - st.totalResetsReceived
 
 		// Update stats object with latest values
 		st.totalSent = c.MonotonicSentBytes
@@ -431,12 +455,18 @@ func (ns *networkState) updateConnWithStats(client *client, key string, c *Conne
 		st.totalRetransmits = c.MonotonicRetransmits
 		st.totalTCPEstablished = c.MonotonicTCPEstablished
 		st.totalTCPClosed = c.MonotonicTCPClosed
+		st.totalFailedConnects = c.MonotonicTCPFailedConnects
+		st.totalResetsSent = c.MonotonicTCPResetsSent
+		st.totalResetsReceived = c.MonotonicTCPResetsReceived
 	} else {
 		c.LastSentBytes = c.MonotonicSentBytes
 		c.LastRecvBytes = c.MonotonicRecvBytes
 		c.LastRetransmits = c.MonotonicRetransmits
 		c.LastTCPEstablished = c.MonotonicTCPEstablished
 		c.LastTCPClosed = c.MonotonicTCPClosed
+		c.LastTCPFailedConnects = c.MonotonicTCPFailedConnects
+		c.LastTCPResetsSent = c.MonotonicTCPResetsSent
+		c.LastTCPResetsReceived = c.MonotonicTCPResetsReceived
 	}
 }
 
@@ -557,6 +587,9 @@ func (ns *networkState) DumpState(clientID string) map[string]interface{} {
 				"total_retransmits":     uint64(s.totalRetransmits),
 				"total_tcp_established": uint64(s.totalTCPEstablished),
 				"total_tcp_closed":      uint64(s.totalTCPClosed),
+				"total_failed_connects": uint64(s.totalFailedConnects),
+				"total_resets_sent":     uint64(s.totalResetsSent),
+				"total_resets_received": uint64(s.totalResetsReceived),
 			}
 		}
 	}
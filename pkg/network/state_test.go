@@ -319,6 +319,45 @@ func TestLastStats(t *testing.T) {
 	assert.Equal(t, conn3.MonotonicRetransmits, conns[0].MonotonicRetransmits)
 }
 
+func TestLastTCPFailuresAndResets(t *testing.T) {
+	client := "1"
+	state := newDefaultState()
+
+	conn := ConnectionStats{
+		Pid:                        123,
+		Type:                       TCP,
+		Family:                     AFINET,
+		Source:                     util.AddressFromString("127.0.0.1"),
+		Dest:                       util.AddressFromString("127.0.0.1"),
+		SPort:                      31890,
+		DPort:                      80,
+		MonotonicTCPFailedConnects: 1,
+		MonotonicTCPResetsReceived: 1,
+	}
+
+	conn2 := conn
+	conn2.MonotonicTCPFailedConnects += 2
+	conn2.MonotonicTCPResetsSent += 3
+	conn2.MonotonicTCPResetsReceived += 2
+
+	// First get, we should not have any connections stored
+	conns := state.Connections(client, latestEpochTime(), nil, nil)
+	assert.Equal(t, 0, len(conns))
+
+	conns = state.Connections(client, latestEpochTime(), []ConnectionStats{conn}, nil)
+	require.Equal(t, 1, len(conns))
+	assert.Equal(t, uint32(1), conns[0].LastTCPFailedConnects)
+	assert.Equal(t, uint32(0), conns[0].LastTCPResetsSent)
+	assert.Equal(t, uint32(1), conns[0].LastTCPResetsReceived)
+
+	conns = state.Connections(client, latestEpochTime(), []ConnectionStats{conn2}, nil)
+	require.Equal(t, 1, len(conns))
+	assert.Equal(t, uint32(2), conns[0].LastTCPFailedConnects)
+	assert.Equal(t, uint32(3), conns[0].LastTCPResetsSent)
+	assert.Equal(t, uint32(2), conns[0].LastTCPResetsReceived)
+	assert.Equal(t, uint32(3), conns[0].MonotonicTCPFailedConnects)
+}
+
 func TestLastStatsForClosedConnection(t *testing.T) {
 	clientID := "1"
 	state := newDefaultState()
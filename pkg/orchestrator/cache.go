@@ -23,19 +23,29 @@ const (
 )
 
 var (
-	cacheExpVars        = expvar.NewMap("orchestrator-cache")
-	deploymentCacheHits = expvar.Int{}
-	replicaSetCacheHits = expvar.Int{}
-	nodeCacheHits       = expvar.Int{}
-	serviceCacheHits    = expvar.Int{}
-	podCacheHits        = expvar.Int{}
+	cacheExpVars         = expvar.NewMap("orchestrator-cache")
+	deploymentCacheHits  = expvar.Int{}
+	replicaSetCacheHits  = expvar.Int{}
+	nodeCacheHits        = expvar.Int{}
+	serviceCacheHits     = expvar.Int{}
+	podCacheHits         = expvar.Int{}
+	statefulSetCacheHits = expvar.Int{}
+	daemonSetCacheHits   = expvar.Int{}
+	jobCacheHits         = expvar.Int{}
+	cronJobCacheHits     = expvar.Int{}
+	pvcCacheHits         = expvar.Int{}
 
-	sendExpVars    = expvar.NewMap("orchestrator-sends")
-	deploymentHits = expvar.Int{}
-	replicaSetHits = expvar.Int{}
-	nodeHits       = expvar.Int{}
-	serviceHits    = expvar.Int{}
-	podHits        = expvar.Int{}
+	sendExpVars     = expvar.NewMap("orchestrator-sends")
+	deploymentHits  = expvar.Int{}
+	replicaSetHits  = expvar.Int{}
+	nodeHits        = expvar.Int{}
+	serviceHits     = expvar.Int{}
+	podHits         = expvar.Int{}
+	statefulSetHits = expvar.Int{}
+	daemonSetHits   = expvar.Int{}
+	jobHits         = expvar.Int{}
+	cronJobHits     = expvar.Int{}
+	pvcHits         = expvar.Int{}
 
 	// KubernetesResourceCache provides an in-memory key:value store similar to memcached for kubernetes resources.
 	KubernetesResourceCache = cache.New(defaultExpire, defaultPurge)
@@ -47,12 +57,22 @@ func init() {
 	cacheExpVars.Set("ReplicaSets", &replicaSetCacheHits)
 	cacheExpVars.Set("Nodes", &nodeCacheHits)
 	cacheExpVars.Set("Services", &serviceCacheHits)
+	cacheExpVars.Set("StatefulSets", &statefulSetCacheHits)
+	cacheExpVars.Set("DaemonSets", &daemonSetCacheHits)
+	cacheExpVars.Set("Jobs", &jobCacheHits)
+	cacheExpVars.Set("CronJobs", &cronJobCacheHits)
+	cacheExpVars.Set("PersistentVolumeClaims", &pvcCacheHits)
 
 	sendExpVars.Set("Pods", &podHits)
 	sendExpVars.Set("Deployments", &deploymentHits)
 	sendExpVars.Set("ReplicaSets", &replicaSetHits)
 	sendExpVars.Set("Nodes", &nodeHits)
 	sendExpVars.Set("Services", &serviceHits)
+	sendExpVars.Set("StatefulSets", &statefulSetHits)
+	sendExpVars.Set("DaemonSets", &daemonSetHits)
+	sendExpVars.Set("Jobs", &jobHits)
+	sendExpVars.Set("CronJobs", &cronJobHits)
+	sendExpVars.Set("PersistentVolumeClaims", &pvcHits)
 }
 
 // SkipKubernetesResource checks with a global kubernetes cache whether the resource was already reported.
@@ -89,6 +109,16 @@ func incCacheHit(nodeType NodeType) {
 		deploymentCacheHits.Add(1)
 	case K8sPod:
 		podCacheHits.Add(1)
+	case K8sStatefulSet:
+		statefulSetCacheHits.Add(1)
+	case K8sDaemonSet:
+		daemonSetCacheHits.Add(1)
+	case K8sJob:
+		jobCacheHits.Add(1)
+	case K8sCronJob:
+		cronJobCacheHits.Add(1)
+	case K8sPersistentVolumeClaim:
+		pvcCacheHits.Add(1)
 	default:
 		log.Errorf("Cannot increment unknown nodeType, iota: %v", nodeType)
 	}
@@ -106,6 +136,16 @@ func incCacheMiss(nodeType NodeType) {
 		deploymentHits.Add(1)
 	case K8sPod:
 		podHits.Add(1)
+	case K8sStatefulSet:
+		statefulSetHits.Add(1)
+	case K8sDaemonSet:
+		daemonSetHits.Add(1)
+	case K8sJob:
+		jobHits.Add(1)
+	case K8sCronJob:
+		cronJobHits.Add(1)
+	case K8sPersistentVolumeClaim:
+		pvcHits.Add(1)
 	default:
 		log.Errorf("Cannot increment unknown nodeType, iota: %v", nodeType)
 	}
@@ -21,11 +21,21 @@ const (
 	K8sService
 	// K8sNode represents a Kubernetes Node
 	K8sNode
+	// K8sStatefulSet represents a Kubernetes StatefulSet
+	K8sStatefulSet
+	// K8sDaemonSet represents a Kubernetes DaemonSet
+	K8sDaemonSet
+	// K8sJob represents a Kubernetes Job
+	K8sJob
+	// K8sCronJob represents a Kubernetes CronJob
+	K8sCronJob
+	// K8sPersistentVolumeClaim represents a Kubernetes PersistentVolumeClaim
+	K8sPersistentVolumeClaim
 )
 
 // NodeTypes returns the current existing NodesTypes as a slice to iterate over.
 func NodeTypes() []NodeType {
-	return []NodeType{K8sNode, K8sPod, K8sReplicaSet, K8sDeployment, K8sService}
+	return []NodeType{K8sNode, K8sPod, K8sReplicaSet, K8sDeployment, K8sService, K8sStatefulSet, K8sDaemonSet, K8sJob, K8sCronJob, K8sPersistentVolumeClaim}
 }
 
 func (n NodeType) String() string {
@@ -40,6 +50,16 @@ func (n NodeType) String() string {
 		return "Deployment"
 	case K8sPod:
 		return "Pod"
+	case K8sStatefulSet:
+		return "StatefulSet"
+	case K8sDaemonSet:
+		return "DaemonSet"
+	case K8sJob:
+		return "Job"
+	case K8sCronJob:
+		return "CronJob"
+	case K8sPersistentVolumeClaim:
+		return "PersistentVolumeClaim"
 	default:
 		log.Errorf("trying to convert unknown NodeType iota: %v", n)
 		return ""
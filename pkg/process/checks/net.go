@@ -15,6 +15,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/process/net/resolver"
 	procutil "github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -35,6 +36,9 @@ type ConnectionsCheck struct {
 	networkID              string
 	notInitializedLogLimit *procutil.LogLimit
 	lastTelemetry          *model.CollectorConnectionsTelemetry
+	kubeResolver           *resolver.KubeResolver
+	clusterResolution      bool
+	dcaClient              clusteragent.DCAClientInterface
 }
 
 // Init initializes a ConnectionsCheck instance.
@@ -54,6 +58,11 @@ func (c *ConnectionsCheck) Init(cfg *config.AgentConfig, _ *model.SystemInfo) {
 	}
 	c.networkID = networkID
 
+	if cfg.EnableKubernetesResolution {
+		c.kubeResolver = resolver.NewKubeResolver(cfg.KubernetesResolutionRetention)
+		c.clusterResolution = cfg.EnableKubernetesClusterResolution
+	}
+
 	// Run the check one time on init to register the client on the system probe
 	_, _ = c.Run(cfg, 0)
 }
@@ -85,11 +94,13 @@ func (c *ConnectionsCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.
 	dockerproxy.NewFilter().Filter(conns)
 	// Resolve the Raddr side of connections for local containers
 	LocalResolver.Resolve(conns)
+	// Resolve the pods owning both sides of connections
+	ctrs := c.resolveKubeEndpoints(conns.Conns, start)
 
-	tel := c.diffTelemetry(conns.Telemetry)
+	tel := c.diffTelemetry(conns.ConnTelemetry)
 
 	log.Debugf("collected connections in %s", time.Since(start))
-	return batchConnections(cfg, groupID, c.enrichConnections(conns.Conns), conns.Dns, c.networkID, tel), nil
+	return batchConnections(cfg, groupID, c.enrichConnections(conns.Conns), conns.Dns, ctrs, c.networkID, tel), nil
 }
 
 func (c *ConnectionsCheck) getConnections() (*model.Connections, error) {
@@ -103,6 +114,22 @@ func (c *ConnectionsCheck) getConnections() (*model.Connections, error) {
 	return tu.GetConnections(c.tracerClientID)
 }
 
+func (c *ConnectionsCheck) resolveKubeEndpoints(conns []*model.Connection, now time.Time) map[string]*model.ResourceMetadata {
+	if c.kubeResolver == nil {
+		return nil
+	}
+
+	// Keep resolving with the IP owners we already know of when none could be retrieved
+	endpoints, err := getKubeEndpoints()
+	if err != nil {
+		log.Debugf("unable to retrieve pod IPs: %s", err)
+	} else {
+		c.kubeResolver.Update(endpoints, now)
+	}
+	c.lookupClusterEndpoints(conns, now)
+	return c.kubeResolver.Resolve(conns)
+}
+
 func (c *ConnectionsCheck) enrichConnections(conns []*model.Connection) []*model.Connection {
 	// Process create-times required to construct unique process hash keys on the backend
 	createTimeForPID := Process.createTimesforPIDs(connectionPIDs(conns))
@@ -164,6 +191,7 @@ func batchConnections(
 	groupID int32,
 	cxs []*model.Connection,
 	dns map[string]*model.DNSEntry,
+	ctrs map[string]*model.ResourceMetadata,
 	networkID string,
 	telemetry *model.CollectorConnectionsTelemetry,
 ) []model.MessageBody {
@@ -188,6 +216,7 @@ func batchConnections(
 
 		ctrIDForPID := make(map[int32]string)
 		batchDNS := make(map[string]*model.DNSEntry)
+		var batchCtrs map[string]*model.ResourceMetadata
 		for _, c := range batchConns { // We only want to include DNS entries relevant to this batch of connections
 			if entries, ok := dns[c.Raddr.Ip]; ok {
 				batchDNS[c.Raddr.Ip] = entries
@@ -196,6 +225,16 @@ func batchConnections(
 			if c.Laddr.ContainerId != "" {
 				ctrIDForPID[c.Pid] = c.Laddr.ContainerId
 			}
+
+			// Same for the metadata of the resolved containers
+			for _, cid := range []string{c.Laddr.ContainerId, c.Raddr.ContainerId} {
+				if ctr, ok := ctrs[cid]; ok {
+					if batchCtrs == nil {
+						batchCtrs = make(map[string]*model.ResourceMetadata)
+					}
+					batchCtrs[cid] = ctr
+				}
+			}
 		}
 
 		cc := &model.CollectorConnections{
@@ -206,11 +245,12 @@ func batchConnections(
 			GroupSize:         groupSize,
 			ContainerForPid:   ctrIDForPID,
 			EncodedDNS:        dnsEncoder.Encode(batchDNS),
+			ResolvedResources: batchCtrs,
 			ContainerHostType: cfg.ContainerHostType,
 		}
 		// only add the telemetry to the first message to prevent double counting
 		if len(batches) == 0 {
-			cc.Telemetry = telemetry
+			cc.ConnTelemetry = telemetry
 		}
 		batches = append(batches, cc)
 
@@ -246,3 +286,13 @@ func connectionPIDs(conns []*model.Connection) []int32 {
 	}
 	return pids
 }
+
+// sortedValues returns the values of a map sorted
+func sortedValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,67 @@
+// +build kubelet
+
+package checks
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/net/resolver"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// getKubeEndpoints returns the pods running on the node indexed by their IP
+func getKubeEndpoints() (map[string]resolver.KubeEndpoint, error) {
+	kubeUtil, err := kubelet.GetKubeUtil()
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := kubeUtil.GetLocalPodList()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make(map[string]resolver.KubeEndpoint, len(pods))
+	for _, pod := range pods {
+		// Pods in the host network share the IP of the host
+		if pod.Spec.HostNetwork || pod.Status.PodIP == "" {
+			continue
+		}
+
+		// Init containers are done by the time the pod serves connections
+		containerIDs := make(map[string]string, len(pod.Status.Containers))
+		for _, ctr := range pod.Status.Containers {
+			if ctr.ID != "" && ctr.State.Running != nil {
+				containerIDs[ctr.Name] = containers.ContainerIDForEntity(ctr.ID)
+			}
+		}
+		containerPorts := make(map[int32]string)
+		for _, ctr := range pod.Spec.Containers {
+			id, ok := containerIDs[ctr.Name]
+			if !ok {
+				continue
+			}
+			for _, port := range ctr.Ports {
+				containerPorts[int32(port.ContainerPort)] = id
+			}
+		}
+
+		// The service tags of the pod are provided by the cluster-agent to the tagger when it is enabled
+		tags, err := tagger.Tag(kubelet.PodUIDToTaggerEntityName(pod.Metadata.UID), collectors.OrchestratorCardinality)
+		if err != nil {
+			log.Debugf("unable to retrieve tags for pod %s/%s: %s", pod.Metadata.Namespace, pod.Metadata.Name, err)
+		}
+
+		endpoints[pod.Status.PodIP] = resolver.KubeEndpoint{
+			Name:           pod.Metadata.Name,
+			Namespace:      pod.Metadata.Namespace,
+			UID:            pod.Metadata.UID,
+			ContainerIDs:   sortedValues(containerIDs),
+			ContainerPorts: containerPorts,
+			Tags:           tags,
+		}
+	}
+	return endpoints, nil
+}
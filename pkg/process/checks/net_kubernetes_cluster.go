@@ -0,0 +1,65 @@
+package checks
+
+import (
+	"time"
+
+	model "github.com/DataDog/agent-payload/process"
+	apiv1 "github.com/DataDog/datadog-agent/pkg/clusteragent/api/v1"
+	"github.com/DataDog/datadog-agent/pkg/process/net/resolver"
+	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// lookupClusterEndpoints looks up the pods of other nodes owning the remote IPs of the
+// given connections in the cluster-agent
+func (c *ConnectionsCheck) lookupClusterEndpoints(conns []*model.Connection, now time.Time) {
+	dcaClient := c.clusterAgentClient()
+	if dcaClient == nil {
+		return
+	}
+
+	ips := c.kubeResolver.UnresolvedIPs(conns)
+	if len(ips) == 0 {
+		return
+	}
+
+	// IPs which couldn't be looked up aren't retried before the retention period is over
+	metadata, err := dcaClient.GetPodsMetadataForIPs(ips)
+	if err != nil {
+		log.Debugf("unable to retrieve the pods owning %d IPs from the cluster agent: %s", len(ips), err)
+	}
+	endpoints := make(map[string]resolver.KubeEndpoint, len(metadata))
+	for ip, meta := range metadata {
+		endpoints[ip] = podIPEndpoint(meta)
+	}
+	c.kubeResolver.UpdateLookup(ips, endpoints, now)
+}
+
+// clusterAgentClient returns the cluster-agent client, once it could be initialized
+func (c *ConnectionsCheck) clusterAgentClient() clusteragent.DCAClientInterface {
+	if c.dcaClient != nil || !c.clusterResolution {
+		return c.dcaClient
+	}
+	dcaClient, err := clusteragent.GetClusterAgentClient()
+	if err != nil {
+		log.Debugf("unable to initialize the cluster agent client: %s", err)
+		return nil
+	}
+	c.dcaClient = dcaClient
+	return c.dcaClient
+}
+
+func podIPEndpoint(meta *apiv1.PodIPMetadata) resolver.KubeEndpoint {
+	tags := []string{"pod_name:" + meta.Name, "kube_namespace:" + meta.Namespace}
+	for _, svc := range meta.Services {
+		tags = append(tags, "kube_service:"+svc)
+	}
+	return resolver.KubeEndpoint{
+		Name:           meta.Name,
+		Namespace:      meta.Namespace,
+		UID:            meta.UID,
+		ContainerIDs:   meta.ContainerIDs,
+		ContainerPorts: meta.ContainerPorts,
+		Tags:           tags,
+	}
+}
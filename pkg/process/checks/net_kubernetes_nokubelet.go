@@ -0,0 +1,12 @@
+// +build !kubelet
+
+package checks
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/net/resolver"
+)
+
+// getKubeEndpoints is not implemented without the kubelet
+func getKubeEndpoints() (map[string]resolver.KubeEndpoint, error) {
+	return nil, nil
+}
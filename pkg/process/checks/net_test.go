@@ -3,10 +3,15 @@ package checks
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	model "github.com/DataDog/agent-payload/process"
+	apiv1 "github.com/DataDog/datadog-agent/pkg/clusteragent/api/v1"
 	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/net/resolver"
+	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func makeConnection(pid int32) *model.Connection {
@@ -70,7 +75,7 @@ func TestNetworkConnectionBatching(t *testing.T) {
 	} {
 		cfg.MaxConnsPerMessage = tc.maxSize
 		tm := &model.CollectorConnectionsTelemetry{}
-		chunks := batchConnections(cfg, 0, tc.cur, map[string]*model.DNSEntry{}, "nid", tm)
+		chunks := batchConnections(cfg, 0, tc.cur, map[string]*model.DNSEntry{}, nil, "nid", tm)
 
 		assert.Len(t, chunks, tc.expectedChunks, "len %d", i)
 		total := 0
@@ -89,9 +94,9 @@ func TestNetworkConnectionBatching(t *testing.T) {
 
 			// ensure only first chunk has telemetry
 			if i == 0 {
-				assert.NotNil(t, connections.Telemetry)
+				assert.NotNil(t, connections.ConnTelemetry)
 			} else {
-				assert.Nil(t, connections.Telemetry)
+				assert.Nil(t, connections.ConnTelemetry)
 			}
 		}
 		assert.Equal(t, tc.expectedTotal, total, "total test %d", i)
@@ -109,7 +114,7 @@ func TestNetworkConnectionBatchingWithDNS(t *testing.T) {
 	cfg := config.NewDefaultAgentConfig(false)
 	cfg.MaxConnsPerMessage = 1
 
-	chunks := batchConnections(cfg, 0, p, dns, "nid", nil)
+	chunks := batchConnections(cfg, 0, p, dns, nil, "nid", nil)
 
 	assert.Len(t, chunks, 4)
 	total := 0
@@ -137,6 +142,32 @@ func TestNetworkConnectionBatchingWithDNS(t *testing.T) {
 	assert.Equal(t, 4, total)
 }
 
+func TestNetworkConnectionBatchingWithResolvedContainers(t *testing.T) {
+	p := makeConnections(4)
+
+	p[3].Raddr.ContainerId = "pod-ctr"
+	ctrs := map[string]*model.ResourceMetadata{
+		"pod-ctr": {Id: "pod-ctr", Tags: []string{"pod_name:web"}},
+	}
+
+	cfg := config.NewDefaultAgentConfig(false)
+	cfg.MaxConnsPerMessage = 1
+
+	chunks := batchConnections(cfg, 0, p, map[string]*model.DNSEntry{}, ctrs, "nid", nil)
+
+	assert.Len(t, chunks, 4)
+	for i, c := range chunks {
+		connections := c.(*model.CollectorConnections)
+
+		// Only the last chunk should have the resolved container
+		if i == 3 {
+			assert.Equal(t, ctrs, connections.ResolvedResources)
+		} else {
+			assert.Empty(t, connections.ResolvedResources)
+		}
+	}
+}
+
 func TestBatchSimilarConnectionsTogether(t *testing.T) {
 	p := makeConnections(6)
 
@@ -150,7 +181,7 @@ func TestBatchSimilarConnectionsTogether(t *testing.T) {
 	cfg := config.NewDefaultAgentConfig(false)
 	cfg.MaxConnsPerMessage = 2
 
-	chunks := batchConnections(cfg, 0, p, map[string]*model.DNSEntry{}, "nid", nil)
+	chunks := batchConnections(cfg, 0, p, map[string]*model.DNSEntry{}, nil, "nid", nil)
 
 	assert.Len(t, chunks, 3)
 	total := 0
@@ -175,3 +206,56 @@ func TestBatchSimilarConnectionsTogether(t *testing.T) {
 	}
 	assert.Equal(t, 6, total)
 }
+
+type fakePodIPsDCAClient struct {
+	clusteragent.DCAClientInterface
+	pods     apiv1.PodIPsMetadataResponse
+	requests [][]string
+}
+
+func (f *fakePodIPsDCAClient) GetPodsMetadataForIPs(ips []string) (apiv1.PodIPsMetadataResponse, error) {
+	f.requests = append(f.requests, ips)
+	return f.pods, nil
+}
+
+func TestResolveRemotePod(t *testing.T) {
+	dcaClient := &fakePodIPsDCAClient{
+		pods: apiv1.PodIPsMetadataResponse{
+			"10.0.2.5": {
+				Name:           "api-7f9c",
+				Namespace:      "backend",
+				UID:            "uid-api",
+				ContainerIDs:   []string{"ctr-api", "ctr-proxy"},
+				ContainerPorts: map[int32]string{8080: "ctr-api"},
+				Services:       []string{"api"},
+			},
+		},
+	}
+	c := &ConnectionsCheck{
+		kubeResolver:      resolver.NewKubeResolver(time.Minute),
+		clusterResolution: true,
+		dcaClient:         dcaClient,
+	}
+	now := time.Now()
+
+	conns := []*model.Connection{
+		// connection to a pod running on another node
+		{Laddr: &model.Addr{Ip: "10.0.1.3", Port: 40000, ContainerId: "ctr-local"}, Raddr: &model.Addr{Ip: "10.0.2.5", Port: 8080}},
+		// connection outside of the cluster
+		{Laddr: &model.Addr{Ip: "10.0.1.3", Port: 40001, ContainerId: "ctr-local"}, Raddr: &model.Addr{Ip: "1.1.1.1", Port: 443}},
+	}
+	ctrs := c.resolveKubeEndpoints(conns, now)
+
+	require.Len(t, dcaClient.requests, 1)
+	assert.ElementsMatch(t, []string{"10.0.2.5", "1.1.1.1"}, dcaClient.requests[0])
+	assert.Equal(t, "ctr-api", conns[0].Raddr.ContainerId)
+	assert.Empty(t, conns[1].Raddr.ContainerId)
+	require.Contains(t, ctrs, "ctr-api")
+	assert.Equal(t, []string{"pod_name:api-7f9c", "kube_namespace:backend", "kube_service:api"}, ctrs["ctr-api"].Tags)
+
+	// Known pods and IPs without a pod aren't looked up again during the retention period
+	conns[0].Raddr.ContainerId = ""
+	c.resolveKubeEndpoints(conns, now.Add(30*time.Second))
+	assert.Len(t, dcaClient.requests, 1)
+	assert.Equal(t, "ctr-api", conns[0].Raddr.ContainerId)
+}
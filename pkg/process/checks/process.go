@@ -220,6 +220,8 @@ func fmtProcesses(
 		// Hide blacklisted args if the Scrubber is enabled
 		fp.Cmdline = cfg.Scrubber.ScrubProcessCommand(fp)
 
+		// TODO: report the FD limit of the process and context switch rates once the payload has fields for them
+		voluntary, involuntary := formatCtxSwitches(fp)
 		proc := &model.Process{
 			Pid:                    fp.Pid,
 			NsPid:                  fp.NsPid,
@@ -231,8 +233,8 @@ func fmtProcesses(
 			OpenFdCount:            fp.OpenFdCount,
 			State:                  model.ProcessState(model.ProcessState_value[fp.Status]),
 			IoStat:                 formatIO(fp, lastProcs[fp.Pid].IOStat, lastRun),
-			VoluntaryCtxSwitches:   uint64(fp.CtxSwitches.Voluntary),
-			InvoluntaryCtxSwitches: uint64(fp.CtxSwitches.Involuntary),
+			VoluntaryCtxSwitches:   voluntary,
+			InvoluntaryCtxSwitches: involuntary,
 			ContainerId:            ctrByProc[fp.Pid],
 		}
 		_, ok := procsByCtr[proc.ContainerId]
@@ -297,6 +299,17 @@ func formatIO(fp *process.FilledProcess, lastIO *process.IOCountersStat, before
 	}
 }
 
+// formatCtxSwitches returns the voluntary and involuntary context switch counters
+// of a process, which may be missing if /proc/<pid>/status could not be read.
+// Unlike the IO stats, they are cumulative counters rather than rates: this is
+// what the payload fields have always carried.
+func formatCtxSwitches(fp *process.FilledProcess) (uint64, uint64) {
+	if fp.CtxSwitches == nil {
+		return 0, 0
+	}
+	return uint64(fp.CtxSwitches.Voluntary), uint64(fp.CtxSwitches.Involuntary)
+}
+
 func formatMemory(fp *process.FilledProcess) *model.MemoryStat {
 	ms := &model.MemoryStat{
 		Rss:  fp.MemInfo.RSS,
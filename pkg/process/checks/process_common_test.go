@@ -268,7 +268,18 @@ func TestFormatIO(t *testing.T) {
 	assert.Equal(t, float32(6), result.WriteRate)
 	assert.Equal(t, float32(7), result.ReadBytesRate)
 	assert.Equal(t, float32(8), result.WriteBytesRate)
+}
+
+func TestFormatCtxSwitches(t *testing.T) {
+	voluntary, involuntary := formatCtxSwitches(&process.FilledProcess{})
+	assert.Equal(t, uint64(0), voluntary)
+	assert.Equal(t, uint64(0), involuntary)
 
+	voluntary, involuntary = formatCtxSwitches(&process.FilledProcess{
+		CtxSwitches: &process.NumCtxSwitchesStat{Voluntary: 42, Involuntary: 7},
+	})
+	assert.Equal(t, uint64(42), voluntary)
+	assert.Equal(t, uint64(7), involuntary)
 }
 
 func floatEquals(a, b float32) bool {
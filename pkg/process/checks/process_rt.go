@@ -115,6 +115,7 @@ func fmtProcessStats(
 			continue
 		}
 
+		voluntary, involuntary := formatCtxSwitches(fp)
 		chunk = append(chunk, &model.ProcessStat{
 			Pid:                    fp.Pid,
 			CreateTime:             fp.CreateTime,
@@ -125,8 +126,8 @@ func fmtProcessStats(
 			OpenFdCount:            fp.OpenFdCount,
 			ProcessState:           model.ProcessState(model.ProcessState_value[fp.Status]),
 			IoStat:                 formatIO(fp, lastProcs[fp.Pid].IOStat, lastRun),
-			VoluntaryCtxSwitches:   uint64(fp.CtxSwitches.Voluntary),
-			InvoluntaryCtxSwitches: uint64(fp.CtxSwitches.Involuntary),
+			VoluntaryCtxSwitches:   voluntary,
+			InvoluntaryCtxSwitches: involuntary,
 			ContainerId:            cidByPid[fp.Pid],
 		})
 		if len(chunk) == cfg.MaxPerMessage {
@@ -103,11 +103,19 @@ type AgentConfig struct {
 	MaxConnectionsStateBuffered    int
 	OffsetGuessThreshold           uint64
 	EnableTracepoints              bool
+	EnableConnectionAggregation    bool
+	ReplayRecordFile               string
+	ReplayRecordMaxSize            int64
 
 	// DNS stats configuration
 	CollectDNSStats bool
 	DNSTimeout      time.Duration
 
+	// Kubernetes resolution of connection endpoints
+	EnableKubernetesResolution        bool
+	EnableKubernetesClusterResolution bool
+	KubernetesResolutionRetention     time.Duration
+
 	// Orchestrator collection configuration
 	OrchestrationCollectionEnabled bool
 	KubeClusterName                string
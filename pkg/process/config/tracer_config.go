@@ -81,6 +81,12 @@ func SysProbeConfigFromConfig(cfg *AgentConfig) *ebpf.Config {
 		tracerConfig.EnableTracepoints = true
 	}
 
+	tracerConfig.EnableConnectionAggregation = cfg.EnableConnectionAggregation
+	tracerConfig.ReplayRecordFile = cfg.ReplayRecordFile
+	if size := cfg.ReplayRecordMaxSize; size > 0 {
+		tracerConfig.ReplayRecordMaxSize = size
+	}
+
 	tracerConfig.EnableMonotonicCount = cfg.Windows.EnableMonotonicCount
 	tracerConfig.DriverBufferSize = cfg.Windows.DriverBufferSize
 
@@ -170,6 +170,15 @@ func (a *AgentConfig) loadSysProbeYamlConfig(path string) error {
 		a.EnableTracepoints = config.Datadog.GetBool(key(spNS, "enable_tracepoints"))
 	}
 
+	// Collapse outgoing connections which only differ by their source port
+	a.EnableConnectionAggregation = config.Datadog.GetBool(key(spNS, "enable_connection_aggregation"))
+
+	// Record the tracer events so that they can be replayed offline
+	a.ReplayRecordFile = config.Datadog.GetString(key(spNS, "replay_record_file"))
+	if config.Datadog.IsSet(key(spNS, "replay_record_max_size")) {
+		a.ReplayRecordMaxSize = config.Datadog.GetInt64(key(spNS, "replay_record_max_size"))
+	}
+
 	a.Windows.EnableMonotonicCount = config.Datadog.GetBool(key(spNS, "windows", "enable_monotonic_count"))
 
 	if driverBufferSize := config.Datadog.GetInt(key(spNS, "windows", "driver_buffer_size")); driverBufferSize > 0 {
@@ -188,6 +197,14 @@ func (a *AgentConfig) loadSysProbeYamlConfig(path string) error {
 		// System probe can be run without the network module as determined on the network_config.enabled value
 		networkEnabled = config.Datadog.GetBool("network_config.enabled")
 		log.Info(fmt.Sprintf("network_config found, enabled = %v", networkEnabled))
+
+		// Resolve connection addresses to the pods owning them
+		a.EnableKubernetesResolution = config.Datadog.GetBool("network_config.enable_kubernetes_resolution")
+		// Pods of other nodes are looked up in the cluster-agent
+		a.EnableKubernetesClusterResolution = a.EnableKubernetesResolution && config.Datadog.GetBool("cluster_agent.enabled")
+		if retention := config.Datadog.GetInt("network_config.kubernetes_resolution_retention_in_s"); retention > 0 {
+			a.KubernetesResolutionRetention = time.Duration(retention) * time.Second
+		}
 	} else {
 		log.Info("network_config not found, enabling network check by default")
 	}
@@ -0,0 +1,209 @@
+package resolver
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// defaultOwnershipRetention is how long the owner of an IP is remembered after it was last seen,
+// so that connections of short-lived pods can still be resolved once they are gone
+const defaultOwnershipRetention = 2 * time.Minute
+
+// KubeEndpoint is a pod owning an IP
+type KubeEndpoint struct {
+	Name      string
+	Namespace string
+	UID       string
+	// ContainerIDs are the IDs of the running application containers of the pod
+	ContainerIDs []string
+	// ContainerPorts are the IDs of the containers declaring a port, indexed by port
+	ContainerPorts map[int32]string
+	// Tags are the tags of the pod, including its pod name, namespace and service tags
+	Tags []string
+}
+
+// containerFor returns the ID of the container of the pod serving the given port.
+// An address is only bound to a container which is known to serve it, or to the single
+// container of the pod: the first container of a pod may well be a sidecar.
+func (e KubeEndpoint) containerFor(port int32) string {
+	if id, ok := e.ContainerPorts[port]; ok {
+		return id
+	}
+	if len(e.ContainerIDs) == 1 {
+		return e.ContainerIDs[0]
+	}
+	return ""
+}
+
+// ipOwnership is the endpoint an IP was last seen assigned to
+type ipOwnership struct {
+	endpoint KubeEndpoint
+	lastSeen time.Time
+}
+
+// KubeResolver resolves connection addresses to the pods owning them.
+//
+// The connections payload doesn't tell when each connection was last active, so an
+// address is resolved to the pod which was last seen owning its IP. Owners are kept
+// for a retention period after they are gone, so that the connections of short-lived
+// pods can still be resolved, and are replaced as soon as the IP is reused. The IPs of
+// pods running on other nodes are looked up on demand, see UnresolvedIPs.
+type KubeResolver struct {
+	mux       sync.RWMutex
+	owners    map[string]*ipOwnership
+	retention time.Duration
+	// lookups are the times the IPs which aren't owned by a known pod were last looked up
+	lookups map[string]time.Time
+}
+
+// NewKubeResolver creates a new KubeResolver
+func NewKubeResolver(retention time.Duration) *KubeResolver {
+	if retention <= 0 {
+		retention = defaultOwnershipRetention
+	}
+	return &KubeResolver{
+		owners:    make(map[string]*ipOwnership),
+		retention: retention,
+		lookups:   make(map[string]time.Time),
+	}
+}
+
+// Update records the IP owners seen at the given time, and forgets the owners
+// which haven't been seen for longer than the retention period.
+func (k *KubeResolver) Update(endpoints map[string]KubeEndpoint, now time.Time) {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	k.update(endpoints, now)
+}
+
+// UpdateLookup records the owners of the IPs looked up at the given time. The IPs which
+// aren't owned by a pod aren't returned by UnresolvedIPs until the retention period is over.
+func (k *KubeResolver) UpdateLookup(ips []string, endpoints map[string]KubeEndpoint, now time.Time) {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	for _, ip := range ips {
+		if _, ok := endpoints[ip]; !ok {
+			k.lookups[ip] = now
+		}
+	}
+	k.update(endpoints, now)
+}
+
+func (k *KubeResolver) update(endpoints map[string]KubeEndpoint, now time.Time) {
+	for ip, endpoint := range endpoints {
+		k.owners[ip] = &ipOwnership{endpoint: endpoint, lastSeen: now}
+		delete(k.lookups, ip)
+	}
+
+	for ip, o := range k.owners {
+		if now.Sub(o.lastSeen) > k.retention {
+			delete(k.owners, ip)
+		}
+	}
+	for ip, lastLookup := range k.lookups {
+		if now.Sub(lastLookup) > k.retention {
+			delete(k.lookups, ip)
+		}
+	}
+}
+
+// UnresolvedIPs returns the remote IPs of the given connections which aren't owned by a
+// known pod and weren't looked up during the retention period.
+func (k *KubeResolver) UnresolvedIPs(conns []*model.Connection) []string {
+	k.mux.RLock()
+	defer k.mux.RUnlock()
+
+	seen := make(map[string]struct{})
+	var ips []string
+	for _, conn := range conns {
+		if conn.Raddr == nil {
+			continue
+		}
+		ip, _ := remoteAddr(conn)
+		if _, ok := seen[ip]; ok {
+			continue
+		}
+		seen[ip] = struct{}{}
+
+		if _, ok := k.owners[ip]; ok {
+			continue
+		}
+		if _, ok := k.lookups[ip]; ok {
+			continue
+		}
+		if parsed := net.ParseIP(ip); parsed == nil || parsed.IsLoopback() || parsed.IsUnspecified() {
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+// Lookup returns the endpoint owning the given IP
+func (k *KubeResolver) Lookup(ip string) (KubeEndpoint, bool) {
+	k.mux.RLock()
+	defer k.mux.RUnlock()
+
+	o, ok := k.owners[ip]
+	if !ok {
+		return KubeEndpoint{}, false
+	}
+	return o.endpoint, true
+}
+
+// Resolve resolves the local and remote addresses of the given connections to the
+// pods owning them. Addresses resolved to a pod are bound to the
+// container serving them if they aren't already, and the pod tags, including its
+// service tags, are returned as the metadata of the resolved containers indexed by
+// their ID. Service ClusterIPs which weren't translated to a pod aren't resolved.
+func (k *KubeResolver) Resolve(conns []*model.Connection) map[string]*model.ResourceMetadata {
+	resolved := make(map[string]*model.ResourceMetadata)
+	for _, conn := range conns {
+		if conn.Laddr != nil {
+			k.resolveAddr(conn.Laddr, conn.Laddr.Ip, conn.Laddr.Port, resolved)
+		}
+		if conn.Raddr != nil {
+			ip, port := remoteAddr(conn)
+			k.resolveAddr(conn.Raddr, ip, port, resolved)
+		}
+	}
+	log.Tracef("resolved %d kubernetes entities", len(resolved))
+	return resolved
+}
+
+// resolveAddr binds an address to the container of the pod owning it
+func (k *KubeResolver) resolveAddr(addr *model.Addr, ip string, port int32, resolved map[string]*model.ResourceMetadata) {
+	endpoint, ok := k.Lookup(ip)
+	if !ok {
+		return
+	}
+
+	containerID := endpoint.containerFor(port)
+	if containerID == "" {
+		return
+	}
+	if addr.ContainerId == "" {
+		addr.ContainerId = containerID
+	}
+	if _, ok := resolved[containerID]; !ok {
+		resolved[containerID] = &model.ResourceMetadata{
+			Id:   containerID,
+			Tags: endpoint.Tags,
+		}
+	}
+}
+
+// remoteAddr returns the IP and port of the remote side of a connection. Connections to a
+// service ClusterIP are resolved to the pod selected by the NAT translation.
+func remoteAddr(conn *model.Connection) (string, int32) {
+	if conn.IpTranslation != nil && conn.IpTranslation.ReplSrcIP != "" {
+		return conn.IpTranslation.ReplSrcIP, conn.IpTranslation.ReplSrcPort
+	}
+	return conn.Raddr.Ip, conn.Raddr.Port
+}
@@ -0,0 +1,149 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubeResolverIPReuse(t *testing.T) {
+	r := NewKubeResolver(time.Minute)
+	t0 := time.Now()
+
+	web := KubeEndpoint{Name: "web", Namespace: "default", UID: "uid-web", ContainerIDs: []string{"ctr-web"}}
+	db := KubeEndpoint{Name: "db", Namespace: "default", UID: "uid-db", ContainerIDs: []string{"ctr-db"}}
+
+	r.Update(map[string]KubeEndpoint{"10.0.0.1": web}, t0)
+	e, ok := r.Lookup("10.0.0.1")
+	require.True(t, ok)
+	assert.Equal(t, "web", e.Name)
+
+	// The web pod is gone and its IP is reused by the db pod, which replaces it right away
+	r.Update(map[string]KubeEndpoint{"10.0.0.1": db}, t0.Add(10*time.Second))
+	e, ok = r.Lookup("10.0.0.1")
+	require.True(t, ok)
+	assert.Equal(t, "db", e.Name)
+
+	_, ok = r.Lookup("10.0.0.2")
+	assert.False(t, ok)
+}
+
+func TestKubeResolverRetention(t *testing.T) {
+	r := NewKubeResolver(time.Minute)
+	t0 := time.Now()
+
+	job := KubeEndpoint{Name: "job", Namespace: "batch", UID: "uid-job", ContainerIDs: []string{"ctr-job"}}
+	r.Update(map[string]KubeEndpoint{"10.0.0.1": job}, t0)
+
+	// The short-lived pod is still resolved after it is gone
+	r.Update(nil, t0.Add(30*time.Second))
+	_, ok := r.Lookup("10.0.0.1")
+	assert.True(t, ok)
+
+	// And forgotten after the retention period
+	r.Update(nil, t0.Add(2*time.Minute))
+	_, ok = r.Lookup("10.0.0.1")
+	assert.False(t, ok)
+}
+
+func TestKubeResolverResolve(t *testing.T) {
+	r := NewKubeResolver(time.Minute)
+	now := time.Now()
+
+	r.Update(map[string]KubeEndpoint{
+		"10.0.0.1": {Name: "client", Namespace: "default", UID: "uid-client", ContainerIDs: []string{"ctr-client"}, Tags: []string{"pod_name:client"}},
+		"10.0.0.2": {Name: "server", Namespace: "default", UID: "uid-server", ContainerIDs: []string{"ctr-server"}, Tags: []string{"pod_name:server", "kube_service:api"}},
+	}, now)
+
+	conns := []*model.Connection{
+		// connection to a pod
+		{Laddr: &model.Addr{Ip: "10.0.0.1", Port: 5000}, Raddr: &model.Addr{Ip: "10.0.0.2", Port: 80}},
+		// connection to a service ClusterIP translated to a pod
+		{
+			Laddr:         &model.Addr{Ip: "10.0.0.1", Port: 5001, ContainerId: "local-ctr"},
+			Raddr:         &model.Addr{Ip: "10.96.0.10", Port: 80},
+			IpTranslation: &model.IPTranslation{ReplSrcIP: "10.0.0.2", ReplSrcPort: 80},
+		},
+		// connection to a service ClusterIP which wasn't translated, it can't be resolved
+		{Laddr: &model.Addr{Ip: "10.0.0.1", Port: 5002}, Raddr: &model.Addr{Ip: "10.96.0.10", Port: 80}},
+		// connection outside of the cluster
+		{Laddr: &model.Addr{Ip: "10.0.0.3", Port: 5003}, Raddr: &model.Addr{Ip: "1.1.1.1", Port: 443}},
+	}
+
+	ctrs := r.Resolve(conns)
+	assert.Len(t, ctrs, 2)
+	assert.Equal(t, []string{"pod_name:client"}, ctrs["ctr-client"].Tags)
+	assert.Equal(t, []string{"pod_name:server", "kube_service:api"}, ctrs["ctr-server"].Tags)
+
+	assert.Equal(t, "ctr-client", conns[0].Laddr.ContainerId)
+	assert.Equal(t, "ctr-server", conns[0].Raddr.ContainerId)
+	// Containers already resolved are left untouched
+	assert.Equal(t, "local-ctr", conns[1].Laddr.ContainerId)
+	assert.Equal(t, "ctr-server", conns[1].Raddr.ContainerId)
+	assert.Equal(t, "ctr-client", conns[2].Laddr.ContainerId)
+	assert.Empty(t, conns[2].Raddr.ContainerId)
+	assert.Empty(t, conns[3].Laddr.ContainerId)
+	assert.Empty(t, conns[3].Raddr.ContainerId)
+}
+
+func TestKubeResolverSidecar(t *testing.T) {
+	r := NewKubeResolver(time.Minute)
+	now := time.Now()
+
+	r.Update(map[string]KubeEndpoint{
+		"10.0.0.2": {
+			Name:           "web",
+			UID:            "uid-web",
+			ContainerIDs:   []string{"ctr-proxy", "ctr-web"},
+			ContainerPorts: map[int32]string{8080: "ctr-web", 15001: "ctr-proxy"},
+		},
+	}, now)
+
+	conns := []*model.Connection{
+		{Laddr: &model.Addr{Ip: "10.0.0.1"}, Raddr: &model.Addr{Ip: "10.0.0.2", Port: 8080}},
+		{Laddr: &model.Addr{Ip: "10.0.0.1"}, Raddr: &model.Addr{Ip: "10.0.0.2", Port: 9000}},
+	}
+	ctrs := r.Resolve(conns)
+
+	// The address is bound to the container serving the port
+	assert.Equal(t, "ctr-web", conns[0].Raddr.ContainerId)
+	assert.Contains(t, ctrs, "ctr-web")
+	// And left unbound when the container can't be told
+	assert.Empty(t, conns[1].Raddr.ContainerId)
+}
+
+func TestKubeResolverUnresolvedIPs(t *testing.T) {
+	r := NewKubeResolver(time.Minute)
+	t0 := time.Now()
+
+	r.Update(map[string]KubeEndpoint{"10.0.0.1": {Name: "client", UID: "uid-client", ContainerIDs: []string{"ctr-client"}}}, t0)
+
+	conns := []*model.Connection{
+		{Laddr: &model.Addr{Ip: "10.0.0.1"}, Raddr: &model.Addr{Ip: "10.0.1.2", Port: 80}},
+		{Laddr: &model.Addr{Ip: "10.0.0.1"}, Raddr: &model.Addr{Ip: "10.0.1.2", Port: 81}},
+		{Laddr: &model.Addr{Ip: "10.0.0.2"}, Raddr: &model.Addr{Ip: "10.0.0.1", Port: 80}},
+		{Laddr: &model.Addr{Ip: "127.0.0.1"}, Raddr: &model.Addr{Ip: "127.0.0.1", Port: 80}},
+		{
+			Laddr:         &model.Addr{Ip: "10.0.0.1"},
+			Raddr:         &model.Addr{Ip: "10.96.0.10", Port: 80},
+			IpTranslation: &model.IPTranslation{ReplSrcIP: "10.0.1.3", ReplSrcPort: 80},
+		},
+	}
+	// Known pods and loopback IPs aren't looked up, service ClusterIPs are looked up through their translation
+	assert.Equal(t, []string{"10.0.1.2", "10.0.1.3"}, r.UnresolvedIPs(conns))
+
+	r.UpdateLookup([]string{"10.0.1.2", "10.0.1.3"}, map[string]KubeEndpoint{
+		"10.0.1.3": {Name: "server", UID: "uid-server", ContainerIDs: []string{"ctr-server"}},
+	}, t0)
+	assert.Empty(t, r.UnresolvedIPs(conns))
+	e, ok := r.Lookup("10.0.1.3")
+	require.True(t, ok)
+	assert.Equal(t, "server", e.Name)
+
+	// IPs without a pod are looked up again once the retention period is over
+	r.Update(map[string]KubeEndpoint{"10.0.0.1": {Name: "client", UID: "uid-client", ContainerIDs: []string{"ctr-client"}}}, t0.Add(2*time.Minute))
+	assert.Equal(t, []string{"10.0.1.2", "10.0.1.3"}, r.UnresolvedIPs(conns))
+}
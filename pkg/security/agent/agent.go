@@ -8,7 +8,6 @@ package agent
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"io"
 	"sync"
 	"sync/atomic"
@@ -16,6 +15,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
@@ -32,6 +32,7 @@ type RuntimeSecurityAgent struct {
 	wg            sync.WaitGroup
 	connected     atomic.Value
 	eventReceived uint64
+	sinks         []*sinkWorker
 }
 
 // NewRuntimeSecurityAgent instantiates a new RuntimeSecurityAgent
@@ -41,9 +42,17 @@ func NewRuntimeSecurityAgent(hostname string, reporter event.Reporter) (*Runtime
 		return nil, errors.New("runtime_security_config.socket must be set")
 	}
 
+	sinks, err := newSinks(hostname)
+	if err != nil {
+		return nil, err
+	}
+
 	path := "unix://" + socketPath
 	conn, err := grpc.Dial(path, grpc.WithInsecure())
 	if err != nil {
+		for _, sink := range sinks {
+			sink.sink.Close()
+		}
 		return nil, err
 	}
 
@@ -51,11 +60,38 @@ func NewRuntimeSecurityAgent(hostname string, reporter event.Reporter) (*Runtime
 		conn:     conn,
 		reporter: reporter,
 		hostname: hostname,
+		sinks:    sinks,
 	}, nil
 }
 
+// newSinks returns the local sinks defined in the configuration
+func newSinks(hostname string) ([]*sinkWorker, error) {
+	var configs []*SinkConfig
+	if err := coreconfig.Datadog.UnmarshalKey("runtime_security_config.sinks", &configs); err != nil {
+		return nil, errors.Wrap(err, "invalid runtime_security_config.sinks")
+	}
+
+	var sinks []*sinkWorker
+	for i, config := range configs {
+		sink, err := NewSink(config, hostname)
+		if err != nil {
+			for _, sink := range sinks {
+				sink.sink.Close()
+			}
+			return nil, errors.Wrapf(err, "invalid runtime_security_config.sinks (# %d)", i+1)
+		}
+		sinks = append(sinks, newSinkWorker(config, sink))
+	}
+
+	return sinks, nil
+}
+
 // Start the runtime security agent
 func (rsa *RuntimeSecurityAgent) Start() {
+	for _, sink := range rsa.sinks {
+		sink.start()
+	}
+
 	// Start the system-probe events listener
 	go rsa.StartEventListener()
 }
@@ -65,6 +101,10 @@ func (rsa *RuntimeSecurityAgent) Stop() {
 	rsa.running.Store(false)
 	rsa.wg.Wait()
 	rsa.conn.Close()
+
+	for _, sink := range rsa.sinks {
+		sink.stop()
+	}
 }
 
 // StartEventListener starts listening for new events from system-probe
@@ -110,23 +150,32 @@ func (rsa *RuntimeSecurityAgent) StartEventListener() {
 	}
 }
 
-// SendSecurityEvent sends a security event with the provided status
-func (rsa *RuntimeSecurityAgent) SendSecurityEvent(evt *api.SecurityEventMessage, status string) {
-	event := &event.Event{
+func (rsa *RuntimeSecurityAgent) newEvent(evt *api.SecurityEventMessage) *event.Event {
+	return &event.Event{
 		AgentRuleID:  evt.RuleID,
 		ResourceID:   rsa.hostname,
 		ResourceType: "host",
 		Tags:         evt.Tags,
 		Data:         json.RawMessage(evt.GetData()),
 	}
+}
 
-	rsa.reporter.Report(event)
+// SendSecurityEvent sends a security event with the provided status
+func (rsa *RuntimeSecurityAgent) SendSecurityEvent(evt *api.SecurityEventMessage, status string) {
+	rsa.reporter.Report(rsa.newEvent(evt))
 }
 
 // DispatchEvent dispatches a security event message to the subsytems of the runtime security agent
 func (rsa *RuntimeSecurityAgent) DispatchEvent(evt *api.SecurityEventMessage) {
-	// For now simply log to Datadog
 	rsa.SendSecurityEvent(evt, message.StatusAlert)
+
+	// write the event to the local sinks whose tags match
+	if len(rsa.sinks) > 0 {
+		event := rsa.newEvent(evt)
+		for _, sink := range rsa.sinks {
+			sink.dispatch(event)
+		}
+	}
 }
 
 // GetStatus returns the current status on the agent
@@ -134,5 +183,14 @@ func (rsa *RuntimeSecurityAgent) GetStatus() map[string]interface{} {
 	return map[string]interface{}{
 		"connected":     rsa.connected.Load(),
 		"eventReceived": atomic.LoadUint64(&rsa.eventReceived),
+		"sinks":         rsa.getSinksStatus(),
+	}
+}
+
+func (rsa *RuntimeSecurityAgent) getSinksStatus() []map[string]interface{} {
+	var status []map[string]interface{}
+	for _, sink := range rsa.sinks {
+		status = append(status, sink.getStatus())
 	}
+	return status
 }
@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package agent
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+
+	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/security/api"
+)
+
+// RuntimeSecurityClient is used to send requests to the runtime security module of system-probe
+type RuntimeSecurityClient struct {
+	apiClient api.SecurityModuleClient
+	conn      *grpc.ClientConn
+}
+
+// ReloadPolicies asks the runtime security module to reload its policies
+func (c *RuntimeSecurityClient) ReloadPolicies() (*api.ReloadPoliciesReport, error) {
+	return c.apiClient.ReloadPolicies(context.Background(), &api.ReloadPoliciesParams{})
+}
+
+// Close closes the connection
+func (c *RuntimeSecurityClient) Close() {
+	c.conn.Close()
+}
+
+// NewRuntimeSecurityClient instantiates a new RuntimeSecurityClient
+func NewRuntimeSecurityClient() (*RuntimeSecurityClient, error) {
+	socketPath := coreconfig.Datadog.GetString("runtime_security_config.socket")
+	if socketPath == "" {
+		return nil, errors.New("runtime_security_config.socket must be set")
+	}
+
+	path := "unix://" + socketPath
+	conn, err := grpc.Dial(path, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	return &RuntimeSecurityClient{
+		conn:      conn,
+		apiClient: api.NewSecurityModuleClient(conn),
+	}, nil
+}
@@ -0,0 +1,222 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package agent
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gobwas/glob"
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// FileSinkType is the type of the sinks writing events to a rotating JSON-lines file
+	FileSinkType = "file"
+	// SyslogSinkType is the type of the sinks sending events to a syslog destination
+	SyslogSinkType = "syslog"
+	// WebhookSinkType is the type of the sinks posting events to an HTTP webhook
+	WebhookSinkType = "webhook"
+
+	// Size of the queue of events waiting to be sent to a sink
+	sinkQueueSize = 1000
+)
+
+// SinkConfig describes the configuration of a local sink of security events
+type SinkConfig struct {
+	Type string `mapstructure:"type"`
+	// Tags restricts the sink to the events having a tag matching one of these glob patterns,
+	// all the events are sent when empty. Wildcards also match slashes.
+	Tags []string `mapstructure:"tags"`
+
+	// file sink
+	Path       string `mapstructure:"path"`
+	MaxSize    int64  `mapstructure:"max_size"`
+	MaxBackups int    `mapstructure:"max_backups"`
+
+	// syslog sink
+	Network  string `mapstructure:"network"`
+	Address  string `mapstructure:"address"`
+	Facility int    `mapstructure:"facility"`
+
+	// webhook sink
+	URL        string            `mapstructure:"url"`
+	Headers    map[string]string `mapstructure:"headers"`
+	Timeout    int               `mapstructure:"timeout"`
+	MaxRetries int               `mapstructure:"max_retries"`
+}
+
+// Sink describes a destination of security events
+type Sink interface {
+	Send(event *event.Event) error
+	Close() error
+}
+
+// interruptibleSink is a sink whose pending retries can be interrupted, so that it doesn't delay a shutdown
+type interruptibleSink interface {
+	Sink
+	Interrupt()
+}
+
+// NewSink returns a new sink for the given configuration
+func NewSink(config *SinkConfig, hostname string) (Sink, error) {
+	if _, err := compileTagPatterns(config.Tags); err != nil {
+		return nil, err
+	}
+
+	switch config.Type {
+	case FileSinkType:
+		return NewFileSink(config)
+	case SyslogSinkType:
+		return NewSyslogSink(config, hostname)
+	case WebhookSinkType:
+		return NewWebhookSink(config)
+	default:
+		return nil, fmt.Errorf("unknown sink type `%s`", config.Type)
+	}
+}
+
+// compileTagPatterns compiles the tag patterns of a sink. Tag values, like image names, may hold slashes,
+// the patterns are compiled without separators so that their wildcards match them.
+func compileTagPatterns(patterns []string) ([]glob.Glob, error) {
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid tag pattern `%s`", pattern)
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+// matchTags returns whether one of the tags matches one of the patterns
+func matchTags(patterns []glob.Glob, tags []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		for _, tag := range tags {
+			if pattern.Match(tag) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sinkWorker sends the events to a sink from a dedicated goroutine so that a slow
+// destination doesn't delay the other sinks nor the reporter
+type sinkWorker struct {
+	config  *SinkConfig
+	tags    []glob.Glob
+	sink    Sink
+	queue   chan *event.Event
+	stopped chan struct{}
+	wg      sync.WaitGroup
+
+	sent    uint64
+	dropped uint64
+	errors  uint64
+}
+
+func newSinkWorker(config *SinkConfig, sink Sink) *sinkWorker {
+	// the patterns were validated when the sink was created
+	tags, _ := compileTagPatterns(config.Tags)
+
+	return &sinkWorker{
+		config:  config,
+		tags:    tags,
+		sink:    sink,
+		queue:   make(chan *event.Event, sinkQueueSize),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (w *sinkWorker) start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		for {
+			select {
+			case evt := <-w.queue:
+				w.send(evt)
+			case <-w.stopped:
+				w.drain()
+				return
+			}
+		}
+	}()
+}
+
+// send sends an event to the sink and returns whether it was sent
+func (w *sinkWorker) send(evt *event.Event) bool {
+	if err := w.sink.Send(evt); err != nil {
+		atomic.AddUint64(&w.errors, 1)
+		log.Errorf("Failed to send event of rule `%s` to %s sink: %v", evt.AgentRuleID, w.config.Type, err)
+		return false
+	}
+	atomic.AddUint64(&w.sent, 1)
+	return true
+}
+
+// drain sends the queued events. The events still queued after a failure are dropped,
+// so that an unreachable destination doesn't delay the shutdown.
+func (w *sinkWorker) drain() {
+	for {
+		select {
+		case evt := <-w.queue:
+			if !w.send(evt) {
+				atomic.AddUint64(&w.dropped, uint64(len(w.queue)))
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// dispatch queues the event if it matches the tags of the sink
+func (w *sinkWorker) dispatch(evt *event.Event) {
+	if !matchTags(w.tags, evt.Tags) {
+		return
+	}
+
+	select {
+	case w.queue <- evt:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+}
+
+// stop interrupts the pending retries, sends the queued events and closes the sink
+func (w *sinkWorker) stop() {
+	close(w.stopped)
+	if sink, ok := w.sink.(interruptibleSink); ok {
+		sink.Interrupt()
+	}
+	w.wg.Wait()
+
+	if err := w.sink.Close(); err != nil {
+		log.Errorf("Failed to close %s sink: %v", w.config.Type, err)
+	}
+}
+
+func (w *sinkWorker) getStatus() map[string]interface{} {
+	return map[string]interface{}{
+		"type":    w.config.Type,
+		"sent":    atomic.LoadUint64(&w.sent),
+		"dropped": atomic.LoadUint64(&w.dropped),
+		"errors":  atomic.LoadUint64(&w.errors),
+	}
+}
@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	defaultFileSinkMaxSize    = 10 * 1024 * 1024
+	defaultFileSinkMaxBackups = 5
+)
+
+// FileSink writes the events to a JSON-lines file rotated according to its size
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+// NewFileSink returns a new file sink
+func NewFileSink(config *SinkConfig) (*FileSink, error) {
+	if config.Path == "" {
+		return nil, errors.New("path must be set for a file sink")
+	}
+
+	s := &FileSink{
+		path:       config.Path,
+		maxSize:    config.MaxSize,
+		maxBackups: config.MaxBackups,
+	}
+
+	if s.maxSize <= 0 {
+		s.maxSize = defaultFileSinkMaxSize
+	}
+	if s.maxBackups <= 0 {
+		s.maxBackups = defaultFileSinkMaxBackups
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	file, size, err := openFileSinkFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.file, s.size = file, size
+	return nil
+}
+
+func openFileSinkFile(path string) (*os.File, int64, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+
+	return file, info.Size(), nil
+}
+
+// rotate shifts the backups, <path>.1 being the most recent one, and opens a new file.
+// The current file is only closed once the new one is opened: when the rotation fails,
+// the events keep being written to the current file and the rotation is retried on the next event.
+func (s *FileSink) rotate() error {
+	for i := s.maxBackups - 1; i > 0; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+
+	file, size, err := openFileSinkFile(s.path)
+	if err != nil {
+		// Put the current file back in place so that it keeps being written at its path
+		if renameErr := os.Rename(s.path+".1", s.path); renameErr != nil {
+			log.Errorf("failed to restore %s after a failed rotation: %s", s.path, renameErr)
+		}
+		return err
+	}
+
+	if err := s.file.Close(); err != nil {
+		log.Warnf("failed to close rotated file %s: %s", s.path+".1", err)
+	}
+	s.file, s.size = file, size
+	return nil
+}
+
+// Send writes the event as a JSON line
+func (s *FileSink) Send(evt *event.Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			log.Warnf("Failed to rotate %s, events keep being written to it: %v", s.path, err)
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+
+	return err
+}
+
+// Close the file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
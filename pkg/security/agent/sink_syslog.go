@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+const (
+	syslogAppName = "datadog-security-agent"
+	// warning severity
+	syslogSeverity = 4
+	// auth facility
+	defaultSyslogFacility = 4
+	// maximum length of the MSGID field
+	syslogMsgIDMaxLen = 32
+)
+
+// SyslogSink sends the events to a syslog destination using the RFC 5424 format. Stream
+// connections use the octet counting framing of RFC 6587.
+type SyslogSink struct {
+	network  string
+	address  string
+	facility int
+	hostname string
+
+	conn net.Conn
+}
+
+// NewSyslogSink returns a new syslog sink
+func NewSyslogSink(config *SinkConfig, hostname string) (*SyslogSink, error) {
+	if config.Address == "" {
+		return nil, errors.New("address must be set for a syslog sink")
+	}
+
+	s := &SyslogSink{
+		network:  config.Network,
+		address:  config.Address,
+		facility: config.Facility,
+		hostname: hostname,
+	}
+
+	if s.network == "" {
+		s.network = "udp"
+	}
+	if s.facility == 0 {
+		s.facility = defaultSyslogFacility
+	} else if s.facility < 0 || s.facility > 23 {
+		return nil, fmt.Errorf("invalid syslog facility %d", s.facility)
+	}
+	if s.hostname == "" {
+		s.hostname = "-"
+	}
+
+	switch s.network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network `%s`", s.network)
+	}
+
+	return s, nil
+}
+
+func (s *SyslogSink) isStream() bool {
+	return strings.HasPrefix(s.network, "tcp") || s.network == "unix"
+}
+
+// format returns the RFC 5424 message of the event
+func (s *SyslogSink) format(evt *event.Event, timestamp time.Time) ([]byte, error) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	msgID := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, evt.AgentRuleID)
+	if len(msgID) > syslogMsgIDMaxLen {
+		msgID = msgID[:syslogMsgIDMaxLen]
+	} else if msgID == "" {
+		msgID = "-"
+	}
+
+	header := fmt.Sprintf("<%d>1 %s %s %s %d %s - ",
+		s.facility*8+syslogSeverity, timestamp.UTC().Format(time.RFC3339Nano), s.hostname, syslogAppName, os.Getpid(), msgID)
+
+	msg := append([]byte(header), data...)
+	if s.isStream() {
+		msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+	}
+
+	return msg, nil
+}
+
+// Send the event, the connection is established again once on failure
+func (s *SyslogSink) Send(evt *event.Event) error {
+	msg, err := s.format(evt, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for retry := 0; ; retry++ {
+		if s.conn == nil {
+			if s.conn, err = net.Dial(s.network, s.address); err != nil {
+				return err
+			}
+		}
+
+		if _, err = s.conn.Write(msg); err == nil || retry > 0 {
+			return err
+		}
+
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Close the connection
+func (s *SyslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,316 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+func newTestEvent(ruleID string) *event.Event {
+	return &event.Event{
+		AgentRuleID:  ruleID,
+		ResourceID:   "myhost",
+		ResourceType: "host",
+		Tags:         []string{"rule_id:" + ruleID},
+		Data:         json.RawMessage(`{"file":{"path":"/etc/shadow"}}`),
+	}
+}
+
+func TestMatchTags(t *testing.T) {
+	tags := []string{"rule_id:shadow_access", "image_name:gcr.io/datadoghq/nginx"}
+
+	tests := []struct {
+		patterns []string
+		expected bool
+	}{
+		{nil, true},
+		{[]string{"rule_id:shadow_access"}, true},
+		{[]string{"rule_id:*shadow*"}, true},
+		{[]string{"rule_id:passwd", "image_name:*"}, true},
+		{[]string{"image_name:*/nginx"}, true},
+		{[]string{"image_name:gcr.io/*"}, true},
+		{[]string{"rule_id:passwd"}, false},
+		{[]string{"rule_id"}, false},
+	}
+
+	for _, test := range tests {
+		patterns, err := compileTagPatterns(test.patterns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matchTags(patterns, tags) != test.expected {
+			t.Errorf("expected %v for %v", test.expected, test.patterns)
+		}
+	}
+
+	if _, err := NewSink(&SinkConfig{Type: WebhookSinkType, URL: "http://localhost", Tags: []string{"rule_id:["}}, ""); err == nil {
+		t.Error("expected an error for an invalid tag pattern")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "events.json")
+	line, _ := json.Marshal(newTestEvent("rule_0"))
+
+	// each file holds at most 2 events
+	sink, err := NewFileSink(&SinkConfig{Path: path, MaxSize: int64(2*len(line) + 3), MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 7; i++ {
+		if err := sink.Send(newTestEvent("rule_" + strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		path:        {"rule_6"},
+		path + ".1": {"rule_4", "rule_5"},
+		path + ".2": {"rule_2", "rule_3"},
+	}
+
+	for filename, ruleIDs := range expected {
+		file, err := os.Open(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var found []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var evt event.Event
+			if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil {
+				t.Fatal(err)
+			}
+			found = append(found, evt.AgentRuleID)
+		}
+		file.Close()
+
+		if strings.Join(found, ",") != strings.Join(ruleIDs, ",") {
+			t.Errorf("expected %v in %s, got %v", ruleIDs, filename, found)
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 backups")
+	}
+}
+
+func TestFileSinkRotationFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "events.json")
+	line, _ := json.Marshal(newTestEvent("rule_0"))
+
+	// a non-empty directory in place of the oldest backup makes the rotation fail
+	if err := os.MkdirAll(filepath.Join(path+".2", "file"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path+".1", line, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, err := NewFileSink(&SinkConfig{Path: path, MaxSize: int64(len(line) + 1), MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	// the events are still written to the current file
+	for i := 0; i < 2; i++ {
+		if err := sink.Send(newTestEvent("rule_" + strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if content, _ := ioutil.ReadFile(path); strings.Count(string(content), "\n") != 2 {
+		t.Errorf("expected 2 events in %s, got %q", path, content)
+	}
+
+	// the rotation succeeds once the backup can be replaced
+	if err := os.RemoveAll(path + ".2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(newTestEvent("rule_2")); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := ioutil.ReadFile(path); strings.Count(string(content), "\n") != 1 {
+		t.Errorf("expected 1 event in %s after the rotation, got %q", path, content)
+	}
+	if content, _ := ioutil.ReadFile(path + ".1"); strings.Count(string(content), "\n") != 2 {
+		t.Errorf("expected 2 events in %s after the rotation, got %q", path+".1", content)
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	sink, err := NewSyslogSink(&SinkConfig{Network: "tcp", Address: listener.Addr().String(), Facility: 13}, "myhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if err := sink.Send(newTestEvent("shadow access")); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// octet counting framing
+	reader := bufio.NewReader(conn)
+	length, err := reader.ReadString(' ')
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(reader, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := regexp.MustCompile(`^<108>1 \S+Z myhost datadog-security-agent \d+ shadow_access - \{.*"agent_rule_id":"shadow access".*\}$`)
+	if !pattern.Match(msg) {
+		t.Errorf("unexpected syslog message: %s", msg)
+	}
+
+	if _, err := NewSyslogSink(&SinkConfig{Network: "http", Address: "localhost:514"}, ""); err == nil {
+		t.Error("expected an error for an unsupported network")
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var evt event.Event
+		if err := json.NewDecoder(r.Body).Decode(&evt); err != nil || evt.AgentRuleID != "shadow_access" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(&SinkConfig{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.retryDelay = time.Millisecond
+
+	if err := sink.Send(newTestEvent("shadow_access")); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+
+	// client errors are not retried
+	atomic.StoreInt32(&requests, 2)
+	if err := sink.Send(newTestEvent("passwd_access")); err == nil {
+		t.Error("expected an error for a rejected event")
+	}
+	if requests != 3 {
+		t.Errorf("expected a single request, got %d", requests-2)
+	}
+
+	// the retries are bounded
+	atomic.StoreInt32(&requests, -10)
+	sink.maxRetries = 2
+	if err := sink.Send(newTestEvent("shadow_access")); err == nil {
+		t.Error("expected an error once the retries are exhausted")
+	}
+	if requests != -7 {
+		t.Errorf("expected 3 attempts, got %d", requests+10)
+	}
+
+	// an interrupted sink doesn't wait before retrying
+	atomic.StoreInt32(&requests, -10)
+	sink.retryDelay = time.Hour
+	sink.Interrupt()
+	if err := sink.Send(newTestEvent("shadow_access")); err == nil {
+		t.Error("expected an error once the sink is interrupted")
+	}
+	if requests != -9 {
+		t.Errorf("expected a single attempt, got %d", requests+10)
+	}
+}
+
+func TestSinkWorkerDrain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := &SinkConfig{Type: FileSinkType, Path: filepath.Join(dir, "events.json")}
+	sink, err := NewSink(config, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the events queued before the worker is stopped are sent
+	worker := newSinkWorker(config, sink)
+	for i := 0; i != 10; i++ {
+		worker.dispatch(newTestEvent("shadow_access"))
+	}
+	worker.start()
+	worker.stop()
+
+	if sent := atomic.LoadUint64(&worker.sent); sent != 10 {
+		t.Errorf("expected 10 events to be sent, got %d", sent)
+	}
+}
@@ -0,0 +1,132 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+const (
+	defaultWebhookTimeout    = 10 * time.Second
+	defaultWebhookMaxRetries = 3
+	// delay before the first retry, doubled after each attempt
+	webhookRetryDelay = 500 * time.Millisecond
+)
+
+// WebhookSink posts the events to an HTTP endpoint, retrying on network and server errors
+type WebhookSink struct {
+	url        string
+	headers    map[string]string
+	maxRetries int
+	retryDelay time.Duration
+	client     *http.Client
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewWebhookSink returns a new webhook sink
+func NewWebhookSink(config *SinkConfig) (*WebhookSink, error) {
+	if config.URL == "" {
+		return nil, errors.New("url must be set for a webhook sink")
+	}
+
+	timeout := defaultWebhookTimeout
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultWebhookMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	return &WebhookSink{
+		url:        config.URL,
+		headers:    config.Headers,
+		maxRetries: maxRetries,
+		retryDelay: webhookRetryDelay,
+		client:     &http.Client{Timeout: timeout},
+		stop:       make(chan struct{}),
+	}, nil
+}
+
+// post sends the payload once, it returns whether the request can be retried on error
+func (s *WebhookSink) post(data []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+// Send posts the event with an exponential backoff between the attempts. Once the sink
+// is interrupted, the event is only posted once.
+func (s *WebhookSink) Send(evt *event.Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	delay := s.retryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(data)
+		if err == nil || !retry || attempt >= s.maxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-s.stop:
+			timer.Stop()
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// Interrupt stops the pending and future retries
+func (s *WebhookSink) Interrupt() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// Close the sink
+func (s *WebhookSink) Close() error {
+	s.Interrupt()
+	return nil
+}
@@ -30,6 +30,7 @@ type Config struct {
 	SyscallMonitor      bool
 	EventServerBurst    int
 	EventServerRate     int
+	EnvsDenylist        []string
 }
 
 // NewConfig returns a new Config object
@@ -45,6 +46,7 @@ func NewConfig(cfg *config.AgentConfig) (*Config, error) {
 		PoliciesDir:         aconfig.Datadog.GetString("runtime_security_config.policies.dir"),
 		EventServerBurst:    aconfig.Datadog.GetInt("runtime_security_config.event_server.burst"),
 		EventServerRate:     aconfig.Datadog.GetInt("runtime_security_config.event_server.rate"),
+		EnvsDenylist:        aconfig.Datadog.GetStringSlice("runtime_security_config.envs_denylist"),
 	}
 
 	if cfg != nil {
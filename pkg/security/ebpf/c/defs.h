@@ -194,6 +194,10 @@ enum event_type
     EVENT_SETXATTR,
     EVENT_REMOVEXATTR,
     EVENT_EXEC,
+    EVENT_EXIT,
+    EVENT_CONNECT,
+    EVENT_BIND,
+    EVENT_LISTEN,
 };
 
 struct kevent_t {
@@ -220,6 +224,8 @@ struct process_context_t {
     u32 uid;
     u32 gid;
     struct file_t executable;
+    u32 cookie;
+    u32 padding;
 };
 
 struct container_context_t {
@@ -229,6 +235,9 @@ struct container_context_t {
 struct proc_cache_t {
     struct file_t executable;
     char container_id[CONTAINER_ID_LEN];
+    u32 parent_cookie;
+    u32 pid;
+    char comm[TASK_COMM_LEN];
 };
 
 struct bpf_map_def SEC("maps/events") events = {
@@ -5,6 +5,38 @@
 #include "syscalls.h"
 #include "container.h"
 
+#define MAX_EXEC_ARGS 16
+#define MAX_EXEC_ARG_LEN 128
+
+static struct proc_cache_t * fill_process_data(struct process_context_t *data);
+
+struct exec_args_t {
+    u32 args_count;
+    u32 envs_count;
+    u8 args_truncated;
+    u8 envs_truncated;
+    u16 padding16;
+    u32 padding32;
+    char args[MAX_EXEC_ARGS][MAX_EXEC_ARG_LEN];
+    char envs[MAX_EXEC_ARGS][MAX_EXEC_ARG_LEN];
+};
+
+struct exec_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    struct file_t file;
+    struct exec_args_t args;
+};
+
+struct exit_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+};
+
 struct _tracepoint_sched_process_fork
 {
     unsigned short common_type;
@@ -21,6 +53,9 @@ struct _tracepoint_sched_process_fork
 void __attribute__((always_inline)) copy_proc_cache(struct proc_cache_t *dst, struct proc_cache_t *src) {
     dst->executable = src->executable;
     copy_container_id(dst->container_id, src->container_id);
+    dst->parent_cookie = src->parent_cookie;
+    dst->pid = src->pid;
+    bpf_probe_read(&dst->comm, sizeof(dst->comm), &src->comm);
     return;
 }
 
@@ -42,22 +77,105 @@ struct bpf_map_def SEC("maps/pid_cookie") pid_cookie = {
     .namespace = "",
 };
 
-int __attribute__((always_inline)) trace__sys_execveat() {
+struct bpf_map_def SEC("maps/exec_policy") exec_policy = {
+    .type = BPF_MAP_TYPE_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct policy_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+struct bpf_map_def SEC("maps/exit_policy") exit_policy = {
+    .type = BPF_MAP_TYPE_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct policy_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+// exec_args holds the arguments and environment variables of the exec syscalls in flight,
+// as they can only be read from the memory of the calling process, before the new image is loaded
+struct bpf_map_def SEC("maps/exec_args") exec_args = {
+    .type = BPF_MAP_TYPE_LRU_HASH,
+    .key_size = sizeof(u64),
+    .value_size = sizeof(struct exec_args_t),
+    .max_entries = 256,
+    .pinning = 0,
+    .namespace = "",
+};
+
+// exec_gen is used as a scratch buffer as the exec arguments and event do not fit on the stack
+struct bpf_map_def SEC("maps/exec_gen") exec_gen = {
+    .type = BPF_MAP_TYPE_PERCPU_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct exec_event_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+static __attribute__((always_inline)) int is_event_enabled(struct bpf_map_def *policy_map) {
+    u32 key = 0;
+    struct policy_t *policy = bpf_map_lookup_elem(policy_map, &key);
+    return policy && policy->mode != 0;
+}
+
+static __attribute__((always_inline)) void read_exec_strings(const char **strs, char dst[MAX_EXEC_ARGS][MAX_EXEC_ARG_LEN], u32 *count, u8 *truncated) {
+    const char *str = NULL;
+
+#pragma unroll
+    for (int i = 0; i < MAX_EXEC_ARGS; i++) {
+        bpf_probe_read(&str, sizeof(str), (void *)&strs[i]);
+        if (!str) {
+            return;
+        }
+        bpf_probe_read_str(dst[i], MAX_EXEC_ARG_LEN, (void *)str);
+        *count = i + 1;
+    }
+
+    bpf_probe_read(&str, sizeof(str), (void *)&strs[MAX_EXEC_ARGS]);
+    *truncated = str != NULL;
+}
+
+int __attribute__((always_inline)) trace__sys_execveat(const char **argv, const char **envp) {
     struct syscall_cache_t syscall = {
         .type = EVENT_EXEC,
     };
 
     cache_syscall(&syscall);
 
+    if (!is_event_enabled(&exec_policy)) {
+        return 0;
+    }
+
+    u32 key = 0;
+    struct exec_event_t *gen = bpf_map_lookup_elem(&exec_gen, &key);
+    if (!gen) {
+        return 0;
+    }
+    struct exec_args_t *args = &gen->args;
+    args->args_count = 0;
+    args->envs_count = 0;
+    args->args_truncated = 0;
+    args->envs_truncated = 0;
+
+    read_exec_strings(argv, args->args, &args->args_count, &args->args_truncated);
+    read_exec_strings(envp, args->envs, &args->envs_count, &args->envs_truncated);
+
+    u64 id = bpf_get_current_pid_tgid();
+    bpf_map_update_elem(&exec_args, &id, args, BPF_ANY);
+
     return 0;
 }
 
-SYSCALL_KPROBE0(execve) {
-    return trace__sys_execveat();
+SYSCALL_KPROBE3(execve, const char *, filename, const char **, argv, const char **, envp) {
+    return trace__sys_execveat(argv, envp);
 }
 
-SYSCALL_KPROBE0(execveat) {
-    return trace__sys_execveat();
+SYSCALL_KPROBE4(execveat, int, fd, const char *, filename, const char **, argv, const char **, envp) {
+    return trace__sys_execveat(argv, envp);
 }
 
 struct proc_cache_t * __attribute__((always_inline)) get_pid_cache(u32 tgid) {
@@ -73,6 +191,11 @@ struct proc_cache_t * __attribute__((always_inline)) get_pid_cache(u32 tgid) {
 }
 
 int __attribute__((always_inline)) vfs_handle_exec_event(struct pt_regs *ctx, struct syscall_cache_t *syscall) {
+    // Only the first file opened is the executable, the next ones are the interpreters
+    if (syscall->exec.resolved) {
+        return 0;
+    }
+
     struct path *path = (struct path *)PT_REGS_PARM1(ctx);
 
     // new cache entry
@@ -89,10 +212,18 @@ int __attribute__((always_inline)) vfs_handle_exec_event(struct pt_regs *ctx, st
     u64 pid_tgid = bpf_get_current_pid_tgid();
     u32 tgid = pid_tgid >> 32;
 
-    struct proc_cache_t *parent_entry = get_pid_cache(tgid);
-    if (parent_entry) {
-        // inherit container ID
-        copy_container_id(entry.container_id, parent_entry->container_id);
+    entry.pid = tgid;
+
+    u32 *parent_cookie = (u32 *) bpf_map_lookup_elem(&pid_cookie, &tgid);
+    if (parent_cookie) {
+        // keep track of the process image that executed this one to resolve ancestors
+        entry.parent_cookie = *parent_cookie;
+
+        struct proc_cache_t *parent_entry = bpf_map_lookup_elem(&proc_cache, &entry.parent_cookie);
+        if (parent_entry) {
+            // inherit container ID
+            copy_container_id(entry.container_id, parent_entry->container_id);
+        }
     }
 
     // insert new proc cache entry
@@ -102,11 +233,62 @@ int __attribute__((always_inline)) vfs_handle_exec_event(struct pt_regs *ctx, st
     // insert pid <-> cookie mapping
     bpf_map_update_elem(&pid_cookie, &tgid, &cookie, BPF_ANY);
 
-    pop_syscall();
+    // keep the executable for the exec event, the syscall is popped on return
+    syscall->exec.file = entry.executable;
+    syscall->exec.resolved = 1;
+
+    return 0;
+}
+
+int __attribute__((always_inline)) trace__sys_execveat_ret(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = pop_syscall();
+    if (!syscall || syscall->type != EVENT_EXEC)
+        return 0;
+
+    u64 id = bpf_get_current_pid_tgid();
+
+    // the comm of the process is only updated once the new image is loaded
+    struct proc_cache_t *proc_entry = get_pid_cache(id >> 32);
+    if (proc_entry) {
+        bpf_get_current_comm(&proc_entry->comm, sizeof(proc_entry->comm));
+    }
+    struct exec_args_t *args = bpf_map_lookup_elem(&exec_args, &id);
+    if (!args)
+        return 0;
+
+    int retval = PT_REGS_RC(ctx);
+    if (IS_UNHANDLED_ERROR(retval))
+        goto exit;
+
+    u32 key = 0;
+    struct exec_event_t *event = bpf_map_lookup_elem(&exec_gen, &key);
+    if (!event)
+        goto exit;
+
+    event->event.type = EVENT_EXEC;
+    event->syscall.retval = retval;
+    event->syscall.timestamp = bpf_ktime_get_ns();
+    event->file = syscall->exec.file;
+    bpf_probe_read(&event->args, sizeof(event->args), args);
+
+    struct proc_cache_t *entry = fill_process_data(&event->process);
+    fill_container_data(entry, &event->container);
 
+    bpf_perf_event_output(ctx, &events, bpf_get_smp_processor_id(), event, sizeof(*event));
+
+exit:
+    bpf_map_delete_elem(&exec_args, &id);
     return 0;
 }
 
+SYSCALL_KRETPROBE(execve) {
+    return trace__sys_execveat_ret(ctx);
+}
+
+SYSCALL_KRETPROBE(execveat) {
+    return trace__sys_execveat_ret(ctx);
+}
+
 SEC("tracepoint/sched/sched_process_fork")
 int sched_process_fork(struct _tracepoint_sched_process_fork *args)
 {
@@ -131,8 +313,24 @@ int kprobe_do_exit(struct pt_regs *ctx) {
     u32 tgid = pid_tgid >> 32;
     u32 pid = pid_tgid;
 
-    // Delete pid <-> cookie mapping
     if (tgid == pid) {
+        if (is_event_enabled(&exit_policy)) {
+            struct exit_event_t event = {
+                .event.type = EVENT_EXIT,
+                .syscall = {
+                    .retval = (long)PT_REGS_PARM1(ctx),
+                    .timestamp = bpf_ktime_get_ns(),
+                },
+            };
+
+            // the process cache entry is still available at this point
+            struct proc_cache_t *entry = fill_process_data(&event.process);
+            fill_container_data(entry, &event.container);
+
+            send_event(ctx, event);
+        }
+
+        // Delete pid <-> cookie mapping
         bpf_map_delete_elem(&pid_cookie, &tgid);
     }
     // (do not delete cookie <-> proc_cache entry since it can be used by a parent process)
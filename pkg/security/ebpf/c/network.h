@@ -0,0 +1,178 @@
+#ifndef _NETWORK_H_
+#define _NETWORK_H_
+
+#include <linux/in.h>
+#include <linux/in6.h>
+#include <linux/net.h>
+#include <net/sock.h>
+
+#include "syscalls.h"
+
+struct socket_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    struct socket_info_t socket;
+};
+
+struct bpf_map_def SEC("maps/connect_policy") connect_policy = {
+    .type = BPF_MAP_TYPE_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct policy_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+struct bpf_map_def SEC("maps/bind_policy") bind_policy = {
+    .type = BPF_MAP_TYPE_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct policy_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+struct bpf_map_def SEC("maps/listen_policy") listen_policy = {
+    .type = BPF_MAP_TYPE_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct policy_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+static __attribute__((always_inline)) u16 get_socket_protocol(struct socket *sock) {
+    short type = 0;
+    bpf_probe_read(&type, sizeof(type), &sock->type);
+
+    // sk_protocol is a bitfield on older kernels, derive the protocol from the socket type instead
+    switch (type) {
+    case SOCK_STREAM:
+        return IPPROTO_TCP;
+    case SOCK_DGRAM:
+        return IPPROTO_UDP;
+    }
+    return 0;
+}
+
+static __attribute__((always_inline)) void read_sockaddr(struct sockaddr *address, struct socket_info_t *info) {
+    bpf_probe_read(&info->family, sizeof(info->family), &address->sa_family);
+
+    switch (info->family) {
+    case AF_INET: {
+        struct sockaddr_in *addr_in = (struct sockaddr_in *)address;
+        bpf_probe_read(&info->port, sizeof(info->port), &addr_in->sin_port);
+        bpf_probe_read(&info->addr, sizeof(addr_in->sin_addr), &addr_in->sin_addr);
+        break;
+    }
+    case AF_INET6: {
+        struct sockaddr_in6 *addr_in6 = (struct sockaddr_in6 *)address;
+        bpf_probe_read(&info->port, sizeof(info->port), &addr_in6->sin6_port);
+        bpf_probe_read(&info->addr, sizeof(addr_in6->sin6_addr), &addr_in6->sin6_addr);
+        break;
+    }
+    }
+    info->port = ntohs(info->port);
+}
+
+static __attribute__((always_inline)) int trace__security_socket_addr(struct pt_regs *ctx, u16 type, struct bpf_map_def *policy_map) {
+    if (!is_event_enabled(policy_map)) {
+        return 0;
+    }
+
+    struct socket *sock = (struct socket *)PT_REGS_PARM1(ctx);
+    struct syscall_cache_t syscall = {
+        .type = type,
+    };
+    read_sockaddr((struct sockaddr *)PT_REGS_PARM2(ctx), &syscall.socket);
+    syscall.socket.protocol = get_socket_protocol(sock);
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SEC("kprobe/security_socket_connect")
+int kprobe__security_socket_connect(struct pt_regs *ctx) {
+    return trace__security_socket_addr(ctx, EVENT_CONNECT, &connect_policy);
+}
+
+SEC("kprobe/security_socket_bind")
+int kprobe__security_socket_bind(struct pt_regs *ctx) {
+    return trace__security_socket_addr(ctx, EVENT_BIND, &bind_policy);
+}
+
+SEC("kprobe/security_socket_listen")
+int kprobe__security_socket_listen(struct pt_regs *ctx) {
+    if (!is_event_enabled(&listen_policy)) {
+        return 0;
+    }
+
+    struct socket *sock = (struct socket *)PT_REGS_PARM1(ctx);
+    struct syscall_cache_t syscall = {
+        .type = EVENT_LISTEN,
+        .socket = {
+            .backlog = (u32)PT_REGS_PARM2(ctx),
+            .protocol = get_socket_protocol(sock),
+        },
+    };
+
+    // the socket is already bound, read the local address from the socket itself
+    struct sock *sk = NULL;
+    bpf_probe_read(&sk, sizeof(sk), &sock->sk);
+    bpf_probe_read(&syscall.socket.family, sizeof(syscall.socket.family), &sk->__sk_common.skc_family);
+    bpf_probe_read(&syscall.socket.port, sizeof(syscall.socket.port), &sk->__sk_common.skc_num);
+    if (syscall.socket.family == AF_INET) {
+        bpf_probe_read(&syscall.socket.addr, sizeof(sk->__sk_common.skc_rcv_saddr), &sk->__sk_common.skc_rcv_saddr);
+    }
+#if IS_ENABLED(CONFIG_IPV6)
+    else if (syscall.socket.family == AF_INET6) {
+        bpf_probe_read(&syscall.socket.addr, sizeof(sk->__sk_common.skc_v6_rcv_saddr), &sk->__sk_common.skc_v6_rcv_saddr);
+    }
+#endif
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+static __attribute__((always_inline)) int trace__sys_socket_ret(struct pt_regs *ctx, u16 type) {
+    struct syscall_cache_t *syscall = pop_syscall();
+    if (!syscall || syscall->type != type)
+        return 0;
+
+    int retval = PT_REGS_RC(ctx);
+    // non blocking connects return EINPROGRESS, still report them
+    if (IS_UNHANDLED_ERROR(retval) && retval != -EINPROGRESS)
+        return 0;
+
+    struct socket_event_t event = {
+        .event.type = type,
+        .syscall = {
+            .retval = retval,
+            .timestamp = bpf_ktime_get_ns(),
+        },
+        .socket = syscall->socket,
+    };
+
+    struct proc_cache_t *entry = fill_process_data(&event.process);
+    fill_container_data(entry, &event.container);
+
+    send_event(ctx, event);
+
+    return 0;
+}
+
+SYSCALL_KRETPROBE(connect) {
+    return trace__sys_socket_ret(ctx, EVENT_CONNECT);
+}
+
+SYSCALL_KRETPROBE(bind) {
+    return trace__sys_socket_ret(ctx, EVENT_BIND);
+}
+
+SYSCALL_KRETPROBE(listen) {
+    return trace__sys_socket_ret(ctx, EVENT_LISTEN);
+}
+
+#endif
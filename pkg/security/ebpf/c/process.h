@@ -38,7 +38,12 @@ static struct proc_cache_t * __attribute__((always_inline)) fill_process_data(st
     data->uid = userid >> 32;
     data->gid = userid;
 
-    struct proc_cache_t *entry = get_pid_cache(tgid);
+    struct proc_cache_t *entry = NULL;
+    u32 *cookie = (u32 *) bpf_map_lookup_elem(&pid_cookie, &tgid);
+    if (cookie) {
+        data->cookie = *cookie;
+        entry = bpf_map_lookup_elem(&proc_cache, &data->cookie);
+    }
     if (entry) {
         data->executable = entry->executable;
     }
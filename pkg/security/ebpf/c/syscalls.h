@@ -3,6 +3,16 @@
 
 #include "filters.h"
 
+struct socket_info_t {
+    u16 family;
+    u16 port;
+    u16 protocol;
+    u16 padding;
+    u32 backlog;
+    u32 padding32;
+    u8 addr[16];
+};
+
 struct ktimeval {
     long tv_sec;
     long tv_nsec;
@@ -90,6 +100,13 @@ struct syscall_cache_t {
             struct path_key_t path_key;
             const char *name;
         } setxattr;
+
+        struct {
+            struct file_t file;
+            u8 resolved;
+        } exec;
+
+        struct socket_info_t socket;
     };
 };
 
@@ -13,6 +13,7 @@ import (
 	"unsafe"
 
 	bpflib "github.com/iovisor/gobpf/elf"
+	"golang.org/x/sys/unix"
 )
 
 // Table represents an eBPF map
@@ -65,6 +66,66 @@ func (t *Table) Delete(key []byte) error {
 	return t.module.DeleteElement(t.Map, unsafe.Pointer(&key[0]))
 }
 
+// TableInfo describes the definition of an eBPF map, as reported by the kernel
+type TableInfo struct {
+	Type       uint32
+	ID         uint32
+	KeySize    uint32
+	ValueSize  uint32
+	MaxEntries uint32
+	MapFlags   uint32
+}
+
+// IsArray returns whether the map is an array, whose entries can't be deleted
+func (i *TableInfo) IsArray() bool {
+	return i.Type == unix.BPF_MAP_TYPE_ARRAY || i.Type == unix.BPF_MAP_TYPE_PERCPU_ARRAY
+}
+
+// Info returns the definition of the map
+func (t *Table) Info() (*TableInfo, error) {
+	var info TableInfo
+
+	// struct bpf_attr of the BPF_OBJ_GET_INFO_BY_FD command
+	attr := struct {
+		fd      uint32
+		infoLen uint32
+		info    uint64
+	}{
+		fd:      uint32(t.Fd()),
+		infoLen: uint32(unsafe.Sizeof(info)),
+		info:    uint64(uintptr(unsafe.Pointer(&info))),
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_BPF, unix.BPF_OBJ_GET_INFO_BY_FD, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr)); errno != 0 {
+		return nil, errno
+	}
+	return &info, nil
+}
+
+// Keys returns the keys of a hash map. The iteration starts from the null key, which isn't returned.
+func (t *Table) Keys() ([][]byte, error) {
+	info, err := t.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	// looking up a key that doesn't exist returns the first key of the map
+	var keys [][]byte
+	key := make([]byte, info.KeySize)
+	for {
+		more, next, _, err := t.GetNext(key)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			return keys, nil
+		}
+
+		keys = append(keys, next)
+		key = next
+	}
+}
+
 // BytesTableItem describes a raw table key or value
 type BytesTableItem []byte
 
@@ -14,7 +14,11 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
@@ -34,14 +38,16 @@ import (
 
 // Module represents the system-probe module for the runtime security agent
 type Module struct {
-	probe        *sprobe.Probe
-	config       *config.Config
-	ruleSet      *rules.RuleSet
-	eventServer  *EventServer
-	grpcServer   *grpc.Server
-	listener     net.Listener
-	statsdClient *statsd.Client
-	rateLimiter  *RateLimiter
+	reloadLock     sync.Mutex
+	probe          *sprobe.Probe
+	config         *config.Config
+	currentRuleSet atomic.Value
+	eventServer    *EventServer
+	grpcServer     *grpc.Server
+	listener       net.Listener
+	statsdClient   *statsd.Client
+	rateLimiter    *RateLimiter
+	sigupChan      chan os.Signal
 }
 
 // Register the runtime security agent module
@@ -66,7 +72,7 @@ func (m *Module) Register(httpMux *http.ServeMux) error {
 	}()
 
 	m.probe.SetEventHandler(m)
-	m.ruleSet.AddListener(m)
+	m.GetRuleSet().AddListener(m)
 
 	go m.statsMonitor(context.Background())
 
@@ -74,10 +80,7 @@ func (m *Module) Register(httpMux *http.ServeMux) error {
 		return err
 	}
 
-	rsa := sprobe.NewRuleSetApplier(m.config)
-
-	report, err := rsa.Apply(m.ruleSet, m.probe)
-	if err != nil {
+	if err := m.applyRuleSet(m.GetRuleSet()); err != nil {
 		log.Warn(err)
 	}
 
@@ -87,14 +90,105 @@ func (m *Module) Register(httpMux *http.ServeMux) error {
 		return err
 	}
 
+	signal.Notify(m.sigupChan, syscall.SIGHUP)
+
+	go func() {
+		for range m.sigupChan {
+			log.Info("Reload policies")
+
+			if _, err := m.ReloadPolicies(); err != nil {
+				log.Errorf("failed to reload policies: %s", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// applyRuleSet computes the in-kernel filters of the given ruleset and applies them to the probe
+func (m *Module) applyRuleSet(ruleSet *rules.RuleSet) error {
+	rsa := sprobe.NewRuleSetApplier(m.config)
+
+	report, err := rsa.Apply(ruleSet, m.probe)
+	if err != nil {
+		return err
+	}
+
 	content, _ := json.MarshalIndent(report, "", "\t")
 	log.Debug(string(content))
 
 	return nil
 }
 
+// ReloadPolicies loads the policies again and replaces the current ruleset. The current ruleset
+// is kept if the new policies fail to load or to apply.
+func (m *Module) ReloadPolicies() (*rules.RuleSetDiff, error) {
+	m.reloadLock.Lock()
+	defer m.reloadLock.Unlock()
+
+	ruleSet := m.probe.NewRuleSet(rules.NewOptsWithParams(m.config.Debug, sprobe.SECLConstants, sprobe.InvalidDiscarders))
+	if err := policy.LoadPolicies(m.config, ruleSet); err != nil {
+		return nil, errors.Wrap(err, "failed to load policies")
+	}
+
+	previous := m.GetRuleSet()
+	diff := ruleSet.Diff(previous)
+
+	ruleSet.AddListener(m)
+
+	if err := m.swapRuleSet(ruleSet); err != nil {
+		return nil, m.rollback(previous, err)
+	}
+
+	// send the events aggregated with the previous rate limits before applying the new ones
+	for _, aggregated := range m.rateLimiter.FlushAggregatedEvents() {
+		m.eventServer.SendAggregatedEvent(aggregated)
+	}
+	m.rateLimiter.Apply(ruleSet.ListRuleDefinitions())
+	m.eventServer.Apply(ruleSet.ListRuleIDs())
+
+	log.Infof("Policies reloaded: added %v, removed %v, changed %v", diff.Added, diff.Removed, diff.Changed)
+
+	return diff, nil
+}
+
+// swapRuleSet replaces the current ruleset and its in-kernel filters. The discarders of the
+// previous ruleset may filter events the new rules depend on, they are removed before the new
+// filters are applied. The approvers of the previous ruleset are only removed once the new ones
+// are set, so that events are never filtered by an empty set of approvers in between.
+func (m *Module) swapRuleSet(ruleSet *rules.RuleSet) error {
+	m.currentRuleSet.Store(ruleSet)
+
+	kFilters, err := m.probe.GetKFilters()
+	if err != nil {
+		return err
+	}
+
+	if err := m.probe.RemoveDiscarders(kFilters); err != nil {
+		return err
+	}
+
+	if err := m.applyRuleSet(ruleSet); err != nil {
+		return err
+	}
+
+	return m.probe.RemoveStaleApprovers(kFilters)
+}
+
+// rollback restores the previous ruleset after a failed reload
+func (m *Module) rollback(previous *rules.RuleSet, reloadErr error) error {
+	if err := m.swapRuleSet(previous); err != nil {
+		log.Errorf("failed to apply the previous policies during rollback: %s", err)
+	}
+
+	return errors.Wrap(reloadErr, "failed to apply policies, previous policies restored")
+}
+
 // Close the module
 func (m *Module) Close() {
+	signal.Stop(m.sigupChan)
+	close(m.sigupChan)
+
 	if m.grpcServer != nil {
 		m.grpcServer.Stop()
 	}
@@ -109,15 +203,20 @@ func (m *Module) Close() {
 
 // RuleMatch is called by the ruleset when a rule matches
 func (m *Module) RuleMatch(rule *eval.Rule, event eval.Event) {
-	if m.rateLimiter.Allow(rule.ID) {
+	if m.rateLimiter.Allow(rule, event) {
 		m.eventServer.SendEvent(rule, event)
 	} else {
-		log.Debugf("Event on rule %s was dropped due to rate limiting", rule.ID)
+		log.Debugf("Event on rule %s was dropped or aggregated due to rate limiting", rule.ID)
 	}
 }
 
 // EventDiscarderFound is called by the ruleset when a new discarder discovered
 func (m *Module) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field string) {
+	// the ruleset was replaced during the evaluation, the discarder may not be valid for the current rules
+	if rs != m.GetRuleSet() {
+		return
+	}
+
 	if err := m.probe.OnNewDiscarder(rs, event.(*sprobe.Event), field); err != nil {
 		log.Trace(err)
 	}
@@ -125,7 +224,7 @@ func (m *Module) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field
 
 // HandleEvent is called by the probe when an event arrives from the kernel
 func (m *Module) HandleEvent(event *sprobe.Event) {
-	m.ruleSet.Evaluate(event)
+	m.GetRuleSet().Evaluate(event)
 }
 
 func (m *Module) statsMonitor(ctx context.Context) {
@@ -135,8 +234,15 @@ func (m *Module) statsMonitor(ctx context.Context) {
 	ticker := time.NewTicker(20 * time.Second)
 	defer ticker.Stop()
 
+	aggregationTicker := time.NewTicker(aggregationPeriod)
+	defer aggregationTicker.Stop()
+
 	for {
 		select {
+		case <-aggregationTicker.C:
+			for _, aggregated := range m.rateLimiter.FlushAggregatedEvents() {
+				m.eventServer.SendAggregatedEvent(aggregated)
+			}
 		case <-ticker.C:
 			if err := m.probe.SendStats(m.statsdClient); err != nil {
 				log.Debug(err)
@@ -167,7 +273,7 @@ func (m *Module) GetStats() map[string]interface{} {
 
 // GetRuleSet returns the set of loaded rules
 func (m *Module) GetRuleSet() *rules.RuleSet {
-	return m.ruleSet
+	return m.currentRuleSet.Load().(*rules.RuleSet)
 }
 
 // NewModule instantiates a runtime security system-probe module
@@ -211,12 +317,14 @@ func NewModule(cfg *aconfig.AgentConfig) (api.Module, error) {
 	m := &Module{
 		config:       config,
 		probe:        probe,
-		ruleSet:      ruleSet,
 		eventServer:  NewEventServer(ruleSet.ListRuleIDs(), config),
 		grpcServer:   grpc.NewServer(),
 		statsdClient: statsdClient,
-		rateLimiter:  NewRateLimiter(ruleSet.ListRuleIDs()),
+		rateLimiter:  NewRateLimiter(ruleSet.ListRuleDefinitions()),
+		sigupChan:    make(chan os.Signal, 1),
 	}
+	m.currentRuleSet.Store(ruleSet)
+	m.eventServer.module = m
 
 	sapi.RegisterSecurityModuleServer(m.grpcServer, m.eventServer)
 
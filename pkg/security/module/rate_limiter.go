@@ -8,13 +8,18 @@
 package module
 
 import (
+	"encoding/json"
 	"fmt"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
 	"golang.org/x/time/rate"
 
 	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
 )
 
 const (
@@ -23,8 +28,31 @@ const (
 	// Default Token bucket size. 40 is meant to handle sudden burst of events while making sure that we prevent
 	// flooding.
 	defaultBurst int = 40
+	// Period after which the aggregated events are sent
+	aggregationPeriod = 10 * time.Second
+	// Maximum number of processes for which the events of a rule are aggregated during a period
+	maxAggregatedProcesses = 1000
 )
 
+// AggregatedEvent is the summary of the events of a rule and a process that exceeded the rate limit of the rule.
+// It has the shape of a rules.RuleEvent holding the first aggregated event, with the aggregation counts.
+type AggregatedEvent struct {
+	RuleID      string          `json:"rule_id"`
+	Event       json.RawMessage `json:"event"`
+	Aggregation Aggregation     `json:"aggregation"`
+
+	rule      *eval.Rule
+	eventType string
+	tags      []string
+}
+
+// Aggregation holds the number of aggregated events and when they were first and last seen
+type Aggregation struct {
+	Count     int64     `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
 // Limiter describes an object that applies limits on
 // the rate of triggering of a rule to ensure we don't overflow
 // with too permissive rules
@@ -32,10 +60,15 @@ type Limiter struct {
 	limiter *rate.Limiter
 
 	// https://github.com/golang/go/issues/36606
-	padding int32
-	dropped int64
-	align   int64
-	allowed int64
+	padding    int32
+	dropped    int64
+	align      int64
+	allowed    int64
+	aggregated int64
+
+	definition   rules.RateLimitDefinition
+	lock         sync.Mutex
+	aggregations map[uint32]*AggregatedEvent
 }
 
 // NewLimiter returns a new rule limiter
@@ -45,25 +78,119 @@ func NewLimiter(limit rate.Limit, burst int) *Limiter {
 	}
 }
 
+// newRuleLimiter returns a new limiter applying the rate limit settings of the given rule
+func newRuleLimiter(ruleDef *rules.RuleDefinition) *Limiter {
+	var definition rules.RateLimitDefinition
+	if ruleDef.RateLimit != nil {
+		definition = *ruleDef.RateLimit
+	}
+
+	limit, burst := defaultLimit, defaultBurst
+	if definition.Limit > 0 {
+		limit = rate.Limit(definition.Limit)
+	}
+	if definition.Burst > 0 {
+		burst = definition.Burst
+	}
+
+	limiter := NewLimiter(limit, burst)
+	limiter.definition = definition
+	if definition.Aggregate {
+		limiter.aggregations = make(map[uint32]*AggregatedEvent)
+	}
+
+	return limiter
+}
+
+// aggregate folds the given event into the aggregated event of its process
+func (l *Limiter) aggregate(rule *eval.Rule, event *probe.Event) bool {
+	now := time.Now()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	aggregated, exists := l.aggregations[event.Process.Pid]
+	if !exists {
+		if len(l.aggregations) >= maxAggregatedProcesses {
+			return false
+		}
+
+		// the event is serialized right away as its fields may not be resolvable anymore when the
+		// aggregated event is sent
+		data, err := json.Marshal(event)
+		if err != nil {
+			return false
+		}
+
+		aggregated = &AggregatedEvent{
+			RuleID:      rule.ID,
+			Event:       data,
+			Aggregation: Aggregation{FirstSeen: now},
+			rule:        rule,
+			eventType:   event.GetType(),
+			tags:        event.GetTags(),
+		}
+		l.aggregations[event.Process.Pid] = aggregated
+	}
+
+	aggregated.Aggregation.Count++
+	aggregated.Aggregation.LastSeen = now
+
+	return true
+}
+
+// flush returns the aggregated events and resets them
+func (l *Limiter) flush() []*AggregatedEvent {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var events []*AggregatedEvent
+	for pid, aggregated := range l.aggregations {
+		events = append(events, aggregated)
+		delete(l.aggregations, pid)
+	}
+
+	return events
+}
+
 // RateLimiter describes a set of rule rate limiters
 type RateLimiter struct {
+	sync.RWMutex
 	limiters map[string]*Limiter
 }
 
 // NewRateLimiter initializes an empty rate limiter
-func NewRateLimiter(ids []string) *RateLimiter {
-	limiters := make(map[string]*Limiter)
-	for _, id := range ids {
-		limiters[id] = NewLimiter(defaultLimit, defaultBurst)
+func NewRateLimiter(ruleDefs []*rules.RuleDefinition) *RateLimiter {
+	rl := &RateLimiter{
+		limiters: make(map[string]*Limiter),
 	}
-	return &RateLimiter{
-		limiters: limiters,
+	rl.Apply(ruleDefs)
+
+	return rl
+}
+
+// Apply a set of rules, the limiters of the rules whose rate limit didn't change are kept
+func (rl *RateLimiter) Apply(ruleDefs []*rules.RuleDefinition) {
+	rl.Lock()
+	defer rl.Unlock()
+
+	limiters := make(map[string]*Limiter)
+	for _, ruleDef := range ruleDefs {
+		limiter := newRuleLimiter(ruleDef)
+		if prev, exists := rl.limiters[ruleDef.ID]; exists && prev.definition == limiter.definition {
+			limiter = prev
+		}
+		limiters[ruleDef.ID] = limiter
 	}
+	rl.limiters = limiters
 }
 
-// Allow returns true if a specific rule shall be allowed to sent a new event
-func (rl *RateLimiter) Allow(ruleID string) bool {
-	ruleLimiter, ok := rl.limiters[ruleID]
+// Allow returns true if a specific rule shall be allowed to sent a new event. When the rule
+// aggregates its events, the events that are not allowed are folded into an aggregated event.
+func (rl *RateLimiter) Allow(rule *eval.Rule, event eval.Event) bool {
+	rl.RLock()
+	ruleLimiter, ok := rl.limiters[rule.ID]
+	rl.RUnlock()
 	if !ok {
 		return false
 	}
@@ -71,30 +198,53 @@ func (rl *RateLimiter) Allow(ruleID string) bool {
 		atomic.AddInt64(&ruleLimiter.allowed, 1)
 		return true
 	}
+	if ruleLimiter.definition.Aggregate && ruleLimiter.aggregate(rule, event.(*probe.Event)) {
+		atomic.AddInt64(&ruleLimiter.aggregated, 1)
+		return false
+	}
 	atomic.AddInt64(&ruleLimiter.dropped, 1)
 	return false
 }
 
+// FlushAggregatedEvents returns the events aggregated since the last flush
+func (rl *RateLimiter) FlushAggregatedEvents() []*AggregatedEvent {
+	rl.RLock()
+	defer rl.RUnlock()
+
+	var events []*AggregatedEvent
+	for _, ruleLimiter := range rl.limiters {
+		if ruleLimiter.definition.Aggregate {
+			events = append(events, ruleLimiter.flush()...)
+		}
+	}
+	return events
+}
+
 // RateLimiterStat represents the rate limiting statistics
 type RateLimiterStat struct {
-	dropped int64
-	allowed int64
+	dropped    int64
+	allowed    int64
+	aggregated int64
 }
 
 // GetStats returns a map indexed by ruleIDs that describes the amount of events
-// that were dropped because of the rate limiter
+// that were dropped or aggregated because of the rate limiter
 func (rl *RateLimiter) GetStats() map[string]RateLimiterStat {
+	rl.RLock()
+	defer rl.RUnlock()
+
 	stats := make(map[string]RateLimiterStat)
 	for ruleID, ruleLimiter := range rl.limiters {
 		stats[ruleID] = RateLimiterStat{
-			dropped: atomic.SwapInt64(&ruleLimiter.dropped, 0),
-			allowed: atomic.SwapInt64(&ruleLimiter.allowed, 0),
+			dropped:    atomic.SwapInt64(&ruleLimiter.dropped, 0),
+			allowed:    atomic.SwapInt64(&ruleLimiter.allowed, 0),
+			aggregated: atomic.SwapInt64(&ruleLimiter.aggregated, 0),
 		}
 	}
 	return stats
 }
 
-// SendStats sends statistics about the number of sent and drops events
+// SendStats sends statistics about the number of sent, dropped and aggregated events
 // for the set of rules
 func (rl *RateLimiter) SendStats(client *statsd.Client) error {
 	for ruleID, counts := range rl.GetStats() {
@@ -109,6 +259,11 @@ func (rl *RateLimiter) SendStats(client *statsd.Client) error {
 				return err
 			}
 		}
+		if counts.aggregated > 0 {
+			if err := client.Count(probe.MetricPrefix+".rules.rate_limiter.aggregate", counts.aggregated, tags, 1.0); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux_bpf
+
+package module
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+func TestRateLimiterAggregate(t *testing.T) {
+	tr, err := probe.NewTimeResolver()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newEvent := func(pid uint32) *probe.Event {
+		event := probe.NewEvent(&probe.Resolvers{TimeResolver: tr})
+		event.Type = uint64(probe.ExitEventType)
+		event.Process.Pid = pid
+		return event
+	}
+
+	rl := NewRateLimiter([]*rules.RuleDefinition{
+		{ID: "aggregated", RateLimit: &rules.RateLimitDefinition{Limit: 0.001, Burst: 1, Aggregate: true}},
+		{ID: "dropped", RateLimit: &rules.RateLimitDefinition{Limit: 0.001, Burst: 1}},
+	})
+
+	aggregatedRule := &eval.Rule{ID: "aggregated"}
+	droppedRule := &eval.Rule{ID: "dropped"}
+
+	for i := 0; i != 5; i++ {
+		pid := uint32(100 + i%2)
+		if allowed := rl.Allow(aggregatedRule, newEvent(pid)); allowed != (i == 0) {
+			t.Errorf("event %d of rule `aggregated`: expected allowed to be %v", i, i == 0)
+		}
+		if allowed := rl.Allow(droppedRule, newEvent(pid)); allowed != (i == 0) {
+			t.Errorf("event %d of rule `dropped`: expected allowed to be %v", i, i == 0)
+		}
+	}
+
+	counts := make(map[uint32]int64)
+	for _, aggregated := range rl.FlushAggregatedEvents() {
+		if aggregated.RuleID != "aggregated" {
+			t.Errorf("unexpected aggregated event for rule `%s`", aggregated.RuleID)
+		}
+
+		var event struct {
+			Process struct {
+				Pid uint32 `json:"pid"`
+			} `json:"process"`
+		}
+		if err := json.Unmarshal(aggregated.Event, &event); err != nil {
+			t.Fatal(err)
+		}
+		counts[event.Process.Pid] = aggregated.Aggregation.Count
+
+		// the aggregated event has the shape of a rule event
+		data, err := json.Marshal(aggregated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []string{"rule_id", "event", "aggregation"} {
+			if _, exists := payload[key]; !exists || len(payload) != 3 {
+				t.Errorf("expected the keys rule_id, event and aggregation, got %s", data)
+			}
+		}
+
+		if aggregated.eventType != "exit" {
+			t.Errorf("expected an aggregated event of type `exit`, got `%s`", aggregated.eventType)
+		}
+
+		if aggregated.Aggregation.LastSeen.Before(aggregated.Aggregation.FirstSeen) {
+			t.Errorf("last seen before first seen")
+		}
+	}
+
+	if counts[100] != 2 || counts[101] != 2 || len(counts) != 2 {
+		t.Errorf("unexpected aggregated counts: %v", counts)
+	}
+
+	if events := rl.FlushAggregatedEvents(); len(events) != 0 {
+		t.Errorf("expected no aggregated event after a flush, got %d", len(events))
+	}
+
+	stats := rl.GetStats()
+	if stat := stats["aggregated"]; stat.allowed != 1 || stat.aggregated != 4 || stat.dropped != 0 {
+		t.Errorf("unexpected stats for rule `aggregated`: %+v", stat)
+	}
+	if stat := stats["dropped"]; stat.allowed != 1 || stat.aggregated != 0 || stat.dropped != 4 {
+		t.Errorf("unexpected stats for rule `dropped`: %+v", stat)
+	}
+}
@@ -8,8 +8,10 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -27,9 +29,11 @@ import (
 // EventServer represents a gRPC server in charge of receiving events sent by
 // the runtime security system-probe module and forwards them to Datadog
 type EventServer struct {
+	sync.RWMutex
 	msgs          chan *api.SecurityEventMessage
 	expiredEvents map[string]*int64
 	rate          *Limiter
+	module        *Module
 }
 
 // GetEvents waits for security events
@@ -58,23 +62,59 @@ LOOP:
 	return nil
 }
 
+// ReloadPolicies reloads the policies of the runtime security module and returns the rules
+// that were added, removed or changed
+func (e *EventServer) ReloadPolicies(ctx context.Context, params *api.ReloadPoliciesParams) (*api.ReloadPoliciesReport, error) {
+	diff, err := e.module.ReloadPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ReloadPoliciesReport{
+		Added:   diff.Added,
+		Removed: diff.Removed,
+		Changed: diff.Changed,
+	}, nil
+}
+
 // SendEvent forwards events sent by the runtime security module to Datadog
 func (e *EventServer) SendEvent(rule *eval.Rule, event eval.Event) {
 	data, err := json.Marshal(rules.RuleEvent{Event: event, RuleID: rule.ID})
 	if err != nil {
 		return
 	}
-	tags := append(rule.Tags, "rule_id:"+rule.ID)
+	// the tags of the rule are shared by all its events, they are copied before being appended to
+	tags := append(append([]string{}, rule.Tags...), "rule_id:"+rule.ID)
 	tags = append(tags, event.(*sprobe.Event).GetTags()...)
 	log.Infof("Sending event message for rule `%s` to security-agent `%s` with tags %v", rule.ID, string(data), tags)
 
-	msg := &api.SecurityEventMessage{
+	e.sendMessage(&api.SecurityEventMessage{
 		RuleID: rule.ID,
 		Type:   event.GetType(),
 		Tags:   tags,
 		Data:   data,
+	})
+}
+
+// SendAggregatedEvent forwards the summary of the events that exceeded the rate limit of a rule to Datadog
+func (e *EventServer) SendAggregatedEvent(aggregated *AggregatedEvent) {
+	data, err := json.Marshal(aggregated)
+	if err != nil {
+		return
 	}
+	tags := append(append([]string{}, aggregated.rule.Tags...), "rule_id:"+aggregated.RuleID, "aggregated:true")
+	tags = append(tags, aggregated.tags...)
+	log.Infof("Sending aggregated event message for rule `%s` to security-agent `%s` with tags %v", aggregated.RuleID, string(data), tags)
 
+	e.sendMessage(&api.SecurityEventMessage{
+		RuleID: aggregated.RuleID,
+		Type:   aggregated.eventType,
+		Tags:   tags,
+		Data:   data,
+	})
+}
+
+func (e *EventServer) sendMessage(msg *api.SecurityEventMessage) {
 	select {
 	case e.msgs <- msg:
 		break
@@ -97,6 +137,9 @@ func (e *EventServer) SendEvent(rule *eval.Rule, event eval.Event) {
 
 // expireEvent updates the count of expired messages for the appropriate rule
 func (e *EventServer) expireEvent(msg *api.SecurityEventMessage) {
+	e.RLock()
+	defer e.RUnlock()
+
 	// Update metric
 	count, ok := e.expiredEvents[msg.RuleID]
 	if ok {
@@ -108,6 +151,9 @@ func (e *EventServer) expireEvent(msg *api.SecurityEventMessage) {
 // GetStats returns a map indexed by ruleIDs that describes the amount of events
 // that were expired or rate limited before reaching
 func (e *EventServer) GetStats() map[string]int64 {
+	e.RLock()
+	defer e.RUnlock()
+
 	stats := make(map[string]int64)
 	for ruleID, val := range e.expiredEvents {
 		stats[ruleID] = atomic.SwapInt64(val, 0)
@@ -128,6 +174,23 @@ func (e *EventServer) SendStats(client *statsd.Client) error {
 	return nil
 }
 
+// Apply a set of rules, the counters of the rules that are still present are kept
+func (e *EventServer) Apply(ruleIDs []string) {
+	e.Lock()
+	defer e.Unlock()
+
+	expiredEvents := make(map[string]*int64)
+	for _, id := range ruleIDs {
+		if count, exists := e.expiredEvents[id]; exists {
+			expiredEvents[id] = count
+		} else {
+			var val int64
+			expiredEvents[id] = &val
+		}
+	}
+	e.expiredEvents = expiredEvents
+}
+
 // NewEventServer returns a new gRPC event server
 func NewEventServer(ids []string, cfg *config.Config) *EventServer {
 	es := &EventServer{
@@ -68,6 +68,10 @@ func LoadPolicy(r io.Reader) (*Policy, error) {
 		if ruleDef.Expression == "" {
 			return nil, errors.New("rule has no expression")
 		}
+
+		if rateLimit := ruleDef.RateLimit; rateLimit != nil && (rateLimit.Limit < 0 || rateLimit.Burst < 0) {
+			return nil, fmt.Errorf("rule `%s` has a negative rate limit", ruleDef.ID)
+		}
 	}
 
 	return policy, nil
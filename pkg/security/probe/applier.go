@@ -28,6 +28,7 @@ type Applier interface {
 	ApplyFilterPolicy(eventType eval.EventType, tableName string, mode PolicyMode, flags PolicyFlag) error
 	ApplyApprovers(eventType eval.EventType, approvers rules.Approvers) error
 	RegisterKProbe(kprobe *ebpf.KProbe) error
+	UnregisterKProbe(kprobe *ebpf.KProbe) error
 	RegisterTracepoint(tracepoint string) error
 }
 
@@ -63,6 +64,14 @@ func (rsa *RuleSetApplier) registerKProbe(kprobe *ebpf.KProbe, applier Applier)
 	return nil
 }
 
+func (rsa *RuleSetApplier) unregisterKProbe(kprobe *ebpf.KProbe, applier Applier) error {
+	if applier != nil {
+		return applier.UnregisterKProbe(kprobe)
+	}
+
+	return nil
+}
+
 func (rsa *RuleSetApplier) registerTracepoint(tracepoint string, applier Applier) error {
 	if applier != nil {
 		return applier.RegisterTracepoint(tracepoint)
@@ -180,6 +189,20 @@ func (rsa *RuleSetApplier) Apply(rs *rules.RuleSet, applier Applier) (*Report, e
 		}
 	}
 
+	// unregister the kprobes of the hook points the ruleset doesn't need anymore. Tracepoints
+	// can't be detached with the eBPF library, they are kept once registered.
+	for _, hookPoint := range allHookPoints {
+		if hookPoint.EventTypes == nil || alreadyRegistered[hookPoint] {
+			continue
+		}
+
+		for _, kprobe := range hookPoint.KProbes {
+			if err := rsa.unregisterKProbe(kprobe, applier); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return rsa.reporter.GetReport(), nil
 }
 
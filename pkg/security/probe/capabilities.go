@@ -70,4 +70,9 @@ func (e ErrCapabilityNotFound) Error() string {
 
 func init() {
 	allCapabilities["open"] = openCapabilities
+	allCapabilities["exec"] = Capabilities{}
+	allCapabilities["exit"] = Capabilities{}
+	allCapabilities["connect"] = Capabilities{}
+	allCapabilities["bind"] = Capabilities{}
+	allCapabilities["listen"] = Capabilities{}
 }
@@ -54,6 +54,16 @@ const (
 	FileSetXAttrEventType
 	// FileRemoveXAttrEventType - Removexattr event
 	FileRemoveXAttrEventType
+	// ExecEventType - Process exec event
+	ExecEventType
+	// ExitEventType - Process exit event
+	ExitEventType
+	// ConnectEventType - Socket connect event
+	ConnectEventType
+	// BindEventType - Socket bind event
+	BindEventType
+	// ListenEventType - Socket listen event
+	ListenEventType
 	// internalEventType - used internally to get the maximum number of event. Has to be the last one
 	maxEventType
 )
@@ -86,6 +96,16 @@ func (t EventType) String() string {
 		return "setxattr"
 	case FileRemoveXAttrEventType:
 		return "removexattr"
+	case ExecEventType:
+		return "exec"
+	case ExitEventType:
+		return "exit"
+	case ConnectEventType:
+		return "connect"
+	case BindEventType:
+		return "bind"
+	case ListenEventType:
+		return "listen"
 	}
 	return "unknown"
 }
@@ -285,6 +305,17 @@ var (
 		"AT_REMOVEDIR": unix.AT_REMOVEDIR,
 	}
 
+	addressFamilyConstants = map[string]int{
+		"AF_UNIX":  unix.AF_UNIX,
+		"AF_INET":  unix.AF_INET,
+		"AF_INET6": unix.AF_INET6,
+	}
+
+	protocolConstants = map[string]int{
+		"IPPROTO_TCP": unix.IPPROTO_TCP,
+		"IPPROTO_UDP": unix.IPPROTO_UDP,
+	}
+
 	// SECLConstants are constants available in runtime security agent rules
 	SECLConstants = map[string]interface{}{
 		// boolean
@@ -294,9 +325,11 @@ var (
 )
 
 var (
-	openFlagsStrings   = map[int]string{}
-	chmodModeStrings   = map[int]string{}
-	unlinkFlagsStrings = map[int]string{}
+	openFlagsStrings     = map[int]string{}
+	chmodModeStrings     = map[int]string{}
+	unlinkFlagsStrings   = map[int]string{}
+	addressFamilyStrings = map[int]string{}
+	protocolStrings      = map[int]string{}
 )
 
 func initOpenConstants() {
@@ -329,6 +362,18 @@ func initUnlinkConstanst() {
 	}
 }
 
+func initNetworkConstants() {
+	for k, v := range addressFamilyConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		addressFamilyStrings[v] = k
+	}
+
+	for k, v := range protocolConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		protocolStrings[v] = k
+	}
+}
+
 func initErrorConstants() {
 	for k, v := range errorConstants {
 		SECLConstants[k] = &eval.IntEvaluator{Value: v}
@@ -340,6 +385,7 @@ func initConstants() {
 	initOpenConstants()
 	initChmodConstants()
 	initUnlinkConstanst()
+	initNetworkConstants()
 }
 
 func bitmaskToString(bitmask int, intToStrMap map[int]string) string {
@@ -390,6 +436,26 @@ func (f UnlinkFlags) String() string {
 	return bitmaskToString(int(f), unlinkFlagsStrings)
 }
 
+// AddressFamily represents a socket address family value
+type AddressFamily int
+
+func (f AddressFamily) String() string {
+	if s, ok := addressFamilyStrings[int(f)]; ok {
+		return s
+	}
+	return fmt.Sprintf("%d", int(f))
+}
+
+// Protocol represents a socket protocol value
+type Protocol int
+
+func (p Protocol) String() string {
+	if s, ok := protocolStrings[int(p)]; ok {
+		return s
+	}
+	return fmt.Sprintf("%d", int(p))
+}
+
 // RetValError represents a syscall return error value
 type RetValError int
 
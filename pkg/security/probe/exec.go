@@ -8,6 +8,10 @@
 package probe
 
 import (
+	"path"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/security/config"
 	"github.com/DataDog/datadog-agent/pkg/security/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
 )
@@ -18,6 +22,7 @@ var execHookPoints = []*HookPoint{
 		Name: "sys_execve",
 		KProbes: []*ebpf.KProbe{{
 			EntryFunc: "kprobe/" + getSyscallFnName("execve"),
+			ExitFunc:  "kretprobe/" + getSyscallFnName("execve"),
 		}},
 		EventTypes: []eval.EventType{"*"},
 	},
@@ -25,6 +30,7 @@ var execHookPoints = []*HookPoint{
 		Name: "sys_execveat",
 		KProbes: []*ebpf.KProbe{{
 			EntryFunc: "kprobe/" + getSyscallFnName("execveat"),
+			ExitFunc:  "kretprobe/" + getSyscallFnName("execveat"),
 		}},
 		EventTypes: []eval.EventType{"*"},
 		Optional:   true,
@@ -74,3 +80,55 @@ var execHookPoints = []*HookPoint{
 		Optional:   true,
 	},
 }
+
+const (
+	// maxExecArgs is the maximum number of arguments and environment variables captured by exec events
+	maxExecArgs = 16
+	// maxExecArgLen is the maximum length of a captured argument or environment variable
+	maxExecArgLen = 128
+)
+
+// defaultEnvsDenylist holds the patterns of the environment variables whose values are always scrubbed
+var defaultEnvsDenylist = []string{
+	"*KEY*",
+	"*SECRET*",
+	"*TOKEN*",
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*CREDENTIAL*",
+}
+
+// envsScrubber scrubs the values of the sensitive environment variables of exec events
+type envsScrubber struct {
+	denylist []string
+}
+
+func newEnvsScrubber(cfg *config.Config) *envsScrubber {
+	denylist := append([]string{}, defaultEnvsDenylist...)
+	if cfg != nil {
+		for _, pattern := range cfg.EnvsDenylist {
+			denylist = append(denylist, strings.ToUpper(pattern))
+		}
+	}
+	return &envsScrubber{denylist: denylist}
+}
+
+// scrub returns the given NAME=VALUE environment variable with its value replaced if its name is denylisted
+func (s *envsScrubber) scrub(env string) string {
+	if s == nil {
+		s = &envsScrubber{denylist: defaultEnvsDenylist}
+	}
+
+	els := strings.SplitN(env, "=", 2)
+	if len(els) != 2 {
+		return env
+	}
+
+	name := strings.ToUpper(els[0])
+	for _, pattern := range s.denylist {
+		if matched, _ := path.Match(pattern, name); matched {
+			return els[0] + "=********"
+		}
+	}
+	return env
+}
@@ -11,4 +11,8 @@ package probe
 var execTables = []string{
 	"proc_cache",
 	"pid_cookie",
+	"exec_policy",
+	"exit_policy",
+	"exec_args",
+	"exec_gen",
 }
@@ -256,4 +256,5 @@ func init() {
 	allHookPoints = append(allHookPoints, mountHookPoints...)
 	allHookPoints = append(allHookPoints, execHookPoints...)
 	allHookPoints = append(allHookPoints, UnlinkHookPoints...)
+	allHookPoints = append(allHookPoints, networkHookPoints...)
 }
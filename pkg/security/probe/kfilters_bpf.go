@@ -16,6 +16,36 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
 )
 
+// kApproverTables lists the maps holding in-kernel approvers
+var kApproverTables = []string{
+	"open_basename_approvers",
+	"open_flags_approvers",
+	"open_process_inode_approvers",
+}
+
+// kDiscarderTables lists the maps holding in-kernel discarders
+var kDiscarderTables = []string{
+	"open_flags_discarders",
+	"open_path_inode_discarders",
+	"unlink_path_inode_discarders",
+}
+
+// KFilters holds the keys of in-kernel approvers or discarders, indexed by table name
+type KFilters map[string]map[string]bool
+
+func (k KFilters) add(tableName string, key []byte) {
+	keys, exists := k[tableName]
+	if !exists {
+		keys = make(map[string]bool)
+		k[tableName] = keys
+	}
+	keys[string(key)] = true
+}
+
+func (k KFilters) contains(tableName string, key []byte) bool {
+	return k[tableName][string(key)]
+}
+
 func discardInode(probe *Probe, mountID uint32, inode uint64, tableName string) (bool, error) {
 	key := pathKey{mountID: mountID, inode: inode}
 
@@ -43,13 +73,7 @@ func discardParentInode(probe *Probe, rs *rules.RuleSet, eventType eval.EventTyp
 
 func approveBasename(probe *Probe, tableName string, basename string) error {
 	key := ebpf.NewStringTableItem(basename, BasenameFilterSize)
-
-	table := probe.Table(tableName)
-	if err := table.Set(key, ebpf.ZeroUint8TableItem); err != nil {
-		return err
-	}
-
-	return nil
+	return probe.setApprover(tableName, key, ebpf.ZeroUint8TableItem)
 }
 
 func approveBasenames(probe *Probe, tableName string, basenames ...string) error {
@@ -61,14 +85,26 @@ func approveBasenames(probe *Probe, tableName string, basenames ...string) error
 	return nil
 }
 
-func setFlagsFilter(probe *Probe, tableName string, flags ...int) error {
+func flagsTableItem(flags ...int) ebpf.Uint32TableItem {
 	var flagsItem ebpf.Uint32TableItem
 
 	for _, flag := range flags {
 		flagsItem |= ebpf.Uint32TableItem(flag)
 	}
 
-	if flagsItem != 0 {
+	return flagsItem
+}
+
+func approveFlags(probe *Probe, tableName string, flags ...int) error {
+	if flagsItem := flagsTableItem(flags...); flagsItem != 0 {
+		return probe.setApprover(tableName, ebpf.ZeroUint32TableItem, flagsItem)
+	}
+
+	return nil
+}
+
+func discardFlags(probe *Probe, tableName string, flags ...int) error {
+	if flagsItem := flagsTableItem(flags...); flagsItem != 0 {
 		table := probe.Table(tableName)
 		if err := table.Set(ebpf.ZeroUint32TableItem, flagsItem); err != nil {
 			return err
@@ -78,14 +114,6 @@ func setFlagsFilter(probe *Probe, tableName string, flags ...int) error {
 	return nil
 }
 
-func approveFlags(probe *Probe, tableName string, flags ...int) error {
-	return setFlagsFilter(probe, tableName, flags...)
-}
-
-func discardFlags(probe *Probe, tableName string, flags ...int) error {
-	return setFlagsFilter(probe, tableName, flags...)
-}
-
 func approveProcessFilename(probe *Probe, tableName string, filename string) error {
 	fileinfo, err := os.Stat(filename)
 	if err != nil {
@@ -94,11 +122,7 @@ func approveProcessFilename(probe *Probe, tableName string, filename string) err
 	stat, _ := fileinfo.Sys().(*syscall.Stat_t)
 	key := ebpf.Uint64TableItem(uint64(stat.Ino))
 
-	table := probe.Table(tableName)
-	if err := table.Set(key, ebpf.ZeroUint8TableItem); err != nil {
-		return err
-	}
-	return nil
+	return probe.setApprover(tableName, key, ebpf.ZeroUint8TableItem)
 }
 
 func approveProcessFilenames(probe *Probe, tableName string, filenames ...string) error {
@@ -14,6 +14,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os/user"
 	"path"
 	"strconv"
@@ -49,6 +50,7 @@ var InvalidDiscarders = map[eval.Field][]interface{}{
 	"process.filename":     dentryInvalidDiscarder,
 	"setxattr.filename":    dentryInvalidDiscarder,
 	"removexattr.filename": dentryInvalidDiscarder,
+	"exec.filename":        dentryInvalidDiscarder,
 }
 
 // ErrNotEnoughData is returned when the buffer is too small to unmarshal the event
@@ -64,8 +66,8 @@ func (m *Model) NewEvent() eval.Event {
 
 // ValidateField validates the value of a field
 func (m *Model) ValidateField(key string, field eval.FieldValue) error {
-	// check that all path are absolute
-	if strings.HasSuffix(key, "filename") || strings.HasSuffix(key, "_path") {
+	// check that all path are absolute, regular expressions are not paths
+	if (strings.HasSuffix(key, "filename") || strings.HasSuffix(key, "_path")) && field.Type != eval.RegexpValueType {
 		value, ok := field.Value.(string)
 		if ok {
 			if value != path.Clean(value) || !path.IsAbs(value) {
@@ -106,7 +108,7 @@ func (e *BaseEvent) marshalJSON(eventType EventType, resolvers *Resolvers) ([]by
 	var buf bytes.Buffer
 	buf.WriteRune('{')
 	fmt.Fprintf(&buf, `"type":"%s",`, eventType.String())
-	fmt.Fprintf(&buf, `"timestamp":"%s",`, e.ResolveMonotonicTimestamp(resolvers))
+	fmt.Fprintf(&buf, `"timestamp":"%s",`, e.ResolveMonotonicTimestamp(resolvers).Format(time.RFC3339Nano))
 	fmt.Fprintf(&buf, `"retval":%d`, e.Retval)
 	buf.WriteRune('}')
 
@@ -495,6 +497,10 @@ type UtimesEvent struct {
 	FileEvent
 	Atime time.Time
 	Mtime time.Time
+
+	// access and modification times in nanoseconds, ex: utimes.mtime < 5m
+	AtimeNsec int64 `field:"atime,timestamp"`
+	MtimeNsec int64 `field:"mtime,timestamp"`
 }
 
 func (e *UtimesEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
@@ -505,8 +511,8 @@ func (e *UtimesEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
 	fmt.Fprintf(&buf, `"inode":%d,`, e.Inode)
 	fmt.Fprintf(&buf, `"mount_id":%d,`, e.MountID)
 	fmt.Fprintf(&buf, `"overlay_numlower":%d,`, e.OverlayNumLower)
-	fmt.Fprintf(&buf, `"access_time":"%s",`, e.Atime)
-	fmt.Fprintf(&buf, `"modification_time":"%s"`, e.Mtime)
+	fmt.Fprintf(&buf, `"access_time":"%s",`, e.Atime.Format(time.RFC3339Nano))
+	fmt.Fprintf(&buf, `"modification_time":"%s"`, e.Mtime.Format(time.RFC3339Nano))
 	buf.WriteRune('}')
 
 	return buf.Bytes(), nil
@@ -527,10 +533,12 @@ func (e *UtimesEvent) UnmarshalBinary(data []byte) (int, error) {
 	timeSec := byteOrder.Uint64(data[0:8])
 	timeNsec := byteOrder.Uint64(data[8:16])
 	e.Atime = time.Unix(int64(timeSec), int64(timeNsec))
+	e.AtimeNsec = e.Atime.UnixNano()
 
 	timeSec = byteOrder.Uint64(data[16:24])
 	timeNsec = byteOrder.Uint64(data[24:32])
 	e.Mtime = time.Unix(int64(timeSec), int64(timeNsec))
+	e.MtimeNsec = e.Mtime.UnixNano()
 
 	return n + 32, nil
 }
@@ -666,6 +674,252 @@ func (e *UmountEvent) UnmarshalBinary(data []byte) (int, error) {
 	return 4, nil
 }
 
+// ExecEvent represents a process execution event
+type ExecEvent struct {
+	BaseEvent
+	FileEvent
+	Args          string `field:"args" handler:"ResolveArgs,string"`
+	Envs          string `field:"envs" handler:"ResolveEnvs,string"`
+	ArgsTruncated bool   `field:"args_truncated"`
+	EnvsTruncated bool   `field:"envs_truncated"`
+
+	ArgsArray []string `field:"-"`
+	EnvsArray []string `field:"-"`
+}
+
+func (e *ExecEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
+	args, err := json.Marshal(e.ArgsArray)
+	if err != nil {
+		return nil, err
+	}
+	envs, err := json.Marshal(e.ResolveEnvsArray(resolvers))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteRune('{')
+	fmt.Fprintf(&buf, `"filename":"%s",`, e.ResolveInode(resolvers))
+	fmt.Fprintf(&buf, `"container_path":"%s",`, e.ResolveContainerPath(resolvers))
+	fmt.Fprintf(&buf, `"inode":%d,`, e.Inode)
+	fmt.Fprintf(&buf, `"mount_id":%d,`, e.MountID)
+	fmt.Fprintf(&buf, `"overlay_numlower":%d,`, e.OverlayNumLower)
+	fmt.Fprintf(&buf, `"args":%s,`, args)
+	fmt.Fprintf(&buf, `"args_truncated":%t,`, e.ArgsTruncated)
+	fmt.Fprintf(&buf, `"envs":%s,`, envs)
+	fmt.Fprintf(&buf, `"envs_truncated":%t`, e.EnvsTruncated)
+	buf.WriteRune('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *ExecEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := unmarshalBinary(data, &e.BaseEvent, &e.FileEvent)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < 16+2*maxExecArgs*maxExecArgLen {
+		return n, ErrNotEnoughData
+	}
+
+	argsCount := byteOrder.Uint32(data[0:4])
+	envsCount := byteOrder.Uint32(data[4:8])
+	e.ArgsTruncated = data[8] != 0
+	e.EnvsTruncated = data[9] != 0
+
+	// Notes: bytes 10 to 16 are used to pad the structure
+
+	read := 16
+	e.ArgsArray, read = unmarshalExecStrings(data, read, argsCount)
+	e.EnvsArray, read = unmarshalExecStrings(data, read, envsCount)
+
+	return n + read, nil
+}
+
+func unmarshalExecStrings(data []byte, offset int, count uint32) ([]string, int) {
+	if count > maxExecArgs {
+		count = maxExecArgs
+	}
+
+	strs := make([]string, 0, count)
+	for i := 0; i < int(count); i++ {
+		str := data[offset+i*maxExecArgLen : offset+(i+1)*maxExecArgLen]
+		if end := bytes.IndexByte(str, 0); end >= 0 {
+			str = str[:end]
+		}
+		strs = append(strs, string(str))
+	}
+	return strs, offset + maxExecArgs*maxExecArgLen
+}
+
+// ResolveArgs resolves the arguments of the process, without argv[0], as a single string
+func (e *ExecEvent) ResolveArgs(resolvers *Resolvers) string {
+	if len(e.Args) == 0 && len(e.ArgsArray) > 1 {
+		e.Args = strings.Join(e.ArgsArray[1:], " ")
+	}
+	return e.Args
+}
+
+// ResolveEnvsArray resolves the environment variables of the process, with the values
+// of the denylisted variables scrubbed
+func (e *ExecEvent) ResolveEnvsArray(resolvers *Resolvers) []string {
+	var scrubber *envsScrubber
+	if resolvers != nil {
+		scrubber = resolvers.envsScrubber
+	}
+
+	envs := make([]string, len(e.EnvsArray))
+	for i, env := range e.EnvsArray {
+		envs[i] = scrubber.scrub(env)
+	}
+	return envs
+}
+
+// ResolveEnvs resolves the environment variables of the process as a single string
+func (e *ExecEvent) ResolveEnvs(resolvers *Resolvers) string {
+	if len(e.Envs) == 0 && len(e.EnvsArray) > 0 {
+		e.Envs = strings.Join(e.ResolveEnvsArray(resolvers), " ")
+	}
+	return e.Envs
+}
+
+// ExitEvent represents a process exit event
+type ExitEvent struct {
+	BaseEvent
+	Code uint32 `field:"code" handler:"ResolveCode,int"`
+}
+
+func (e *ExitEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteRune('{')
+	fmt.Fprintf(&buf, `"code":%d`, e.ResolveCode(resolvers))
+	buf.WriteRune('}')
+
+	return buf.Bytes(), nil
+}
+
+// ResolveCode resolves the exit code of the process from the raw status passed to do_exit.
+// Processes terminated by a signal get the 128 + signal number code reported by shells.
+func (e *ExitEvent) ResolveCode(resolvers *Resolvers) uint32 {
+	if e.Code == 0 {
+		status := syscall.WaitStatus(e.Retval)
+		if status.Signaled() {
+			e.Code = 128 + uint32(status.Signal())
+		} else {
+			e.Code = uint32(status.ExitStatus())
+		}
+	}
+	return e.Code
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *ExitEvent) UnmarshalBinary(data []byte) (int, error) {
+	return unmarshalBinary(data, &e.BaseEvent)
+}
+
+// SocketEvent represents a socket event (connect, bind)
+type SocketEvent struct {
+	BaseEvent
+	Family   uint16 `field:"family"`
+	Port     uint16 `field:"port"`
+	Protocol uint16 `field:"protocol"`
+	IP       string `field:"ip,address" handler:"ResolveIP,string"`
+
+	Addr [16]byte `field:"-"`
+}
+
+func (e *SocketEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteRune('{')
+	fmt.Fprintf(&buf, `"family":"%s",`, AddressFamily(e.Family))
+	fmt.Fprintf(&buf, `"ip":"%s",`, e.ResolveIP(resolvers))
+	fmt.Fprintf(&buf, `"port":%d,`, e.Port)
+	fmt.Fprintf(&buf, `"protocol":"%s"`, Protocol(e.Protocol))
+	buf.WriteRune('}')
+
+	return buf.Bytes(), nil
+}
+
+// ResolveIP resolves the IP address of the socket event
+func (e *SocketEvent) ResolveIP(resolvers *Resolvers) string {
+	if len(e.IP) == 0 {
+		switch e.Family {
+		case syscall.AF_INET:
+			e.IP = net.IP(e.Addr[:4]).String()
+		case syscall.AF_INET6:
+			e.IP = net.IP(e.Addr[:]).String()
+		}
+	}
+	return e.IP
+}
+
+// socketInfoSize is the size of the socket_info_t structure following the base event
+const socketInfoSize = 32
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *SocketEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := unmarshalBinary(data, &e.BaseEvent)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < socketInfoSize {
+		return n, ErrNotEnoughData
+	}
+	e.unmarshalSocketInfo(data)
+
+	return n + socketInfoSize, nil
+}
+
+// unmarshalSocketInfo decodes the socket_info_t structure
+func (e *SocketEvent) unmarshalSocketInfo(data []byte) {
+	e.Family = byteOrder.Uint16(data[0:2])
+	e.Port = byteOrder.Uint16(data[2:4])
+	e.Protocol = byteOrder.Uint16(data[4:6])
+	// Notes: bytes 6 to 8 are used to pad the structure, bytes 8 to 16 hold the listen backlog
+	copy(e.Addr[:], data[16:32])
+}
+
+// ListenEvent represents a socket listen event
+type ListenEvent struct {
+	SocketEvent
+	Backlog uint32 `field:"backlog"`
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *ListenEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := unmarshalBinary(data, &e.BaseEvent)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < socketInfoSize {
+		return n, ErrNotEnoughData
+	}
+	e.unmarshalSocketInfo(data)
+	e.Backlog = byteOrder.Uint32(data[8:12])
+
+	return n + socketInfoSize, nil
+}
+
+func (e *ListenEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteRune('{')
+	fmt.Fprintf(&buf, `"family":"%s",`, AddressFamily(e.Family))
+	fmt.Fprintf(&buf, `"ip":"%s",`, e.ResolveIP(resolvers))
+	fmt.Fprintf(&buf, `"port":%d,`, e.Port)
+	fmt.Fprintf(&buf, `"protocol":"%s",`, Protocol(e.Protocol))
+	fmt.Fprintf(&buf, `"backlog":%d`, e.Backlog)
+	buf.WriteRune('}')
+
+	return buf.Bytes(), nil
+}
+
 // ContainerEvent holds the container context of an event
 type ContainerEvent struct {
 	ID string `field:"id" handler:"ResolveContainerID,string"`
@@ -724,8 +978,15 @@ type ProcessEvent struct {
 	User    string `field:"user" handler:"ResolveUser,string"`
 	Group   string `field:"group" handler:"ResolveGroup,string"`
 
-	CommRaw    [16]byte `field:"-"`
-	TTYNameRaw [64]byte `field:"-"`
+	AncestorsNames     []string `field:"ancestors.name" handler:"ResolveAncestorsNames,[]string"`
+	AncestorsFilenames []string `field:"ancestors.filename" handler:"ResolveAncestorsFilenames,[]string"`
+
+	CommRaw    [16]byte           `field:"-"`
+	TTYNameRaw [64]byte           `field:"-"`
+	Cookie     uint32             `field:"-"`
+	Ancestors  []*ProcessAncestor `field:"-"`
+
+	ancestorsResolved bool `field:"-"`
 }
 
 func (p *ProcessEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
@@ -740,11 +1001,59 @@ func (p *ProcessEvent) marshalJSON(resolvers *Resolvers) ([]byte, error) {
 	fmt.Fprintf(&buf, `"tid":%d,`, p.Tid)
 	fmt.Fprintf(&buf, `"uid":%d,`, p.UID)
 	fmt.Fprintf(&buf, `"gid":%d`, p.GID)
+	if ancestors := p.ResolveAncestors(resolvers); len(ancestors) > 0 {
+		buf.WriteString(`,"ancestors":[`)
+		for i, ancestor := range ancestors {
+			if i > 0 {
+				buf.WriteRune(',')
+			}
+			fmt.Fprintf(&buf, `{"pid":%d,"name":"%s"`, ancestor.Pid, ancestor.Comm)
+			if filename := ancestor.ResolveInode(resolvers); filename != "" {
+				fmt.Fprintf(&buf, `,"filename":"%s"`, filename)
+			}
+			buf.WriteRune('}')
+		}
+		buf.WriteRune(']')
+	}
 	buf.WriteRune('}')
 
 	return buf.Bytes(), nil
 }
 
+// ResolveAncestors resolves the ancestors of the process. The process cache is only
+// walked once per event, even when the process has no known ancestor.
+func (p *ProcessEvent) ResolveAncestors(resolvers *Resolvers) []*ProcessAncestor {
+	if !p.ancestorsResolved && p.Ancestors == nil && resolvers != nil {
+		p.Ancestors = resolvers.ResolveAncestors(p.Cookie)
+		p.ancestorsResolved = true
+	}
+	return p.Ancestors
+}
+
+// ResolveAncestorsNames resolves the names of the ancestors of the process
+func (p *ProcessEvent) ResolveAncestorsNames(resolvers *Resolvers) []string {
+	if p.AncestorsNames == nil {
+		ancestors := p.ResolveAncestors(resolvers)
+		p.AncestorsNames = make([]string, len(ancestors))
+		for i, ancestor := range ancestors {
+			p.AncestorsNames[i] = ancestor.Comm
+		}
+	}
+	return p.AncestorsNames
+}
+
+// ResolveAncestorsFilenames resolves the paths of the executables of the ancestors of the process
+func (p *ProcessEvent) ResolveAncestorsFilenames(resolvers *Resolvers) []string {
+	if p.AncestorsFilenames == nil {
+		ancestors := p.ResolveAncestors(resolvers)
+		p.AncestorsFilenames = make([]string, len(ancestors))
+		for i, ancestor := range ancestors {
+			p.AncestorsFilenames[i] = ancestor.ResolveInode(resolvers)
+		}
+	}
+	return p.AncestorsFilenames
+}
+
 // ResolveTTY resolves the name of the process tty
 func (p *ProcessEvent) ResolveTTY(resolvers *Resolvers) string {
 	return p.GetTTY()
@@ -791,7 +1100,7 @@ func (p *ProcessEvent) ResolveGroup(resolvers *Resolvers) string {
 
 // UnmarshalBinary unmarshals a binary representation of itself
 func (p *ProcessEvent) UnmarshalBinary(data []byte) (int, error) {
-	if len(data) < 120 {
+	if len(data) < 128 {
 		return 0, ErrNotEnoughData
 	}
 	p.Pidns = byteOrder.Uint64(data[0:8])
@@ -810,7 +1119,34 @@ func (p *ProcessEvent) UnmarshalBinary(data []byte) (int, error) {
 	if err != nil {
 		return 104 + read, err
 	}
-	return 104 + read, nil
+	p.Cookie = byteOrder.Uint32(data[120:124])
+	return 128, nil
+}
+
+// ProcessAncestor holds the process cache entry of an ancestor of a process
+type ProcessAncestor struct {
+	FileEvent
+	ParentCookie uint32
+	Pid          uint32
+	Comm         string
+}
+
+// UnmarshalBinary unmarshals the binary representation of a process cache entry
+func (p *ProcessAncestor) UnmarshalBinary(data []byte) (int, error) {
+	if len(data) < 104 {
+		return 0, ErrNotEnoughData
+	}
+
+	read, err := p.FileEvent.UnmarshalBinary(data)
+	if err != nil {
+		return read, err
+	}
+
+	// skip the container ID
+	p.ParentCookie = byteOrder.Uint32(data[80:84])
+	p.Pid = byteOrder.Uint32(data[84:88])
+	p.Comm = string(bytes.Trim(data[88:104], "\x00"))
+	return 104, nil
 }
 
 // Event represents an event sent from the kernel
@@ -832,6 +1168,11 @@ type Event struct {
 	Link        LinkEvent      `yaml:"link" field:"link" event:"link"`
 	SetXAttr    SetXAttrEvent  `yaml:"setxattr" field:"setxattr" event:"setxattr"`
 	RemoveXAttr SetXAttrEvent  `yaml:"removexattr" field:"removexattr" event:"removexattr"`
+	Exec        ExecEvent      `yaml:"exec" field:"exec" event:"exec"`
+	Exit        ExitEvent      `yaml:"exit" field:"exit" event:"exit"`
+	Connect     SocketEvent    `yaml:"connect" field:"connect" event:"connect"`
+	Bind        SocketEvent    `yaml:"bind" field:"bind" event:"bind"`
+	Listen      ListenEvent    `yaml:"listen" field:"listen" event:"listen"`
 	Mount       MountEvent     `yaml:"mount" field:"-"`
 	Umount      UmountEvent    `yaml:"umount" field:"-"`
 
@@ -1020,6 +1361,56 @@ func (e *Event) MarshalJSON() ([]byte, error) {
 				field:      "file",
 				marshalFnc: e.RemoveXAttr.marshalJSON,
 			})
+	case ExecEventType:
+		entries = append(entries,
+			eventMarshaler{
+				field:      "syscall",
+				marshalFnc: eventMarshalJSON(&e.Exec.BaseEvent),
+			},
+			eventMarshaler{
+				field:      "exec",
+				marshalFnc: e.Exec.marshalJSON,
+			})
+	case ExitEventType:
+		entries = append(entries,
+			eventMarshaler{
+				field:      "syscall",
+				marshalFnc: eventMarshalJSON(&e.Exit.BaseEvent),
+			},
+			eventMarshaler{
+				field:      "exit",
+				marshalFnc: e.Exit.marshalJSON,
+			})
+	case ConnectEventType:
+		entries = append(entries,
+			eventMarshaler{
+				field:      "syscall",
+				marshalFnc: eventMarshalJSON(&e.Connect.BaseEvent),
+			},
+			eventMarshaler{
+				field:      "connect",
+				marshalFnc: e.Connect.marshalJSON,
+			})
+	case BindEventType:
+		entries = append(entries,
+			eventMarshaler{
+				field:      "syscall",
+				marshalFnc: eventMarshalJSON(&e.Bind.BaseEvent),
+			},
+			eventMarshaler{
+				field:      "bind",
+				marshalFnc: e.Bind.marshalJSON,
+			})
+	case ListenEventType:
+		entries = append(entries,
+			eventMarshaler{
+				field:      "syscall",
+				marshalFnc: eventMarshalJSON(&e.Listen.BaseEvent),
+			},
+			eventMarshaler{
+				field:      "listen",
+				marshalFnc: e.Listen.marshalJSON,
+			})
 	}
 
 	var prev bool
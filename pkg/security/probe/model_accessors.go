@@ -13,6 +13,50 @@ import (
 func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 	switch field {
 
+	case "bind.family":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Bind.Family) },
+
+			Field: field,
+		}, nil
+
+	case "bind.ip":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Bind.ResolveIP((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+
+			IsAddress: true,
+		}, nil
+
+	case "bind.port":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Bind.Port) },
+
+			Field: field,
+		}, nil
+
+	case "bind.protocol":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Bind.Protocol) },
+
+			Field: field,
+		}, nil
+
+	case "bind.retval":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Bind.Retval) },
+
+			Field: field,
+		}, nil
+
 	case "chmod.basename":
 
 		return &eval.StringEvaluator{
@@ -145,6 +189,50 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 			Field: field,
 		}, nil
 
+	case "connect.family":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Connect.Family) },
+
+			Field: field,
+		}, nil
+
+	case "connect.ip":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Connect.ResolveIP((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+
+			IsAddress: true,
+		}, nil
+
+	case "connect.port":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Connect.Port) },
+
+			Field: field,
+		}, nil
+
+	case "connect.protocol":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Connect.Protocol) },
+
+			Field: field,
+		}, nil
+
+	case "connect.retval":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Connect.Retval) },
+
+			Field: field,
+		}, nil
+
 	case "container.id":
 
 		return &eval.StringEvaluator{
@@ -155,6 +243,114 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 			Field: field,
 		}, nil
 
+	case "exec.args":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Exec.ResolveArgs((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+		}, nil
+
+	case "exec.args_truncated":
+
+		return &eval.BoolEvaluator{
+			EvalFnc: func(ctx *eval.Context) bool { return (*Event)(ctx.Object).Exec.ArgsTruncated },
+
+			Field: field,
+		}, nil
+
+	case "exec.basename":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Exec.ResolveBasename((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+		}, nil
+
+	case "exec.container_path":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Exec.ResolveContainerPath((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+		}, nil
+
+	case "exec.envs":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Exec.ResolveEnvs((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+		}, nil
+
+	case "exec.envs_truncated":
+
+		return &eval.BoolEvaluator{
+			EvalFnc: func(ctx *eval.Context) bool { return (*Event)(ctx.Object).Exec.EnvsTruncated },
+
+			Field: field,
+		}, nil
+
+	case "exec.filename":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Exec.ResolveInode((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+		}, nil
+
+	case "exec.inode":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Exec.Inode) },
+
+			Field: field,
+		}, nil
+
+	case "exec.overlay_numlower":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Exec.OverlayNumLower) },
+
+			Field: field,
+		}, nil
+
+	case "exec.retval":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Exec.Retval) },
+
+			Field: field,
+		}, nil
+
+	case "exit.code":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Exit.ResolveCode((*Event)(ctx.Object).resolvers))
+			},
+
+			Field: field,
+		}, nil
+
+	case "exit.retval":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Exit.Retval) },
+
+			Field: field,
+		}, nil
+
 	case "link.retval":
 
 		return &eval.IntEvaluator{
@@ -255,6 +451,58 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 			Field: field,
 		}, nil
 
+	case "listen.backlog":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Listen.Backlog) },
+
+			Field: field,
+		}, nil
+
+	case "listen.family":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Listen.Family) },
+
+			Field: field,
+		}, nil
+
+	case "listen.ip":
+
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+				return (*Event)(ctx.Object).Listen.ResolveIP((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+
+			IsAddress: true,
+		}, nil
+
+	case "listen.port":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Listen.Port) },
+
+			Field: field,
+		}, nil
+
+	case "listen.protocol":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Listen.Protocol) },
+
+			Field: field,
+		}, nil
+
+	case "listen.retval":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Listen.Retval) },
+
+			Field: field,
+		}, nil
+
 	case "mkdir.basename":
 
 		return &eval.StringEvaluator{
@@ -387,6 +635,26 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 			Field: field,
 		}, nil
 
+	case "process.ancestors.filename":
+
+		return &eval.StringArrayEvaluator{
+			EvalFnc: func(ctx *eval.Context) []string {
+				return (*Event)(ctx.Object).Process.ResolveAncestorsFilenames((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+		}, nil
+
+	case "process.ancestors.name":
+
+		return &eval.StringArrayEvaluator{
+			EvalFnc: func(ctx *eval.Context) []string {
+				return (*Event)(ctx.Object).Process.ResolveAncestorsNames((*Event)(ctx.Object).resolvers)
+			},
+
+			Field: field,
+		}, nil
+
 	case "process.basename":
 
 		return &eval.StringEvaluator{
@@ -877,6 +1145,16 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 			Field: field,
 		}, nil
 
+	case "utimes.atime":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Utimes.AtimeNsec) },
+
+			Field: field,
+
+			IsTimestamp: true,
+		}, nil
+
 	case "utimes.basename":
 
 		return &eval.StringEvaluator{
@@ -915,6 +1193,16 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 			Field: field,
 		}, nil
 
+	case "utimes.mtime":
+
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return int((*Event)(ctx.Object).Utimes.MtimeNsec) },
+
+			Field: field,
+
+			IsTimestamp: true,
+		}, nil
+
 	case "utimes.overlay_numlower":
 
 		return &eval.IntEvaluator{
@@ -939,6 +1227,26 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 	switch field {
 
+	case "bind.family":
+
+		return int(e.Bind.Family), nil
+
+	case "bind.ip":
+
+		return e.Bind.ResolveIP(e.resolvers), nil
+
+	case "bind.port":
+
+		return int(e.Bind.Port), nil
+
+	case "bind.protocol":
+
+		return int(e.Bind.Protocol), nil
+
+	case "bind.retval":
+
+		return int(e.Bind.Retval), nil
+
 	case "chmod.basename":
 
 		return e.Chmod.ResolveBasename(e.resolvers), nil
@@ -999,10 +1307,78 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Chown.UID), nil
 
+	case "connect.family":
+
+		return int(e.Connect.Family), nil
+
+	case "connect.ip":
+
+		return e.Connect.ResolveIP(e.resolvers), nil
+
+	case "connect.port":
+
+		return int(e.Connect.Port), nil
+
+	case "connect.protocol":
+
+		return int(e.Connect.Protocol), nil
+
+	case "connect.retval":
+
+		return int(e.Connect.Retval), nil
+
 	case "container.id":
 
 		return e.Container.ResolveContainerID(e.resolvers), nil
 
+	case "exec.args":
+
+		return e.Exec.ResolveArgs(e.resolvers), nil
+
+	case "exec.args_truncated":
+
+		return e.Exec.ArgsTruncated, nil
+
+	case "exec.basename":
+
+		return e.Exec.ResolveBasename(e.resolvers), nil
+
+	case "exec.container_path":
+
+		return e.Exec.ResolveContainerPath(e.resolvers), nil
+
+	case "exec.envs":
+
+		return e.Exec.ResolveEnvs(e.resolvers), nil
+
+	case "exec.envs_truncated":
+
+		return e.Exec.EnvsTruncated, nil
+
+	case "exec.filename":
+
+		return e.Exec.ResolveInode(e.resolvers), nil
+
+	case "exec.inode":
+
+		return int(e.Exec.Inode), nil
+
+	case "exec.overlay_numlower":
+
+		return int(e.Exec.OverlayNumLower), nil
+
+	case "exec.retval":
+
+		return int(e.Exec.Retval), nil
+
+	case "exit.code":
+
+		return int(e.Exit.ResolveCode(e.resolvers)), nil
+
+	case "exit.retval":
+
+		return int(e.Exit.Retval), nil
+
 	case "link.retval":
 
 		return int(e.Link.Retval), nil
@@ -1047,6 +1423,30 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Link.Target.OverlayNumLower), nil
 
+	case "listen.backlog":
+
+		return int(e.Listen.Backlog), nil
+
+	case "listen.family":
+
+		return int(e.Listen.Family), nil
+
+	case "listen.ip":
+
+		return e.Listen.ResolveIP(e.resolvers), nil
+
+	case "listen.port":
+
+		return int(e.Listen.Port), nil
+
+	case "listen.protocol":
+
+		return int(e.Listen.Protocol), nil
+
+	case "listen.retval":
+
+		return int(e.Listen.Retval), nil
+
 	case "mkdir.basename":
 
 		return e.Mkdir.ResolveBasename(e.resolvers), nil
@@ -1107,6 +1507,14 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Open.Retval), nil
 
+	case "process.ancestors.filename":
+
+		return e.Process.ResolveAncestorsFilenames(e.resolvers), nil
+
+	case "process.ancestors.name":
+
+		return e.Process.ResolveAncestorsNames(e.resolvers), nil
+
 	case "process.basename":
 
 		return e.Process.ResolveBasename(e.resolvers), nil
@@ -1323,6 +1731,10 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Unlink.Retval), nil
 
+	case "utimes.atime":
+
+		return int(e.Utimes.AtimeNsec), nil
+
 	case "utimes.basename":
 
 		return e.Utimes.ResolveBasename(e.resolvers), nil
@@ -1339,6 +1751,10 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Utimes.Inode), nil
 
+	case "utimes.mtime":
+
+		return int(e.Utimes.MtimeNsec), nil
+
 	case "utimes.overlay_numlower":
 
 		return int(e.Utimes.OverlayNumLower), nil
@@ -1355,6 +1771,21 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	switch field {
 
+	case "bind.family":
+		return "bind", nil
+
+	case "bind.ip":
+		return "bind", nil
+
+	case "bind.port":
+		return "bind", nil
+
+	case "bind.protocol":
+		return "bind", nil
+
+	case "bind.retval":
+		return "bind", nil
+
 	case "chmod.basename":
 		return "chmod", nil
 
@@ -1400,9 +1831,60 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "chown.uid":
 		return "chown", nil
 
+	case "connect.family":
+		return "connect", nil
+
+	case "connect.ip":
+		return "connect", nil
+
+	case "connect.port":
+		return "connect", nil
+
+	case "connect.protocol":
+		return "connect", nil
+
+	case "connect.retval":
+		return "connect", nil
+
 	case "container.id":
 		return "*", nil
 
+	case "exec.args":
+		return "exec", nil
+
+	case "exec.args_truncated":
+		return "exec", nil
+
+	case "exec.basename":
+		return "exec", nil
+
+	case "exec.container_path":
+		return "exec", nil
+
+	case "exec.envs":
+		return "exec", nil
+
+	case "exec.envs_truncated":
+		return "exec", nil
+
+	case "exec.filename":
+		return "exec", nil
+
+	case "exec.inode":
+		return "exec", nil
+
+	case "exec.overlay_numlower":
+		return "exec", nil
+
+	case "exec.retval":
+		return "exec", nil
+
+	case "exit.code":
+		return "exit", nil
+
+	case "exit.retval":
+		return "exit", nil
+
 	case "link.retval":
 		return "link", nil
 
@@ -1436,6 +1918,24 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "link.target.overlay_numlower":
 		return "link", nil
 
+	case "listen.backlog":
+		return "listen", nil
+
+	case "listen.family":
+		return "listen", nil
+
+	case "listen.ip":
+		return "listen", nil
+
+	case "listen.port":
+		return "listen", nil
+
+	case "listen.protocol":
+		return "listen", nil
+
+	case "listen.retval":
+		return "listen", nil
+
 	case "mkdir.basename":
 		return "mkdir", nil
 
@@ -1481,6 +1981,12 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "open.retval":
 		return "open", nil
 
+	case "process.ancestors.filename":
+		return "*", nil
+
+	case "process.ancestors.name":
+		return "*", nil
+
 	case "process.basename":
 		return "*", nil
 
@@ -1643,6 +2149,9 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "unlink.retval":
 		return "unlink", nil
 
+	case "utimes.atime":
+		return "utimes", nil
+
 	case "utimes.basename":
 		return "utimes", nil
 
@@ -1655,19 +2164,42 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "utimes.inode":
 		return "utimes", nil
 
+	case "utimes.mtime":
+		return "utimes", nil
+
 	case "utimes.overlay_numlower":
 		return "utimes", nil
 
-	case "utimes.retval":
-		return "utimes", nil
+	case "utimes.retval":
+		return "utimes", nil
+
+	}
+
+	return "", &eval.ErrFieldNotFound{Field: field}
+}
+
+func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
+	switch field {
+
+	case "bind.family":
+
+		return reflect.Int, nil
+
+	case "bind.ip":
+
+		return reflect.String, nil
+
+	case "bind.port":
+
+		return reflect.Int, nil
 
-	}
+	case "bind.protocol":
 
-	return "", &eval.ErrFieldNotFound{Field: field}
-}
+		return reflect.Int, nil
 
-func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
-	switch field {
+	case "bind.retval":
+
+		return reflect.Int, nil
 
 	case "chmod.basename":
 
@@ -1729,10 +2261,78 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "connect.family":
+
+		return reflect.Int, nil
+
+	case "connect.ip":
+
+		return reflect.String, nil
+
+	case "connect.port":
+
+		return reflect.Int, nil
+
+	case "connect.protocol":
+
+		return reflect.Int, nil
+
+	case "connect.retval":
+
+		return reflect.Int, nil
+
 	case "container.id":
 
 		return reflect.String, nil
 
+	case "exec.args":
+
+		return reflect.String, nil
+
+	case "exec.args_truncated":
+
+		return reflect.Bool, nil
+
+	case "exec.basename":
+
+		return reflect.String, nil
+
+	case "exec.container_path":
+
+		return reflect.String, nil
+
+	case "exec.envs":
+
+		return reflect.String, nil
+
+	case "exec.envs_truncated":
+
+		return reflect.Bool, nil
+
+	case "exec.filename":
+
+		return reflect.String, nil
+
+	case "exec.inode":
+
+		return reflect.Int, nil
+
+	case "exec.overlay_numlower":
+
+		return reflect.Int, nil
+
+	case "exec.retval":
+
+		return reflect.Int, nil
+
+	case "exit.code":
+
+		return reflect.Int, nil
+
+	case "exit.retval":
+
+		return reflect.Int, nil
+
 	case "link.retval":
 
 		return reflect.Int, nil
@@ -1777,6 +2377,30 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "listen.backlog":
+
+		return reflect.Int, nil
+
+	case "listen.family":
+
+		return reflect.Int, nil
+
+	case "listen.ip":
+
+		return reflect.String, nil
+
+	case "listen.port":
+
+		return reflect.Int, nil
+
+	case "listen.protocol":
+
+		return reflect.Int, nil
+
+	case "listen.retval":
+
+		return reflect.Int, nil
+
 	case "mkdir.basename":
 
 		return reflect.String, nil
@@ -1837,6 +2461,14 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "process.ancestors.filename":
+
+		return reflect.Slice, nil
+
+	case "process.ancestors.name":
+
+		return reflect.Slice, nil
+
 	case "process.basename":
 
 		return reflect.String, nil
@@ -2053,6 +2685,10 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "utimes.atime":
+
+		return reflect.Int, nil
+
 	case "utimes.basename":
 
 		return reflect.String, nil
@@ -2069,6 +2705,10 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "utimes.mtime":
+
+		return reflect.Int, nil
+
 	case "utimes.overlay_numlower":
 
 		return reflect.Int, nil
@@ -2086,6 +2726,49 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 	var ok bool
 	switch field {
 
+	case "bind.family":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Family"}
+		}
+		e.Bind.Family = uint16(v)
+		return nil
+
+	case "bind.ip":
+
+		if e.Bind.IP, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.IP"}
+		}
+		return nil
+
+	case "bind.port":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Port"}
+		}
+		e.Bind.Port = uint16(v)
+		return nil
+
+	case "bind.protocol":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Protocol"}
+		}
+		e.Bind.Protocol = uint16(v)
+		return nil
+
+	case "bind.retval":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Retval"}
+		}
+		e.Bind.Retval = int64(v)
+		return nil
+
 	case "chmod.basename":
 
 		if e.Chmod.BasenameStr, ok = value.(string); !ok {
@@ -2209,6 +2892,49 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Chown.UID = int32(v)
 		return nil
 
+	case "connect.family":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Family"}
+		}
+		e.Connect.Family = uint16(v)
+		return nil
+
+	case "connect.ip":
+
+		if e.Connect.IP, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.IP"}
+		}
+		return nil
+
+	case "connect.port":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Port"}
+		}
+		e.Connect.Port = uint16(v)
+		return nil
+
+	case "connect.protocol":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Protocol"}
+		}
+		e.Connect.Protocol = uint16(v)
+		return nil
+
+	case "connect.retval":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Retval"}
+		}
+		e.Connect.Retval = int64(v)
+		return nil
+
 	case "container.id":
 
 		if e.Container.ID, ok = value.(string); !ok {
@@ -2216,6 +2942,100 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		}
 		return nil
 
+	case "exec.args":
+
+		if e.Exec.Args, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.Args"}
+		}
+		return nil
+
+	case "exec.args_truncated":
+
+		if e.Exec.ArgsTruncated, ok = value.(bool); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.ArgsTruncated"}
+		}
+		return nil
+
+	case "exec.basename":
+
+		if e.Exec.BasenameStr, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.BasenameStr"}
+		}
+		return nil
+
+	case "exec.container_path":
+
+		if e.Exec.ContainerPath, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.ContainerPath"}
+		}
+		return nil
+
+	case "exec.envs":
+
+		if e.Exec.Envs, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.Envs"}
+		}
+		return nil
+
+	case "exec.envs_truncated":
+
+		if e.Exec.EnvsTruncated, ok = value.(bool); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.EnvsTruncated"}
+		}
+		return nil
+
+	case "exec.filename":
+
+		if e.Exec.PathnameStr, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.PathnameStr"}
+		}
+		return nil
+
+	case "exec.inode":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.Inode"}
+		}
+		e.Exec.Inode = uint64(v)
+		return nil
+
+	case "exec.overlay_numlower":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.OverlayNumLower"}
+		}
+		e.Exec.OverlayNumLower = int32(v)
+		return nil
+
+	case "exec.retval":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exec.Retval"}
+		}
+		e.Exec.Retval = int64(v)
+		return nil
+
+	case "exit.code":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exit.Code"}
+		}
+		e.Exit.Code = uint32(v)
+		return nil
+
+	case "exit.retval":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Exit.Retval"}
+		}
+		e.Exit.Retval = int64(v)
+		return nil
+
 	case "link.retval":
 
 		v, ok := value.(int)
@@ -2303,6 +3123,58 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Link.Target.OverlayNumLower = int32(v)
 		return nil
 
+	case "listen.backlog":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Listen.Backlog"}
+		}
+		e.Listen.Backlog = uint32(v)
+		return nil
+
+	case "listen.family":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Listen.Family"}
+		}
+		e.Listen.Family = uint16(v)
+		return nil
+
+	case "listen.ip":
+
+		if e.Listen.IP, ok = value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Listen.IP"}
+		}
+		return nil
+
+	case "listen.port":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Listen.Port"}
+		}
+		e.Listen.Port = uint16(v)
+		return nil
+
+	case "listen.protocol":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Listen.Protocol"}
+		}
+		e.Listen.Protocol = uint16(v)
+		return nil
+
+	case "listen.retval":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Listen.Retval"}
+		}
+		e.Listen.Retval = int64(v)
+		return nil
+
 	case "mkdir.basename":
 
 		if e.Mkdir.BasenameStr, ok = value.(string); !ok {
@@ -2426,6 +3298,30 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Open.Retval = int64(v)
 		return nil
 
+	case "process.ancestors.filename":
+
+		switch v := value.(type) {
+		case string:
+			e.Process.AncestorsFilenames = []string{v}
+		case []string:
+			e.Process.AncestorsFilenames = v
+		default:
+			return &eval.ErrValueTypeMismatch{Field: "Process.AncestorsFilenames"}
+		}
+		return nil
+
+	case "process.ancestors.name":
+
+		switch v := value.(type) {
+		case string:
+			e.Process.AncestorsNames = []string{v}
+		case []string:
+			e.Process.AncestorsNames = v
+		default:
+			return &eval.ErrValueTypeMismatch{Field: "Process.AncestorsNames"}
+		}
+		return nil
+
 	case "process.basename":
 
 		if e.Process.BasenameStr, ok = value.(string); !ok {
@@ -2854,6 +3750,15 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Unlink.Retval = int64(v)
 		return nil
 
+	case "utimes.atime":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Utimes.AtimeNsec"}
+		}
+		e.Utimes.AtimeNsec = int64(v)
+		return nil
+
 	case "utimes.basename":
 
 		if e.Utimes.BasenameStr, ok = value.(string); !ok {
@@ -2884,6 +3789,15 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Utimes.Inode = uint64(v)
 		return nil
 
+	case "utimes.mtime":
+
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Utimes.MtimeNsec"}
+		}
+		e.Utimes.MtimeNsec = int64(v)
+		return nil
+
 	case "utimes.overlay_numlower":
 
 		v, ok := value.(int)
@@ -10,6 +10,8 @@ package probe
 import (
 	"bytes"
 	"encoding/json"
+	"net"
+	"syscall"
 	"testing"
 )
 
@@ -50,3 +52,151 @@ func TestMkdirJSON(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestExecUnmarshalBinary(t *testing.T) {
+	data := make([]byte, 32+16+2*maxExecArgs*maxExecArgLen)
+	byteOrder.PutUint64(data[16:24], 42)
+	byteOrder.PutUint32(data[32:36], 2)
+	byteOrder.PutUint32(data[36:40], 2)
+	data[41] = 1
+
+	offset := 48
+	copy(data[offset:], "ls")
+	copy(data[offset+maxExecArgLen:], "-l")
+	offset += maxExecArgs * maxExecArgLen
+	copy(data[offset:], "HOME=/root")
+	copy(data[offset+maxExecArgLen:], "AWS_SECRET_ACCESS_KEY=abc")
+
+	var e ExecEvent
+	n, err := e.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Errorf("expected %d bytes to be read, got %d", len(data), n)
+	}
+
+	if e.Inode != 42 {
+		t.Errorf("expected inode 42, got %d", e.Inode)
+	}
+	if args := e.ResolveArgs(nil); args != "-l" {
+		t.Errorf("expected args `-l`, got `%s`", args)
+	}
+	if envs := e.ResolveEnvs(&Resolvers{envsScrubber: newEnvsScrubber(nil)}); envs != "HOME=/root AWS_SECRET_ACCESS_KEY=********" {
+		t.Errorf("expected scrubbed envs, got `%s`", envs)
+	}
+	if e.ArgsTruncated || !e.EnvsTruncated {
+		t.Errorf("expected only envs to be truncated")
+	}
+
+	if _, err := e.UnmarshalBinary(data[:100]); err == nil {
+		t.Error("expected an error for a truncated event")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	for raw, code := range map[int64]uint32{
+		0:         0,
+		3 << 8:    3,
+		9:         137,
+		15 | 0x80: 143,
+		255 << 8:  255,
+	} {
+		e := ExitEvent{BaseEvent: BaseEvent{Retval: raw}}
+		if c := e.ResolveCode(nil); c != code {
+			t.Errorf("expected exit code %d for status %d, got %d", code, raw, c)
+		}
+	}
+}
+
+func TestSocketUnmarshalBinary(t *testing.T) {
+	data := make([]byte, 16+32)
+	byteOrder.PutUint16(data[16:18], syscall.AF_INET6)
+	byteOrder.PutUint16(data[18:20], 8080)
+	byteOrder.PutUint16(data[20:22], syscall.IPPROTO_TCP)
+	byteOrder.PutUint32(data[24:28], 128)
+	copy(data[32:48], net.ParseIP("fd00::1"))
+
+	var e ListenEvent
+	n, err := e.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Errorf("expected %d bytes to be read, got %d", len(data), n)
+	}
+
+	if ip := e.ResolveIP(nil); ip != "fd00::1" {
+		t.Errorf("expected IP fd00::1, got %s", ip)
+	}
+	if e.Port != 8080 || e.Protocol != syscall.IPPROTO_TCP || e.Backlog != 128 {
+		t.Errorf("unexpected listen event %+v", e)
+	}
+
+	data = make([]byte, 16+32)
+	byteOrder.PutUint16(data[16:18], syscall.AF_INET)
+	copy(data[32:36], net.ParseIP("10.0.0.1").To4())
+
+	var c SocketEvent
+	if _, err := c.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if ip := c.ResolveIP(nil); ip != "10.0.0.1" {
+		t.Errorf("expected IP 10.0.0.1, got %s", ip)
+	}
+
+	if _, err := c.UnmarshalBinary(data[:20]); err == nil {
+		t.Error("expected an error for a truncated event")
+	}
+	if _, err := e.UnmarshalBinary(data[:40]); err == nil {
+		t.Error("expected an error for a truncated listen event")
+	}
+}
+
+func TestProcessUnmarshalBinary(t *testing.T) {
+	data := make([]byte, 128)
+	byteOrder.PutUint32(data[88:92], 123)
+	byteOrder.PutUint64(data[104:112], 33)
+	byteOrder.PutUint32(data[120:124], 0xdeadbeef)
+
+	var process ProcessEvent
+	read, err := process.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if read != len(data) {
+		t.Errorf("expected %d bytes to be read, got %d", len(data), read)
+	}
+
+	if process.Pid != 123 || process.Inode != 33 || process.Cookie != 0xdeadbeef {
+		t.Errorf("unexpected process context: %+v", process)
+	}
+
+	if _, err := process.UnmarshalBinary(data[:120]); err != ErrNotEnoughData {
+		t.Errorf("expected ErrNotEnoughData, got %v", err)
+	}
+}
+
+func TestProcessAncestorUnmarshalBinary(t *testing.T) {
+	data := make([]byte, 104)
+	byteOrder.PutUint64(data[0:8], 33)
+	byteOrder.PutUint32(data[8:12], 7)
+	byteOrder.PutUint32(data[80:84], 42)
+	byteOrder.PutUint32(data[84:88], 1)
+	copy(data[88:104], "systemd")
+
+	var ancestor ProcessAncestor
+	if _, err := ancestor.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if ancestor.Inode != 33 || ancestor.MountID != 7 || ancestor.ParentCookie != 42 || ancestor.Pid != 1 || ancestor.Comm != "systemd" {
+		t.Errorf("unexpected ancestor: %+v", ancestor)
+	}
+
+	process := ProcessEvent{Ancestors: []*ProcessAncestor{&ancestor}}
+	if names := process.ResolveAncestorsNames(nil); len(names) != 1 || names[0] != "systemd" {
+		t.Errorf("unexpected ancestors names: %v", names)
+	}
+}
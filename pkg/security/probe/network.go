@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/security/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+// networkHookPoints holds the list of hookpoints to track network activity
+var networkHookPoints = []*HookPoint{
+	{
+		Name: "security_socket_connect",
+		KProbes: []*ebpf.KProbe{{
+			EntryFunc: "kprobe/security_socket_connect",
+		}},
+		EventTypes: []eval.EventType{"connect"},
+	},
+	{
+		Name: "sys_connect",
+		KProbes: []*ebpf.KProbe{{
+			ExitFunc: "kretprobe/" + getSyscallFnName("connect"),
+		}},
+		EventTypes: []eval.EventType{"connect"},
+	},
+	{
+		Name: "security_socket_bind",
+		KProbes: []*ebpf.KProbe{{
+			EntryFunc: "kprobe/security_socket_bind",
+		}},
+		EventTypes: []eval.EventType{"bind"},
+	},
+	{
+		Name: "sys_bind",
+		KProbes: []*ebpf.KProbe{{
+			ExitFunc: "kretprobe/" + getSyscallFnName("bind"),
+		}},
+		EventTypes: []eval.EventType{"bind"},
+	},
+	{
+		Name: "security_socket_listen",
+		KProbes: []*ebpf.KProbe{{
+			EntryFunc: "kprobe/security_socket_listen",
+		}},
+		EventTypes: []eval.EventType{"listen"},
+	},
+	{
+		Name: "sys_listen",
+		KProbes: []*ebpf.KProbe{{
+			ExitFunc: "kretprobe/" + getSyscallFnName("listen"),
+		}},
+		EventTypes: []eval.EventType{"listen"},
+	},
+}
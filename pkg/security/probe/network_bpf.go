@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux_bpf
+
+package probe
+
+// networkTables holds the list of eBPF tables used by the network kprobes
+var networkTables = []string{
+	"connect_policy",
+	"bind_policy",
+	"listen_policy",
+}
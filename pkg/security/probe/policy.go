@@ -84,4 +84,9 @@ func (f PolicyFlag) MarshalJSON() ([]byte, error) {
 
 func init() {
 	allPolicyTables["open"] = "open_policy"
+	allPolicyTables["exec"] = "exec_policy"
+	allPolicyTables["exit"] = "exit_policy"
+	allPolicyTables["connect"] = "connect_policy"
+	allPolicyTables["bind"] = "bind_policy"
+	allPolicyTables["listen"] = "listen_policy"
 }
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/DataDog/datadog-go/statsd"
+	"github.com/pkg/errors"
 
 	"github.com/DataDog/datadog-agent/pkg/ebpf/bytecode"
 	"github.com/DataDog/datadog-agent/pkg/security/config"
@@ -55,6 +56,9 @@ type Probe struct {
 	resolvers        *Resolvers
 	onDiscardersFncs map[eval.EventType][]onDiscarderFnc
 	tables           map[string]*ebpf.Table
+	kprobes          map[*ebpf.KProbe]bool
+	tracepoints      map[string]bool
+	approvers        KFilters
 	syscallMonitor   *SyscallMonitor
 	kernelVersion    uint32
 	_                uint32 // padding for goarch=386
@@ -74,6 +78,7 @@ func (p *Probe) getTableNames() []string {
 	tables = append(tables, execTables...)
 	tables = append(tables, unlinkTables...)
 	tables = append(tables, mountTables...)
+	tables = append(tables, networkTables...)
 
 	return tables
 }
@@ -342,6 +347,31 @@ func (p *Probe) handleEvent(data []byte) {
 			log.Errorf("failed to decode removexattr event: %s (offset %d, len %d)", err, offset, len(data))
 			return
 		}
+	case ExecEventType:
+		if _, err := event.Exec.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode exec event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case ExitEventType:
+		if _, err := event.Exit.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode exit event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case ConnectEventType:
+		if _, err := event.Connect.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode connect event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case BindEventType:
+		if _, err := event.Bind.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode bind event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case ListenEventType:
+		if _, err := event.Listen.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode listen event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
 	default:
 		log.Errorf("unsupported event type %d", eventType)
 		return
@@ -386,8 +416,10 @@ func (p *Probe) OnNewDiscarder(rs *rules.RuleSet, event *Event, field eval.Field
 	return nil
 }
 
-// Init initialises the probe
+// Init initialises the probe before a ruleset is applied
 func (p *Probe) Init() error {
+	p.approvers = make(KFilters)
+
 	if !p.config.EnableKernelFilters {
 		log.Warn("Forcing in-kernel filter policy to `pass`: filtering not enabled")
 	}
@@ -425,10 +457,129 @@ func (p *Probe) ApplyApprovers(eventType eval.EventType, approvers rules.Approve
 	return err
 }
 
-// RegisterKProbe register the given kprobe
+// setApprover adds an in-kernel approver and records it as an approver of the ruleset being applied
+func (p *Probe) setApprover(tableName string, key, value ebpf.TableItem) error {
+	table := p.Table(tableName)
+	if table == nil {
+		return fmt.Errorf("unable to find table `%s`", tableName)
+	}
+
+	bKey, err := key.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if err := table.Set(key, value); err != nil {
+		return err
+	}
+	p.approvers.add(tableName, bKey)
+
+	return nil
+}
+
+// GetKFilters returns the keys of the in-kernel approvers and discarders currently set
+func (p *Probe) GetKFilters() (KFilters, error) {
+	kFilters := make(KFilters)
+
+	for _, tableName := range append(kApproverTables, kDiscarderTables...) {
+		table := p.Table(tableName)
+		if table == nil {
+			return nil, fmt.Errorf("unable to find table `%s`", tableName)
+		}
+
+		info, err := table.Info()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get the definition of table `%s`", tableName)
+		}
+
+		// the entries of an array always exist
+		if info.IsArray() {
+			for i := uint32(0); i < info.MaxEntries; i++ {
+				key, _ := ebpf.Uint32TableItem(i).Bytes()
+				kFilters.add(tableName, key)
+			}
+			continue
+		}
+
+		keys, err := table.Keys()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the keys of table `%s`", tableName)
+		}
+		for _, key := range keys {
+			kFilters.add(tableName, key)
+		}
+	}
+
+	return kFilters, nil
+}
+
+// removeKFilter removes an entry of a table of approvers or discarders. Array entries are reset to zero.
+func (p *Probe) removeKFilter(tableName string, key []byte) error {
+	table := p.Table(tableName)
+	if table == nil {
+		return fmt.Errorf("unable to find table `%s`", tableName)
+	}
+
+	info, err := table.Info()
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the definition of table `%s`", tableName)
+	}
+
+	if info.IsArray() {
+		return table.SetP(key, make([]byte, info.ValueSize))
+	}
+
+	// the entry may have been evicted in the meantime
+	if err := table.Delete(key); err != nil {
+		log.Debugf("unable to remove entry from table `%s`: %s", tableName, err)
+	}
+
+	return nil
+}
+
+// RemoveDiscarders removes the given in-kernel discarders. Discarders of a previous ruleset
+// may filter events the rules of the new one depend on, they have to be removed before the
+// new ruleset is applied.
+func (p *Probe) RemoveDiscarders(kFilters KFilters) error {
+	for _, tableName := range kDiscarderTables {
+		for key := range kFilters[tableName] {
+			if err := p.removeKFilter(tableName, []byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RemoveStaleApprovers removes the given in-kernel approvers which weren't set again by the
+// last applied ruleset. Approvers only let more events through, the ones of the previous ruleset
+// are kept until the new ones are set so that no event is filtered in between.
+func (p *Probe) RemoveStaleApprovers(kFilters KFilters) error {
+	for _, tableName := range kApproverTables {
+		for key := range kFilters[tableName] {
+			if p.approvers.contains(tableName, []byte(key)) {
+				continue
+			}
+
+			if err := p.removeKFilter(tableName, []byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisterKProbe register the given kprobe, a kprobe already registered is skipped
 func (p *Probe) RegisterKProbe(kprobe *ebpf.KProbe) error {
+	if p.kprobes[kprobe] {
+		return nil
+	}
+
 	err := p.Module.RegisterKprobe(kprobe)
 	if err == nil {
+		p.kprobes[kprobe] = true
 		log.Infof("kProbe `%s` registered", kprobe.Name)
 	} else {
 		log.Errorf("failed to register kProbe `%s`", kprobe.Name)
@@ -437,10 +588,32 @@ func (p *Probe) RegisterKProbe(kprobe *ebpf.KProbe) error {
 	return err
 }
 
-// RegisterTracepoint registers the given tracepoint
+// UnregisterKProbe unregisters the given kprobe, a kprobe which isn't registered is skipped
+func (p *Probe) UnregisterKProbe(kprobe *ebpf.KProbe) error {
+	if !p.kprobes[kprobe] {
+		return nil
+	}
+
+	err := p.Module.UnregisterKprobe(kprobe)
+	if err == nil {
+		delete(p.kprobes, kprobe)
+		log.Infof("kProbe `%s` unregistered", kprobe.Name)
+	} else {
+		log.Errorf("failed to unregister kProbe `%s`", kprobe.Name)
+	}
+
+	return err
+}
+
+// RegisterTracepoint registers the given tracepoint, a tracepoint already registered is skipped
 func (p *Probe) RegisterTracepoint(tracepoint string) error {
+	if p.tracepoints[tracepoint] {
+		return nil
+	}
+
 	err := p.Module.RegisterTracepoint(tracepoint)
 	if err == nil {
+		p.tracepoints[tracepoint] = true
 		log.Infof("tracepoint `%s` registered", tracepoint)
 	} else {
 		log.Errorf("failed to register tracepoint `%s`", tracepoint)
@@ -460,6 +633,9 @@ func NewProbe(config *config.Config) (*Probe, error) {
 		config:           config,
 		onDiscardersFncs: make(map[eval.EventType][]onDiscarderFnc),
 		tables:           make(map[string]*ebpf.Table),
+		kprobes:          make(map[*ebpf.KProbe]bool),
+		tracepoints:      make(map[string]bool),
+		approvers:        make(KFilters),
 	}
 
 	p.Probe = &ebpf.Probe{
@@ -17,6 +17,7 @@ import (
 // Probe represents the runtime security eBPF probe in charge of
 // setting up the required kProbes and decoding events sent from the kernel
 type Probe struct {
+	config    *config.Config
 	resolvers *Resolvers
 }
 
@@ -40,6 +41,11 @@ func (p *Probe) RegisterKProbe(kprobe *ebpf.KProbe) error {
 	return nil
 }
 
+// UnregisterKProbe unregisters the given kprobe
+func (p *Probe) UnregisterKProbe(kprobe *ebpf.KProbe) error {
+	return nil
+}
+
 // RegisterTracepoint registers the given tracepoint
 func (p *Probe) RegisterTracepoint(tracepoint string) error {
 	return nil
@@ -47,7 +53,9 @@ func (p *Probe) RegisterTracepoint(tracepoint string) error {
 
 // NewProbe instantiates a new runtime security agent probe
 func NewProbe(config *config.Config) (*Probe, error) {
-	p := &Probe{}
+	p := &Probe{
+		config: config,
+	}
 
 	resolvers, err := NewResolvers(p)
 	if err != nil {
@@ -22,5 +22,6 @@ func NewResolvers(probe *Probe) (*Resolvers, error) {
 		DentryResolver: dentryResolver,
 		MountResolver:  NewMountResolver(probe),
 		TimeResolver:   timeResolver,
+		envsScrubber:   newEnvsScrubber(probe.config),
 	}, nil
 }
@@ -9,6 +9,7 @@ package probe
 
 import (
 	"os"
+	"sort"
 	"syscall"
 
 	"github.com/DataDog/gopsutil/process"
@@ -32,20 +33,29 @@ var SnapshotProbes = []*ebpf.KProbe{
 	},
 }
 
+// maxAncestors is the maximum number of ancestors resolved for a process
+const maxAncestors = 64
+
 // ProcCache this structure holds the container context that we keep in kernel for each process
 type ProcCache struct {
-	Inode    uint64
-	Numlower uint32
-	Padding  uint32
-	ID       [utils.ContainerIDLen]byte
+	Inode        uint64
+	Numlower     uint32
+	Padding      uint32
+	ID           [utils.ContainerIDLen]byte
+	ParentCookie uint32
+	Pid          uint32
+	Comm         [16]byte
 }
 
 // Bytes returns the bytes representation of process cache entry
 func (pc ProcCache) Bytes() []byte {
-	b := make([]byte, 16+utils.ContainerIDLen)
+	b := make([]byte, 16+utils.ContainerIDLen+8+len(pc.Comm))
 	byteOrder.PutUint64(b[0:8], pc.Inode)
 	byteOrder.PutUint32(b[8:12], pc.Numlower)
 	copy(b[16:16+utils.ContainerIDLen], pc.ID[:])
+	byteOrder.PutUint32(b[16+utils.ContainerIDLen:20+utils.ContainerIDLen], pc.ParentCookie)
+	byteOrder.PutUint32(b[20+utils.ContainerIDLen:24+utils.ContainerIDLen], pc.Pid)
+	copy(b[24+utils.ContainerIDLen:], pc.Comm[:])
 	return b
 }
 
@@ -60,6 +70,8 @@ type Resolvers struct {
 	MountResolver     *MountResolver
 	ContainerResolver *ContainerResolver
 	TimeResolver      *TimeResolver
+
+	envsScrubber *envsScrubber
 }
 
 // Start the resolvers
@@ -131,16 +143,25 @@ func (r *Resolvers) snapshot(retry int) error {
 		return err
 	}
 
+	// Snapshot parents before their children so that the ancestry chain can be linked
+	pids := make([]int32, 0, len(processes))
+	for pid := range processes {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
 	cacheModified := false
 
-	for _, p := range processes {
+	for _, pid := range pids {
+		p := processes[pid]
+
 		// If Exe is not set, the process is a short lived process and its /proc entry has already expired, move on.
 		if len(p.Exe) == 0 {
 			continue
 		}
 
 		// Notify that we modified the cache.
-		if r.snapshotProcess(uint32(p.Pid)) {
+		if r.snapshotProcess(p) {
 			cacheModified = true
 		}
 	}
@@ -155,9 +176,13 @@ func (r *Resolvers) snapshot(retry int) error {
 	return nil
 }
 
-// snapshotProcess snapshots /proc for the provided pid. This method returns true if it updated the kernel process cache.
-func (r *Resolvers) snapshotProcess(pid uint32) bool {
-	entry := ProcCache{}
+// snapshotProcess snapshots /proc for the provided process. This method returns true if it updated the kernel process cache.
+func (r *Resolvers) snapshotProcess(p *process.FilledProcess) bool {
+	pid := uint32(p.Pid)
+	entry := ProcCache{
+		Pid: pid,
+	}
+	copy(entry.Comm[:], p.Name)
 	pidb := make([]byte, 4)
 	cookieb := make([]byte, 4)
 	inodeb := make([]byte, 8)
@@ -207,6 +232,13 @@ func (r *Resolvers) snapshotProcess(pid uint32) bool {
 	}
 	entry.Numlower = byteOrder.Uint32(numlowerb)
 
+	// Link the entry to the process cache entry of the parent
+	ppidb := make([]byte, 4)
+	byteOrder.PutUint32(ppidb, uint32(p.Ppid))
+	if parentCookieb, err := r.pidCookieMap.Get(ppidb); err == nil {
+		entry.ParentCookie = byteOrder.Uint32(parentCookieb)
+	}
+
 	// Generate a new cookie for this pid
 	byteOrder.PutUint32(cookieb, utils.NewCookie())
 
@@ -221,3 +253,45 @@ func (r *Resolvers) snapshotProcess(pid uint32) bool {
 	}
 	return true
 }
+
+// ResolveAncestors resolves the ancestors of the process image identified by the provided cookie,
+// from the closest to the farthest one
+func (r *Resolvers) ResolveAncestors(cookie uint32) []*ProcessAncestor {
+	if r.procCacheMap == nil || cookie == 0 {
+		return nil
+	}
+
+	cookieb := make([]byte, 4)
+	byteOrder.PutUint32(cookieb, cookie)
+	data, err := r.procCacheMap.Get(cookieb)
+	if err != nil {
+		return nil
+	}
+
+	var entry ProcessAncestor
+	if _, err := entry.UnmarshalBinary(data); err != nil {
+		return nil
+	}
+
+	var ancestors []*ProcessAncestor
+	visited := map[uint32]bool{cookie: true}
+
+	for cookie = entry.ParentCookie; cookie != 0 && !visited[cookie] && len(ancestors) < maxAncestors; cookie = entry.ParentCookie {
+		visited[cookie] = true
+
+		byteOrder.PutUint32(cookieb, cookie)
+		if data, err = r.procCacheMap.Get(cookieb); err != nil {
+			break
+		}
+
+		entry = ProcessAncestor{}
+		if _, err := entry.UnmarshalBinary(data); err != nil {
+			break
+		}
+
+		ancestor := entry
+		ancestors = append(ancestors, &ancestor)
+	}
+
+	return ancestors
+}
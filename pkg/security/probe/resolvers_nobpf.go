@@ -14,4 +14,11 @@ type Resolvers struct {
 	MountResolver     *MountResolver
 	ContainerResolver *ContainerResolver
 	TimeResolver      *TimeResolver
+
+	envsScrubber *envsScrubber
+}
+
+// ResolveAncestors resolves the ancestors of the process image identified by the provided cookie
+func (r *Resolvers) ResolveAncestors(cookie uint32) []*ProcessAncestor {
+	return nil
 }
@@ -0,0 +1,368 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux
+
+package replay
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+// sectionPrefixes maps the sections of a recorded event, that are not named after their event type,
+// to the prefix of their fields
+var sectionPrefixes = map[string]string{
+	"old":    "rename.old",
+	"new":    "rename.new",
+	"source": "link.source",
+	"target": "link.target",
+}
+
+// fieldAliases maps the keys of a recorded event that are not named after their field
+var fieldAliases = map[string]eval.Field{
+	"container.container_id":          "container.id",
+	"setxattr.attribute_name":         "setxattr.name",
+	"setxattr.attribute_namespace":    "setxattr.namespace",
+	"removexattr.attribute_name":      "removexattr.name",
+	"removexattr.attribute_namespace": "removexattr.namespace",
+	"utimes.access_time":              "utimes.atime",
+	"utimes.modification_time":        "utimes.mtime",
+}
+
+// Event is a runtime security event decoded from its JSON representation. Its fields
+// hold the values that were resolved when the event was recorded.
+type Event struct {
+	ID        string
+	Type      eval.EventType
+	Timestamp time.Time
+
+	values map[eval.Field]interface{}
+}
+
+// GetType returns the event type
+func (e *Event) GetType() eval.EventType {
+	return e.Type
+}
+
+// GetTimestamp returns the time the event was recorded, the rules are evaluated at that time
+func (e *Event) GetTimestamp() time.Time {
+	return e.Timestamp
+}
+
+// GetPointer returns an unsafe.Pointer of this object
+func (e *Event) GetPointer() unsafe.Pointer {
+	return unsafe.Pointer(e)
+}
+
+// GetFieldEventType returns the event type of the given field
+func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
+	return (&probe.Event{}).GetFieldEventType(field)
+}
+
+// GetFieldType returns the type of the given field
+func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
+	return (&probe.Event{}).GetFieldType(field)
+}
+
+// GetFieldValue returns the value of the given field
+func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
+	kind, err := e.GetFieldType(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if value, exists := e.values[field]; exists {
+		return value, nil
+	}
+	return zeroValue(kind), nil
+}
+
+// SetFieldValue sets the value of the given field
+func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
+	kind, err := e.GetFieldType(field)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return &eval.ErrValueTypeMismatch{Field: field}
+		}
+	case reflect.Int:
+		if _, ok := value.(int); !ok {
+			return &eval.ErrValueTypeMismatch{Field: field}
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return &eval.ErrValueTypeMismatch{Field: field}
+		}
+	case reflect.Slice:
+		switch v := value.(type) {
+		case string:
+			value = []string{v}
+		case []string:
+		default:
+			return &eval.ErrValueTypeMismatch{Field: field}
+		}
+	}
+
+	if e.values == nil {
+		e.values = make(map[eval.Field]interface{})
+	}
+	e.values[field] = value
+
+	return nil
+}
+
+func (e *Event) getString(field eval.Field) string {
+	value, _ := e.values[field].(string)
+	return value
+}
+
+func (e *Event) getInt(field eval.Field) int {
+	value, _ := e.values[field].(int)
+	return value
+}
+
+func (e *Event) getBool(field eval.Field) bool {
+	value, _ := e.values[field].(bool)
+	return value
+}
+
+func (e *Event) getStrings(field eval.Field) []string {
+	value, _ := e.values[field].([]string)
+	return value
+}
+
+func zeroValue(kind reflect.Kind) interface{} {
+	switch kind {
+	case reflect.String:
+		return ""
+	case reflect.Int:
+		return 0
+	case reflect.Bool:
+		return false
+	case reflect.Slice:
+		return []string{}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the JSON representation of a runtime security event
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+
+	if raw, exists := sections["id"]; exists {
+		if err := json.Unmarshal(raw, &e.ID); err != nil {
+			return errors.Wrap(err, "invalid event id")
+		}
+	}
+
+	var syscall struct {
+		Type      string `json:"type"`
+		Timestamp string `json:"timestamp"`
+	}
+	if raw, exists := sections["syscall"]; exists {
+		if err := json.Unmarshal(raw, &syscall); err != nil {
+			return errors.Wrap(err, "invalid syscall section")
+		}
+	}
+	if syscall.Type == "" {
+		return errors.New("event type not found")
+	}
+	e.Type = syscall.Type
+
+	if syscall.Timestamp != "" {
+		timestamp, err := parseTime(syscall.Timestamp)
+		if err != nil {
+			return errors.Wrap(err, "invalid event timestamp")
+		}
+		e.Timestamp = timestamp
+	}
+
+	for section, raw := range sections {
+		var prefix string
+		switch section {
+		case "id":
+			continue
+		case "file", "syscall":
+			prefix = e.Type
+		default:
+			if prefix = sectionPrefixes[section]; prefix == "" {
+				prefix = section
+			}
+		}
+
+		decoder := json.NewDecoder(strings.NewReader(string(raw)))
+		decoder.UseNumber()
+
+		var values map[string]interface{}
+		if err := decoder.Decode(&values); err != nil {
+			return errors.Wrapf(err, "invalid section `%s`", section)
+		}
+
+		if err := e.setValues(prefix, values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Event) setValues(prefix string, values map[string]interface{}) error {
+	for key, value := range values {
+		field := prefix + "." + key
+		if alias, exists := fieldAliases[field]; exists {
+			field = alias
+		}
+
+		if field == "process.ancestors" {
+			if err := e.setAncestors(value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		kind, err := e.GetFieldType(field)
+		if err != nil {
+			// not all the keys of a recorded event are fields
+			continue
+		}
+
+		value, err := toFieldValue(field, kind, value)
+		if err != nil {
+			return err
+		}
+
+		if err := e.SetFieldValue(field, value); err != nil {
+			return err
+		}
+
+		// the basename is not part of the recorded event, compute it from the filename
+		if strings.HasSuffix(field, ".filename") {
+			basename := strings.TrimSuffix(field, "filename") + "basename"
+			if _, err := e.GetFieldType(basename); err == nil {
+				if err := e.SetFieldValue(basename, path.Base(value.(string))); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *Event) setAncestors(value interface{}) error {
+	ancestors, ok := value.([]interface{})
+	if !ok {
+		return &eval.ErrValueTypeMismatch{Field: "process.ancestors"}
+	}
+
+	var names, filenames []string
+	for _, ancestor := range ancestors {
+		ancestor, ok := ancestor.(map[string]interface{})
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "process.ancestors"}
+		}
+
+		name, _ := ancestor["name"].(string)
+		names = append(names, name)
+
+		filename, _ := ancestor["filename"].(string)
+		filenames = append(filenames, filename)
+	}
+
+	if err := e.SetFieldValue("process.ancestors.name", names); err != nil {
+		return err
+	}
+	return e.SetFieldValue("process.ancestors.filename", filenames)
+}
+
+// toFieldValue converts a value of a recorded event to the type of its field
+func toFieldValue(field eval.Field, kind reflect.Kind, value interface{}) (interface{}, error) {
+	switch kind {
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []interface{}:
+			// the first argument of a process is not part of its args
+			if field == "exec.args" && len(v) > 0 {
+				v = v[1:]
+			}
+
+			strs := make([]string, len(v))
+			for i, s := range v {
+				strs[i], _ = s.(string)
+			}
+			return strings.Join(strs, " "), nil
+		}
+	case reflect.Int:
+		switch v := value.(type) {
+		case json.Number:
+			i, err := v.Int64()
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid value for `%s`", field)
+			}
+			return int(i), nil
+		case string:
+			if t, err := parseTime(v); err == nil {
+				return int(t.UnixNano()), nil
+			}
+			return parseConstants(field, v)
+		}
+	case reflect.Bool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	}
+
+	return nil, &eval.ErrValueTypeMismatch{Field: field}
+}
+
+// parseConstants converts a bitmask or a constant, as formatted in the recorded events, to its value.
+// ex: O_CREAT | O_RDWR, AF_INET
+func parseConstants(field eval.Field, value string) (int, error) {
+	var result int
+	for _, name := range strings.Split(value, "|") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		// unknown bits are formatted as a number
+		if value, err := strconv.Atoi(name); err == nil {
+			result |= value
+			continue
+		}
+
+		constant, ok := probe.SECLConstants[name].(*eval.IntEvaluator)
+		if !ok {
+			return 0, errors.Errorf("unknown constant `%s` for `%s`", name, field)
+		}
+		result |= constant.Value
+	}
+	return result, nil
+}
+
+// parseTime parses the timestamps of the recorded events, formatted as RFC3339 with nanoseconds
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux
+
+package replay
+
+import (
+	"reflect"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+// Model describes the data model of the recorded runtime security events. It exposes
+// the same fields as the model of the probe.
+type Model struct {
+	probe.Model
+}
+
+// NewEvent returns a new event
+func (m *Model) NewEvent() eval.Event {
+	return &Event{}
+}
+
+// GetEvaluator returns an evaluator for the given field
+func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
+	kind, err := (&probe.Event{}).GetFieldType(field)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case reflect.String:
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string { return (*Event)(ctx.Object).getString(field) },
+			Field:   field,
+		}, nil
+	case reflect.Int:
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return (*Event)(ctx.Object).getInt(field) },
+			Field:   field,
+		}, nil
+	case reflect.Bool:
+		return &eval.BoolEvaluator{
+			EvalFnc: func(ctx *eval.Context) bool { return (*Event)(ctx.Object).getBool(field) },
+			Field:   field,
+		}, nil
+	case reflect.Slice:
+		return &eval.StringArrayEvaluator{
+			EvalFnc: func(ctx *eval.Context) []string { return (*Event)(ctx.Object).getStrings(field) },
+			Field:   field,
+		}, nil
+	}
+
+	return nil, &eval.ErrFieldNotFound{Field: field}
+}
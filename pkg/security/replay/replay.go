@@ -0,0 +1,169 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux
+
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+// RuleReport describes the evaluations of a rule against the recorded events
+type RuleReport struct {
+	ID            eval.RuleID `json:"id"`
+	Hits          int         `json:"hits"`
+	Evaluations   int         `json:"evaluations"`
+	EvalTimeNs    int64       `json:"eval_time_ns"`
+	AvgEvalTimeNs int64       `json:"avg_eval_time_ns"`
+	Events        []string    `json:"events,omitempty"`
+}
+
+// Report describes the result of a replay
+type Report struct {
+	Events        int           `json:"events"`
+	MatchedEvents int           `json:"matched_events"`
+	Rules         []*RuleReport `json:"rules"`
+}
+
+// Replayer evaluates the rules of a ruleset against recorded events. It listens to the ruleset
+// to report the rules matching the events and their evaluation time.
+type Replayer struct {
+	ruleSet *rules.RuleSet
+	reports map[eval.RuleID]*RuleReport
+	report  Report
+}
+
+// NewRuleSet returns a new ruleset able to evaluate recorded events
+func NewRuleSet(opts *rules.Opts) *rules.RuleSet {
+	return rules.NewRuleSet(&Model{}, func() eval.Event { return &Event{} }, opts)
+}
+
+// NewReplayer returns a new replayer for the given ruleset
+func NewReplayer(ruleSet *rules.RuleSet) *Replayer {
+	r := &Replayer{
+		ruleSet: ruleSet,
+		reports: make(map[eval.RuleID]*RuleReport),
+	}
+
+	for _, eventType := range ruleSet.GetEventTypes() {
+		for _, rule := range ruleSet.GetBucket(eventType).GetRules() {
+			if _, exists := r.reports[rule.ID]; !exists {
+				r.reports[rule.ID] = &RuleReport{ID: rule.ID}
+			}
+		}
+	}
+	ruleSet.AddListener(r)
+
+	return r
+}
+
+// RuleMatch is called by the ruleset when a rule matches a recorded event
+func (r *Replayer) RuleMatch(rule *eval.Rule, event eval.Event) {
+	report := r.reports[rule.ID]
+	report.Hits++
+	if id := event.(*Event).ID; id != "" {
+		report.Events = append(report.Events, id)
+	}
+}
+
+// EventDiscarderFound is called by the ruleset when a discarder is found for a recorded event
+func (r *Replayer) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field eval.Field) {}
+
+// RuleEvaluated is called by the ruleset for each evaluation of a rule against a recorded event
+func (r *Replayer) RuleEvaluated(rule *eval.Rule, event eval.Event, elapsed time.Duration) {
+	report := r.reports[rule.ID]
+	report.Evaluations++
+	report.EvalTimeNs += elapsed.Nanoseconds()
+}
+
+// Replay evaluates the rules against the given event
+func (r *Replayer) Replay(event *Event) {
+	r.report.Events++
+
+	if r.ruleSet.Evaluate(event) {
+		r.report.MatchedEvents++
+	}
+}
+
+// ReplayFrom decodes the recorded events of the given reader and evaluates the rules against them.
+// The events are either a JSON array or a stream of JSON objects.
+func (r *Replayer) ReplayFrom(reader io.Reader) error {
+	buffered := bufio.NewReader(reader)
+
+	isArray, err := isJSONArray(buffered)
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to read events")
+	}
+
+	decoder := json.NewDecoder(buffered)
+	if isArray {
+		// consume the opening bracket
+		if _, err := decoder.Token(); err != nil {
+			return errors.Wrap(err, "failed to decode events")
+		}
+	}
+
+	for i := 0; ; i++ {
+		if isArray && !decoder.More() {
+			break
+		}
+
+		var event Event
+		if err := decoder.Decode(&event); err == io.EOF && !isArray {
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to decode event %d", i)
+		}
+		r.Replay(&event)
+	}
+
+	return nil
+}
+
+// isJSONArray returns whether the first non space character of the reader opens a JSON array
+func isJSONArray(reader *bufio.Reader) (bool, error) {
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return false, err
+		}
+
+		if !unicode.IsSpace(rune(c)) {
+			return c == '[', reader.UnreadByte()
+		}
+	}
+}
+
+// GetReport returns the report of the evaluations, the rules are sorted by ID
+func (r *Replayer) GetReport() *Report {
+	report := r.report
+	report.Rules = make([]*RuleReport, 0, len(r.reports))
+
+	for _, ruleReport := range r.reports {
+		if ruleReport.Evaluations > 0 {
+			ruleReport.AvgEvalTimeNs = ruleReport.EvalTimeNs / int64(ruleReport.Evaluations)
+		}
+		report.Rules = append(report.Rules, ruleReport)
+	}
+
+	sort.Slice(report.Rules, func(i, j int) bool {
+		return report.Rules[i].ID < report.Rules[j].ID
+	})
+
+	return &report
+}
@@ -0,0 +1,166 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build linux
+
+package replay
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+)
+
+const recordedEvents = `
+{
+	"id": "1",
+	"process": {"pidns": 4026531836, "name": "cat", "pid": 100, "tid": 100, "uid": 0, "gid": 0, "ancestors": [{"pid": 99, "name": "bash", "filename": "/usr/bin/bash"}]},
+	"syscall": {"type": "open", "timestamp": "2020-10-18T10:00:00.000000001Z", "retval": 3},
+	"file": {"filename": "/etc/shadow", "container_path": "", "inode": 42, "mount_id": 1, "overlay_numlower": 0, "mode": 0, "flags": "O_CREAT | O_RDWR"}
+}
+{
+	"id": "2",
+	"process": {"pidns": 4026531836, "name": "cat", "pid": 101, "tid": 101, "uid": 0, "gid": 0},
+	"syscall": {"type": "open", "timestamp": "2020-10-18T10:00:01Z", "retval": 3},
+	"file": {"filename": "/etc/passwd", "container_path": "", "inode": 43, "mount_id": 1, "overlay_numlower": 0, "mode": 0, "flags": "O_RDONLY"}
+}
+{
+	"id": "3",
+	"process": {"pidns": 4026531836, "name": "rm", "pid": 102, "tid": 102, "uid": 1000, "gid": 1000},
+	"syscall": {"type": "unlink", "timestamp": "2020-10-18T10:00:02+00:00", "retval": 0},
+	"file": {"filename": "/tmp/test", "container_path": "", "inode": 44, "mount_id": 1, "overlay_numlower": 0}
+}
+`
+
+func newTestReplayer(t *testing.T) *Replayer {
+	ruleSet := NewRuleSet(rules.NewOptsWithParams(false, probe.SECLConstants, probe.InvalidDiscarders))
+
+	ruleDefs := []*rules.RuleDefinition{
+		{ID: "shadow", Expression: `open.filename == "/etc/shadow" && open.flags & O_CREAT > 0 && process.ancestors.name == "bash"`},
+		{ID: "etc", Expression: `open.basename in ["shadow", "passwd"] && process.name == "cat"`},
+		{ID: "unlink", Expression: `unlink.filename =~ "/tmp/*" && process.uid == 1000`},
+		{ID: "never", Expression: `rmdir.filename == "/"`},
+	}
+	if err := ruleSet.AddRules(ruleDefs); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewReplayer(ruleSet)
+}
+
+func TestReplay(t *testing.T) {
+	replayer := newTestReplayer(t)
+	if err := replayer.ReplayFrom(strings.NewReader(recordedEvents)); err != nil {
+		t.Fatal(err)
+	}
+
+	report := replayer.GetReport()
+	if report.Events != 3 || report.MatchedEvents != 3 {
+		t.Fatalf("unexpected event counts: %+v", report)
+	}
+
+	expected := map[string][]string{
+		"etc":    {"1", "2"},
+		"never":  nil,
+		"shadow": {"1"},
+		"unlink": {"3"},
+	}
+
+	if len(report.Rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %d", len(expected), len(report.Rules))
+	}
+
+	for i, rule := range report.Rules {
+		if i > 0 && report.Rules[i-1].ID >= rule.ID {
+			t.Errorf("rules should be sorted by ID")
+		}
+
+		events := expected[rule.ID]
+		if rule.Hits != len(events) || strings.Join(rule.Events, ",") != strings.Join(events, ",") {
+			t.Errorf("expected rule `%s` to match %v, got %v", rule.ID, events, rule.Events)
+		}
+	}
+}
+
+func TestReplayTime(t *testing.T) {
+	ruleSet := NewRuleSet(rules.NewOptsWithParams(false, probe.SECLConstants, probe.InvalidDiscarders))
+	if err := ruleSet.AddRules([]*rules.RuleDefinition{
+		{ID: "recent", Expression: `utimes.mtime < 5m`},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	replayer := NewReplayer(ruleSet)
+
+	// the rules are evaluated at the time the events were recorded
+	events := `
+{
+	"id": "1",
+	"process": {"pidns": 4026531836, "name": "touch", "pid": 100, "tid": 100, "uid": 0, "gid": 0},
+	"syscall": {"type": "utimes", "timestamp": "2020-10-18T10:00:00Z", "retval": 0},
+	"file": {"filename": "/tmp/test", "container_path": "", "inode": 44, "mount_id": 1, "overlay_numlower": 0},
+	"utimes": {"access_time": "2020-10-18T09:58:00Z", "modification_time": "2020-10-18T09:58:00Z"}
+}
+`
+	if err := replayer.ReplayFrom(strings.NewReader(events)); err != nil {
+		t.Fatal(err)
+	}
+
+	report := replayer.GetReport()
+	if len(report.Rules) != 1 || report.Rules[0].Hits != 1 || report.Rules[0].Evaluations != 1 {
+		t.Fatalf("expected the rule to match the recorded event: %+v", report.Rules[0])
+	}
+}
+
+func TestReplayArray(t *testing.T) {
+	replayer := newTestReplayer(t)
+
+	events := "[" + strings.Replace(strings.TrimSpace(recordedEvents), "}\n{", "},\n{", -1) + "]"
+	if err := replayer.ReplayFrom(strings.NewReader(events)); err != nil {
+		t.Fatal(err)
+	}
+
+	if report := replayer.GetReport(); report.Events != 3 || report.MatchedEvents != 3 {
+		t.Fatalf("unexpected event counts: %+v", report)
+	}
+}
+
+func TestReplayError(t *testing.T) {
+	replayer := newTestReplayer(t)
+
+	events := recordedEvents + `{"syscall": {"type": "open"}, "file": {"flags": "O_UNKNOWN"}}`
+	if err := replayer.ReplayFrom(strings.NewReader(events)); err == nil || !strings.Contains(err.Error(), "event 3") {
+		t.Fatalf("expected an error for event 3, got %v", err)
+	}
+}
+
+func TestEventUnmarshalJSON(t *testing.T) {
+	var event Event
+	if err := event.UnmarshalJSON([]byte(strings.TrimSpace(strings.SplitN(recordedEvents, "}\n{", 2)[0]) + "}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if event.GetType() != "open" || event.Timestamp.Nanosecond() != 1 {
+		t.Errorf("unexpected event type or timestamp: %s %s", event.GetType(), event.Timestamp)
+	}
+
+	if flags := event.getInt("open.flags"); flags != syscall.O_CREAT|syscall.O_RDWR {
+		t.Errorf("unexpected flags: %d", flags)
+	}
+
+	if basename := event.getString("open.basename"); basename != "shadow" {
+		t.Errorf("unexpected basename: %s", basename)
+	}
+
+	if retval := event.getInt("open.retval"); retval != 3 {
+		t.Errorf("unexpected retval: %d", retval)
+	}
+
+	if names := event.getStrings("process.ancestors.name"); len(names) != 1 || names[0] != "bash" {
+		t.Errorf("unexpected ancestors: %v", names)
+	}
+}
@@ -0,0 +1,160 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package rules
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+// RuleSetDiff describes the rules that differ between two rulesets
+type RuleSetDiff struct {
+	Added   []eval.RuleID `json:"added"`
+	Removed []eval.RuleID `json:"removed"`
+	Changed []eval.RuleID `json:"changed"`
+}
+
+// IsEmpty returns whether the two rulesets hold the same rules
+func (d *RuleSetDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// astEqual returns whether two AST nodes are the same, regardless of their position and of the
+// formatting of the expression they were parsed from
+func astEqual(a, b interface{}) bool {
+	return nodesEqual(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func nodesEqual(a, b reflect.Value) bool {
+	if a.Kind() != b.Kind() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return nodesEqual(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if name := a.Type().Field(i).Name; name == "Pos" || name == "Expr" {
+				continue
+			}
+			if !nodesEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
+
+// identifiers returns the identifiers, fields, constants or macros, used by an AST node
+func identifiers(node interface{}) []string {
+	var idents []string
+	collectIdentifiers(reflect.ValueOf(node), &idents)
+	return idents
+}
+
+func collectIdentifiers(v reflect.Value, idents *[]string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			collectIdentifiers(v.Elem(), idents)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if ident, ok := field.Interface().(*string); ok && v.Type().Field(i).Name == "Ident" {
+				if ident != nil {
+					*idents = append(*idents, *ident)
+				}
+				continue
+			}
+			collectIdentifiers(field, idents)
+		}
+	}
+}
+
+// changedMacros returns the macros whose definition differs between the two rulesets, including
+// the macros that use a changed macro
+func changedMacros(rs, previous *RuleSet) map[eval.MacroID]bool {
+	changed := make(map[eval.MacroID]bool)
+
+	for id, macro := range rs.opts.Macros {
+		if prev, exists := previous.opts.Macros[id]; !exists || !astEqual(prev.GetAst(), macro.GetAst()) {
+			changed[id] = true
+		}
+	}
+
+	for id := range previous.opts.Macros {
+		if _, exists := rs.opts.Macros[id]; !exists {
+			changed[id] = true
+		}
+	}
+
+	// propagate the changes to the macros using a changed macro
+	for propagated := true; propagated; {
+		propagated = false
+		for id, macro := range rs.opts.Macros {
+			if changed[id] {
+				continue
+			}
+			for _, ident := range identifiers(macro.GetAst()) {
+				if changed[ident] {
+					changed[id] = true
+					propagated = true
+					break
+				}
+			}
+		}
+	}
+
+	return changed
+}
+
+// Diff returns the rules that were added, removed or changed compared to the given previous ruleset. A rule
+// is changed when its parsed expression, its rate limit or one of the macros it uses changed.
+func (rs *RuleSet) Diff(previous *RuleSet) *RuleSetDiff {
+	diff := &RuleSetDiff{}
+	macros := changedMacros(rs, previous)
+
+	for id, rule := range rs.rules {
+		prev, exists := previous.rules[id]
+		if !exists {
+			diff.Added = append(diff.Added, id)
+			continue
+		}
+
+		if !astEqual(prev.GetAst(), rule.GetAst()) || !reflect.DeepEqual(previous.ruleDefinitions[id].RateLimit, rs.ruleDefinitions[id].RateLimit) {
+			diff.Changed = append(diff.Changed, id)
+			continue
+		}
+
+		for _, ident := range identifiers(rule.GetAst()) {
+			if macros[ident] {
+				diff.Changed = append(diff.Changed, id)
+				break
+			}
+		}
+	}
+
+	for id := range previous.rules {
+		if _, exists := rs.rules[id]; !exists {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+
+	return diff
+}
@@ -15,10 +15,11 @@ import (
 )
 
 type testProcess struct {
-	name   string
-	uid    int
-	gid    int
-	isRoot bool
+	name      string
+	uid       int
+	gid       int
+	isRoot    bool
+	ancestors []string
 }
 
 type testOpen struct {
@@ -85,6 +86,13 @@ func (m *testModel) GetEvaluator(key string) (eval.Evaluator, error) {
 			Field:   key,
 		}, nil
 
+	case "process.ancestors.name":
+
+		return &eval.StringArrayEvaluator{
+			EvalFnc: func(ctx *eval.Context) []string { return (*testEvent)(ctx.Object).process.ancestors },
+			Field:   key,
+		}, nil
+
 	case "process.uid":
 
 		return &eval.IntEvaluator{
@@ -153,6 +161,10 @@ func (e *testEvent) GetFieldValue(key string) (interface{}, error) {
 
 		return e.process.name, nil
 
+	case "process.ancestors.name":
+
+		return e.process.ancestors, nil
+
 	case "process.uid":
 
 		return e.process.uid, nil
@@ -197,6 +209,10 @@ func (e *testEvent) GetFieldEventType(key string) (string, error) {
 
 		return "*", nil
 
+	case "process.ancestors.name":
+
+		return "*", nil
+
 	case "process.uid":
 
 		return "*", nil
@@ -242,6 +258,16 @@ func (e *testEvent) SetFieldValue(key string, value interface{}) error {
 		e.process.name = value.(string)
 		return nil
 
+	case "process.ancestors.name":
+
+		switch v := value.(type) {
+		case string:
+			e.process.ancestors = []string{v}
+		case []string:
+			e.process.ancestors = v
+		}
+		return nil
+
 	case "process.uid":
 
 		e.process.uid = value.(int)
@@ -294,6 +320,10 @@ func (e *testEvent) GetFieldType(key string) (reflect.Kind, error) {
 
 		return reflect.String, nil
 
+	case "process.ancestors.name":
+
+		return reflect.Slice, nil
+
 	case "process.uid":
 
 		return reflect.Int, nil
@@ -7,6 +7,7 @@ package rules
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
@@ -27,11 +28,22 @@ type MacroDefinition struct {
 // RuleID represents the ID of a rule
 type RuleID = string
 
+// RateLimitDefinition holds the rate limit settings of a rule
+type RateLimitDefinition struct {
+	// Limit is the number of events per second sent for the rule
+	Limit float64 `yaml:"limit"`
+	// Burst is the number of events that can be sent at once
+	Burst int `yaml:"burst"`
+	// Aggregate folds the events exceeding the limit into summary events instead of dropping them
+	Aggregate bool `yaml:"aggregate"`
+}
+
 // RuleDefinition holds the definition of a rule
 type RuleDefinition struct {
-	ID         RuleID            `yaml:"id"`
-	Expression string            `yaml:"expression"`
-	Tags       map[string]string `yaml:"tags"`
+	ID         RuleID               `yaml:"id"`
+	Expression string               `yaml:"expression"`
+	Tags       map[string]string    `yaml:"tags"`
+	RateLimit  *RateLimitDefinition `yaml:"rate_limit"`
 }
 
 // GetTags returns the tags associated to a rule
@@ -52,6 +64,12 @@ type RuleSetListener interface {
 	EventDiscarderFound(rs *RuleSet, event eval.Event, field eval.Field)
 }
 
+// RuleEvaluationListener describes the method implemented by a listener, added with AddListener,
+// used to be notified of the time taken by each rule evaluation
+type RuleEvaluationListener interface {
+	RuleEvaluated(rule *eval.Rule, event eval.Event, elapsed time.Duration)
+}
+
 // Opts defines rules set options
 type Opts struct {
 	eval.Opts
@@ -95,9 +113,11 @@ type RuleSet struct {
 	opts             *Opts
 	eventRuleBuckets map[eval.EventType]*RuleBucket
 	rules            map[eval.RuleID]*eval.Rule
+	ruleDefinitions  map[eval.RuleID]*RuleDefinition
 	model            eval.Model
 	eventCtor        func() eval.Event
 	listeners        []RuleSetListener
+	evalListeners    []RuleEvaluationListener
 	// fields holds the list of event field queries (like "process.uid") used by the entire set of rules
 	fields            []string
 	invalidDiscarders map[eval.Field]map[interface{}]bool
@@ -112,6 +132,15 @@ func (rs *RuleSet) ListRuleIDs() []string {
 	return ids
 }
 
+// ListRuleDefinitions returns the definitions of the rules of the ruleset
+func (rs *RuleSet) ListRuleDefinitions() []*RuleDefinition {
+	var ruleDefs []*RuleDefinition
+	for _, ruleDef := range rs.ruleDefinitions {
+		ruleDefs = append(ruleDefs, ruleDef)
+	}
+	return ruleDefs
+}
+
 // AddMacros parses the macros AST and adds them to the list of macros of the ruleset
 func (rs *RuleSet) AddMacros(macros []*MacroDefinition) error {
 	var result *multierror.Error
@@ -123,7 +152,7 @@ func (rs *RuleSet) AddMacros(macros []*MacroDefinition) error {
 		}
 	}
 
-	return result
+	return result.ErrorOrNil()
 }
 
 // AddMacro parses the macro AST and adds it to the list of macros of the ruleset
@@ -213,6 +242,7 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 	rs.AddFields(rule.GetEvaluator().GetFields())
 
 	rs.rules[ruleDef.ID] = rule
+	rs.ruleDefinitions[ruleDef.ID] = ruleDef
 
 	return rule, nil
 }
@@ -231,9 +261,19 @@ func (rs *RuleSet) NotifyDiscarderFound(event eval.Event, field eval.Field) {
 	}
 }
 
+// NotifyRuleEvaluated notifies the ruleset evaluation listeners of the time taken by a rule evaluation
+func (rs *RuleSet) NotifyRuleEvaluated(rule *eval.Rule, event eval.Event, elapsed time.Duration) {
+	for _, listener := range rs.evalListeners {
+		listener.RuleEvaluated(rule, event, elapsed)
+	}
+}
+
 // AddListener adds a listener on the ruleset
 func (rs *RuleSet) AddListener(listener RuleSetListener) {
 	rs.listeners = append(rs.listeners, listener)
+	if evalListener, ok := listener.(RuleEvaluationListener); ok {
+		rs.evalListeners = append(rs.evalListeners, evalListener)
+	}
 }
 
 // HasRulesForEventType returns if there is at least one rule for the given event type
@@ -312,6 +352,11 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 	ctx := &eval.Context{}
 	ctx.SetObject(event.GetPointer())
 
+	// events which happened at a known time, like recorded events, are evaluated at that time
+	if timedEvent, ok := event.(eval.TimedEvent); ok {
+		ctx.SetNow(timedEvent.GetTimestamp())
+	}
+
 	eventType := event.GetType()
 
 	result := false
@@ -321,8 +366,19 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 	}
 	log.Tracef("Evaluating event of type `%s` against set of %d rules", eventType, len(bucket.rules))
 
+	timed := len(rs.evalListeners) > 0
 	for _, rule := range bucket.rules {
-		if rule.GetEvaluator().Eval(ctx) {
+		var start time.Time
+		if timed {
+			start = time.Now()
+		}
+
+		matched := rule.GetEvaluator().Eval(ctx)
+		if timed {
+			rs.NotifyRuleEvaluated(rule, event, time.Since(start))
+		}
+
+		if matched {
 			log.Infof("Rule `%s` matches with event `%s`\n", rule.ID, event)
 
 			rs.NotifyRuleMatch(rule, event)
@@ -340,6 +396,11 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 			}
 			value := evaluator.Eval(ctx)
 
+			// array values, like the process ancestors, can't be used as discarders
+			if _, isArray := value.([]string); isArray {
+				continue
+			}
+
 			if rs.isInvalidDiscarder(field, value) {
 				continue
 			}
@@ -365,6 +426,11 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 	return result
 }
 
+// GetBucket returns the bucket of rules for the given event type, nil if there is none
+func (rs *RuleSet) GetBucket(eventType eval.EventType) *RuleBucket {
+	return rs.eventRuleBuckets[eventType]
+}
+
 // GetEventTypes returns all the event types handled by the ruleset
 func (rs *RuleSet) GetEventTypes() []eval.EventType {
 	eventTypes := make([]string, 0, len(rs.eventRuleBuckets))
@@ -410,6 +476,7 @@ func NewRuleSet(model eval.Model, eventCtor func() eval.Event, opts *Opts) *Rule
 		opts:              opts,
 		eventRuleBuckets:  make(map[eval.EventType]*RuleBucket),
 		rules:             make(map[eval.RuleID]*eval.Rule),
+		ruleDefinitions:   make(map[eval.RuleID]*RuleDefinition),
 		invalidDiscarders: opts.getInvalidDiscarders(),
 	}
 }
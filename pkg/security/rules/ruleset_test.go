@@ -8,6 +8,7 @@ package rules
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"syscall"
 	"testing"
 
@@ -372,6 +373,125 @@ func TestRuleSetFilters6(t *testing.T) {
 	}
 }
 
+func TestRuleSetFiltersAncestors(t *testing.T) {
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+
+	addRuleExpr(t, rs, `open.filename == "/etc/passwd" && process.ancestors.name == "httpd"`)
+
+	caps := FieldCapabilities{
+		{
+			Field: "open.filename",
+			Types: eval.ScalarValueType,
+		},
+	}
+
+	approvers, err := rs.GetApprovers("open", caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values, exists := approvers["open.filename"]; !exists || len(values) != 1 {
+		t.Fatal("expected approver not found")
+	}
+
+	rs = NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+
+	addRuleExpr(t, rs, `open.filename == "/etc/passwd" || process.ancestors.name == "httpd"`)
+
+	if _, err := rs.GetApprovers("open", caps); err == nil {
+		t.Fatal("shouldn't get any approver")
+	}
+}
+
+func TestRuleSetDiscardersAncestors(t *testing.T) {
+	model := &testModel{}
+
+	handler := &testHandler{
+		model:   model,
+		filters: make(map[string]testFieldValues),
+	}
+	rs := NewRuleSet(model, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+	rs.AddListener(handler)
+
+	addRuleExpr(t, rs, `open.filename == "/etc/passwd" && process.ancestors.name == "httpd"`)
+
+	event := &testEvent{
+		kind: "open",
+		process: testProcess{
+			ancestors: []string{"bash", "sshd"},
+		},
+		open: testOpen{
+			filename: "/usr/local/bin/rootkit",
+		},
+	}
+
+	rs.Evaluate(event)
+
+	expected := map[string]testFieldValues{
+		"open": {
+			"open.filename": []interface{}{
+				"/usr/local/bin/rootkit",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, handler.filters) {
+		t.Fatalf("unable to find expected discarders, expected: `%v`, got: `%v`", expected, handler.filters)
+	}
+}
+
+func TestRuleSetFiltersRegexp(t *testing.T) {
+	caps := FieldCapabilities{
+		{
+			Field: "open.filename",
+			Types: eval.ScalarValueType | eval.PatternValueType,
+		},
+	}
+
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+	addRuleExpr(t, rs, `open.filename == "/etc/passwd" && process.name =~ r"^ng(i|o)nx\d*$"`)
+
+	approvers, err := rs.GetApprovers("open", caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values, exists := approvers["open.filename"]; !exists || len(values) != 1 {
+		t.Fatal("expected approver not found")
+	}
+
+	rs = NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+	addRuleExpr(t, rs, `open.filename == "/etc/passwd" || process.name =~ r"^ng(i|o)nx\d*$"`)
+
+	if _, err := rs.GetApprovers("open", caps); err == nil {
+		t.Fatal("shouldn't get any approver")
+	}
+
+	rs = NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+	addRuleExpr(t, rs, `open.filename =~ r"^/etc/.*"`)
+
+	if _, err := rs.GetApprovers("open", caps); err == nil {
+		t.Fatal("regular expressions shouldn't be used as approvers")
+	}
+}
+
+func TestRegexpSample(t *testing.T) {
+	for _, expr := range []string{`^ng(i|o)nx\d*$`, `/etc/[a-z]+\.conf`, `^a{3,5}b?$`, `(?i)^NGINX$`, `.*`} {
+		sample, err := regexpSample(expr)
+		if err != nil {
+			t.Fatalf("unable to generate a sample for `%s`: %s", expr, err)
+		}
+
+		if !regexp.MustCompile(expr).MatchString(sample) {
+			t.Errorf("sample `%s` doesn't match `%s`", sample, expr)
+		}
+	}
+
+	if _, err := regexpSample(`a\bb`); err == nil {
+		t.Error("shouldn't be able to generate a sample")
+	}
+}
+
 func TestRuleSetInvalidDiscarders(t *testing.T) {
 	discarders := map[eval.Field][]interface{}{
 		"open.filename": {
@@ -403,3 +523,95 @@ func TestRuleSetInvalidDiscarders(t *testing.T) {
 		t.Errorf("shouldn't be an invalid discarder")
 	}
 }
+
+func TestRuleSetDiff(t *testing.T) {
+	newRuleSet := func(macros []*MacroDefinition, rules []*RuleDefinition) *RuleSet {
+		rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+		if err := rs.AddMacros(macros); err != nil {
+			t.Fatal(err)
+		}
+		if err := rs.AddRules(rules); err != nil {
+			t.Fatal(err)
+		}
+		return rs
+	}
+
+	previous := newRuleSet(nil, []*RuleDefinition{
+		{ID: "passwd", Expression: `open.filename == "/etc/passwd"`},
+		{ID: "mkdir", Expression: `mkdir.filename =~ "/var/run/*"`},
+		{ID: "removed", Expression: `mkdir.filename == "/tmp"`},
+	})
+
+	rs := newRuleSet(nil, []*RuleDefinition{
+		{ID: "passwd", Expression: `open.filename == "/etc/passwd"`, RateLimit: &RateLimitDefinition{Limit: 1}},
+		{ID: "mkdir", Expression: `mkdir.filename =~ "/var/run/sbin"`},
+		{ID: "added", Expression: `mkdir.filename == "/tmp"`},
+	})
+
+	expected := &RuleSetDiff{
+		Added:   []eval.RuleID{"added"},
+		Removed: []eval.RuleID{"removed"},
+		Changed: []eval.RuleID{"mkdir", "passwd"},
+	}
+
+	if diff := rs.Diff(previous); !reflect.DeepEqual(diff, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, diff)
+	}
+
+	if diff := rs.Diff(rs); !diff.IsEmpty() {
+		t.Fatalf("expected an empty diff, got %+v", diff)
+	}
+
+	previous = newRuleSet([]*MacroDefinition{
+		{ID: "sbin", Expression: `open.filename =~ "/sbin/*"`},
+		{ID: "sbin_creat", Expression: `sbin && open.flags & O_CREAT > 0`},
+		{ID: "passwd", Expression: `open.filename == "/etc/passwd"`},
+	}, nil)
+
+	rs = newRuleSet([]*MacroDefinition{
+		{ID: "sbin", Expression: `open.filename =~ "/usr/sbin/*"`},
+		{ID: "sbin_creat", Expression: `sbin && open.flags & O_CREAT > 0`},
+		{ID: "passwd", Expression: `open.filename == "/etc/passwd"`},
+	}, nil)
+
+	expectedMacros := map[eval.MacroID]bool{"sbin": true, "sbin_creat": true}
+	if macros := changedMacros(rs, previous); !reflect.DeepEqual(macros, expectedMacros) {
+		t.Fatalf("expected %+v, got %+v", expectedMacros, macros)
+	}
+
+	previous = newRuleSet([]*MacroDefinition{
+		{ID: "sbin", Expression: `["/sbin/ls"]`},
+		{ID: "passwd", Expression: `["/etc/passwd"]`},
+	}, []*RuleDefinition{
+		{ID: "sbin", Expression: `open.filename in sbin`},
+		{ID: "literal", Expression: `open.filename == "sbin"`},
+		{ID: "passwd", Expression: `open.filename in passwd`},
+	})
+
+	rs = newRuleSet([]*MacroDefinition{
+		{ID: "sbin", Expression: `["/usr/sbin/ls"]`},
+		{ID: "passwd", Expression: `[ "/etc/passwd" ]`},
+	}, []*RuleDefinition{
+		{ID: "sbin", Expression: `open.filename in sbin`},
+		{ID: "literal", Expression: `open.filename == "sbin"`},
+		{ID: "passwd", Expression: `open.filename  in  passwd`},
+	})
+
+	// only the rule using the changed macro changed, the formatting of expressions doesn't matter
+	expected = &RuleSetDiff{Changed: []eval.RuleID{"sbin"}}
+	if diff := rs.Diff(previous); !reflect.DeepEqual(diff, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, diff)
+	}
+}
+
+func TestRuleSetAddMacros(t *testing.T) {
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(true, testConstants, nil))
+
+	if err := rs.AddMacros([]*MacroDefinition{{ID: "sbin", Expression: `"/sbin/*"`}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := rs.AddMacros([]*MacroDefinition{{ID: "invalid", Expression: `open.filename ==`}}); err == nil {
+		t.Fatal("expected an error for an invalid macro")
+	}
+}
@@ -122,7 +122,7 @@ func genFilterValues(rule *eval.Rule, event eval.Event) ([]FilterValues, error)
 		var values FilterValues
 		for _, fValue := range fValues {
 			switch fValue.Type {
-			case eval.ScalarValueType, eval.PatternValueType:
+			case eval.ScalarValueType, eval.PatternValueType, eval.CIDRValueType:
 				values = append(values, FilterValue{
 					Field: field,
 					Value: fValue.Value,
@@ -134,6 +134,30 @@ func genFilterValues(rule *eval.Rule, event eval.Event) ([]FilterValues, error)
 					return nil, &ErrValueTypeUnknown{Field: field}
 				}
 
+				values = append(values, FilterValue{
+					Field: field,
+					Value: notValue,
+					Type:  fValue.Type,
+					Not:   true,
+				})
+			case eval.RegexpValueType:
+				// the value has to be matched by the regular expression for the truth table to be valid
+				sample, err := regexpSample(fValue.Value.(string))
+				if err != nil {
+					return nil, &ErrValueTypeUnknown{Field: field}
+				}
+
+				values = append(values, FilterValue{
+					Field: field,
+					Value: sample,
+					Type:  fValue.Type,
+				})
+
+				notValue, err := notOfValue(sample)
+				if err != nil {
+					return nil, &ErrValueTypeUnknown{Field: field}
+				}
+
 				values = append(values, FilterValue{
 					Field: field,
 					Value: notValue,
@@ -6,6 +6,10 @@
 package rules
 
 import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
 	"github.com/pkg/errors"
 
 	"github.com/DataDog/datadog-agent/pkg/security/utils"
@@ -23,3 +27,59 @@ func notOfValue(value interface{}) (interface{}, error) {
 
 	return nil, errors.New("value type unknown")
 }
+
+// regexpSample returns a string matched by the given regular expression
+func regexpSample(expr string) (string, error) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := writeRegexpSample(&builder, re.Simplify()); err != nil {
+		return "", err
+	}
+	sample := builder.String()
+
+	// some constructs, like word boundaries, are not taken into account, ensure that the sample matches
+	if matched, err := regexp.MatchString(expr, sample); err != nil || !matched {
+		return "", errors.Errorf("unable to generate a sample for `%s`", expr)
+	}
+
+	return sample, nil
+}
+
+func writeRegexpSample(builder *strings.Builder, re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpLiteral:
+		builder.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return errors.New("empty character class")
+		}
+		builder.WriteRune(re.Rune[0])
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		builder.WriteRune('a')
+	case syntax.OpCapture, syntax.OpPlus:
+		return writeRegexpSample(builder, re.Sub[0])
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			if err := writeRegexpSample(builder, re.Sub[0]); err != nil {
+				return err
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := writeRegexpSample(builder, sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		return writeRegexpSample(builder, re.Sub[0])
+	case syntax.OpNoMatch:
+		return errors.New("regexp doesn't match anything")
+	}
+
+	// the other operators, like anchors, star or quest, can match an empty string
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/ast"
 )
@@ -197,6 +198,12 @@ func (d *Marshaler) getChildren(n interface{}) ([]interface{}, error) {
 		if n.String != nil {
 			return []interface{}{newNode(fmt.Sprintf("String%p", n.String), fmt.Sprintf("String\\n%s", *n.String))}, nil
 		}
+		if n.Regexp != nil {
+			return []interface{}{newNode(fmt.Sprintf("Regexp%p", n.Regexp), fmt.Sprintf("Regexp\\n%s", *n.Regexp))}, nil
+		}
+		if n.Duration != nil {
+			return []interface{}{newNode(fmt.Sprintf("Duration%p", n.Duration), fmt.Sprintf("Duration\\n%s", time.Duration(*n.Duration)))}, nil
+		}
 		if n.SubExpression != nil {
 			return []interface{}{n.SubExpression}, nil
 		}
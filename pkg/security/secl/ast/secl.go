@@ -7,6 +7,9 @@ package ast
 
 import (
 	"bytes"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/alecthomas/participle"
 	"github.com/alecthomas/participle/lexer"
@@ -15,8 +18,10 @@ import (
 
 var (
 	seclLexer = lexer.Must(ebnf.New(`
+Regexp = "r\"" { "\u0000"…"\uffff"-"\""-"\\" | "\\" any } "\"" .
 Ident = (alpha | "_") { "_" | alpha | digit | "." } .
 String = "\"" { "\u0000"…"\uffff"-"\""-"\\" | "\\" any } "\"" .
+Duration = digit { digit } ( "m" [ "s" ] | "s" | "h" ) .
 Int = [ "-" | "+" ] digit { digit } .
 Punct = "!"…"/" | ":"…"@" | "["…` + "\"`\"" + ` | "{"…"~" .
 Whitespace = ( " " | "\t" ) { " " | "\t" } .
@@ -26,12 +31,35 @@ any = "\u0000"…"\uffff" .
 `))
 )
 
-// ParseRule parses a SECL rule.
-func ParseRule(expr string) (*Rule, error) {
-	parser, err := participle.Build(&Rule{},
+// unquoteRegexp removes the r"" delimiters of a regular expression literal. Only the escaped
+// double quotes are unescaped, the other escape sequences are left to the regexp syntax.
+func unquoteRegexp(token lexer.Token) (lexer.Token, error) {
+	token.Value = strings.Replace(token.Value[2:len(token.Value)-1], `\"`, `"`, -1)
+	return token, nil
+}
+
+// parseDuration converts a duration literal, like 5m, to its number of nanoseconds
+func parseDuration(token lexer.Token) (lexer.Token, error) {
+	duration, err := time.ParseDuration(token.Value)
+	if err != nil {
+		return token, lexer.ErrorWithTokenf(token, "invalid duration %q: %s", token.Value, err)
+	}
+	token.Value = strconv.FormatInt(int64(duration), 10)
+	return token, nil
+}
+
+func newParser(grammar interface{}) (*participle.Parser, error) {
+	return participle.Build(grammar,
 		participle.Lexer(seclLexer),
 		participle.Elide("Whitespace"),
-		participle.Unquote("String"))
+		participle.Unquote("String"),
+		participle.Map(unquoteRegexp, "Regexp"),
+		participle.Map(parseDuration, "Duration"))
+}
+
+// ParseRule parses a SECL rule.
+func ParseRule(expr string) (*Rule, error) {
+	parser, err := newParser(&Rule{})
 	if err != nil {
 		return nil, err
 	}
@@ -57,10 +85,7 @@ type Rule struct {
 
 // ParseMacro parses a SECL macro
 func ParseMacro(expr string) (*Macro, error) {
-	parser, err := participle.Build(&Macro{},
-		participle.Lexer(seclLexer),
-		participle.Elide("Whitespace"),
-		participle.Unquote("String"))
+	parser, err := newParser(&Macro{})
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +138,7 @@ type Comparison struct {
 type ScalarComparison struct {
 	Pos lexer.Position
 
-	Op   *string     `parser:"@( \">\" | \">\" \"=\" | \"<\" | \"<\" \"=\" | \"!\" \"=\" | \"=\" \"=\" | \"=\" \"~\" | \"!\" \"~\" )"`
+	Op   *string     `parser:"@( \">\" \"=\" | \">\" | \"<\" \"=\" | \"<\" | \"!\" \"=\" | \"=\" \"=\" | \"=\" \"~\" | \"!\" \"~\" )"`
 	Next *Comparison `parser:"@@"`
 }
 
@@ -125,12 +150,12 @@ type ArrayComparison struct {
 	Array *Array  `parser:"@@ )"`
 }
 
-// BitOperation describes an operation on bits
+// BitOperation describes an operation on bits, or the difference between two timestamps
 type BitOperation struct {
 	Pos lexer.Position
 
 	Unary *Unary        `parser:"@@"`
-	Op    *string       `parser:"[ @( \"&\" | \"|\" | \"^\" )"`
+	Op    *string       `parser:"[ @( \"&\" | \"|\" | \"^\" | \"-\" )"`
 	Next  *BitOperation `parser:"@@ ]"`
 }
 
@@ -144,13 +169,15 @@ type Unary struct {
 }
 
 // Primary describes a single operand. It can be a simple identifier, a number,
-// a string or a full expression in parenthesis
+// a string, a regular expression, a duration or a full expression in parenthesis
 type Primary struct {
 	Pos lexer.Position
 
 	Ident         *string     `parser:"@Ident"`
 	Number        *int        `parser:"| @Int"`
 	String        *string     `parser:"| @String"`
+	Regexp        *string     `parser:"| @Regexp"`
+	Duration      *int        `parser:"| @Duration"`
 	SubExpression *Expression `parser:"| \"(\" @@ \")\""`
 }
 
@@ -7,7 +7,9 @@ package ast
 
 import (
 	"encoding/json"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func print(t *testing.T, i interface{}) {
@@ -124,3 +126,119 @@ func TestMacroExpression(t *testing.T) {
 
 	print(t, macro)
 }
+
+func TestRegexp(t *testing.T) {
+	rule, err := ParseRule(`process.name =~ r"^ng(i|o)nx\d+ \"x\"$"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	print(t, rule)
+
+	regexp := rule.BooleanExpression.Expression.Comparison.ScalarComparison.Next.BitOperation.Unary.Primary.Regexp
+	if regexp == nil || *regexp != `^ng(i|o)nx\d+ "x"$` {
+		t.Errorf("unexpected regexp: %v", regexp)
+	}
+
+	if _, err := ParseRule(`rename.old.filename == "/etc/passwd"`); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for expr, expected := range map[string]int{
+		`utimes.mtime < 5m`:    int(5 * time.Minute),
+		`utimes.mtime < 1h`:    int(time.Hour),
+		`utimes.mtime < 30s`:   int(30 * time.Second),
+		`utimes.mtime < 500ms`: int(500 * time.Millisecond),
+	} {
+		rule, err := ParseRule(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		duration := rule.BooleanExpression.Expression.Comparison.ScalarComparison.Next.BitOperation.Unary.Primary.Duration
+		if duration == nil || *duration != expected {
+			t.Errorf("unexpected duration for `%s`: %v", expr, duration)
+		}
+	}
+
+	rule, err := ParseRule(`process.pid > 5`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if number := rule.BooleanExpression.Expression.Comparison.ScalarComparison.Next.BitOperation.Unary.Primary.Number; number == nil || *number != 5 {
+		t.Errorf("expected a number, got %v", number)
+	}
+
+	for _, expr := range []string{`utimes.mtime < 5hs`, `utimes.mtime < 5ss`, `utimes.mtime < 5mss`} {
+		if _, err := ParseRule(expr); err == nil {
+			t.Errorf("expected an invalid duration error for `%s`", expr)
+		}
+	}
+}
+
+func TestMinus(t *testing.T) {
+	rule, err := ParseRule(`process.uid == 5`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := rule.BooleanExpression.Expression.Comparison.ScalarComparison.Next.BitOperation.Unary.Primary
+	if primary.Number == nil || *primary.Number != 5 || primary.Duration != nil {
+		t.Errorf("expected the number 5, got %v", primary.Number)
+	}
+
+	// a signed number is lexed as a single number
+	rule, err = ParseRule(`process.uid == -5`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bitOperation := rule.BooleanExpression.Expression.Comparison.ScalarComparison.Next.BitOperation
+	if bitOperation.Op != nil || bitOperation.Unary.Primary == nil || bitOperation.Unary.Primary.Number == nil || *bitOperation.Unary.Primary.Number != -5 {
+		t.Errorf("expected the number -5, got %+v", bitOperation)
+	}
+
+	// a minus between two operands is a subtraction, whatever their type
+	for expr, operands := range map[string][2]string{
+		`process.uid - 5 == 3`:             {"process.uid", "5"},
+		`5 - process.uid == 3`:             {"5", "process.uid"},
+		`utimes.mtime - utimes.atime < 5m`: {"utimes.mtime", "utimes.atime"},
+	} {
+		rule, err := ParseRule(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bitOperation := rule.BooleanExpression.Expression.Comparison.BitOperation
+		if bitOperation.Op == nil || *bitOperation.Op != "-" || bitOperation.Next == nil {
+			t.Errorf("expected a subtraction for `%s`", expr)
+			continue
+		}
+		if left := primaryString(bitOperation.Unary.Primary); left != operands[0] {
+			t.Errorf("unexpected left operand for `%s`: %s", expr, left)
+		}
+		if right := primaryString(bitOperation.Next.Unary.Primary); right != operands[1] {
+			t.Errorf("unexpected right operand for `%s`: %s", expr, right)
+		}
+	}
+
+	// without a space, the minus is lexed as the sign of the number, which can't follow an operand
+	for _, expr := range []string{`process.uid -5 == 3`, `process.uid-5 == 3`} {
+		if _, err := ParseRule(expr); err == nil {
+			t.Errorf("expected an error for `%s`", expr)
+		}
+	}
+}
+
+func primaryString(primary *Primary) string {
+	switch {
+	case primary == nil:
+		return ""
+	case primary.Ident != nil:
+		return *primary.Ident
+	case primary.Number != nil:
+		return strconv.Itoa(*primary.Number)
+	}
+	return ""
+}
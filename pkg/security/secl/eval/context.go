@@ -7,6 +7,7 @@ package eval
 
 import (
 	"strings"
+	"time"
 	"unsafe"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -18,6 +19,21 @@ type Context struct {
 	Debug  bool
 
 	evalDepth int
+	now       time.Time
+}
+
+// Now returns the time of the evaluation, the same value is returned for all the comparisons
+// evaluated with this context
+func (c *Context) Now() time.Time {
+	if c.now.IsZero() {
+		c.now = time.Now()
+	}
+	return c.now
+}
+
+// SetNow sets the time of the evaluation, ex: the time of a recorded event
+func (c *Context) SetNow(now time.Time) {
+	c.now = now
 }
 
 // SetObject set the given object to the context
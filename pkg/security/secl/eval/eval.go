@@ -13,6 +13,7 @@ import (
 	"sort"
 
 	"github.com/alecthomas/participle/lexer"
+	"github.com/pkg/errors"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/ast"
 )
@@ -33,6 +34,8 @@ const (
 	ScalarValueType  FieldValueType = 1
 	PatternValueType FieldValueType = 2
 	BitmaskValueType FieldValueType = 4
+	CIDRValueType    FieldValueType = 8
+	RegexpValueType  FieldValueType = 16
 )
 
 // FieldValue describes a field value with its type
@@ -81,8 +84,12 @@ type IntEvaluator struct {
 	EvalFnc func(ctx *Context) int
 	Field   Field
 	Value   int
+	// IsTimestamp indicates that the field holds a timestamp in nanoseconds, compared with durations
+	IsTimestamp bool
 
-	isPartial bool
+	isPartial  bool
+	isDuration bool
+	isDelta    bool
 }
 
 // Eval returns the result of the evaluation
@@ -95,8 +102,11 @@ type StringEvaluator struct {
 	EvalFnc func(ctx *Context) string
 	Field   Field
 	Value   string
+	// IsAddress indicates that the field holds an IP address, matched by the CIDRs of an array
+	IsAddress bool
 
 	isPartial bool
+	isRegexp  bool
 }
 
 // Eval returns the result of the evaluation
@@ -104,6 +114,23 @@ func (s *StringEvaluator) Eval(ctx *Context) interface{} {
 	return s.EvalFnc(ctx)
 }
 
+// StringArrayEvaluator returns an array of strings as result of the evaluation. Comparisons
+// against such an evaluator are true when any of its values matches.
+type StringArrayEvaluator struct {
+	EvalFnc func(ctx *Context) []string
+	Field   Field
+	Values  []string
+	// IsAddress indicates that the field holds IP addresses, matched by the CIDRs of an array
+	IsAddress bool
+
+	isPartial bool
+}
+
+// Eval returns the result of the evaluation
+func (s *StringArrayEvaluator) Eval(ctx *Context) interface{} {
+	return s.EvalFnc(ctx)
+}
+
 // StringArray represents an array of string values
 type StringArray struct {
 	Values []string
@@ -198,6 +225,12 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, in
 					return nil, nil, pos, err
 				}
 				return IntEvaluator, nil, obj.Pos, nil
+			case "-":
+				intEvaluator, err := TimestampDelta(bitInt, nextInt, opts, state)
+				if err != nil {
+					return nil, nil, pos, NewOpError(obj.Pos, *obj.Op, err)
+				}
+				return intEvaluator, nil, obj.Pos, nil
 			}
 			return nil, nil, pos, NewOpUnknownError(obj.Pos, *obj.Op)
 		}
@@ -227,6 +260,17 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, in
 					return nil, nil, pos, err
 				}
 				return boolEvaluator, nil, obj.Pos, nil
+			case *StringArrayEvaluator:
+				nextStringArray, ok := next.(*StringArray)
+				if !ok {
+					return nil, nil, pos, NewTypeError(pos, reflect.Array)
+				}
+
+				boolEvaluator, err := StringValuesArrayContains(unary, nextStringArray, *obj.ArrayComparison.Op == "notin", opts, state)
+				if err != nil {
+					return nil, nil, pos, err
+				}
+				return boolEvaluator, nil, obj.Pos, nil
 			case *IntEvaluator:
 				nextIntArray, ok := next.(*IntArray)
 				if !ok {
@@ -275,6 +319,10 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, in
 					return nil, nil, pos, NewTypeError(pos, reflect.String)
 				}
 
+				if err := checkRegexpOp(unary, nextString, *obj.ScalarComparison.Op); err != nil {
+					return nil, nil, pos, NewOpError(obj.Pos, *obj.ScalarComparison.Op, err)
+				}
+
 				switch *obj.ScalarComparison.Op {
 				case "!=":
 					stringEvaluator, err := StringNotEquals(unary, nextString, opts, state)
@@ -296,12 +344,53 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, in
 					return eval, nil, obj.Pos, nil
 				}
 				return nil, nil, pos, NewOpUnknownError(obj.Pos, *obj.ScalarComparison.Op)
+			case *StringArrayEvaluator:
+				nextString, ok := next.(*StringEvaluator)
+				if !ok {
+					return nil, nil, pos, NewTypeError(pos, reflect.String)
+				}
+
+				if err := checkRegexpOp(nil, nextString, *obj.ScalarComparison.Op); err != nil {
+					return nil, nil, pos, NewOpError(obj.Pos, *obj.ScalarComparison.Op, err)
+				}
+
+				switch *obj.ScalarComparison.Op {
+				case "==", "!=":
+					boolEvaluator, err := StringValuesContains(unary, nextString, *obj.ScalarComparison.Op == "!=", opts, state)
+					if err != nil {
+						return nil, nil, pos, NewOpError(obj.Pos, *obj.ScalarComparison.Op, err)
+					}
+					return boolEvaluator, nil, obj.Pos, nil
+				case "=~", "!~":
+					boolEvaluator, err := StringValuesMatches(unary, nextString, *obj.ScalarComparison.Op == "!~", opts, state)
+					if err != nil {
+						return nil, nil, pos, NewOpError(obj.Pos, *obj.ScalarComparison.Op, err)
+					}
+					return boolEvaluator, nil, obj.Pos, nil
+				}
+				return nil, nil, pos, NewOpUnknownError(obj.Pos, *obj.ScalarComparison.Op)
 			case *IntEvaluator:
 				nextInt, ok := next.(*IntEvaluator)
 				if !ok {
 					return nil, nil, pos, NewTypeError(pos, reflect.Int)
 				}
 
+				if unary.isDuration {
+					return nil, nil, pos, NewOpError(obj.Pos, *obj.ScalarComparison.Op, errors.New("a duration has to be the right operand of a comparison"))
+				}
+
+				if nextInt.isDelta || (unary.isDelta && !nextInt.isDuration) {
+					return nil, nil, pos, NewOpError(obj.Pos, *obj.ScalarComparison.Op, errors.New("a time difference can only be compared with a duration"))
+				}
+
+				if nextInt.isDuration {
+					boolEvaluator, err := DurationCompare(unary, nextInt, *obj.ScalarComparison.Op, opts, state)
+					if err != nil {
+						return nil, nil, pos, NewOpError(obj.Pos, *obj.ScalarComparison.Op, err)
+					}
+					return boolEvaluator, nil, obj.Pos, nil
+				}
+
 				switch *obj.ScalarComparison.Op {
 				case "<":
 					boolEvaluator, err := LesserThan(unary, nextInt, opts, state)
@@ -415,6 +504,16 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, in
 			return &StringEvaluator{
 				Value: *obj.String,
 			}, nil, obj.Pos, nil
+		case obj.Regexp != nil:
+			return &StringEvaluator{
+				Value:    *obj.Regexp,
+				isRegexp: true,
+			}, nil, obj.Pos, nil
+		case obj.Duration != nil:
+			return &IntEvaluator{
+				Value:      *obj.Duration,
+				isDuration: true,
+			}, nil, obj.Pos, nil
 		case obj.SubExpression != nil:
 			return nodeToEvaluator(obj.SubExpression, opts, state)
 		default:
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/ast"
@@ -152,6 +153,8 @@ func TestSimpleInt(t *testing.T) {
 		{Expr: `--3 == 3`, Expected: true},
 		{Expr: `3 ^ 3 == 0`, Expected: true},
 		{Expr: `^0 == -1`, Expected: true},
+		{Expr: `process.uid <= 444`, Expected: true},
+		{Expr: `process.uid >= 445`, Expected: false},
 	}
 
 	for _, test := range tests {
@@ -281,6 +284,171 @@ func TestRegexp(t *testing.T) {
 		{Expr: `process.name =~ "/bin/"`, Expected: false},
 		{Expr: `process.name =~ "/bin/*"`, Expected: false},
 		{Expr: `process.name =~ ""`, Expected: false},
+		{Expr: `process.name =~ r"^/usr/bin/c\$t$"`, Expected: true},
+		{Expr: `process.name =~ r"^/usr/(s)?bin/[a-z]\$t$"`, Expected: true},
+		{Expr: `process.name =~ r"^/usr/sbin/"`, Expected: false},
+		{Expr: `process.name !~ r"^/usr/sbin/"`, Expected: true},
+		{Expr: `process.name =~ r"bin"`, Expected: true},
+	}
+
+	for _, test := range tests {
+		result, _, err := eval(t, event, test.Expr)
+		if err != nil {
+			t.Fatalf("error while evaluating `%s`: %s", test.Expr, err)
+		}
+
+		if result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t`\n%s", test.Expected, result, test.Expr)
+		}
+	}
+}
+
+func TestRegexpError(t *testing.T) {
+	model := &testModel{}
+
+	for _, expr := range []string{
+		`process.name == r"^/usr/bin/.*"`,
+		`process.name != r"^/usr/bin/.*"`,
+		`process.name =~ r"^/usr/bin/(.*"`,
+		`process.ancestors.name == r"nginx"`,
+		`r"^/usr/bin/.*" =~ process.name`,
+	} {
+		if _, err := parseRule(expr, model, &Opts{}); err == nil {
+			t.Errorf("expected an error for `%s`", expr)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	event := &testEvent{
+		process: testProcess{
+			createdAt: int(time.Now().Add(-2 * time.Minute).UnixNano()),
+		},
+	}
+
+	tests := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{Expr: `process.created_at < 5m`, Expected: true},
+		{Expr: `process.created_at <= 5m`, Expected: true},
+		{Expr: `process.created_at > 5m`, Expected: false},
+		{Expr: `process.created_at > 1m`, Expected: true},
+		{Expr: `process.created_at >= 30s`, Expected: true},
+		{Expr: `process.created_at < 1h && process.created_at > 90000ms`, Expected: true},
+	}
+
+	for _, test := range tests {
+		result, _, err := eval(t, event, test.Expr)
+		if err != nil {
+			t.Fatalf("error while evaluating `%s`: %s", test.Expr, err)
+		}
+
+		if result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t`\n%s", test.Expected, result, test.Expr)
+		}
+	}
+}
+
+func TestDurationBetweenFields(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	event := &testEvent{
+		process: testProcess{
+			createdAt: int(createdAt.UnixNano()),
+		},
+		open: testOpen{
+			mtime: int(createdAt.Add(2 * time.Minute).UnixNano()),
+		},
+	}
+
+	tests := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{Expr: `open.mtime - process.created_at < 5m`, Expected: true},
+		{Expr: `open.mtime - process.created_at > 1m`, Expected: true},
+		{Expr: `open.mtime - process.created_at >= 2m`, Expected: true},
+		{Expr: `open.mtime - process.created_at < 90s`, Expected: false},
+		{Expr: `process.created_at - open.mtime < 0s`, Expected: true},
+		{Expr: `open.mtime - process.created_at < 5m && open.mtime > 30m`, Expected: true},
+	}
+
+	for _, test := range tests {
+		result, _, err := eval(t, event, test.Expr)
+		if err != nil {
+			t.Fatalf("error while evaluating `%s`: %s", test.Expr, err)
+		}
+
+		if result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t`\n%s", test.Expected, result, test.Expr)
+		}
+	}
+}
+
+func TestDurationError(t *testing.T) {
+	model := &testModel{}
+
+	for _, expr := range []string{
+		`process.created_at == 5m`,
+		`process.created_at != 5m`,
+		`5m > process.created_at`,
+		`10 < 5m`,
+		`process.name < 5m`,
+		`open.mtime - process.created_at == 5`,
+		`open.mtime - process.created_at < 5`,
+		`process.created_at < open.mtime - process.created_at`,
+		`open.mtime - 5 < 5m`,
+		`process.uid < 5m`,
+		`process.uid >= 30s`,
+		`process.uid - process.gid < 5m`,
+		`open.mtime - process.uid < 5m`,
+	} {
+		if _, err := parseRule(expr, model, &Opts{}); err == nil {
+			t.Errorf("expected an error for `%s`", expr)
+		}
+	}
+}
+
+func TestTimestampDeltaError(t *testing.T) {
+	model := &testModel{}
+
+	for _, expr := range []string{
+		`process.uid - 5 == 3`,
+		`5 - process.uid == 3`,
+		`process.uid - process.gid < 5m`,
+		`open.mtime - 5 < 5m`,
+		`5 - 3 == 2`,
+	} {
+		// the rule is parsed, the operands are only checked when the evaluator is generated
+		rule := &Rule{ID: "id1", Expression: expr}
+		if err := rule.Parse(); err != nil {
+			t.Errorf("unexpected parse error for `%s`: %s", expr, err)
+			continue
+		}
+
+		err := rule.GenEvaluator(model, &Opts{})
+		if err == nil || !strings.Contains(err.Error(), "a time difference can only be computed between two timestamp fields") {
+			t.Errorf("expected a time difference error for `%s`, got: %v", expr, err)
+		}
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	event := &testEvent{
+		process: testProcess{
+			gid: 5,
+		},
+	}
+
+	tests := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{Expr: `process.gid == 5`, Expected: true},
+		{Expr: `process.gid != -5`, Expected: true},
+		{Expr: `process.gid > -5`, Expected: true},
+		{Expr: `-5 < process.gid`, Expected: true},
+		{Expr: `process.gid == - 5`, Expected: false},
 	}
 
 	for _, test := range tests {
@@ -337,6 +505,98 @@ func TestInArray(t *testing.T) {
 	}
 }
 
+func TestInCIDR(t *testing.T) {
+	event := &testEvent{
+		process: testProcess{
+			name:      "192.168.1.20",
+			ancestors: []string{"192.168.1.20"},
+		},
+		connect: testConnect{
+			ip: "192.168.1.20",
+		},
+	}
+
+	tests := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{Expr: `connect.ip in [ "10.0.0.0/8", "192.168.0.0/16" ]`, Expected: true},
+		{Expr: `connect.ip in [ "10.0.0.0/8", "172.16.0.0/12" ]`, Expected: false},
+		{Expr: `connect.ip not in [ "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16" ]`, Expected: false},
+		{Expr: `connect.ip in [ "192.168.1.20", "10.0.0.0/8" ]`, Expected: true},
+		{Expr: `connect.ip in [ "192.168.1.20/32" ]`, Expected: true},
+		{Expr: `connect.ip in [ "fd00::/8", "192.168.1.0/24" ]`, Expected: true},
+		// only address fields match CIDRs
+		{Expr: `process.name in [ "192.168.0.0/16" ]`, Expected: false},
+		{Expr: `process.name in [ "192.168.1.20", "10.0.0.0/8" ]`, Expected: true},
+		{Expr: `process.ancestors.name in [ "192.168.0.0/16" ]`, Expected: false},
+		{Expr: `process.ancestors.name in [ "192.168.1.20", "10.0.0.0/8" ]`, Expected: true},
+		{Expr: `"fd00::1" in [ "fd00::/8" ]`, Expected: false},
+		{Expr: `"10.0.0.0/8" in [ "10.0.0.0/8" ]`, Expected: true},
+	}
+
+	for _, test := range tests {
+		result, _, err := eval(t, event, test.Expr)
+		if err != nil {
+			t.Fatalf("error while evaluating `%s: %s`", test.Expr, err)
+		}
+
+		if result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t`\n%s", test.Expected, result, test.Expr)
+		}
+	}
+}
+
+func TestStringValues(t *testing.T) {
+	event := &testEvent{
+		process: testProcess{
+			name:      "sh",
+			ancestors: []string{"bash", "nginx", "systemd"},
+		},
+	}
+
+	tests := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{Expr: `process.ancestors.name == "nginx"`, Expected: true},
+		{Expr: `process.ancestors.name == "cron"`, Expected: false},
+		{Expr: `process.ancestors.name != "nginx"`, Expected: false},
+		{Expr: `process.ancestors.name != "cron"`, Expected: true},
+		{Expr: `process.ancestors.name =~ "ngi*"`, Expected: true},
+		{Expr: `process.ancestors.name !~ "ngi*"`, Expected: false},
+		{Expr: `process.ancestors.name in [ "cron", "nginx" ]`, Expected: true},
+		{Expr: `process.ancestors.name not in [ "cron", "nginx" ]`, Expected: false},
+		{Expr: `process.ancestors.name not in [ "cron", "atd" ]`, Expected: true},
+		{Expr: `process.name == "sh" && process.ancestors.name == "nginx"`, Expected: true},
+		{Expr: `process.name == "sh" && !(process.ancestors.name == "nginx")`, Expected: false},
+	}
+
+	for _, test := range tests {
+		result, _, err := eval(t, event, test.Expr)
+		if err != nil {
+			t.Fatalf("error while evaluating `%s: %s`", test.Expr, err)
+		}
+
+		if result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t`\n%s", test.Expected, result, test.Expr)
+		}
+	}
+
+	event.process.ancestors = nil
+	result, _, err := eval(t, event, `process.ancestors.name == "nginx"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result {
+		t.Error("expected a process without ancestors not to match")
+	}
+
+	if _, _, err := eval(t, event, `process.ancestors.name == process.name`); err == nil {
+		t.Error("expected an error when comparing ancestors to a field")
+	}
+}
+
 func TestComplex(t *testing.T) {
 	event := &testEvent{
 		open: testOpen{
@@ -415,6 +675,15 @@ func TestPartial(t *testing.T) {
 		{Expr: `open.filename == "test1" && process.uid == 123`, Field: "process.uid", IsDiscarder: false},
 		{Expr: `open.filename == "test1" && !process.is_root`, Field: "process.is_root", IsDiscarder: true},
 		{Expr: `open.filename == "test1" && process.is_root`, Field: "process.is_root", IsDiscarder: false},
+		{Expr: `open.filename == "test1" && process.ancestors.name == "nginx"`, Field: "open.filename", IsDiscarder: true},
+		{Expr: `open.filename == "xyz" && process.ancestors.name == "nginx"`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `open.filename == "test1" || process.ancestors.name == "nginx"`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `open.filename == "test1" || process.ancestors.name not in [ "nginx" ]`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `process.name =~ r"^ab"`, Field: "process.name", IsDiscarder: false},
+		{Expr: `process.name =~ r"^xy"`, Field: "process.name", IsDiscarder: true},
+		{Expr: `open.filename == "test1" && process.created_at < 5m`, Field: "open.filename", IsDiscarder: true},
+		{Expr: `open.filename == "test1" || process.created_at < 5m`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `process.created_at < 5m`, Field: "process.created_at", IsDiscarder: false},
 	}
 
 	ctx := &Context{}
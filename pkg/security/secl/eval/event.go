@@ -7,6 +7,7 @@ package eval
 
 import (
 	"reflect"
+	"time"
 	"unsafe"
 )
 
@@ -29,6 +30,13 @@ type Event interface {
 	GetPointer() unsafe.Pointer
 }
 
+// TimedEvent is implemented by the events which have to be evaluated at the time they happened, like
+// recorded events, instead of the time of the evaluation
+type TimedEvent interface {
+	// GetTimestamp returns the time of the Event, the time of the evaluation is used if it is zero
+	GetTimestamp() time.Time
+}
+
 func eventTypesFromFields(model Model, state *state) ([]EventType, error) {
 	events := make(map[EventType]bool)
 	for field := range state.fieldValues {
@@ -14,16 +14,19 @@ import (
 )
 
 type testProcess struct {
-	name   string
-	uid    int
-	gid    int
-	isRoot bool
+	name      string
+	ancestors []string
+	uid       int
+	gid       int
+	isRoot    bool
+	createdAt int
 }
 
 type testOpen struct {
 	filename string
 	mode     int
 	flags    int
+	mtime    int
 }
 
 type testMkdir struct {
@@ -31,6 +34,10 @@ type testMkdir struct {
 	mode     int
 }
 
+type testConnect struct {
+	ip string
+}
+
 type testEvent struct {
 	id   string
 	kind string
@@ -38,6 +45,7 @@ type testEvent struct {
 	process testProcess
 	open    testOpen
 	mkdir   testMkdir
+	connect testConnect
 }
 
 type testModel struct {
@@ -84,6 +92,13 @@ func (m *testModel) GetEvaluator(key string) (Evaluator, error) {
 			Field:   key,
 		}, nil
 
+	case "process.ancestors.name":
+
+		return &StringArrayEvaluator{
+			EvalFnc: func(ctx *Context) []string { return (*testEvent)(ctx.Object).process.ancestors },
+			Field:   key,
+		}, nil
+
 	case "process.uid":
 
 		return &IntEvaluator{
@@ -98,6 +113,14 @@ func (m *testModel) GetEvaluator(key string) (Evaluator, error) {
 			Field:   key,
 		}, nil
 
+	case "process.created_at":
+
+		return &IntEvaluator{
+			EvalFnc:     func(ctx *Context) int { return (*testEvent)(ctx.Object).process.createdAt },
+			Field:       key,
+			IsTimestamp: true,
+		}, nil
+
 	case "process.is_root":
 
 		return &BoolEvaluator{
@@ -126,6 +149,14 @@ func (m *testModel) GetEvaluator(key string) (Evaluator, error) {
 			Field:   key,
 		}, nil
 
+	case "open.mtime":
+
+		return &IntEvaluator{
+			EvalFnc:     func(ctx *Context) int { return (*testEvent)(ctx.Object).open.mtime },
+			Field:       key,
+			IsTimestamp: true,
+		}, nil
+
 	case "mkdir.filename":
 
 		return &StringEvaluator{
@@ -140,6 +171,14 @@ func (m *testModel) GetEvaluator(key string) (Evaluator, error) {
 			Field:   key,
 		}, nil
 
+	case "connect.ip":
+
+		return &StringEvaluator{
+			EvalFnc:   func(ctx *Context) string { return (*testEvent)(ctx.Object).connect.ip },
+			Field:     key,
+			IsAddress: true,
+		}, nil
+
 	}
 
 	return nil, &ErrFieldNotFound{Field: key}
@@ -152,6 +191,10 @@ func (e *testEvent) GetFieldValue(key string) (interface{}, error) {
 
 		return e.process.name, nil
 
+	case "process.ancestors.name":
+
+		return e.process.ancestors, nil
+
 	case "process.uid":
 
 		return e.process.uid, nil
@@ -160,6 +203,10 @@ func (e *testEvent) GetFieldValue(key string) (interface{}, error) {
 
 		return e.process.gid, nil
 
+	case "process.created_at":
+
+		return e.process.createdAt, nil
+
 	case "process.is_root":
 
 		return e.process.isRoot, nil
@@ -176,6 +223,10 @@ func (e *testEvent) GetFieldValue(key string) (interface{}, error) {
 
 		return e.open.mode, nil
 
+	case "open.mtime":
+
+		return e.open.mtime, nil
+
 	case "mkdir.filename":
 
 		return e.mkdir.filename, nil
@@ -184,6 +235,10 @@ func (e *testEvent) GetFieldValue(key string) (interface{}, error) {
 
 		return e.mkdir.mode, nil
 
+	case "connect.ip":
+
+		return e.connect.ip, nil
+
 	}
 
 	return nil, &ErrFieldNotFound{Field: key}
@@ -196,6 +251,10 @@ func (e *testEvent) GetFieldEventType(key string) (string, error) {
 
 		return "*", nil
 
+	case "process.ancestors.name":
+
+		return "*", nil
+
 	case "process.uid":
 
 		return "*", nil
@@ -204,6 +263,10 @@ func (e *testEvent) GetFieldEventType(key string) (string, error) {
 
 		return "*", nil
 
+	case "process.created_at":
+
+		return "*", nil
+
 	case "process.is_root":
 
 		return "*", nil
@@ -220,6 +283,10 @@ func (e *testEvent) GetFieldEventType(key string) (string, error) {
 
 		return "open", nil
 
+	case "open.mtime":
+
+		return "open", nil
+
 	case "mkdir.filename":
 
 		return "mkdir", nil
@@ -228,6 +295,10 @@ func (e *testEvent) GetFieldEventType(key string) (string, error) {
 
 		return "mkdir", nil
 
+	case "connect.ip":
+
+		return "connect", nil
+
 	}
 
 	return "", &ErrFieldNotFound{Field: key}
@@ -241,6 +312,11 @@ func (e *testEvent) SetFieldValue(key string, value interface{}) error {
 		e.process.name = value.(string)
 		return nil
 
+	case "process.ancestors.name":
+
+		e.process.ancestors = []string{value.(string)}
+		return nil
+
 	case "process.uid":
 
 		e.process.uid = value.(int)
@@ -251,6 +327,11 @@ func (e *testEvent) SetFieldValue(key string, value interface{}) error {
 		e.process.gid = value.(int)
 		return nil
 
+	case "process.created_at":
+
+		e.process.createdAt = value.(int)
+		return nil
+
 	case "process.is_root":
 
 		e.process.isRoot = value.(bool)
@@ -271,6 +352,11 @@ func (e *testEvent) SetFieldValue(key string, value interface{}) error {
 		e.open.mode = value.(int)
 		return nil
 
+	case "open.mtime":
+
+		e.open.mtime = value.(int)
+		return nil
+
 	case "mkdir.filename":
 
 		e.mkdir.filename = value.(string)
@@ -281,6 +367,11 @@ func (e *testEvent) SetFieldValue(key string, value interface{}) error {
 		e.mkdir.mode = value.(int)
 		return nil
 
+	case "connect.ip":
+
+		e.connect.ip = value.(string)
+		return nil
+
 	}
 
 	return &ErrFieldNotFound{Field: key}
@@ -293,6 +384,10 @@ func (e *testEvent) GetFieldType(key string) (reflect.Kind, error) {
 
 		return reflect.String, nil
 
+	case "process.ancestors.name":
+
+		return reflect.Slice, nil
+
 	case "process.uid":
 
 		return reflect.Int, nil
@@ -301,6 +396,10 @@ func (e *testEvent) GetFieldType(key string) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "process.created_at":
+
+		return reflect.Int, nil
+
 	case "process.is_root":
 
 		return reflect.Bool, nil
@@ -317,6 +416,10 @@ func (e *testEvent) GetFieldType(key string) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "open.mtime":
+
+		return reflect.Int, nil
+
 	case "mkdir.filename":
 
 		return reflect.String, nil
@@ -325,6 +428,10 @@ func (e *testEvent) GetFieldType(key string) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "connect.ip":
+
+		return reflect.String, nil
+
 	}
 
 	return reflect.Invalid, &ErrFieldNotFound{Field: key}
@@ -6,8 +6,11 @@
 package eval
 
 import (
+	"fmt"
+	"net"
 	"regexp"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -68,9 +71,35 @@ func patternToRegexp(pattern string) (*regexp.Regexp, error) {
 	return regexp.Compile("^" + quoted + "$")
 }
 
+// toRegexp compiles the right operand of a matching operator, either a regular expression
+// literal or a pattern, and returns the type of value it represents
+func toRegexp(b *StringEvaluator) (*regexp.Regexp, FieldValueType, error) {
+	if b.isRegexp {
+		re, err := regexp.Compile(b.Value)
+		if err != nil {
+			return nil, RegexpValueType, &ErrInvalidRegexp{Regexp: b.Value, Err: err}
+		}
+		return re, RegexpValueType, nil
+	}
+
+	re, err := patternToRegexp(b.Value)
+	return re, PatternValueType, err
+}
+
+// checkRegexpOp ensures that regular expression literals are only used with matching operators
+func checkRegexpOp(a *StringEvaluator, b *StringEvaluator, op string) error {
+	if a != nil && a.isRegexp {
+		return errors.New("a regular expression has to be the right operand")
+	}
+	if b.isRegexp && op != "=~" && op != "!~" {
+		return errors.New("a regular expression can only be used with `=~` and `!~`")
+	}
+	return nil
+}
+
 // StringMatches - String pattern matching operator
 func StringMatches(a *StringEvaluator, b *StringEvaluator, not bool, opts *Opts, state *state) (*BoolEvaluator, error) {
-	re, err := patternToRegexp(b.Value)
+	re, valueType, err := toRegexp(b)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +114,7 @@ func StringMatches(a *StringEvaluator, b *StringEvaluator, not bool, opts *Opts,
 	}
 
 	if a.Field != "" {
-		if err := state.UpdateFieldValues(a.Field, FieldValue{Value: b.Value, Type: PatternValueType}); err != nil {
+		if err := state.UpdateFieldValues(a.Field, FieldValue{Value: b.Value, Type: valueType}); err != nil {
 			return nil, err
 		}
 	}
@@ -139,6 +168,97 @@ func StringMatches(a *StringEvaluator, b *StringEvaluator, not bool, opts *Opts,
 	}, nil
 }
 
+// TimestampDelta - utimes.mtime - utimes.atime operator, the time elapsed between two timestamps, expressed
+// in nanoseconds. The result can only be compared with a duration.
+func TimestampDelta(a *IntEvaluator, b *IntEvaluator, opts *Opts, state *state) (*IntEvaluator, error) {
+	if !a.IsTimestamp || !b.IsTimestamp {
+		return nil, errors.New("a time difference can only be computed between two timestamp fields")
+	}
+
+	ea, eb := a.EvalFnc, b.EvalFnc
+
+	var evalFnc func(ctx *Context) int
+	if opts.Debug {
+		evalFnc = func(ctx *Context) int {
+			ctx.evalDepth++
+			op1, op2 := ea(ctx), eb(ctx)
+			result := op1 - op2
+			ctx.Logf("Evaluating %v - %v => %v", op1, op2, time.Duration(result))
+			ctx.evalDepth--
+			return result
+		}
+	} else {
+		evalFnc = func(ctx *Context) int {
+			return ea(ctx) - eb(ctx)
+		}
+	}
+
+	return &IntEvaluator{
+		EvalFnc: evalFnc,
+		isDelta: true,
+	}, nil
+}
+
+// DurationCompare - utimes.mtime < 5m operator, compares the time elapsed since a timestamp, expressed
+// in nanoseconds, with a duration. The time elapsed between two timestamps, utimes.mtime - utimes.atime < 5m,
+// is compared as is.
+func DurationCompare(a *IntEvaluator, b *IntEvaluator, op string, opts *Opts, state *state) (*BoolEvaluator, error) {
+	if !a.IsTimestamp && !a.isDelta {
+		return nil, errors.New("a duration can only be compared with a timestamp field")
+	}
+
+	var cmp func(elapsed int, duration int) bool
+	switch op {
+	case "<":
+		cmp = func(elapsed int, duration int) bool { return elapsed < duration }
+	case "<=":
+		cmp = func(elapsed int, duration int) bool { return elapsed <= duration }
+	case ">":
+		cmp = func(elapsed int, duration int) bool { return elapsed > duration }
+	case ">=":
+		cmp = func(elapsed int, duration int) bool { return elapsed >= duration }
+	default:
+		return nil, errors.New("a duration can only be compared with `<`, `<=`, `>` and `>=`")
+	}
+
+	// the result depends on the time of the evaluation, it can't be used to compute discarders
+	if state.field != "" {
+		return &BoolEvaluator{
+			Value:     true,
+			isPartial: true,
+		}, nil
+	}
+
+	ea, duration := a.EvalFnc, b.Value
+
+	elapsedFnc := func(ctx *Context) int {
+		return int(ctx.Now().UnixNano()) - ea(ctx)
+	}
+	if a.isDelta {
+		elapsedFnc = ea
+	}
+
+	var evalFnc func(ctx *Context) bool
+	if opts.Debug {
+		evalFnc = func(ctx *Context) bool {
+			ctx.evalDepth++
+			elapsed := elapsedFnc(ctx)
+			result := cmp(elapsed, duration)
+			ctx.Logf("Evaluating %v %s %v => %v", time.Duration(elapsed), op, time.Duration(duration), result)
+			ctx.evalDepth--
+			return result
+		}
+	} else {
+		evalFnc = func(ctx *Context) bool {
+			return cmp(elapsedFnc(ctx), duration)
+		}
+	}
+
+	return &BoolEvaluator{
+		EvalFnc: evalFnc,
+	}, nil
+}
+
 // Not - !true operator
 func Not(a *BoolEvaluator, opts *Opts, state *state) *BoolEvaluator {
 	isPartialLeaf := a.isPartial
@@ -235,14 +355,31 @@ func StringArrayContains(a *StringEvaluator, b *StringArray, not bool, opts *Opt
 		isPartialLeaf = true
 	}
 
+	// only address fields match the CIDRs of the array, other fields compare them as plain strings
+	var cidrs map[string]*net.IPNet
+	if a.IsAddress {
+		cidrs = parseCIDRs(b.Values)
+	}
+
 	if a.Field != "" {
 		for _, value := range b.Values {
-			if err := state.UpdateFieldValues(a.Field, FieldValue{Value: value, Type: ScalarValueType}); err != nil {
+			valueType := ScalarValueType
+			if _, isCIDR := cidrs[value]; isCIDR {
+				valueType = CIDRValueType
+			}
+
+			if err := state.UpdateFieldValues(a.Field, FieldValue{Value: value, Type: valueType}); err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	// values match either an element of the array or one of its CIDRs
+	contains := func(s string) bool {
+		i := sort.SearchStrings(b.Values, s)
+		return i < len(b.Values) && b.Values[i] == s || cidrsContain(cidrs, s)
+	}
+
 	if a.EvalFnc != nil {
 		ea := a.EvalFnc
 
@@ -251,8 +388,7 @@ func StringArrayContains(a *StringEvaluator, b *StringArray, not bool, opts *Opt
 			evalFnc = func(ctx *Context) bool {
 				ctx.evalDepth++
 				s := ea(ctx)
-				i := sort.SearchStrings(b.Values, s)
-				result := i < len(b.Values) && b.Values[i] == s
+				result := contains(s)
 				ctx.Logf("Evaluating %s in %+v => %v", s, b.Values, result)
 				if not {
 					result = !result
@@ -263,8 +399,7 @@ func StringArrayContains(a *StringEvaluator, b *StringArray, not bool, opts *Opt
 		} else {
 			evalFnc = func(ctx *Context) bool {
 				s := ea(ctx)
-				i := sort.SearchStrings(b.Values, s)
-				result := i < len(b.Values) && b.Values[i] == s
+				result := contains(s)
 				if not {
 					result = !result
 				}
@@ -280,8 +415,7 @@ func StringArrayContains(a *StringEvaluator, b *StringArray, not bool, opts *Opt
 
 	ea := true
 	if !isPartialLeaf {
-		i := sort.SearchStrings(b.Values, a.Value)
-		ea = i < len(b.Values) && b.Values[i] == a.Value
+		ea = contains(a.Value)
 		if not {
 			ea = !ea
 		}
@@ -293,6 +427,155 @@ func StringArrayContains(a *StringEvaluator, b *StringArray, not bool, opts *Opt
 	}, nil
 }
 
+// StringValuesContains - process.ancestors.name == "nginx" operator, true if any of the values is equal to the string
+func StringValuesContains(a *StringArrayEvaluator, b *StringEvaluator, not bool, opts *Opts, state *state) (*BoolEvaluator, error) {
+	if b.EvalFnc != nil {
+		return nil, errors.New("value has to be a scalar string")
+	}
+
+	if a.Field != "" {
+		if err := state.UpdateFieldValues(a.Field, FieldValue{Value: b.Value, Type: ScalarValueType}); err != nil {
+			return nil, err
+		}
+	}
+
+	return stringValuesAny(a, func(s string) bool { return s == b.Value }, not, "== "+b.Value, opts, state), nil
+}
+
+// StringValuesMatches - process.ancestors.name =~ "ngin*" operator, true if any of the values matches the pattern
+func StringValuesMatches(a *StringArrayEvaluator, b *StringEvaluator, not bool, opts *Opts, state *state) (*BoolEvaluator, error) {
+	if b.EvalFnc != nil {
+		return nil, errors.New("regex has to be a scalar string")
+	}
+
+	re, valueType, err := toRegexp(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Field != "" {
+		if err := state.UpdateFieldValues(a.Field, FieldValue{Value: b.Value, Type: valueType}); err != nil {
+			return nil, err
+		}
+	}
+
+	return stringValuesAny(a, re.MatchString, not, "=~ "+re.String(), opts, state), nil
+}
+
+// StringValuesArrayContains - process.ancestors.name in ["...", "..."] operator, true if any of the values is in the array
+func StringValuesArrayContains(a *StringArrayEvaluator, b *StringArray, not bool, opts *Opts, state *state) (*BoolEvaluator, error) {
+	// only address fields match the CIDRs of the array, other fields compare them as plain strings
+	var cidrs map[string]*net.IPNet
+	if a.IsAddress {
+		cidrs = parseCIDRs(b.Values)
+	}
+
+	if a.Field != "" {
+		for _, value := range b.Values {
+			valueType := ScalarValueType
+			if _, isCIDR := cidrs[value]; isCIDR {
+				valueType = CIDRValueType
+			}
+
+			if err := state.UpdateFieldValues(a.Field, FieldValue{Value: value, Type: valueType}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	contains := func(s string) bool {
+		i := sort.SearchStrings(b.Values, s)
+		return i < len(b.Values) && b.Values[i] == s || cidrsContain(cidrs, s)
+	}
+
+	return stringValuesAny(a, contains, not, fmt.Sprintf("in %+v", b.Values), opts, state), nil
+}
+
+// stringValuesAny returns an evaluator that is true if any of the values of the array evaluator
+// matches, or, when not is set, if none of them matches
+func stringValuesAny(a *StringArrayEvaluator, match func(s string) bool, not bool, desc string, opts *Opts, state *state) *BoolEvaluator {
+	isPartialLeaf := a.isPartial
+	if a.Field != "" && state.field != "" && a.Field != state.field {
+		isPartialLeaf = true
+	}
+
+	anyMatch := func(values []string) bool {
+		for _, value := range values {
+			if match(value) {
+				return !not
+			}
+		}
+		return not
+	}
+
+	if a.EvalFnc != nil {
+		ea := a.EvalFnc
+
+		var evalFnc func(ctx *Context) bool
+		if opts.Debug {
+			evalFnc = func(ctx *Context) bool {
+				ctx.evalDepth++
+				values := ea(ctx)
+				result := anyMatch(values)
+				ctx.Logf("Evaluating any of %+v %s (not: %v) => %v", values, desc, not, result)
+				ctx.evalDepth--
+				return result
+			}
+		} else {
+			evalFnc = func(ctx *Context) bool {
+				return anyMatch(ea(ctx))
+			}
+		}
+
+		return &BoolEvaluator{
+			EvalFnc:   evalFnc,
+			isPartial: isPartialLeaf,
+		}
+	}
+
+	ea := true
+	if !isPartialLeaf {
+		ea = anyMatch(a.Values)
+	}
+
+	return &BoolEvaluator{
+		Value:     ea,
+		isPartial: isPartialLeaf,
+	}
+}
+
+// parseCIDRs returns the values of the array that are valid CIDRs, indexed by value
+func parseCIDRs(values []string) map[string]*net.IPNet {
+	var cidrs map[string]*net.IPNet
+	for _, value := range values {
+		if _, ipnet, err := net.ParseCIDR(value); err == nil {
+			if cidrs == nil {
+				cidrs = make(map[string]*net.IPNet)
+			}
+			cidrs[value] = ipnet
+		}
+	}
+	return cidrs
+}
+
+func cidrsContain(cidrs map[string]*net.IPNet, value string) bool {
+	if len(cidrs) == 0 {
+		return false
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipnet := range cidrs {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // IntArrayContains - 1 in [1, 2, 3] operator
 func IntArrayContains(a *IntEvaluator, b *IntArray, not bool, opts *Opts, state *state) (*BoolEvaluator, error) {
 	isPartialLeaf := a.isPartial
@@ -59,7 +59,9 @@ type structField struct {
 	Public     bool
 	Event      string
 	Handler    string
-	OrigType   string
+	OrigType    string
+	IsAddress   bool
+	IsTimestamp bool
 }
 
 func resolveSymbol(pkg, symbol string) (types.Object, error) {
@@ -70,6 +72,16 @@ func resolveSymbol(pkg, symbol string) (types.Object, error) {
 	return nil, fmt.Errorf("Failed to retrieve package info for %s", pkg)
 }
 
+// hasFieldOption returns whether the options of a field tag contain the given option
+func hasFieldOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
 func origTypeToBasicType(kind string) string {
 	switch kind {
 	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
@@ -78,12 +90,20 @@ func origTypeToBasicType(kind string) string {
 	return kind
 }
 
-func handleBasic(name, alias, kind, event string) {
+func handleBasic(name, alias, kind, event string, options []string) {
 	fmt.Printf("handleBasic %s %s\n", name, kind)
 
 	switch kind {
 	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
-		module.Fields[alias] = &structField{Name: name, ReturnType: "int", Public: true, Event: event, OrigType: kind, BasicType: origTypeToBasicType(kind)}
+		module.Fields[alias] = &structField{
+			Name:        name,
+			ReturnType:  "int",
+			Public:      true,
+			Event:       event,
+			OrigType:    kind,
+			BasicType:   origTypeToBasicType(kind),
+			IsTimestamp: hasFieldOption(options, "timestamp"),
+		}
 	default:
 		public := false
 		firstChar := strings.TrimPrefix(kind, "[]")
@@ -105,7 +125,7 @@ func handleBasic(name, alias, kind, event string) {
 	}
 }
 
-func handleField(astFile *ast.File, name, alias, prefix, aliasPrefix, pkgName string, fieldType *ast.Ident, event string) error {
+func handleField(astFile *ast.File, name, alias, prefix, aliasPrefix, pkgName string, fieldType *ast.Ident, event string, options []string) error {
 	fmt.Printf("handleField fieldName %s, alias %s, prefix %s, aliasPrefix %s, pkgName %s, fieldType, %s\n", name, alias, prefix, aliasPrefix, pkgName, fieldType)
 
 	switch fieldType.Name {
@@ -114,7 +134,7 @@ func handleField(astFile *ast.File, name, alias, prefix, aliasPrefix, pkgName st
 			name = prefix + "." + name
 			alias = aliasPrefix + "." + alias
 		}
-		handleBasic(name, alias, fieldType.Name, event)
+		handleBasic(name, alias, fieldType.Name, event, options)
 	default:
 		symbol, err := resolveSymbol(pkgName, fieldType.Name)
 		if err != nil {
@@ -158,9 +178,11 @@ func handleSpec(astFile *ast.File, spec interface{}, prefix, aliasPrefix, event
 				if len(field.Names) > 0 {
 					fieldName := field.Names[0].Name
 					fieldAlias := fieldName
+					var fieldOptions []string
 
 					if fieldTag, found := tag.Lookup("field"); found {
 						split := strings.Split(fieldTag, ",")
+						fieldOptions = split[1:]
 
 						if fieldAlias = split[0]; fieldAlias == "-" {
 							continue FIELD
@@ -188,6 +210,21 @@ func handleSpec(astFile *ast.File, spec interface{}, prefix, aliasPrefix, event
 									Public:     true,
 									Event:      event,
 									OrigType:   fieldType.Name,
+									IsAddress:  hasFieldOption(split[1:], "address"),
+								}
+							} else if arrayType, ok := field.Type.(*ast.ArrayType); ok {
+								// array fields, like the ancestors of a process, match when any of their values match
+								if eltType, ok := arrayType.Elt.(*ast.Ident); ok {
+									module.Fields[fieldAlias] = &structField{
+										Name:       fmt.Sprintf("%s.%s", prefix, fieldName),
+										BasicType:  origTypeToBasicType(eltType.Name),
+										Handler:    fmt.Sprintf("%s.%s", prefix, fnc),
+										ReturnType: kind,
+										IsArray:    true,
+										Public:     true,
+										Event:      event,
+										OrigType:   "[]" + eltType.Name,
+									}
 								}
 							}
 							continue
@@ -195,13 +232,13 @@ func handleSpec(astFile *ast.File, spec interface{}, prefix, aliasPrefix, event
 					}
 
 					if fieldType, ok := field.Type.(*ast.Ident); ok {
-						if err := handleField(astFile, fieldName, fieldAlias, prefix, aliasPrefix, filepath.Base(pkgname), fieldType, event); err != nil {
+						if err := handleField(astFile, fieldName, fieldAlias, prefix, aliasPrefix, filepath.Base(pkgname), fieldType, event, fieldOptions); err != nil {
 							log.Print(err)
 						}
 						continue
 					} else if fieldType, ok := field.Type.(*ast.StarExpr); ok {
 						if itemIdent, ok := fieldType.X.(*ast.Ident); ok {
-							if err := handleField(astFile, fieldName, fieldAlias, prefix, aliasPrefix, filepath.Base(pkgname), itemIdent, event); err != nil {
+							if err := handleField(astFile, fieldName, fieldAlias, prefix, aliasPrefix, filepath.Base(pkgname), itemIdent, event, fieldOptions); err != nil {
 								log.Print(err)
 							}
 							continue
@@ -340,8 +377,17 @@ func (m *Model) GetEvaluator(field eval.Field) (eval.Evaluator, error) {
 	{{else if eq $Field.ReturnType "bool"}}
 		return &eval.BoolEvaluator{
 			EvalFnc: func(ctx *eval.Context) bool { return {{$Return}} },
+	{{else if eq $Field.ReturnType "[]string"}}
+		return &eval.StringArrayEvaluator{
+			EvalFnc: func(ctx *eval.Context) []string { return {{$Return}} },
 	{{end}}
 			Field: field,
+	{{if $Field.IsAddress}}
+			IsAddress: true,
+	{{end}}
+	{{if $Field.IsTimestamp}}
+			IsTimestamp: true,
+	{{end}}
 		}, nil
 	{{end}}
 	}
@@ -364,6 +410,8 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 			return int({{$Return}}), nil
 		{{else if eq $Field.ReturnType "bool"}}
 			return {{$Return}}, nil
+		{{else if eq $Field.ReturnType "[]string"}}
+			return {{$Return}}, nil
 		{{end}}
 		{{end}}
 		}
@@ -393,6 +441,8 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 			return reflect.Int, nil
 		{{else if eq $Field.ReturnType "bool"}}
 			return reflect.Bool, nil
+		{{else if eq $Field.ReturnType "[]string"}}
+			return reflect.Slice, nil
 		{{end}}
 		{{end}}
 		}
@@ -419,7 +469,17 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 			{{$FieldName}} = {{$Field.OrigType}}(v)
 			return nil
 		{{else if eq $Field.BasicType "bool"}}
-			if {{$FieldName}}, ok = value.(string); !ok {
+			if {{$FieldName}}, ok = value.(bool); !ok {
+				return &eval.ErrValueTypeMismatch{Field: "{{$Field.Name}}"}
+			}
+			return nil
+		{{else if eq $Field.OrigType "[]string"}}
+			switch v := value.(type) {
+			case string:
+				{{$FieldName}} = []string{v}
+			case []string:
+				{{$FieldName}} = v
+			default:
 				return &eval.ErrValueTypeMismatch{Field: "{{$Field.Name}}"}
 			}
 			return nil
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+)
+
+func TestExec(t *testing.T) {
+	ruleDef := &rules.RuleDefinition{
+		ID:         "test_rule",
+		Expression: `exec.filename == "{{.Root}}/test-exec" && exec.args == "-l {{.Root}}"`,
+	}
+
+	test, err := newTestModule(nil, []*rules.RuleDefinition{ruleDef}, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	testFile, _, err := test.Path("test-exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executable, err := exec.LookPath("ls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(executable, testFile); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(testFile)
+
+	cmd := exec.Command(testFile, "-l", test.Root())
+	cmd.Env = []string{"DD_TEST=1", "DD_API_KEY=secret"}
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	event, rule, err := test.GetEvent()
+	if err != nil {
+		t.Error(err)
+	} else {
+		if rule.ID != "test_rule" {
+			t.Errorf("expected rule 'test-rule' to be triggered, got %s", rule.ID)
+		}
+
+		if value, _ := event.GetFieldValue("exec.envs"); !strings.Contains(value.(string), "DD_API_KEY=********") {
+			t.Errorf("expected DD_API_KEY to be scrubbed, got %s", value)
+		}
+	}
+}
+
+func TestExit(t *testing.T) {
+	ruleDef := &rules.RuleDefinition{
+		ID:         "test_rule",
+		Expression: `exit.code == 3 && process.name == "sh"`,
+	}
+
+	test, err := newTestModule(nil, []*rules.RuleDefinition{ruleDef}, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	if err := exec.Command("sh", "-c", "exit 3").Run(); err == nil {
+		t.Fatal("expected the command to fail")
+	}
+
+	_, rule, err := test.GetEvent()
+	if err != nil {
+		t.Error(err)
+	} else if rule.ID != "test_rule" {
+		t.Errorf("expected rule 'test-rule' to be triggered, got %s", rule.ID)
+	}
+}
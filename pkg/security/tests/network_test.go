@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"net"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+)
+
+func TestNetwork(t *testing.T) {
+	ruleDefs := []*rules.RuleDefinition{
+		{
+			ID:         "test_rule_bind",
+			Expression: `bind.family == AF_INET && bind.ip in [ "127.0.0.0/8" ] && bind.protocol == IPPROTO_TCP`,
+		},
+		{
+			ID:         "test_rule_listen",
+			Expression: `listen.family == AF_INET && listen.ip == "127.0.0.1" && listen.port > 0`,
+		},
+		{
+			ID:         "test_rule_connect",
+			Expression: `connect.family == AF_INET && connect.ip in [ "127.0.0.0/8" ] && connect.port > 0`,
+		},
+	}
+
+	test, err := newTestModule(nil, ruleDefs, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	conn, err := net.Dial("tcp4", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, id := range []string{"test_rule_bind", "test_rule_listen", "test_rule_connect"} {
+		_, rule, err := test.GetEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rule.ID != id {
+			t.Errorf("expected rule '%s' to be triggered, got %s", id, rule.ID)
+		}
+	}
+}
@@ -10,6 +10,7 @@ package tests
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"os/user"
 	"path"
 	"testing"
@@ -63,3 +64,45 @@ func TestProcess(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessAncestors(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleDef := &rules.RuleDefinition{
+		ID:         "test_rule",
+		Expression: fmt.Sprintf(`open.filename == "{{.Root}}/test-ancestors" && process.ancestors.name == "sh" && process.ancestors.name == "%s"`, path.Base(executable)),
+	}
+
+	test, err := newTestModule(nil, []*rules.RuleDefinition{ruleDef}, testOpts{enableFilters: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	testFile, _, err := test.Path("test-ancestors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(testFile)
+
+	// the trailing command prevents the shell from replacing itself with touch
+	if err := exec.Command("sh", "-c", fmt.Sprintf("touch %s; true", testFile)).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	event, rule, err := test.GetEvent()
+	if err != nil {
+		t.Error(err)
+	} else {
+		if rule.ID != "test_rule" {
+			t.Errorf("expected rule 'test-rule' to be triggered, got %s", rule.ID)
+		}
+
+		if value, _ := event.GetFieldValue("process.name"); value.(string) != "touch" {
+			t.Errorf("expected process name `touch`, got `%v`", value)
+		}
+	}
+}
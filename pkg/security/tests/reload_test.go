@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/module"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+)
+
+func TestReloadPolicies(t *testing.T) {
+	rule := &rules.RuleDefinition{
+		ID:         "test_rule_open",
+		Expression: `open.filename == "{{.Root}}/test-reload-open" && open.flags & O_CREAT != 0`,
+	}
+
+	test, err := newTestModule(nil, []*rules.RuleDefinition{rule}, testOpts{enableFilters: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	testFile, testFilePtr, err := test.Path("test-reload-mkdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("reload", func(t *testing.T) {
+		ruleDefs := []*rules.RuleDefinition{
+			{
+				ID:         "test_rule_mkdir",
+				Expression: fmt.Sprintf(`mkdir.filename == "%s"`, testFile),
+			},
+		}
+
+		diff, err := test.reloadPolicies(nil, ruleDefs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(diff.Added, []string{"test_rule_mkdir"}) || !reflect.DeepEqual(diff.Removed, []string{"test_rule_open"}) {
+			t.Errorf("unexpected diff: %+v", diff)
+		}
+
+		if _, _, errno := syscall.Syscall(syscall.SYS_MKDIR, uintptr(testFilePtr), 0755, 0); errno != 0 {
+			t.Fatal(error(errno))
+		}
+		defer os.Remove(testFile)
+
+		_, rule, err := test.GetEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rule.ID != "test_rule_mkdir" {
+			t.Errorf("expected rule test_rule_mkdir, got %s", rule.ID)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		ruleDefs := []*rules.RuleDefinition{
+			{
+				ID:         "test_rule_invalid",
+				Expression: `mkdir.filename ==`,
+			},
+		}
+
+		if _, err := test.reloadPolicies(nil, ruleDefs); err == nil {
+			t.Fatal("expected the reload of an invalid policy to fail")
+		}
+
+		if ids := test.module.(*module.Module).GetRuleSet().ListRuleIDs(); !reflect.DeepEqual(ids, []string{"test_rule_mkdir"}) {
+			t.Errorf("expected the previous rules to be kept, got %v", ids)
+		}
+	})
+}
@@ -221,6 +221,46 @@ func newTestModule(macros []*rules.MacroDefinition, rules []*rules.RuleDefinitio
 	return testMod, nil
 }
 
+// reloadPolicies replaces the policies of the module with the given macros and rules, and reloads them
+func (tm *testModule) reloadPolicies(macros []*rules.MacroDefinition, rulesDef []*rules.RuleDefinition) (*rules.RuleSetDiff, error) {
+	tmpl, err := template.New("test-policy").Parse(testPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := tmpl.Execute(buffer, map[string]interface{}{
+		"Rules":  rulesDef,
+		"Macros": macros,
+	}); err != nil {
+		return nil, err
+	}
+
+	testPolicyFile, err := ioutil.TempFile(tm.st.root, "secagent-policy.*.policy")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(testPolicyFile.Name())
+
+	if _, err := testPolicyFile.Write(buffer.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err := testPolicyFile.Close(); err != nil {
+		return nil, err
+	}
+
+	mod := tm.module.(*module.Module)
+
+	diff, err := mod.ReloadPolicies()
+	if err != nil {
+		return nil, err
+	}
+	mod.GetRuleSet().AddListener(tm)
+
+	return diff, nil
+}
+
 func (tm *testModule) Root() string {
 	return tm.st.root
 }
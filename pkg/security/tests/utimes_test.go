@@ -143,3 +143,55 @@ func TestUtime(t *testing.T) {
 		}
 	})
 }
+
+func TestUtimeDuration(t *testing.T) {
+	rule1 := &rules.RuleDefinition{
+		ID:         "test_rule_recent",
+		Expression: `utimes.filename =~ r"/test-utime-duration$" && utimes.mtime < 1h`,
+	}
+
+	rule2 := &rules.RuleDefinition{
+		ID:         "test_rule_old",
+		Expression: `utimes.filename =~ r"/test-utime-duration$" && utimes.mtime > 24h`,
+	}
+
+	test, err := newTestModule(nil, []*rules.RuleDefinition{rule1, rule2}, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	testFile, _, err := test.Path("test-utime-duration")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(testFile)
+
+	for _, tc := range []struct {
+		ruleID string
+		mtime  time.Time
+	}{
+		{ruleID: "test_rule_recent", mtime: time.Now().Add(-time.Minute)},
+		{ruleID: "test_rule_old", mtime: time.Now().Add(-48 * time.Hour)},
+	} {
+		if err := os.Chtimes(testFile, tc.mtime, tc.mtime); err != nil {
+			t.Fatal(err)
+		}
+
+		_, rule, err := test.GetEvent()
+		if err != nil {
+			t.Error(err)
+		} else if rule.ID != tc.ruleID {
+			t.Errorf("expected rule '%s' to be triggered, got %s", tc.ruleID, rule.ID)
+		}
+	}
+}
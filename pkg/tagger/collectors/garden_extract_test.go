@@ -92,6 +92,10 @@ func (fakeDCAClient) GetPodsMetadataForNode(nodeName string) (apiv1.NamespacesPo
 	panic("implement me")
 }
 
+func (fakeDCAClient) GetPodsMetadataForIPs(ips []string) (apiv1.PodIPsMetadataResponse, error) {
+	panic("implement me")
+}
+
 func (fakeDCAClient) GetKubernetesMetadataNames(nodeName, ns, podName string) ([]string, error) {
 	panic("implement me")
 }
@@ -63,6 +63,9 @@ func (f *FakeDCAClient) GetNodeLabels(nodeName string) (map[string]string, error
 func (f *FakeDCAClient) GetPodsMetadataForNode(nodeName string) (apiv1.NamespacesPodsStringsSet, error) {
 	return f.PodMetadataForNode, f.PodMetadataForNodeErr
 }
+func (f *FakeDCAClient) GetPodsMetadataForIPs(ips []string) (apiv1.PodIPsMetadataResponse, error) {
+	panic("implement me")
+}
 func (f *FakeDCAClient) GetKubernetesMetadataNames(nodeName, ns, podName string) ([]string, error) {
 	return f.KubernetesMetadataNames, f.KubernetesMetadataNamesErr
 }
@@ -6,6 +6,7 @@
 package clusteragent
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -49,6 +50,7 @@ type DCAClientInterface interface {
 	GetVersion() (version.Version, error)
 	GetNodeLabels(nodeName string) (map[string]string, error)
 	GetPodsMetadataForNode(nodeName string) (apiv1.NamespacesPodsStringsSet, error)
+	GetPodsMetadataForIPs(ips []string) (apiv1.PodIPsMetadataResponse, error)
 	GetKubernetesMetadataNames(nodeName, ns, podName string) ([]string, error)
 	GetCFAppsMetadataForNode(nodename string) (map[string][]string, error)
 
@@ -365,6 +367,45 @@ func (c *DCAClient) GetPodsMetadataForNode(nodeName string) (apiv1.NamespacesPod
 	return metadataPodPayload.Nodes[nodeName].Services, nil
 }
 
+// GetPodsMetadataForIPs queries the datadog cluster agent to get the metadata of the pods
+// owning the given IPs. IPs which aren't owned by a pod are left out of the response.
+func (c *DCAClient) GetPodsMetadataForIPs(ips []string) (apiv1.PodIPsMetadataResponse, error) {
+	const dcaMetadataPath = "api/v1/tags/ips"
+
+	if c == nil {
+		return nil, fmt.Errorf("cluster agent's client is not properly initialized")
+	}
+
+	// https://host:port/api/v1/tags/ips
+	rawURL := fmt.Sprintf("%s/%s", c.clusterAgentAPIEndpoint, dcaMetadataPath)
+	body, err := json.Marshal(ips)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", rawURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = c.clusterAgentAPIRequestHeaders
+
+	resp, err := c.clusterAgentAPIClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from cluster agent: %d", resp.StatusCode)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var metadata apiv1.PodIPsMetadataResponse
+	err = json.Unmarshal(b, &metadata)
+	return metadata, err
+}
+
 // GetKubernetesMetadataNames queries the datadog cluster agent to get nodeName/podName registered
 // Kubernetes metadata.
 func (c *DCAClient) GetKubernetesMetadataNames(nodeName, ns, podName string) ([]string, error) {
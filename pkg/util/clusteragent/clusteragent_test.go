@@ -86,7 +86,8 @@ func newDummyClusterAgent() (*dummyClusterAgent, error) {
 			},
 		},
 		rawResponses: map[string]string{
-			"/version": `{"Major":0, "Minor":0, "Patch":0, "Pre":"test", "Meta":"test", "Commit":"1337"}`,
+			"/version":         `{"Major":0, "Minor":0, "Patch":0, "Pre":"test", "Meta":"test", "Commit":"1337"}`,
+			"/api/v1/tags/ips": `{"10.0.1.2": {"name": "web", "namespace": "default", "uid": "uid-web", "container_ids": ["ctr-web"], "container_ports": {"8080": "ctr-web"}, "services": ["web-svc"]}}`,
 		},
 		token:    config.Datadog.GetString("cluster_agent.auth_token"),
 		requests: make(chan *http.Request, 100),
@@ -518,6 +519,33 @@ func (suite *clusterAgentSuite) TestGetKubernetesMetadataNames() {
 	}
 }
 
+func (suite *clusterAgentSuite) TestGetPodsMetadataForIPs() {
+	dca, err := newDummyClusterAgent()
+	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
+
+	ts, p, err := dca.StartTLS()
+	defer ts.Close()
+	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
+
+	mockConfig.Set("cluster_agent.url", fmt.Sprintf("https://127.0.0.1:%d", p))
+
+	ca, err := GetClusterAgentClient()
+	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
+
+	metadata, err := ca.GetPodsMetadataForIPs([]string{"10.0.1.2", "1.1.1.1"})
+	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
+	assert.Equal(suite.T(), apiv1.PodIPsMetadataResponse{
+		"10.0.1.2": {
+			Name:           "web",
+			Namespace:      "default",
+			UID:            "uid-web",
+			ContainerIDs:   []string{"ctr-web"},
+			ContainerPorts: map[int32]string{8080: "ctr-web"},
+			Services:       []string{"web-svc"},
+		},
+	}, metadata)
+}
+
 func (suite *clusterAgentSuite) TestGetCFAppsMetadataForNode() {
 	dca, err := newDummyClusterAgentWithCFMetadata()
 	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
@@ -49,6 +49,12 @@ func GetMetadataMapBundleOnAllNodes(_ *APIClient) (*apiv1.MetadataResponse, erro
 	return nil, nil
 }
 
+// GetPodsMetadataForIPs returns the metadata of the pods owning the given IPs.
+func GetPodsMetadataForIPs(ips []string) (apiv1.PodIPsMetadataResponse, error) {
+	log.Errorf("GetPodsMetadataForIPs not implemented %s", ErrNotCompiled.Error())
+	return nil, ErrNotCompiled
+}
+
 // GetNodeLabels retrieves the labels of the queried node from the cache of the shared informer.
 func GetNodeLabels(nodeName string) (map[string]string, error) {
 	log.Errorf("GetNodeLabels not implemented %s", ErrNotCompiled.Error())
@@ -52,6 +52,10 @@ var controllerCatalog = map[controllerName]controllerFuncs{
 		func() bool { return config.Datadog.GetBool("cluster_checks.enabled") },
 		registerEndpointsInformer,
 	},
+	podIPsController: {
+		func() bool { return config.Datadog.GetBool("kubernetes_collect_pod_ips") },
+		registerPodIPsInformers,
+	},
 }
 
 type ControllerContext struct {
@@ -0,0 +1,168 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package apiserver
+
+import (
+	"fmt"
+	"sort"
+
+	apiv1 "github.com/DataDog/datadog-agent/pkg/clusteragent/api/v1"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// podIPIndex is the name of the index of the pods informer on the pod IPs
+const podIPIndex = "podIP"
+
+// registerPodIPsInformers registers the pods informer, indexed on the pod IPs,
+// and the services informer used to find the services selecting a pod.
+func registerPodIPsInformers(ctx ControllerContext, c chan error) {
+	podInformer := ctx.InformerFactory.Core().V1().Pods().Informer()
+	if err := podInformer.AddIndexers(cache.Indexers{podIPIndex: podIPIndexFunc}); err != nil {
+		c <- err
+		return
+	}
+	ctx.informers[PodsInformer] = podInformer
+	ctx.informers[ServicesInformer] = ctx.InformerFactory.Core().V1().Services().Informer()
+}
+
+// podIPIndexFunc indexes the pods on their IPs. Pods in the host network share
+// the IP of their host and aren't indexed.
+func podIPIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || pod.Spec.HostNetwork {
+		return nil, nil
+	}
+	var ips []string
+	if pod.Status.PodIP != "" {
+		ips = append(ips, pod.Status.PodIP)
+	}
+	for _, ip := range pod.Status.PodIPs {
+		if ip.IP != "" && ip.IP != pod.Status.PodIP {
+			ips = append(ips, ip.IP)
+		}
+	}
+	return ips, nil
+}
+
+// GetPodsMetadataForIPs returns the metadata of the pods owning the given IPs from the
+// cache of the shared informers. IPs which aren't owned by a pod are left out.
+func GetPodsMetadataForIPs(ips []string) (apiv1.PodIPsMetadataResponse, error) {
+	if !config.Datadog.GetBool("kubernetes_collect_pod_ips") {
+		return nil, fmt.Errorf("pod IPs collection is disabled on the Cluster Agent")
+	}
+	as, err := GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return podsMetadataForIPs(
+		as.InformerFactory.Core().V1().Pods().Informer().GetIndexer(),
+		as.InformerFactory.Core().V1().Services().Lister(),
+		ips,
+	)
+}
+
+func podsMetadataForIPs(pods cache.Indexer, serviceLister listersv1.ServiceLister, ips []string) (apiv1.PodIPsMetadataResponse, error) {
+	resp := make(apiv1.PodIPsMetadataResponse, len(ips))
+	servicesByNamespace := make(map[string][]*v1.Service)
+	for _, ip := range ips {
+		objs, err := pods.ByIndex(podIPIndex, ip)
+		if err != nil {
+			return nil, err
+		}
+		pod := podOwningIP(objs)
+		if pod == nil {
+			continue
+		}
+
+		services, found := servicesByNamespace[pod.Namespace]
+		if !found {
+			services, err = serviceLister.Services(pod.Namespace).List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			servicesByNamespace[pod.Namespace] = services
+		}
+		resp[ip] = podIPMetadata(pod, services)
+	}
+	return resp, nil
+}
+
+// podOwningIP returns the pod currently owning an IP. The IP of a completed pod
+// may already be reused by a running one.
+func podOwningIP(objs []interface{}) *v1.Pod {
+	var owner *v1.Pod
+	for _, obj := range objs {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			continue
+		}
+		if owner == nil {
+			owner = pod
+			continue
+		}
+		running, ownerRunning := pod.Status.Phase == v1.PodRunning, owner.Status.Phase == v1.PodRunning
+		if running != ownerRunning {
+			if running {
+				owner = pod
+			}
+			continue
+		}
+		if owner.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			owner = pod
+		}
+	}
+	return owner
+}
+
+func podIPMetadata(pod *v1.Pod, services []*v1.Service) *apiv1.PodIPMetadata {
+	containerIDs := make(map[string]string, len(pod.Status.ContainerStatuses))
+	for _, ctr := range pod.Status.ContainerStatuses {
+		if ctr.ContainerID != "" && ctr.State.Running != nil {
+			containerIDs[ctr.Name] = containers.ContainerIDForEntity(ctr.ContainerID)
+		}
+	}
+	meta := &apiv1.PodIPMetadata{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		UID:       string(pod.UID),
+	}
+	for _, id := range containerIDs {
+		meta.ContainerIDs = append(meta.ContainerIDs, id)
+	}
+	sort.Strings(meta.ContainerIDs)
+
+	for _, ctr := range pod.Spec.Containers {
+		id, ok := containerIDs[ctr.Name]
+		if !ok {
+			continue
+		}
+		for _, port := range ctr.Ports {
+			if meta.ContainerPorts == nil {
+				meta.ContainerPorts = make(map[int32]string)
+			}
+			meta.ContainerPorts[port.ContainerPort] = id
+		}
+	}
+
+	for _, svc := range services {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		if labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			meta.Services = append(meta.Services, svc.Name)
+		}
+	}
+	sort.Strings(meta.Services)
+	return meta
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+// +build kubeapiserver
+
+package apiserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apiv1 "github.com/DataDog/datadog-agent/pkg/clusteragent/api/v1"
+)
+
+func newTestPod(name, ip string, phase v1.PodPhase, created time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID("uid-" + name),
+			Labels:            map[string]string{"app": name},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "proxy", Ports: []v1.ContainerPort{{ContainerPort: 15001}}},
+				{Name: "app", Ports: []v1.ContainerPort{{ContainerPort: 8080}}},
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: phase,
+			PodIP: ip,
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "proxy", ContainerID: "docker://ctr-proxy-" + name, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				{Name: "app", ContainerID: "docker://ctr-app-" + name, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			},
+		},
+	}
+}
+
+func TestPodsMetadataForIPs(t *testing.T) {
+	now := time.Now()
+
+	pods := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{podIPIndex: podIPIndexFunc})
+	// The IP of a completed job is reused by a running pod
+	require.NoError(t, pods.Add(newTestPod("job", "10.0.1.2", v1.PodSucceeded, now.Add(-time.Hour))))
+	require.NoError(t, pods.Add(newTestPod("web", "10.0.1.2", v1.PodRunning, now.Add(-time.Minute))))
+	hostPod := newTestPod("host", "192.168.0.1", v1.PodRunning, now)
+	hostPod.Spec.HostNetwork = true
+	require.NoError(t, pods.Add(hostPod))
+
+	services := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, services.Add(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-svc", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}))
+	require.NoError(t, services.Add(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-other-ns", Namespace: "other"},
+		Spec:       v1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}))
+
+	resp, err := podsMetadataForIPs(pods, listersv1.NewServiceLister(services), []string{"10.0.1.2", "192.168.0.1", "1.1.1.1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, apiv1.PodIPsMetadataResponse{
+		"10.0.1.2": {
+			Name:           "web",
+			Namespace:      "default",
+			UID:            "uid-web",
+			ContainerIDs:   []string{"ctr-app-web", "ctr-proxy-web"},
+			ContainerPorts: map[int32]string{8080: "ctr-app-web", 15001: "ctr-proxy-web"},
+			Services:       []string{"web-svc"},
+		},
+	}, resp)
+}
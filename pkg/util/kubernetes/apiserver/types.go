@@ -19,6 +19,7 @@ const (
 	autoscalersController controllerName = "autoscalers"
 	servicesController    controllerName = "services"
 	endpointsController   controllerName = "endpoints"
+	podIPsController      controllerName = "podIPs"
 )
 
 // InformerName represents the kubernetes informer names
@@ -30,6 +31,8 @@ const (
 	SecretsInformer InformerName = "secrets"
 	// WebhooksInformer holds the name of the informer
 	WebhooksInformer InformerName = "webhooks"
+	// ValidatingWebhooksInformer holds the name of the informer
+	ValidatingWebhooksInformer InformerName = "validatingWebhooks"
 	// PodsInformer holds the name of the informer
 	PodsInformer InformerName = "pods"
 	// DeploysInformer holds the name of the informer
@@ -40,4 +43,14 @@ const (
 	ServicesInformer InformerName = "services"
 	// NodesInformer holds the name of the informer
 	NodesInformer InformerName = "nodes"
+	// StatefulSetsInformer holds the name of the informer
+	StatefulSetsInformer InformerName = "statefulSets"
+	// DaemonSetsInformer holds the name of the informer
+	DaemonSetsInformer InformerName = "daemonSets"
+	// JobsInformer holds the name of the informer
+	JobsInformer InformerName = "jobs"
+	// CronJobsInformer holds the name of the informer
+	CronJobsInformer InformerName = "cronJobs"
+	// PersistentVolumeClaimsInformer holds the name of the informer
+	PersistentVolumeClaimsInformer InformerName = "persistentVolumeClaims"
 )
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-2020 Datadog, Inc.
+
+package eventlogs
+
+import (
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+const logsChanSize = 1000
+
+// Log is a Kubernetes event formatted to be forwarded as a log.
+type Log struct {
+	Content []byte
+	Tags    []string
+	Status  string
+}
+
+// LogsChannel is the type of channels of Kubernetes event logs.
+type LogsChannel = chan *Log
+
+var (
+	logsChan = make(LogsChannel, logsChanSize)
+	// consumed is set to 1 while the logs pipeline consumes the logs channel
+	consumed int32
+)
+
+// IsEnabled returns whether Kubernetes events are forwarded as logs.
+func IsEnabled() bool {
+	return config.Datadog.GetBool("kubernetes_events_as_logs")
+}
+
+// SetConsumed records whether the logs pipeline consumes the logs channel.
+func SetConsumed(isConsumed bool) {
+	var v int32
+	if isConsumed {
+		v = 1
+	}
+	atomic.StoreInt32(&consumed, v)
+}
+
+// IsConsumed returns whether the logs pipeline consumes the logs channel,
+// Kubernetes events must not be forwarded as logs otherwise.
+func IsConsumed() bool {
+	return atomic.LoadInt32(&consumed) == 1
+}
+
+// GetLogsChannel returns the channel containing all the Kubernetes event logs to forward.
+func GetLogsChannel() LogsChannel {
+	return logsChan
+}
+
+// Submit sends a Kubernetes event log to the logs channel,
+// it returns false if the channel is full and the log was dropped.
+func Submit(l *Log) bool {
+	select {
+	case logsChan <- l:
+		return true
+	default:
+		return false
+	}
+}
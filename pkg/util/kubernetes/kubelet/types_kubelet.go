@@ -96,6 +96,7 @@ type Status struct {
 	Phase          string            `json:"phase,omitempty"`
 	HostIP         string            `json:"hostIP,omitempty"`
 	PodIP          string            `json:"podIP,omitempty"`
+	StartTime      time.Time         `json:"startTime,omitempty"`
 	Containers     []ContainerStatus `json:"containerStatuses,omitempty"`
 	InitContainers []ContainerStatus `json:"initContainerStatuses,omitempty"`
 	AllContainers  []ContainerStatus